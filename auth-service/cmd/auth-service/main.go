@@ -1,39 +1,258 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	authpbv2 "github.com/Koshsky/subs-service/auth-service/internal/authpb/v2"
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
 	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
 	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
 	"github.com/Koshsky/subs-service/auth-service/internal/server"
 	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/Koshsky/subs-service/auth-service/migrations"
+	"github.com/Koshsky/subs-service/pkg/migrator"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
-// setupServices initializes all services and returns them
-func setupServices(cfg *config.Config) (*services.AuthService, *server.AuthServer, error) {
-	// Initialize RabbitMQ service
+// connectMessageBroker connects to RabbitMQ, the service runs without event publishing when it is unavailable
+func connectMessageBroker(cfg *config.Config) messaging.IMessageBroker {
 	rabbitmqService, err := messaging.NewRabbitMQAdapter(cfg.RabbitMQ)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize RabbitMQ service: %v", err)
 		log.Printf("Auth service will continue without event publishing")
-		rabbitmqService = nil
+		return nil
 	}
+	return rabbitmqService
+}
 
-	// Initialize database and repositories
-	gormAdapter, err := repositories.NewGormAdapter(cfg.Database)
+// setupServices initializes all services on top of the database and the message broker and returns them
+func setupServices(cfg *config.Config, gormAdapter repositories.IDatabase, rabbitmqService messaging.IMessageBroker) (*services.AuthService, *services.AccountDeletionService, *server.AuthServer, error) {
+	keys, err := jwtkeys.Load(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	hasher, err := newPasswordHasher(cfg.PasswordHash)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid password hashing configuration: %w", err)
+	}
+	passwordPolicy, err := newPasswordPolicy(cfg.PasswordPolicy)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid password policy configuration: %w", err)
+	}
+	emailNormalizer, err := newEmailNormalizer(cfg.Email)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, fmt.Errorf("invalid email configuration: %w", err)
 	}
+
 	userRepo := repositories.NewUserRepository(gormAdapter)
-	authService := services.NewAuthService(userRepo, rabbitmqService, cfg)
-	authServer := server.NewAuthServer(authService)
+	accessTokenRepo := repositories.NewAccessTokenRepository(gormAdapter)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(gormAdapter)
+	revokedTokenRepo := repositories.NewRevokedTokenRepository(gormAdapter)
+	singleUseTokenRepo := repositories.NewSingleUseTokenRepository(gormAdapter)
+	accountDeletionRepo := repositories.NewAccountDeletionRepository(gormAdapter)
+	loginFailureRepo := repositories.NewLoginFailureRepository(gormAdapter)
+	twoFactorRepo := repositories.NewTwoFactorRepository(gormAdapter)
+	passkeyRepo := repositories.NewPasskeyRepository(gormAdapter)
+	externalIdentityRepo := repositories.NewExternalIdentityRepository(gormAdapter)
+	sessionRepo := repositories.NewSessionRepository(gormAdapter)
+	authService := services.NewAuthService(userRepo, revokedTokenRepo, rabbitmqService, keys)
+	authService.EmailVerificationPolicy = cfg.EmailVerificationPolicy
+	authService.Passwords = hasher
+	authService.PasswordPolicy = passwordPolicy
+	authService.Emails = emailNormalizer
+	authService.Lockout = services.NewLoginLockoutService(
+		loginFailureRepo,
+		cfg.LoginLockout.AccountThreshold,
+		cfg.LoginLockout.IPThreshold,
+		cfg.LoginLockout.Duration,
+		rabbitmqService,
+	)
+	sessionService := services.NewSessionService(sessionRepo, refreshTokenRepo, rabbitmqService)
+	authService.Sessions = sessionService
+	accessTokenService := services.NewAccessTokenService(accessTokenRepo, userRepo, rabbitmqService)
+	refreshTokenService := services.NewRefreshTokenService(refreshTokenRepo, userRepo, authService, sessionService)
+	passwordResetService := services.NewPasswordResetService(singleUseTokenRepo, userRepo, authService, refreshTokenService, rabbitmqService)
+	passwordResetService.Passwords = hasher
+	passwordResetService.PasswordPolicy = passwordPolicy
+	passwordResetService.Emails = emailNormalizer
+	emailVerificationService := services.NewEmailVerificationService(singleUseTokenRepo, userRepo, rabbitmqService)
+	emailVerificationService.Emails = emailNormalizer
+	accountService := services.NewAccountService(userRepo, authService, refreshTokenService, emailVerificationService)
+	accountService.Passwords = hasher
+	accountService.PasswordPolicy = passwordPolicy
+	accountService.Emails = emailNormalizer
+	accountDeletionService := services.NewAccountDeletionService(accountDeletionRepo, userRepo, authService, refreshTokenService, rabbitmqService)
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, refreshTokenService, cfg.TOTPIssuer)
+	twoFactorService.Lockout = authService.Lockout
+	authService.TwoFactor = twoFactorService
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: cfg.WebAuthn.RPDisplayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid WebAuthn configuration: %w", err)
+	}
+	passkeyService := services.NewPasskeyService(passkeyRepo, userRepo, refreshTokenService, webAuthn)
+	passkeyService.EmailVerificationPolicy = cfg.EmailVerificationPolicy
+	magicLinkService := services.NewMagicLinkService(singleUseTokenRepo, userRepo, refreshTokenService, rabbitmqService)
+	magicLinkService.TwoFactor = twoFactorService
+	magicLinkService.Emails = emailNormalizer
+	oidcLoginService := services.NewOIDCLoginService(externalIdentityRepo, userRepo, refreshTokenService, rabbitmqService)
+	oidcLoginService.TwoFactor = twoFactorService
+	oidcLoginService.Emails = emailNormalizer
+	authServer := server.NewAuthServer(
+		authService,
+		accessTokenService,
+		refreshTokenService,
+		passwordResetService,
+		emailVerificationService,
+		accountService,
+		accountDeletionService,
+		twoFactorService,
+		passkeyService,
+		magicLinkService,
+		oidcLoginService,
+		sessionService,
+	)
+
+	return authService, accountDeletionService, authServer, nil
+}
+
+// newPasswordHasher creates the hasher of new passwords from the PASSWORD_* settings
+func newPasswordHasher(cfg config.PasswordHashConfig) (*passwords.Hasher, error) {
+	if cfg.Argon2Memory < 0 || cfg.Argon2Iterations < 0 || cfg.Argon2Parallelism < 0 || cfg.Argon2Parallelism > 255 {
+		return nil, errors.New("argon2id parameters are out of range")
+	}
+	return passwords.NewHasher(passwords.Params{
+		Algorithm:   cfg.Algorithm,
+		BcryptCost:  cfg.BcryptCost,
+		Memory:      uint32(cfg.Argon2Memory),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+	})
+}
+
+// newPasswordPolicy creates the policy of new passwords from the PASSWORD_* settings
+// and loads the breached password list
+func newPasswordPolicy(cfg config.PasswordPolicyConfig) (*passwords.Policy, error) {
+	if cfg.MinLength < 1 {
+		return nil, errors.New("minimum password length must be at least 1")
+	}
+	if cfg.MaxLength != 0 && cfg.MaxLength < cfg.MinLength {
+		return nil, errors.New("maximum password length must not be less than the minimum")
+	}
 
-	return authService, authServer, nil
+	policy := &passwords.Policy{
+		MinLength:     cfg.MinLength,
+		MaxLength:     cfg.MaxLength,
+		RequireLower:  cfg.RequireLower,
+		RequireUpper:  cfg.RequireUpper,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+	}
+	if !cfg.CheckBreached {
+		return policy, nil
+	}
+
+	if cfg.BreachedFile == "" {
+		policy.Breached = passwords.DefaultBreachedList()
+		return policy, nil
+	}
+	breached, err := passwords.LoadBreachedListFile(cfg.BreachedFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breached passwords: %w", err)
+	}
+	log.Printf("Loaded %d breached password hashes from %s", breached.Len(), cfg.BreachedFile)
+	policy.Breached = breached
+	return policy, nil
+}
+
+// newEmailNormalizer creates the email normalizer from the EMAIL_* settings
+// and loads the disposable domain list
+func newEmailNormalizer(cfg config.EmailConfig) (*emails.Normalizer, error) {
+	normalizer := emails.NewNormalizer(emails.Rules{
+		IgnoreDotsDomains: cfg.IgnoreDotsDomains,
+		SubaddressDomains: cfg.SubaddressDomains,
+	})
+	if cfg.DisposableFile == "" {
+		return normalizer, nil
+	}
+
+	disposable, err := emails.LoadDomainListFile(cfg.DisposableFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load disposable email domains: %w", err)
+	}
+	log.Printf("Loaded %d disposable email domains from %s", disposable.Len(), cfg.DisposableFile)
+	normalizer.Disposable = disposable
+	return normalizer, nil
+}
+
+// normalizeStoredEmails brings the stored canonical emails in line with the current
+// EMAIL_* rules, which may have changed since the accounts were created. Logins look
+// accounts up by the canonical email, so this has to finish before the service serves.
+func normalizeStoredEmails(authService *services.AuthService) error {
+	updated, err := authService.NormalizeStoredEmails(context.Background())
+	if errors.Is(err, services.ErrEmailConflicts) {
+		return fmt.Errorf("%w, list them with 'auth-service emails conflicts'", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to normalize stored emails: %w", err)
+	}
+	if updated > 0 {
+		log.Printf("Normalized the stored emails of %d users", updated)
+	}
+	return nil
+}
+
+// startAccountDeletions consumes the acknowledgements of account deletions and
+// periodically republishes user.deleted for deletions still pending in some service.
+// It returns the acknowledgement consumer, nil without RabbitMQ.
+func startAccountDeletions(ctx context.Context, background *sync.WaitGroup, cfg *config.Config, deletions *services.AccountDeletionService) *messaging.DeletionAckConsumer {
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		deletions.Run(ctx, services.AccountDeletionRetryInterval)
+	}()
+
+	consumer, err := messaging.NewDeletionAckConsumer(cfg.RabbitMQ, func(userID uuid.UUID, service string) error {
+		return deletions.AcknowledgeDeletion(context.Background(), userID, service)
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to subscribe to account deletion acknowledgements: %v", err)
+		log.Printf("Account deletions will stay pending until the service is restarted with RabbitMQ")
+		return nil
+	}
+
+	go func() {
+		if err := consumer.StartConsuming(); err != nil {
+			log.Printf("Account deletion acknowledgement consumer stopped: %v", err)
+		}
+	}()
+	return consumer
 }
 
 // createGRPCServer creates and configures the gRPC server
@@ -53,10 +272,21 @@ func createGRPCServer(cfg *config.Config) (*grpc.Server, error) {
 	return grpcServer, nil
 }
 
-// startServer starts the gRPC server
-func startServer(grpcServer *grpc.Server, authServer *server.AuthServer, port string) error {
+// registerServices registers both versions of the auth API, grpc.health.v1 and,
+// when enabled, server reflection
+func registerServices(grpcServer *grpc.Server, authServer *server.AuthServer, healthServer *health.Server, enableReflection bool) {
 	authpb.RegisterAuthServiceServer(grpcServer, authServer)
+	authpbv2.RegisterAuthServiceServer(grpcServer, server.NewAuthServerV2(authServer.Services))
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	if enableReflection {
+		reflection.Register(grpcServer)
+		log.Printf("gRPC server reflection is enabled")
+	}
+}
 
+// startServer starts the gRPC server
+func startServer(grpcServer *grpc.Server, port string) error {
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		return err
@@ -66,14 +296,210 @@ func startServer(grpcServer *grpc.Server, authServer *server.AuthServer, port st
 	return grpcServer.Serve(lis)
 }
 
+// stopServer waits for in-flight RPCs to finish and cancels those still running after timeout
+func stopServer(grpcServer *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+	case <-timer.C:
+		log.Printf("In-flight RPCs did not finish within %s, cancelling them", timeout)
+		grpcServer.Stop()
+		<-stopped
+	}
+}
+
+// startJWKSServer serves the public JWT verification keys over HTTP in the background
+func startJWKSServer(authService *services.AuthService, port string) *http.Server {
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           server.NewJWKSHandler(authService),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Printf("JWKS endpoint starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("JWKS server stopped: %v", err)
+		}
+	}()
+	return srv
+}
+
+// runMigrate executes the migrate subcommand against the auth database
+func runMigrate(db config.DBConfig, args []string) error {
+	if _, _, err := migrator.ParseArgs(args); err != nil {
+		return err
+	}
+
+	m, err := migrator.New(db.ConnectionString(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Run(args, os.Stdout)
+}
+
+// runKeys executes the keys subcommand against the JWT key directory
+func runKeys(jwtConfig config.JWTConfig, args []string) error {
+	if _, _, err := jwtkeys.ParseArgs(args); err != nil {
+		return err
+	}
+	if jwtConfig.KeysDir == "" {
+		return errors.New("JWT_KEYS_DIR is not set")
+	}
+
+	return jwtkeys.Dir{Path: jwtConfig.KeysDir}.Run(args, os.Stdout, time.Now(), services.JWTTokenTTL)
+}
+
+// runDeletions executes the deletions subcommand, which lists account deletions
+// that some service has not acknowledged yet
+func runDeletions(db config.DBConfig, args []string) error {
+	if len(args) != 1 || args[0] != "pending" {
+		return errors.New("usage: auth-service deletions pending")
+	}
+
+	gormAdapter, err := repositories.NewGormAdapter(db)
+	if err != nil {
+		return err
+	}
+	defer gormAdapter.Close()
+	repo := repositories.NewAccountDeletionRepository(gormAdapter)
+
+	deletions, err := repo.ListPendingAccountDeletions(time.Now())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER ID\tREQUESTED AT\tATTEMPTS\tPENDING IN")
+	for _, deletion := range deletions {
+		acks, err := repo.ListAccountDeletionAcks(deletion.UserID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", deletion.UserID, deletion.RequestedAt.UTC().Format(time.RFC3339),
+			deletion.Attempts, strings.Join(models.PendingDeletionServices(acks), ", "))
+	}
+	return w.Flush()
+}
+
+// runEmails executes the emails subcommand, which lists the accounts that the current
+// EMAIL_* rules give the same canonical email
+func runEmails(db config.DBConfig, emailConfig config.EmailConfig, args []string) error {
+	if len(args) != 1 || args[0] != "conflicts" {
+		return errors.New("usage: auth-service emails conflicts")
+	}
+
+	emailNormalizer, err := newEmailNormalizer(emailConfig)
+	if err != nil {
+		return err
+	}
+	gormAdapter, err := repositories.NewGormAdapter(db)
+	if err != nil {
+		return err
+	}
+	defer gormAdapter.Close()
+	authService := services.NewAuthService(repositories.NewUserRepository(gormAdapter), nil, nil, nil)
+	authService.Emails = emailNormalizer
+
+	conflicts, err := authService.FindEmailConflicts(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CANONICAL EMAIL\tUSER ID\tEMAIL")
+	for _, conflict := range conflicts {
+		for _, user := range conflict.Users {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", conflict.EmailNormalized, user.ID, user.Email)
+		}
+	}
+	return w.Flush()
+}
+
+// checkSchemaVersion verifies that the auth database is migrated to the
+// version embedded in this binary
+func checkSchemaVersion(cfg *config.Config) error {
+	m, err := migrator.New(cfg.Database.ConnectionString(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.CheckVersion()
+}
+
 func main() {
+	// Subcommands load only the settings they use, so they run without the service configuration
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(config.LoadDBConfig(), os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		if err := runKeys(config.LoadJWTConfig(), os.Args[2:]); err != nil {
+			log.Fatalf("Key management failed: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "deletions" {
+		if err := runDeletions(config.LoadDBConfig(), os.Args[2:]); err != nil {
+			log.Fatalf("Listing account deletions failed: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "emails" {
+		if err := runEmails(config.LoadDBConfig(), config.LoadEmailConfig(), os.Args[2:]); err != nil {
+			log.Fatalf("Listing email conflicts failed: %v", err)
+		}
+		return
+	}
+
 	cfg := config.LoadConfig()
 
+	if cfg.CheckSchemaVersion {
+		if err := checkSchemaVersion(cfg); err != nil {
+			log.Fatalf("Refusing to start: %v", err)
+		}
+	}
+
+	rabbitmqService := connectMessageBroker(cfg)
+	gormAdapter, err := repositories.NewGormAdapter(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to setup services: %v", err)
+	}
+
 	// Setup services
-	_, authServer, err := setupServices(cfg)
+	authService, accountDeletionService, authServer, err := setupServices(cfg, gormAdapter, rabbitmqService)
 	if err != nil {
 		log.Fatalf("Failed to setup services: %v", err)
 	}
+	if err := normalizeStoredEmails(authService); err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+
+	// Background work is stopped and waited for before the connections it uses are closed
+	ctx, stopBackground := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	deletionConsumer := startAccountDeletions(ctx, &background, cfg, accountDeletionService)
+
+	var jwksServer *http.Server
+	if cfg.HTTPPort != "" {
+		jwksServer = startJWKSServer(authService, cfg.HTTPPort)
+	}
 
 	// Create gRPC server
 	grpcServer, err := createGRPCServer(cfg)
@@ -81,8 +507,61 @@ func main() {
 		log.Fatalf("Failed to create gRPC server: %v", err)
 	}
 
+	healthServer := health.NewServer()
+	registerServices(grpcServer, authServer, healthServer, cfg.EnableReflection)
+	monitor := server.NewHealthMonitor(healthServer, map[string]server.HealthCheck{
+		"database": gormAdapter.Ping,
+		"rabbitmq": func(ctx context.Context) error {
+			// Without a connection from startup events are not published, but RPCs are still served
+			if rabbitmqService == nil {
+				return fmt.Errorf("%w: RabbitMQ is not connected, events are not published", server.ErrDegraded)
+			}
+			return messaging.CheckBroker(ctx, cfg.RabbitMQ.URL)
+		},
+	})
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		monitor.Run(ctx, server.HealthCheckInterval)
+	}()
+
 	// Start server
-	if err := startServer(grpcServer, authServer, cfg.Port); err != nil {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- startServer(grpcServer, cfg.Port)
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-quit:
+		log.Println("Shutting down auth-service...")
+	case err := <-serveErr:
 		log.Printf("gRPC server stopped: %v", err)
 	}
+
+	// Clients stop sending new RPCs once the service reports NOT_SERVING
+	healthServer.Shutdown()
+	stopServer(grpcServer, cfg.ShutdownTimeout)
+
+	if jwksServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		if err := jwksServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shutdown JWKS server: %v", err)
+		}
+		cancel()
+	}
+
+	stopBackground()
+	background.Wait()
+	if deletionConsumer != nil {
+		deletionConsumer.Close()
+	}
+	if rabbitmqService != nil {
+		rabbitmqService.Close()
+	}
+	if err := gormAdapter.Close(); err != nil {
+		log.Printf("Failed to close database connections: %v", err)
+	}
+	log.Println("Auth service stopped gracefully")
 }
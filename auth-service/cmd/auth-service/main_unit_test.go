@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestCreateGRPCServer_WithoutTLS_Unit(t *testing.T) {
@@ -65,7 +73,199 @@ func TestStartServer_ValidPort(t *testing.T) {
 	defer listener.Close()
 }
 
+func TestStopServer_NoRPCs(t *testing.T) {
+	// Arrange
+	grpcServer := grpc.NewServer()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+
+	// Act
+	start := time.Now()
+	stopServer(grpcServer, 5*time.Second)
+
+	// Assert
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestStopServer_TimeoutCancelsRPCs(t *testing.T) {
+	// Arrange - a Watch stream stays open until the server cancels it
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// Act
+	start := time.Now()
+	stopServer(grpcServer, 100*time.Millisecond)
+
+	// Assert
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	_, err = stream.Recv()
+	assert.Error(t, err)
+}
+
+func TestNewPasswordHasher_Defaults(t *testing.T) {
+	// Arrange
+	cfg := config.PasswordHashConfig{
+		Algorithm:         "argon2id",
+		BcryptCost:        12,
+		Argon2Memory:      64 * 1024,
+		Argon2Iterations:  3,
+		Argon2Parallelism: 2,
+	}
+
+	// Act
+	hasher, err := newPasswordHasher(cfg)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotNil(t, hasher)
+}
+
+func TestNewPasswordHasher_OutOfRange(t *testing.T) {
+	testCases := map[string]config.PasswordHashConfig{
+		"negative memory":      {Algorithm: "argon2id", Argon2Memory: -1, Argon2Iterations: 3, Argon2Parallelism: 2},
+		"parallelism overflow": {Algorithm: "argon2id", Argon2Memory: 64 * 1024, Argon2Iterations: 3, Argon2Parallelism: 256},
+		"bcrypt cost too high": {Algorithm: "bcrypt", BcryptCost: 40},
+	}
+
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			hasher, err := newPasswordHasher(cfg)
+
+			// Assert
+			require.Error(t, err)
+			assert.Nil(t, hasher)
+		})
+	}
+}
+
+func TestNewPasswordPolicy(t *testing.T) {
+	// Arrange
+	cfg := config.PasswordPolicyConfig{MinLength: 10, MaxLength: 72, RequireDigit: true, CheckBreached: true}
+
+	// Act
+	policy, err := newPasswordPolicy(cfg)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 10, policy.MinLength)
+	assert.True(t, policy.RequireDigit)
+	assert.False(t, policy.RequireSymbol)
+	require.NotNil(t, policy.Breached)
+	assert.True(t, policy.Breached.Contains("P@ssw0rd123"))
+}
+
+func TestNewPasswordPolicy_BreachedFile(t *testing.T) {
+	// Arrange - SHA-1 of "password"
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\n"), 0o600))
+	cfg := config.PasswordPolicyConfig{MinLength: 8, CheckBreached: true, BreachedFile: path}
+
+	// Act
+	policy, err := newPasswordPolicy(cfg)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, policy.Breached.Len())
+	assert.True(t, policy.Breached.Contains("password"))
+}
+
+func TestNewPasswordPolicy_BreachedCheckDisabled(t *testing.T) {
+	// Act
+	policy, err := newPasswordPolicy(config.PasswordPolicyConfig{MinLength: 8, BreachedFile: "/nonexistent"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, policy.Breached)
+}
+
+func TestNewPasswordPolicy_Invalid(t *testing.T) {
+	testCases := map[string]config.PasswordPolicyConfig{
+		"no minimum length":         {MinLength: 0},
+		"maximum below the minimum": {MinLength: 12, MaxLength: 8},
+		"missing breached file":     {MinLength: 8, CheckBreached: true, BreachedFile: "/nonexistent/breached.txt"},
+	}
+
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			policy, err := newPasswordPolicy(cfg)
+
+			// Assert
+			require.Error(t, err)
+			assert.Nil(t, policy)
+		})
+	}
+}
+
+func TestNewEmailNormalizer(t *testing.T) {
+	// Arrange
+	cfg := config.EmailConfig{IgnoreDotsDomains: []string{"gmail.com"}, SubaddressDomains: []string{""}}
+
+	// Act
+	normalizer, err := newEmailNormalizer(cfg)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe+tag@gmail.com", normalizer.Canonical("J.Doe+tag@gmail.com"))
+	assert.Nil(t, normalizer.Disposable)
+}
+
+func TestNewEmailNormalizer_DisposableFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	require.NoError(t, os.WriteFile(path, []byte("mailinator.com\n"), 0o600))
+
+	// Act
+	normalizer, err := newEmailNormalizer(config.EmailConfig{DisposableFile: path})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Error(t, normalizer.CheckDomain("test@mailinator.com"))
+}
+
+func TestNewEmailNormalizer_MissingDisposableFile(t *testing.T) {
+	// Act
+	normalizer, err := newEmailNormalizer(config.EmailConfig{DisposableFile: "/nonexistent/disposable.txt"})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, normalizer)
+}
+
 // TestConfigValidation tests configuration validation scenarios
+func TestRunKeys_OnlyNeedsKeysDir(t *testing.T) {
+	// Arrange - none of the service settings such as AUTH_DB_* or JWT_SECRET are set
+	t.Setenv("JWT_KEYS_DIR", t.TempDir())
+
+	// Act
+	err := runKeys(config.LoadJWTConfig(), []string{"list"})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestRunKeys_WithoutKeysDir(t *testing.T) {
+	// Act
+	err := runKeys(config.JWTConfig{}, []string{"list"})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_KEYS_DIR is not set")
+}
+
 func TestConfigValidation(t *testing.T) {
 	t.Run("ValidConfig", func(t *testing.T) {
 		// Arrange
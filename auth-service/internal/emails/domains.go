@@ -0,0 +1,71 @@
+package emails
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DomainList is a set of email domains, e.g. of disposable email providers
+type DomainList struct {
+	domains map[string]bool
+}
+
+// LoadDomainList reads one domain per line, empty lines and # comments are ignored
+func LoadDomainList(r io.Reader) (*DomainList, error) {
+	list := &DomainList{domains: make(map[string]bool)}
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.ContainsAny(line, "@ \t") {
+			return nil, fmt.Errorf("line %d: not a domain", lineNumber)
+		}
+		list.domains[strings.TrimSuffix(line, ".")] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// LoadDomainListFile reads a domain list from path, see LoadDomainList
+func LoadDomainListFile(path string) (*DomainList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list, err := LoadDomainList(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Contains reports whether domain or one of its parent domains is in the list
+func (l *DomainList) Contains(domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	for domain != "" {
+		if l.domains[domain] {
+			return true
+		}
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			return false
+		}
+		domain = parent
+	}
+	return false
+}
+
+// Len returns the number of domains in the list
+func (l *DomainList) Len() int {
+	return len(l.domains)
+}
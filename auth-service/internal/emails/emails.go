@@ -0,0 +1,110 @@
+// Package emails normalizes email addresses. Accounts are looked up and kept unique by the
+// canonical form of their address, so that "Alice@Example.com" and "alice@example.com" belong
+// to the same account. Provider rules, which make "j.doe+news@gmail.com" and "jdoe@gmail.com"
+// the same account too, are only applied when configured.
+package emails
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrInvalidEmail is returned for addresses that are not valid email syntax
+	ErrInvalidEmail = errors.New("invalid email address")
+	// ErrDisposableEmail is returned for addresses of blocked disposable email domains
+	ErrDisposableEmail = errors.New("disposable email addresses are not allowed")
+)
+
+// AllDomains in Rules applies a rule to every domain
+const AllDomains = "*"
+
+// validate checks the email tag of models.User
+var validate = utils.NewValidator()
+
+// Rules lists the providers whose mailboxes ignore parts of the local part.
+// The zero value applies no provider rule, canonical addresses are only lowercased.
+type Rules struct {
+	// IgnoreDotsDomains deliver "j.doe@" and "jdoe@" to the same mailbox
+	IgnoreDotsDomains []string
+	// SubaddressDomains deliver "jdoe+tag@" to "jdoe@"
+	SubaddressDomains []string
+}
+
+// Normalizer normalizes addresses with provider-specific rules
+type Normalizer struct {
+	ignoreDots map[string]bool
+	subaddress map[string]bool
+	// Disposable blocks new addresses of these domains, nothing is blocked when it is nil
+	Disposable *DomainList
+}
+
+// NewNormalizer creates a Normalizer applying rules. Domains are matched case-insensitively.
+func NewNormalizer(rules Rules) *Normalizer {
+	return &Normalizer{
+		ignoreDots: domainSet(rules.IgnoreDotsDomains),
+		subaddress: domainSet(rules.SubaddressDomains),
+	}
+}
+
+// DefaultNormalizer returns a Normalizer without provider rules and blocked domains
+func DefaultNormalizer() *Normalizer {
+	return NewNormalizer(Rules{})
+}
+
+// Normalize returns the address to store and send mail to: trimmed, in Unicode NFC and lowercase.
+// It returns ErrInvalidEmail when the result is not a valid address.
+func (n *Normalizer) Normalize(email string) (string, error) {
+	address := strings.ToLower(norm.NFC.String(strings.TrimSpace(email)))
+	if err := validate.StructPartial(&models.User{Email: address}, "Email"); err != nil {
+		return "", ErrInvalidEmail
+	}
+	return address, nil
+}
+
+// CheckDomain returns ErrDisposableEmail when a normalized address belongs to a blocked domain
+func (n *Normalizer) CheckDomain(address string) error {
+	if n.Disposable == nil {
+		return nil
+	}
+	if _, domain, found := strings.Cut(address, "@"); found && n.Disposable.Contains(domain) {
+		return ErrDisposableEmail
+	}
+	return nil
+}
+
+// Canonical returns the form of email accounts are looked up and kept unique by.
+// It applies the provider rules to the normalized address; invalid input is normalized
+// as far as possible, so that lookups with it simply find nothing.
+func (n *Normalizer) Canonical(email string) string {
+	address := strings.ToLower(norm.NFC.String(strings.TrimSpace(email)))
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return address
+	}
+	local, domain := address[:at], address[at+1:]
+
+	if n.subaddress[domain] || n.subaddress[AllDomains] {
+		if plus := strings.IndexByte(local, '+'); plus > 0 {
+			local = local[:plus]
+		}
+	}
+	if n.ignoreDots[domain] || n.ignoreDots[AllDomains] {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + domain
+}
+
+func domainSet(domains []string) map[string]bool {
+	set := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			set[domain] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,156 @@
+package emails_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	normalizer := emails.DefaultNormalizer()
+
+	testCases := map[string]string{
+		"Alice@Example.com":      "alice@example.com",
+		"  bob@example.com \n":   "bob@example.com",
+		"J.Doe+News@Gmail.com":   "j.doe+news@gmail.com",
+		"jose\u0301@example.com": "jos\u00e9@example.com", // decomposed é is composed
+	}
+
+	for input, expected := range testCases {
+		t.Run(input, func(t *testing.T) {
+			// Act
+			address, err := normalizer.Normalize(input)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, address)
+		})
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	normalizer := emails.DefaultNormalizer()
+
+	for _, input := range []string{"", "   ", "alice", "alice@", "@example.com", "alice@@example.com", "alice example@example.com"} {
+		t.Run(input, func(t *testing.T) {
+			// Act
+			address, err := normalizer.Normalize(input)
+
+			// Assert
+			assert.ErrorIs(t, err, emails.ErrInvalidEmail)
+			assert.Empty(t, address)
+		})
+	}
+}
+
+func TestCanonical_DefaultRules(t *testing.T) {
+	normalizer := emails.DefaultNormalizer()
+
+	testCases := map[string]string{
+		"Alice@Example.com":      "alice@example.com",
+		"J.Doe+News@Gmail.com":   "j.doe+news@gmail.com",
+		"j.doe+news@outlook.com": "j.doe+news@outlook.com",
+		"not an email":           "not an email",
+	}
+
+	for input, expected := range testCases {
+		t.Run(input, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, expected, normalizer.Canonical(input))
+		})
+	}
+}
+
+func TestCanonical_ProviderRules(t *testing.T) {
+	normalizer := emails.NewNormalizer(emails.Rules{
+		IgnoreDotsDomains: []string{"gmail.com", "googlemail.com"},
+		SubaddressDomains: []string{"gmail.com", "googlemail.com", "outlook.com"},
+	})
+
+	testCases := map[string]string{
+		"Alice@Example.com":          "alice@example.com",
+		"J.Doe+News@Gmail.com":       "jdoe@gmail.com",
+		"j.doe@googlemail.com":       "jdoe@googlemail.com",
+		"j.doe+news@outlook.com":     "j.doe@outlook.com",
+		"j.doe+news@example.com":     "j.doe+news@example.com",
+		"+news@gmail.com":            "+news@gmail.com",
+		"not an email":               "not an email",
+		"quoted\"@\"local@gmail.com": "quoted\"@\"local@gmail.com",
+	}
+
+	for input, expected := range testCases {
+		t.Run(input, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, expected, normalizer.Canonical(input))
+		})
+	}
+}
+
+func TestCanonical_CustomRules(t *testing.T) {
+	// Arrange
+	normalizer := emails.NewNormalizer(emails.Rules{
+		IgnoreDotsDomains: []string{" Example.ORG ", ""},
+		SubaddressDomains: []string{emails.AllDomains},
+	})
+
+	// Act & Assert
+	assert.Equal(t, "jdoe@example.org", normalizer.Canonical("j.doe+x@example.org"))
+	assert.Equal(t, "j.doe@example.com", normalizer.Canonical("j.doe+x@example.com"))
+	assert.Equal(t, "j.doe+x@gmail.com", emails.NewNormalizer(emails.Rules{}).Canonical("J.Doe+x@gmail.com"))
+}
+
+func TestCheckDomain(t *testing.T) {
+	// Arrange
+	normalizer := emails.DefaultNormalizer()
+
+	// Act & Assert - nothing is blocked without a list
+	assert.NoError(t, normalizer.CheckDomain("alice@mailinator.com"))
+
+	// Arrange
+	list, err := emails.LoadDomainList(strings.NewReader("# disposable\nMailinator.com\n\ntemp-mail.org.\n"))
+	require.NoError(t, err)
+	normalizer.Disposable = list
+
+	// Act & Assert
+	assert.ErrorIs(t, normalizer.CheckDomain("alice@mailinator.com"), emails.ErrDisposableEmail)
+	assert.ErrorIs(t, normalizer.CheckDomain("alice@eu.temp-mail.org"), emails.ErrDisposableEmail)
+	assert.NoError(t, normalizer.CheckDomain("alice@example.com"))
+	assert.NoError(t, normalizer.CheckDomain("alice@notmailinator.com"))
+}
+
+func TestLoadDomainList_Invalid(t *testing.T) {
+	// Act
+	list, err := emails.LoadDomainList(strings.NewReader("mailinator.com\nalice@example.com\n"))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, list)
+}
+
+func TestLoadDomainListFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	require.NoError(t, os.WriteFile(path, []byte("mailinator.com\nyopmail.com\n"), 0o600))
+
+	// Act
+	list, err := emails.LoadDomainListFile(path)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains("YOPmail.com"))
+}
+
+func TestLoadDomainListFile_Missing(t *testing.T) {
+	// Act
+	list, err := emails.LoadDomainListFile(filepath.Join(t.TempDir(), "missing.txt"))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, list)
+}
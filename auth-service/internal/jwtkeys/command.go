@@ -0,0 +1,120 @@
+package jwtkeys
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Usage describes the keys subcommand
+const Usage = "usage: keys <list | generate [HS256 | RS256 | EdDSA] | promote KID | retire [KID]>"
+
+// defaultGenerateAlgorithm is used by "keys generate" without an algorithm
+const defaultGenerateAlgorithm = AlgEdDSA
+
+// Run executes a keys subcommand such as "generate", "promote KID" or "retire".
+// maxTokenLifetime is how long issued JWTs stay valid, demoted keys are kept at least that long.
+func (d Dir) Run(args []string, out io.Writer, now time.Time, maxTokenLifetime time.Duration) error {
+	cmd, arg, err := ParseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "generate":
+		if arg == "" {
+			arg = defaultGenerateAlgorithm
+		}
+		entry, err := d.Generate(arg, now)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "generated %s key %s\n", entry.Algorithm, entry.ID)
+	case "promote":
+		if err := d.Promote(arg, now); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "key %s now signs new tokens\n", arg)
+	case "retire":
+		if arg != "" {
+			if err := d.Retire(arg, now, maxTokenLifetime); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "retired key %s\n", arg)
+			return nil
+		}
+		retired, err := d.RetireExpired(now, maxTokenLifetime)
+		if err != nil {
+			return err
+		}
+		if len(retired) == 0 {
+			fmt.Fprintln(out, "no keys to retire")
+		}
+		for _, kid := range retired {
+			fmt.Fprintf(out, "retired key %s\n", kid)
+		}
+		return nil
+	case "list":
+		return d.printKeys(out, now, maxTokenLifetime)
+	}
+	return nil
+}
+
+// ParseArgs validates keys subcommand arguments and returns the command and its argument
+func ParseArgs(args []string) (string, string, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return "", "", errors.New(Usage)
+	}
+
+	cmd := args[0]
+	arg := ""
+	if len(args) == 2 {
+		arg = args[1]
+	}
+
+	switch cmd {
+	case "list":
+		if arg != "" {
+			return "", "", errors.New(Usage)
+		}
+	case "generate":
+		if arg != "" && arg != AlgHS256 && arg != AlgRS256 && arg != AlgEdDSA {
+			return "", "", fmt.Errorf("unsupported algorithm %q: %s", arg, Usage)
+		}
+	case "promote":
+		if arg == "" {
+			return "", "", errors.New(Usage)
+		}
+	case "retire":
+	default:
+		return "", "", fmt.Errorf("unknown keys command %q: %s", cmd, Usage)
+	}
+	return cmd, arg, nil
+}
+
+func (d Dir) printKeys(out io.Writer, now time.Time, maxTokenLifetime time.Duration) error {
+	m, err := d.ReadManifest()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tALG\tSTATUS\tCREATED")
+	for _, entry := range m.Keys {
+		var status string
+		switch {
+		case entry.ID == m.SigningKey:
+			status = "signing"
+		case entry.DemotedAt == nil:
+			status = "verifying, not promoted yet"
+		case now.Before(entry.RetireAfter(maxTokenLifetime)):
+			status = "verifying, retire after " + entry.RetireAfter(maxTokenLifetime).Format(time.RFC3339)
+		default:
+			status = "verifying, can be retired"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.ID, entry.Algorithm, status, entry.CreatedAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,74 @@
+package jwtkeys_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantCmd string
+		wantArg string
+		wantErr bool
+	}{
+		{name: "List", args: []string{"list"}, wantCmd: "list"},
+		{name: "GenerateDefault", args: []string{"generate"}, wantCmd: "generate"},
+		{name: "GenerateRS256", args: []string{"generate", "RS256"}, wantCmd: "generate", wantArg: "RS256"},
+		{name: "Promote", args: []string{"promote", "kid-1"}, wantCmd: "promote", wantArg: "kid-1"},
+		{name: "RetireExpired", args: []string{"retire"}, wantCmd: "retire"},
+		{name: "RetireKey", args: []string{"retire", "kid-1"}, wantCmd: "retire", wantArg: "kid-1"},
+		{name: "NoArgs", args: nil, wantErr: true},
+		{name: "UnknownCommand", args: []string{"rotate"}, wantErr: true},
+		{name: "UnsupportedAlgorithm", args: []string{"generate", "ES256"}, wantErr: true},
+		{name: "PromoteWithoutKey", args: []string{"promote"}, wantErr: true},
+		{name: "ListWithArg", args: []string{"list", "kid-1"}, wantErr: true},
+		{name: "TooManyArgs", args: []string{"retire", "a", "b"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			cmd, arg, err := jwtkeys.ParseArgs(tt.args)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCmd, cmd)
+			assert.Equal(t, tt.wantArg, arg)
+		})
+	}
+}
+
+func TestRun_RotationCommands(t *testing.T) {
+	// Arrange
+	dir := jwtkeys.Dir{Path: t.TempDir()}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+
+	// Act
+	require.NoError(t, dir.Run([]string{"generate"}, &out, now, time.Hour))
+	require.NoError(t, dir.Run([]string{"generate", "HS256"}, &out, now, time.Hour))
+	m, err := dir.ReadManifest()
+	require.NoError(t, err)
+	require.Len(t, m.Keys, 2)
+	oldKid, newKid := m.Keys[0].ID, m.Keys[1].ID
+	require.NoError(t, dir.Run([]string{"promote", newKid}, &out, now, time.Hour))
+	require.NoError(t, dir.Run([]string{"retire"}, &out, now.Add(2*time.Hour), time.Hour))
+	out.Reset()
+	require.NoError(t, dir.Run([]string{"list"}, &out, now.Add(2*time.Hour), time.Hour))
+
+	// Assert
+	assert.Contains(t, out.String(), newKid)
+	assert.Contains(t, out.String(), "signing")
+	assert.NotContains(t, out.String(), oldKid)
+}
@@ -0,0 +1,326 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ManifestFile is the file of a key directory listing its keys
+const ManifestFile = "keys.json"
+
+// hmacSecretSize is the size of generated HS256 secrets
+const hmacSecretSize = 32
+
+// KeyEntry describes a key stored in a key directory
+type KeyEntry struct {
+	ID        string     `json:"kid"`
+	Algorithm string     `json:"alg"`
+	File      string     `json:"file"`
+	CreatedAt time.Time  `json:"created_at"`
+	DemotedAt *time.Time `json:"demoted_at,omitempty"` // when the key stopped signing new tokens
+}
+
+// Manifest lists the keys of a key directory and the one new tokens are signed with
+type Manifest struct {
+	SigningKey string     `json:"signing_kid"`
+	Keys       []KeyEntry `json:"keys"`
+}
+
+// Entry returns the key with the given id
+func (m *Manifest) Entry(kid string) (*KeyEntry, bool) {
+	for i := range m.Keys {
+		if m.Keys[i].ID == kid {
+			return &m.Keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// RetireAfter returns when tokens signed with the key have certainly expired.
+// Keys that never signed can be retired at any time, the signing key never.
+func (e *KeyEntry) RetireAfter(maxTokenLifetime time.Duration) time.Time {
+	if e.DemotedAt == nil {
+		return time.Time{}
+	}
+	return e.DemotedAt.Add(maxTokenLifetime)
+}
+
+// Dir is a directory of key files described by a manifest.
+//
+// Rotation happens in three steps, restarting auth-service after each:
+//   - generate a key, it is published in the JWKS but does not sign yet
+//   - promote it once verifiers have picked it up, the previous key keeps verifying
+//   - retire the previous key after the maximum token lifetime has passed
+type Dir struct {
+	Path string
+}
+
+// ReadManifest reads the manifest, a missing manifest is an empty key directory
+func (d Dir) ReadManifest() (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(d.Path, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{Keys: []KeyEntry{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse key manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// writeManifest replaces the manifest atomically so that a starting service never reads a partial file
+func (d Dir) writeManifest(m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.Path, ManifestFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write key manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write key manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write key manifest: %w", err)
+	}
+	return os.Rename(tmp.Name(), filepath.Join(d.Path, ManifestFile))
+}
+
+// LoadKeyRing loads every key of the directory, signing with the manifest's signing key
+func (d Dir) LoadKeyRing() (*KeyRing, error) {
+	m, err := d.ReadManifest()
+	if err != nil {
+		return nil, err
+	}
+	if m.SigningKey == "" {
+		return nil, fmt.Errorf("key directory %s has no signing key, generate and promote one", d.Path)
+	}
+
+	var signing *Key
+	var verification []*Key
+	for _, entry := range m.Keys {
+		key, err := d.loadKey(entry)
+		if err != nil {
+			return nil, err
+		}
+		if entry.ID == m.SigningKey {
+			signing = key
+		} else {
+			verification = append(verification, key)
+		}
+	}
+	if signing == nil {
+		return nil, fmt.Errorf("signing key %q is not listed in %s", m.SigningKey, ManifestFile)
+	}
+	return NewKeyRing(signing, verification...)
+}
+
+// loadKey reads the key material of entry
+func (d Dir) loadKey(entry KeyEntry) (*Key, error) {
+	if entry.File == "" || filepath.Base(entry.File) != entry.File {
+		return nil, fmt.Errorf("key %q: file must be a plain file name inside the key directory", entry.ID)
+	}
+	data, err := os.ReadFile(filepath.Join(d.Path, entry.File))
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+	}
+
+	if entry.Algorithm == AlgHS256 {
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(secret) == 0 {
+			return nil, fmt.Errorf("key %q: secret must be base64 encoded", entry.ID)
+		}
+		return NewHMACKey(entry.ID, secret), nil
+	}
+
+	key, err := ParsePrivateKeyPEM(entry.ID, data)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+	}
+	if key.Algorithm != entry.Algorithm {
+		return nil, fmt.Errorf("key %q is a %s key, but the manifest says %s", entry.ID, key.Algorithm, entry.Algorithm)
+	}
+	return key, nil
+}
+
+// Generate creates a new key. The first key of an empty directory becomes the
+// signing key right away, later keys only verify until they are promoted.
+func (d Dir) Generate(algorithm string, now time.Time) (*KeyEntry, error) {
+	m, err := d.ReadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		kid  string
+		data []byte
+	)
+	switch algorithm {
+	case AlgHS256:
+		secret := make([]byte, hmacSecretSize)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		// The thumbprint of a secret would leak its hash, so HMAC keys get a random id
+		idBytes := make([]byte, 12)
+		if _, err := rand.Read(idBytes); err != nil {
+			return nil, err
+		}
+		kid = base64.RawURLEncoding.EncodeToString(idBytes)
+		data = []byte(base64.StdEncoding.EncodeToString(secret) + "\n")
+	case AlgRS256, AlgEdDSA:
+		var privateKey any
+		var key *Key
+		if algorithm == AlgRS256 {
+			rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, key = rsaKey, NewRSAKey("", rsaKey)
+		} else {
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, key = edKey, NewEd25519Key("", edKey)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		kid = key.ID
+		data = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+
+	if _, exists := m.Entry(kid); exists {
+		return nil, fmt.Errorf("key %q already exists", kid)
+	}
+
+	entry := KeyEntry{ID: kid, Algorithm: algorithm, File: keyFileName(kid, algorithm), CreatedAt: now.UTC()}
+	if err := os.WriteFile(filepath.Join(d.Path, entry.File), data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	m.Keys = append(m.Keys, entry)
+	if m.SigningKey == "" {
+		m.SigningKey = kid
+	}
+	if err := d.writeManifest(m); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Promote makes kid the signing key. The previous signing key keeps verifying
+// tokens until it is retired.
+func (d Dir) Promote(kid string, now time.Time) error {
+	m, err := d.ReadManifest()
+	if err != nil {
+		return err
+	}
+
+	entry, ok := m.Entry(kid)
+	if !ok {
+		return fmt.Errorf("unknown key %q", kid)
+	}
+	if m.SigningKey == kid {
+		return fmt.Errorf("key %q already signs tokens", kid)
+	}
+
+	if previous, ok := m.Entry(m.SigningKey); ok {
+		demotedAt := now.UTC()
+		previous.DemotedAt = &demotedAt
+	}
+	entry.DemotedAt = nil
+	m.SigningKey = kid
+	return d.writeManifest(m)
+}
+
+// Retire removes kid from the directory. Keys that signed tokens can only be
+// retired once such tokens have expired, i.e. maxTokenLifetime after demotion.
+func (d Dir) Retire(kid string, now time.Time, maxTokenLifetime time.Duration) error {
+	m, err := d.ReadManifest()
+	if err != nil {
+		return err
+	}
+
+	entry, ok := m.Entry(kid)
+	if !ok {
+		return fmt.Errorf("unknown key %q", kid)
+	}
+	if m.SigningKey == kid {
+		return fmt.Errorf("key %q signs tokens, promote another key first", kid)
+	}
+	if retireAfter := entry.RetireAfter(maxTokenLifetime); now.Before(retireAfter) {
+		return fmt.Errorf("tokens signed with key %q may be valid until %s", kid, retireAfter.Format(time.RFC3339))
+	}
+
+	return d.remove(m, kid)
+}
+
+// RetireExpired retires every former signing key whose tokens have expired
+// and returns their ids. Keys waiting for promotion are kept.
+func (d Dir) RetireExpired(now time.Time, maxTokenLifetime time.Duration) ([]string, error) {
+	m, err := d.ReadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var retired []string
+	for _, entry := range m.Keys {
+		if entry.DemotedAt != nil && !now.Before(entry.RetireAfter(maxTokenLifetime)) {
+			retired = append(retired, entry.ID)
+		}
+	}
+	for _, kid := range retired {
+		if err := d.remove(m, kid); err != nil {
+			return nil, err
+		}
+	}
+	return retired, nil
+}
+
+// remove drops kid from the manifest first, so that a failure never leaves a listed key without its file
+func (d Dir) remove(m *Manifest, kid string) error {
+	entry, _ := m.Entry(kid)
+	file := entry.File
+
+	m.Keys = slices.DeleteFunc(m.Keys, func(e KeyEntry) bool { return e.ID == kid })
+	if err := d.writeManifest(m); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(d.Path, file)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("key %q retired, but its file could not be removed: %w", kid, err)
+	}
+	return nil
+}
+
+func keyFileName(kid, algorithm string) string {
+	if algorithm == AlgHS256 {
+		return kid + ".secret"
+	}
+	return kid + ".pem"
+}
@@ -0,0 +1,256 @@
+package jwtkeys_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyDirTestSuite struct {
+	suite.Suite
+	dir              jwtkeys.Dir
+	now              time.Time
+	maxTokenLifetime time.Duration
+	claims           jwt.MapClaims
+}
+
+func (suite *KeyDirTestSuite) SetupTest() {
+	suite.dir = jwtkeys.Dir{Path: suite.T().TempDir()}
+	suite.now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.maxTokenLifetime = 24 * time.Hour
+	suite.claims = jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// generate adds a key to the directory
+func (suite *KeyDirTestSuite) generate(algorithm string) string {
+	entry, err := suite.dir.Generate(algorithm, suite.now)
+	suite.Require().NoError(err)
+	return entry.ID
+}
+
+// ring loads the directory the way auth-service does at startup
+func (suite *KeyDirTestSuite) ring() *jwtkeys.KeyRing {
+	ring, err := suite.dir.LoadKeyRing()
+	suite.Require().NoError(err)
+	return ring
+}
+
+// sign issues a token with the current signing key of the directory
+func (suite *KeyDirTestSuite) sign() string {
+	token, err := suite.ring().Sign(suite.claims)
+	suite.Require().NoError(err)
+	return token
+}
+
+// verify validates token against ring the way AuthService does
+func (suite *KeyDirTestSuite) verify(ring *jwtkeys.KeyRing, token string) error {
+	_, err := jwt.Parse(token, ring.Keyfunc, jwt.WithValidMethods(ring.Algorithms()))
+	return err
+}
+
+// ===== GENERATE TESTS =====
+
+func (suite *KeyDirTestSuite) TestGenerate_FirstKeySigns() {
+	// Act
+	kid := suite.generate(jwtkeys.AlgEdDSA)
+
+	// Assert
+	suite.Equal(kid, suite.ring().SigningKey().ID)
+	info, err := os.Stat(filepath.Join(suite.dir.Path, kid+".pem"))
+	suite.Require().NoError(err)
+	suite.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
+
+func (suite *KeyDirTestSuite) TestGenerate_LaterKeyOnlyVerifies() {
+	// Arrange
+	first := suite.generate(jwtkeys.AlgEdDSA)
+
+	// Act
+	second := suite.generate(jwtkeys.AlgRS256)
+
+	// Assert
+	ring := suite.ring()
+	suite.Equal(first, ring.SigningKey().ID)
+	suite.Len(ring.JWKS().Keys, 2, "the new key is published before it signs")
+	suite.NotEqual(first, second)
+}
+
+func (suite *KeyDirTestSuite) TestLoadKeyRing_EmptyDirectory() {
+	// Act
+	ring, err := suite.dir.LoadKeyRing()
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(ring)
+	suite.Contains(err.Error(), "no signing key")
+}
+
+func (suite *KeyDirTestSuite) TestLoadKeyRing_RejectsPathInManifest() {
+	// Arrange
+	manifest := `{"signing_kid":"k","keys":[{"kid":"k","alg":"HS256","file":"../secret"}]}`
+	suite.Require().NoError(os.WriteFile(filepath.Join(suite.dir.Path, jwtkeys.ManifestFile), []byte(manifest), 0o600))
+
+	// Act
+	_, err := suite.dir.LoadKeyRing()
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "plain file name")
+}
+
+// ===== ROTATION TESTS =====
+
+func (suite *KeyDirTestSuite) TestPromote_OldTokensStayValid() {
+	// Arrange
+	oldKid := suite.generate(jwtkeys.AlgEdDSA)
+	oldToken := suite.sign()
+	newKid := suite.generate(jwtkeys.AlgRS256)
+
+	// Act
+	suite.Require().NoError(suite.dir.Promote(newKid, suite.now))
+	ring := suite.ring()
+	newToken := suite.sign()
+
+	// Assert
+	suite.Equal(newKid, ring.SigningKey().ID)
+	suite.NoError(suite.verify(ring, oldToken), "tokens of the demoted key must still verify")
+	suite.NoError(suite.verify(ring, newToken))
+	m, err := suite.dir.ReadManifest()
+	suite.Require().NoError(err)
+	entry, _ := m.Entry(oldKid)
+	suite.Require().NotNil(entry.DemotedAt)
+	suite.Equal(suite.now, *entry.DemotedAt)
+}
+
+func (suite *KeyDirTestSuite) TestPromote_MixedHMACKeys() {
+	// Arrange - rotating shared secrets must not log users out either
+	oldKid := suite.generate(jwtkeys.AlgHS256)
+	oldToken := suite.sign()
+	newKid := suite.generate(jwtkeys.AlgHS256)
+
+	// Act
+	suite.Require().NoError(suite.dir.Promote(newKid, suite.now))
+	ring := suite.ring()
+
+	// Assert
+	suite.NotEqual(oldKid, newKid)
+	suite.NoError(suite.verify(ring, oldToken))
+	suite.NoError(suite.verify(ring, suite.sign()))
+	suite.Empty(ring.JWKS().Keys, "HMAC keys are never published")
+}
+
+func (suite *KeyDirTestSuite) TestPromote_UnknownKey() {
+	// Arrange
+	suite.generate(jwtkeys.AlgEdDSA)
+
+	// Act
+	err := suite.dir.Promote("missing", suite.now)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "unknown key")
+}
+
+func (suite *KeyDirTestSuite) TestRetire_SigningKey() {
+	// Arrange
+	kid := suite.generate(jwtkeys.AlgEdDSA)
+
+	// Act
+	err := suite.dir.Retire(kid, suite.now, suite.maxTokenLifetime)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "promote another key first")
+}
+
+func (suite *KeyDirTestSuite) TestRetire_BeforeTokensExpire() {
+	// Arrange
+	oldKid := suite.generate(jwtkeys.AlgEdDSA)
+	newKid := suite.generate(jwtkeys.AlgEdDSA)
+	suite.Require().NoError(suite.dir.Promote(newKid, suite.now))
+
+	// Act
+	err := suite.dir.Retire(oldKid, suite.now.Add(suite.maxTokenLifetime-time.Minute), suite.maxTokenLifetime)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "may be valid until")
+	suite.Len(suite.ring().JWKS().Keys, 2)
+}
+
+func (suite *KeyDirTestSuite) TestRetire_AfterTokensExpire() {
+	// Arrange
+	oldKid := suite.generate(jwtkeys.AlgEdDSA)
+	oldToken := suite.sign()
+	newKid := suite.generate(jwtkeys.AlgEdDSA)
+	suite.Require().NoError(suite.dir.Promote(newKid, suite.now))
+
+	// Act
+	err := suite.dir.Retire(oldKid, suite.now.Add(suite.maxTokenLifetime), suite.maxTokenLifetime)
+
+	// Assert
+	suite.Require().NoError(err)
+	ring := suite.ring()
+	suite.Require().Len(ring.JWKS().Keys, 1)
+	suite.Equal(newKid, ring.JWKS().Keys[0].Kid)
+	suite.Error(suite.verify(ring, oldToken))
+	_, statErr := os.Stat(filepath.Join(suite.dir.Path, oldKid+".pem"))
+	suite.ErrorIs(statErr, os.ErrNotExist)
+}
+
+func (suite *KeyDirTestSuite) TestRetireExpired_KeepsPendingKeys() {
+	// Arrange - a demoted key past its lifetime, the signing key and a key waiting for promotion
+	oldKid := suite.generate(jwtkeys.AlgEdDSA)
+	signingKid := suite.generate(jwtkeys.AlgEdDSA)
+	suite.Require().NoError(suite.dir.Promote(signingKid, suite.now))
+	pendingKid := suite.generate(jwtkeys.AlgRS256)
+
+	// Act
+	retired, err := suite.dir.RetireExpired(suite.now.Add(2*suite.maxTokenLifetime), suite.maxTokenLifetime)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal([]string{oldKid}, retired)
+	m, err := suite.dir.ReadManifest()
+	suite.Require().NoError(err)
+	_, ok := m.Entry(signingKid)
+	suite.True(ok)
+	_, ok = m.Entry(pendingKid)
+	suite.True(ok)
+}
+
+// ===== LOAD TESTS =====
+
+func (suite *KeyDirTestSuite) TestLoad_DirectoryWithLegacySecret() {
+	// Arrange - tokens signed with JWT_SECRET before switching to the key directory
+	suite.generate(jwtkeys.AlgEdDSA)
+	legacy, err := jwtkeys.NewKeyRing(jwtkeys.NewHMACKey("", []byte("legacy-secret")))
+	suite.Require().NoError(err)
+	legacyToken, err := legacy.Sign(suite.claims)
+	suite.Require().NoError(err)
+	cfg := &config.Config{
+		JWT:       config.JWTConfig{Algorithm: jwtkeys.AlgHS256, KeysDir: suite.dir.Path},
+		JWTSecret: "legacy-secret",
+	}
+
+	// Act
+	ring, err := jwtkeys.Load(cfg)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(jwtkeys.AlgEdDSA, ring.SigningKey().Algorithm)
+	suite.NoError(suite.verify(ring, legacyToken))
+	suite.NoError(suite.verify(ring, suite.sign()))
+}
+
+func TestKeyDirTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyDirTestSuite))
+}
@@ -0,0 +1,148 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key is a JWT signing or verification key identified by its kid
+type Key struct {
+	ID        string
+	Algorithm string
+	signKey   any // []byte, *rsa.PrivateKey or ed25519.PrivateKey; nil for verification-only keys
+	verifyKey any // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+// NewHMACKey creates a shared-secret HS256 key.
+// HMAC keys can never be published, so only auth-service can verify such tokens.
+func NewHMACKey(id string, secret []byte) *Key {
+	return &Key{ID: id, Algorithm: AlgHS256, signKey: secret, verifyKey: secret}
+}
+
+// NewRSAKey creates an RS256 signing key, an empty id is replaced by the key thumbprint
+func NewRSAKey(id string, privateKey *rsa.PrivateKey) *Key {
+	return withThumbprintID(&Key{ID: id, Algorithm: AlgRS256, signKey: privateKey, verifyKey: &privateKey.PublicKey})
+}
+
+// NewEd25519Key creates an EdDSA signing key, an empty id is replaced by the key thumbprint
+func NewEd25519Key(id string, privateKey ed25519.PrivateKey) *Key {
+	return withThumbprintID(&Key{ID: id, Algorithm: AlgEdDSA, signKey: privateKey, verifyKey: privateKey.Public()})
+}
+
+// ParsePrivateKeyPEM parses a PKCS#8 RSA or Ed25519 key, or a PKCS#1 RSA key.
+// An empty id is replaced by the key thumbprint.
+func ParsePrivateKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return NewRSAKey(id, rsaKey), nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	switch privateKey := parsed.(type) {
+	case *rsa.PrivateKey:
+		return NewRSAKey(id, privateKey), nil
+	case ed25519.PrivateKey:
+		return NewEd25519Key(id, privateKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+}
+
+// Method returns the JWT signing method of the key
+func (k *Key) Method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// CanSign reports whether the key holds private key material
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// JWK returns the public part of the key in JWK format, it fails for HMAC keys
+func (k *Key) JWK() (JWK, error) {
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: k.ID,
+			Alg: k.Algorithm,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: k.ID,
+			Alg: k.Algorithm,
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("%s keys cannot be published", k.Algorithm)
+	}
+}
+
+// Thumbprint returns the RFC 7638 SHA-256 thumbprint of the public key
+func (k *Key) Thumbprint() (string, error) {
+	jwk, err := k.JWK()
+	if err != nil {
+		return "", err
+	}
+
+	// Required members only, in lexicographic order
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func withThumbprintID(k *Key) *Key {
+	if k.ID == "" {
+		k.ID, _ = k.Thumbprint()
+	}
+	return k
+}
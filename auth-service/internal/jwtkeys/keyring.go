@@ -0,0 +1,95 @@
+package jwtkeys
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyRing holds the key new tokens are signed with and every key tokens are accepted from
+type KeyRing struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeyRing creates a key ring signing with signing and also verifying with verification.
+// Key IDs must be unique; tokens without a kid header are verified with the key whose ID is empty.
+func NewKeyRing(signing *Key, verification ...*Key) (*KeyRing, error) {
+	if signing == nil || !signing.CanSign() {
+		return nil, errors.New("signing key must hold a private key")
+	}
+
+	ring := &KeyRing{signing: signing, keys: make(map[string]*Key)}
+	for _, key := range append([]*Key{signing}, verification...) {
+		if err := ring.add(key); err != nil {
+			return nil, err
+		}
+	}
+	return ring, nil
+}
+
+// add registers a verification key
+func (r *KeyRing) add(key *Key) error {
+	if _, exists := r.keys[key.ID]; exists {
+		return fmt.Errorf("duplicate key id %q", key.ID)
+	}
+	r.keys[key.ID] = key
+	return nil
+}
+
+// SigningKey returns the key new tokens are signed with
+func (r *KeyRing) SigningKey() *Key {
+	return r.signing
+}
+
+// Sign signs claims with the signing key and sets the kid header
+func (r *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(r.signing.Method(), claims)
+	if r.signing.ID != "" {
+		token.Header["kid"] = r.signing.ID
+	}
+	return token.SignedString(r.signing.signKey)
+}
+
+// Keyfunc selects the verification key by the kid header for jwt.Parse.
+// The token algorithm must match the algorithm of the selected key.
+func (r *KeyRing) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verifyKey, nil
+}
+
+// Algorithms returns the algorithms of all keys in the ring
+func (r *KeyRing) Algorithms() []string {
+	var algorithms []string
+	for _, key := range r.keys {
+		if !slices.Contains(algorithms, key.Algorithm) {
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+	slices.Sort(algorithms)
+	return algorithms
+}
+
+// JWKS returns the public keys of the ring, HMAC keys are left out
+func (r *KeyRing) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range r.keys {
+		if jwk, err := key.JWK(); err == nil {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	slices.SortFunc(jwks.Keys, func(a, b JWK) int {
+		return strings.Compare(a.Kid, b.Kid)
+	})
+	return jwks
+}
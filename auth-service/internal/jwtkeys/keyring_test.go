@@ -0,0 +1,252 @@
+package jwtkeys_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyRingTestSuite struct {
+	suite.Suite
+	rsaKey     *rsa.PrivateKey
+	ed25519Key ed25519.PrivateKey
+	claims     jwt.MapClaims
+}
+
+func (suite *KeyRingTestSuite) SetupSuite() {
+	var err error
+	suite.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	_, suite.ed25519Key, err = ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+}
+
+func (suite *KeyRingTestSuite) SetupTest() {
+	suite.claims = jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// parse verifies token against ring the way AuthService does
+func (suite *KeyRingTestSuite) parse(ring *jwtkeys.KeyRing, token string) (*jwt.Token, error) {
+	return jwt.Parse(token, ring.Keyfunc, jwt.WithValidMethods(ring.Algorithms()))
+}
+
+// writePEM stores der as a PEM file of the given type and returns its path
+func (suite *KeyRingTestSuite) writePEM(blockType string, der []byte) string {
+	path := filepath.Join(suite.T().TempDir(), "jwt.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	suite.Require().NoError(os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// ===== KEY RING TESTS =====
+
+func (suite *KeyRingTestSuite) TestNewKeyRing_RequiresPrivateKey() {
+	// Act
+	ring, err := jwtkeys.NewKeyRing(nil)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(ring)
+}
+
+func (suite *KeyRingTestSuite) TestNewKeyRing_DuplicateKeyID() {
+	// Act
+	ring, err := jwtkeys.NewKeyRing(
+		jwtkeys.NewRSAKey("same", suite.rsaKey),
+		jwtkeys.NewEd25519Key("same", suite.ed25519Key),
+	)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(ring)
+	suite.Contains(err.Error(), "duplicate key id")
+}
+
+func (suite *KeyRingTestSuite) TestSign_SetsKeyID() {
+	// Arrange
+	ring, err := jwtkeys.NewKeyRing(jwtkeys.NewRSAKey("rsa-1", suite.rsaKey))
+	suite.Require().NoError(err)
+
+	// Act
+	token, err := ring.Sign(suite.claims)
+	suite.Require().NoError(err)
+	parsed, err := suite.parse(ring, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("rsa-1", parsed.Header["kid"])
+	suite.Equal(jwtkeys.AlgRS256, parsed.Method.Alg())
+}
+
+func (suite *KeyRingTestSuite) TestSign_HMACWithoutKeyID() {
+	// Arrange
+	ring, err := jwtkeys.NewKeyRing(jwtkeys.NewHMACKey("", []byte("secret")))
+	suite.Require().NoError(err)
+
+	// Act
+	token, err := ring.Sign(suite.claims)
+	suite.Require().NoError(err)
+	parsed, err := suite.parse(ring, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotContains(parsed.Header, "kid")
+}
+
+func (suite *KeyRingTestSuite) TestKeyfunc_RejectsAlgorithmMismatch() {
+	// Arrange - an HS256 token signed with the public key bytes must not verify against the EdDSA key
+	signing := jwtkeys.NewEd25519Key("ed-1", suite.ed25519Key)
+	ring, err := jwtkeys.NewKeyRing(signing, jwtkeys.NewHMACKey("", []byte("secret")))
+	suite.Require().NoError(err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, suite.claims)
+	forged.Header["kid"] = "ed-1"
+	token, err := forged.SignedString([]byte(suite.ed25519Key.Public().(ed25519.PublicKey)))
+	suite.Require().NoError(err)
+
+	// Act
+	_, err = suite.parse(ring, token)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "unexpected signing method")
+}
+
+func (suite *KeyRingTestSuite) TestJWKS_PublishesOnlyPublicKeys() {
+	// Arrange
+	ring, err := jwtkeys.NewKeyRing(
+		jwtkeys.NewEd25519Key("b-ed", suite.ed25519Key),
+		jwtkeys.NewRSAKey("a-rsa", suite.rsaKey),
+		jwtkeys.NewHMACKey("", []byte("secret")),
+	)
+	suite.Require().NoError(err)
+
+	// Act
+	jwks := ring.JWKS()
+
+	// Assert
+	suite.Require().Len(jwks.Keys, 2)
+	suite.Equal("a-rsa", jwks.Keys[0].Kid)
+	suite.Equal("RSA", jwks.Keys[0].Kty)
+	suite.Equal("AQAB", jwks.Keys[0].E)
+	suite.Equal("b-ed", jwks.Keys[1].Kid)
+	suite.Equal("OKP", jwks.Keys[1].Kty)
+	suite.Equal("Ed25519", jwks.Keys[1].Crv)
+}
+
+func (suite *KeyRingTestSuite) TestNewKey_DefaultsToThumbprint() {
+	// Act
+	key := jwtkeys.NewEd25519Key("", suite.ed25519Key)
+	thumbprint, err := key.Thumbprint()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(thumbprint, key.ID)
+	suite.Len(key.ID, 43)
+}
+
+// ===== LOAD TESTS =====
+
+func (suite *KeyRingTestSuite) TestLoad_HS256() {
+	// Arrange
+	cfg := &config.Config{JWT: config.JWTConfig{Algorithm: jwtkeys.AlgHS256}, JWTSecret: "secret"}
+
+	// Act
+	ring, err := jwtkeys.Load(cfg)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(jwtkeys.AlgHS256, ring.SigningKey().Algorithm)
+	suite.Empty(ring.JWKS().Keys)
+}
+
+func (suite *KeyRingTestSuite) TestLoad_PKCS8Ed25519WithLegacySecret() {
+	// Arrange
+	der, err := x509.MarshalPKCS8PrivateKey(suite.ed25519Key)
+	suite.Require().NoError(err)
+	cfg := &config.Config{
+		JWT: config.JWTConfig{
+			Algorithm:      jwtkeys.AlgEdDSA,
+			PrivateKeyFile: suite.writePEM("PRIVATE KEY", der),
+			KeyID:          "ed-1",
+		},
+		JWTSecret: "legacy-secret",
+	}
+	legacy, err := jwtkeys.NewKeyRing(jwtkeys.NewHMACKey("", []byte("legacy-secret")))
+	suite.Require().NoError(err)
+	legacyToken, err := legacy.Sign(suite.claims)
+	suite.Require().NoError(err)
+
+	// Act
+	ring, err := jwtkeys.Load(cfg)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ed-1", ring.SigningKey().ID)
+	suite.Equal([]string{jwtkeys.AlgEdDSA, jwtkeys.AlgHS256}, ring.Algorithms())
+	_, err = suite.parse(ring, legacyToken)
+	suite.NoError(err)
+}
+
+func (suite *KeyRingTestSuite) TestLoad_PKCS1RSA() {
+	// Arrange
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Algorithm:      jwtkeys.AlgRS256,
+		PrivateKeyFile: suite.writePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(suite.rsaKey)),
+	}}
+
+	// Act
+	ring, err := jwtkeys.Load(cfg)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(jwtkeys.AlgRS256, ring.SigningKey().Algorithm)
+	suite.Equal([]string{jwtkeys.AlgRS256}, ring.Algorithms())
+}
+
+func (suite *KeyRingTestSuite) TestLoad_AlgorithmMismatch() {
+	// Arrange
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Algorithm:      jwtkeys.AlgEdDSA,
+		PrivateKeyFile: suite.writePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(suite.rsaKey)),
+	}}
+
+	// Act
+	ring, err := jwtkeys.Load(cfg)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(ring)
+	suite.Contains(err.Error(), "JWT_SIGNING_ALG")
+}
+
+func (suite *KeyRingTestSuite) TestLoad_MissingFile() {
+	// Arrange
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Algorithm:      jwtkeys.AlgRS256,
+		PrivateKeyFile: filepath.Join(suite.T().TempDir(), "missing.pem"),
+	}}
+
+	// Act
+	ring, err := jwtkeys.Load(cfg)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(ring)
+}
+
+func TestKeyRingTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyRingTestSuite))
+}
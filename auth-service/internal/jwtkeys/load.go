@@ -0,0 +1,49 @@
+package jwtkeys
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+)
+
+// Load builds the key ring described by the configuration, from the key
+// directory when one is configured. When not signing with it, a configured JWT
+// secret is kept for verification so that tokens issued before the switch stay
+// valid until they expire.
+func Load(cfg *config.Config) (*KeyRing, error) {
+	if cfg.JWT.KeysDir != "" {
+		ring, err := Dir{Path: cfg.JWT.KeysDir}.LoadKeyRing()
+		if err != nil {
+			return nil, err
+		}
+		if cfg.JWTSecret != "" {
+			if err := ring.add(NewHMACKey("", []byte(cfg.JWTSecret))); err != nil {
+				return nil, err
+			}
+		}
+		return ring, nil
+	}
+
+	if cfg.JWT.Algorithm == "" || cfg.JWT.Algorithm == AlgHS256 {
+		return NewKeyRing(NewHMACKey("", []byte(cfg.JWTSecret)))
+	}
+
+	data, err := os.ReadFile(cfg.JWT.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+	signing, err := ParsePrivateKeyPEM(cfg.JWT.KeyID, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT private key %s: %w", cfg.JWT.PrivateKeyFile, err)
+	}
+	if signing.Algorithm != cfg.JWT.Algorithm {
+		return nil, fmt.Errorf("JWT private key is a %s key, but JWT_SIGNING_ALG is %s", signing.Algorithm, cfg.JWT.Algorithm)
+	}
+
+	var verification []*Key
+	if cfg.JWTSecret != "" {
+		verification = append(verification, NewHMACKey("", []byte(cfg.JWTSecret)))
+	}
+	return NewKeyRing(signing, verification...)
+}
@@ -0,0 +1,105 @@
+package messaging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/google/uuid"
+	"github.com/wagslane/go-rabbitmq"
+)
+
+// UserDeletionAcknowledgedEvent is published by a service once it deleted the data of a deleted user
+type UserDeletionAcknowledgedEvent struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Service string    `json:"service"`
+}
+
+// AcknowledgeFunc records that service deleted the data of the user
+type AcknowledgeFunc func(userID uuid.UUID, service string) error
+
+// DeletionAckConsumer receives the acknowledgements of account deletions from other services
+type DeletionAckConsumer struct {
+	conn        *rabbitmq.Conn
+	consumer    *rabbitmq.Consumer
+	acknowledge AcknowledgeFunc
+}
+
+// NewDeletionAckConsumer subscribes to user.deletion_acknowledged events.
+// The queue is durable and shared by all instances, so no acknowledgement is lost during restarts.
+func NewDeletionAckConsumer(cfg config.RabbitMQConfig, acknowledge AcknowledgeFunc) (*DeletionAckConsumer, error) {
+	conn, err := rabbitmq.NewConn(
+		cfg.URL,
+		rabbitmq.WithConnectionOptionsLogging,
+		rabbitmq.WithConnectionOptionsReconnectInterval(5), // 5 seconds between reconnection attempts
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+	}
+
+	consumer, err := rabbitmq.NewConsumer(
+		conn,
+		cfg.DeletionQueue,
+		rabbitmq.WithConsumerOptionsRoutingKey("user.deletion_acknowledged"),
+		rabbitmq.WithConsumerOptionsExchangeName(cfg.Exchange),
+		rabbitmq.WithConsumerOptionsExchangeDeclare,
+		rabbitmq.WithConsumerOptionsExchangeKind("topic"),
+		rabbitmq.WithConsumerOptionsExchangeDurable,
+		rabbitmq.WithConsumerOptionsQueueDurable,
+		rabbitmq.WithConsumerOptionsLogging,
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create consumer: %v", err)
+	}
+
+	return &DeletionAckConsumer{
+		conn:        conn,
+		consumer:    consumer,
+		acknowledge: acknowledge,
+	}, nil
+}
+
+// StartConsuming handles acknowledgements until the consumer is closed.
+// Malformed events are discarded, acknowledgements that fail to be stored are requeued.
+func (c *DeletionAckConsumer) StartConsuming() error {
+	err := c.consumer.Run(func(d rabbitmq.Delivery) rabbitmq.Action {
+		event, err := decodeDeletionAcknowledged(d.Body)
+		if err != nil {
+			log.Printf("Discarding account deletion acknowledgement: %v", err)
+			return rabbitmq.NackDiscard
+		}
+		if err := c.acknowledge(event.UserID, event.Service); err != nil {
+			log.Printf("Error handling account deletion acknowledgement: %v", err)
+			return rabbitmq.NackRequeue
+		}
+		return rabbitmq.Ack
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start consumer: %v", err)
+	}
+	return nil
+}
+
+func (c *DeletionAckConsumer) Close() {
+	if c.consumer != nil {
+		c.consumer.Close()
+	}
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// decodeDeletionAcknowledged decodes and validates a user.deletion_acknowledged event
+func decodeDeletionAcknowledged(data []byte) (*UserDeletionAcknowledgedEvent, error) {
+	var event UserDeletionAcknowledgedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user deletion acknowledged event: %v", err)
+	}
+	if event.UserID == uuid.Nil || event.Service == "" {
+		return nil, errors.New("user deletion acknowledged event needs user_id and service")
+	}
+	return &event, nil
+}
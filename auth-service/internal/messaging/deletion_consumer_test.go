@@ -0,0 +1,41 @@
+package messaging
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeDeletionAcknowledged(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "valid", body: `{"user_id":"` + userID.String() + `","service":"core-service"}`},
+		{name: "missing service", body: `{"user_id":"` + userID.String() + `"}`, wantErr: true},
+		{name: "missing user", body: `{"service":"core-service"}`, wantErr: true},
+		{name: "invalid user id", body: `{"user_id":"alice","service":"core-service"}`, wantErr: true},
+		{name: "malformed json", body: `{"user_id":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			event, err := decodeDeletionAcknowledged([]byte(tt.body))
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, event.UserID)
+			assert.Equal(t, "core-service", event.Service)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// CheckBroker reports an error while the RabbitMQ broker at rawURL does not accept connections.
+// It only opens a TCP connection, so it does not count as an AMQP client on the broker.
+func CheckBroker(ctx context.Context, rawURL string) error {
+	address, err := brokerAddress(rawURL)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("RabbitMQ is unreachable: %w", err)
+	}
+	return conn.Close()
+}
+
+// brokerAddress returns host:port of an amqp:// or amqps:// URL, with the default port of the scheme
+func brokerAddress(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid RabbitMQ URL: %w", err)
+	}
+
+	var defaultPort string
+	switch u.Scheme {
+	case "amqp":
+		defaultPort = "5672"
+	case "amqps":
+		defaultPort = "5671"
+	default:
+		return "", fmt.Errorf("invalid RabbitMQ URL: unsupported scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid RabbitMQ URL: missing host")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
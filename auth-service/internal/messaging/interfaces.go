@@ -2,8 +2,10 @@ package messaging
 
 import (
 	"context"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
 	"github.com/wagslane/go-rabbitmq"
 )
 
@@ -11,6 +13,14 @@ import (
 type IMessageBroker interface {
 	PublishUserCreated(user *models.User) error
 	PublishUserDeleted(user *models.User) error
+	PublishTokensRevoked(userID uuid.UUID, tokenHash, jti string) error
+	PublishSessionRevoked(userID, sessionID uuid.UUID) error
+	PublishUserTokensRevoked(userID uuid.UUID, revokedAt time.Time) error
+	PublishPasswordResetRequested(user *models.User, token string, expiresAt time.Time) error
+	PublishMagicLinkRequested(user *models.User, token string, expiresAt time.Time) error
+	PublishEmailVerificationRequested(user *models.User, token string, expiresAt time.Time) error
+	PublishUserEmailChanged(userID uuid.UUID, oldEmail, newEmail string) error
+	PublishUserLocked(user *models.User, lockedUntil time.Time) error
 	Close()
 }
 
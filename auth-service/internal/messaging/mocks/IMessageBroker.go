@@ -3,8 +3,12 @@
 package mocks
 
 import (
+	time "time"
+
 	models "github.com/Koshsky/subs-service/auth-service/internal/models"
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // IMessageBroker is an autogenerated mock type for the IMessageBroker type
@@ -17,6 +21,96 @@ func (_m *IMessageBroker) Close() {
 	_m.Called()
 }
 
+// PublishEmailVerificationRequested provides a mock function with given fields: user, token, expiresAt
+func (_m *IMessageBroker) PublishEmailVerificationRequested(user *models.User, token string, expiresAt time.Time) error {
+	ret := _m.Called(user, token, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishEmailVerificationRequested")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.User, string, time.Time) error); ok {
+		r0 = rf(user, token, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishMagicLinkRequested provides a mock function with given fields: user, token, expiresAt
+func (_m *IMessageBroker) PublishMagicLinkRequested(user *models.User, token string, expiresAt time.Time) error {
+	ret := _m.Called(user, token, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishMagicLinkRequested")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.User, string, time.Time) error); ok {
+		r0 = rf(user, token, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishPasswordResetRequested provides a mock function with given fields: user, token, expiresAt
+func (_m *IMessageBroker) PublishPasswordResetRequested(user *models.User, token string, expiresAt time.Time) error {
+	ret := _m.Called(user, token, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishPasswordResetRequested")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.User, string, time.Time) error); ok {
+		r0 = rf(user, token, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishSessionRevoked provides a mock function with given fields: userID, sessionID
+func (_m *IMessageBroker) PublishSessionRevoked(userID uuid.UUID, sessionID uuid.UUID) error {
+	ret := _m.Called(userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishSessionRevoked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishTokensRevoked provides a mock function with given fields: userID, tokenHash, jti
+func (_m *IMessageBroker) PublishTokensRevoked(userID uuid.UUID, tokenHash string, jti string) error {
+	ret := _m.Called(userID, tokenHash, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishTokensRevoked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string, string) error); ok {
+		r0 = rf(userID, tokenHash, jti)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PublishUserCreated provides a mock function with given fields: user
 func (_m *IMessageBroker) PublishUserCreated(user *models.User) error {
 	ret := _m.Called(user)
@@ -53,6 +147,60 @@ func (_m *IMessageBroker) PublishUserDeleted(user *models.User) error {
 	return r0
 }
 
+// PublishUserEmailChanged provides a mock function with given fields: userID, oldEmail, newEmail
+func (_m *IMessageBroker) PublishUserEmailChanged(userID uuid.UUID, oldEmail string, newEmail string) error {
+	ret := _m.Called(userID, oldEmail, newEmail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishUserEmailChanged")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string, string) error); ok {
+		r0 = rf(userID, oldEmail, newEmail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishUserLocked provides a mock function with given fields: user, lockedUntil
+func (_m *IMessageBroker) PublishUserLocked(user *models.User, lockedUntil time.Time) error {
+	ret := _m.Called(user, lockedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishUserLocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.User, time.Time) error); ok {
+		r0 = rf(user, lockedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishUserTokensRevoked provides a mock function with given fields: userID, revokedAt
+func (_m *IMessageBroker) PublishUserTokensRevoked(userID uuid.UUID, revokedAt time.Time) error {
+	ret := _m.Called(userID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishUserTokensRevoked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, time.Time) error); ok {
+		r0 = rf(userID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewIMessageBroker creates a new instance of IMessageBroker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIMessageBroker(t interface {
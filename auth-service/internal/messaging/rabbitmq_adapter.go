@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
@@ -23,10 +24,70 @@ type UserCreatedEvent struct {
 	Email  string    `json:"email"`
 }
 
+// UserDeletedEvent asks every service to delete the data of a deleted account
+// and to acknowledge it with a user.deletion_acknowledged event
 type UserDeletedEvent struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
+// TokenRevokedEvent revokes a token (SHA-256 hex of the token, and its jti for JWTs),
+// the tokens of a login session or, when both are empty, all tokens of the user
+type TokenRevokedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID string    `json:"session_id,omitempty"`
+	TokenHash string    `json:"token_hash,omitempty"`
+	JTI       string    `json:"jti,omitempty"`
+}
+
+// UserTokensRevokedEvent announces that every session token of the user issued before RevokedAt was revoked
+type UserTokensRevokedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// PasswordResetRequestedEvent asks notification-service to email a password reset link.
+// It carries the plaintext token, which is never stored by auth-service.
+type PasswordResetRequestedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MagicLinkRequestedEvent asks notification-service to email a sign-in link.
+// It carries the plaintext token, which is never stored by auth-service.
+type MagicLinkRequestedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EmailVerificationRequestedEvent asks notification-service to email an address confirmation link.
+// It carries the plaintext token, which is never stored by auth-service.
+type EmailVerificationRequestedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserEmailChangedEvent announces that the user confirmed a new email address,
+// so that notification-service can update its contact data and notify the old address
+type UserEmailChangedEvent struct {
+	UserID   uuid.UUID `json:"user_id"`
+	OldEmail string    `json:"old_email"`
+	NewEmail string    `json:"new_email"`
+}
+
+// UserLockedEvent announces that logins to the account are refused until LockedUntil
+// after too many failed attempts, so that notification-service can alert the owner
+type UserLockedEvent struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Email       string    `json:"email"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
 // NewRabbitMQAdapter creates a new RabbitMQ adapter
 func NewRabbitMQAdapter(rabbitmqConfig config.RabbitMQConfig) (IMessageBroker, error) {
 	// Create connection with automatic reconnection
@@ -62,63 +123,125 @@ func NewRabbitMQAdapter(rabbitmqConfig config.RabbitMQConfig) (IMessageBroker, e
 
 // PublishUserCreated publishes user created event to RabbitMQ
 func (r *RabbitMQAdapter) PublishUserCreated(user *models.User) error {
-	if r.publisher == nil {
-		return errors.New("publisher is not initialized")
-	}
-
 	if user == nil {
 		return errors.New("user cannot be nil")
 	}
 
-	event := UserCreatedEvent{
+	return r.publish("user.created", "user created", UserCreatedEvent{
 		UserID: user.ID,
 		Email:  user.Email,
-	}
+	})
+}
 
-	body, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("failed to marshal user created event: %v", err)
+// PublishUserDeleted starts the deletion of the user's data in other services
+func (r *RabbitMQAdapter) PublishUserDeleted(user *models.User) error {
+	if user == nil {
+		return errors.New("user cannot be nil")
 	}
 
-	err = r.publisher.Publish(
-		body,
-		[]string{"user.created"},
-		rabbitmq.WithPublishOptionsContentType("application/json"),
-		rabbitmq.WithPublishOptionsExchange(r.config.Exchange),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to publish user created event: %v", err)
-	}
+	return r.publish("user.deleted", "user deleted", UserDeletedEvent{
+		UserID: user.ID,
+	})
+}
 
-	return nil
+// PublishTokensRevoked tells token validation caches and local verifiers to drop a revoked token.
+// jti is only set for JWTs. An empty tokenHash revokes every cached token of the user.
+func (r *RabbitMQAdapter) PublishTokensRevoked(userID uuid.UUID, tokenHash, jti string) error {
+	return r.publish("token.revoked", "token revoked", TokenRevokedEvent{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		JTI:       jti,
+	})
 }
 
-func (r *RabbitMQAdapter) PublishUserDeleted(user *models.User) error {
-	if r.publisher == nil {
-		return errors.New("publisher is not initialized")
-	}
+// PublishSessionRevoked tells token validation caches to drop the tokens of a revoked session
+func (r *RabbitMQAdapter) PublishSessionRevoked(userID, sessionID uuid.UUID) error {
+	return r.publish("token.revoked", "session revoked", TokenRevokedEvent{
+		UserID:    userID,
+		SessionID: sessionID.String(),
+	})
+}
+
+// PublishUserTokensRevoked announces that the user signed out everywhere at revokedAt,
+// so that downstream caches drop all of the user's tokens and local verifiers refuse older ones
+func (r *RabbitMQAdapter) PublishUserTokensRevoked(userID uuid.UUID, revokedAt time.Time) error {
+	return r.publish("user.tokens_revoked", "user tokens revoked", UserTokensRevokedEvent{
+		UserID:    userID,
+		RevokedAt: revokedAt,
+	})
+}
 
+// PublishPasswordResetRequested asks notification-service to send the reset token to the user
+func (r *RabbitMQAdapter) PublishPasswordResetRequested(user *models.User, token string, expiresAt time.Time) error {
+	return r.publish("user.password_reset_requested", "password reset requested", PasswordResetRequestedEvent{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// PublishMagicLinkRequested asks notification-service to send the sign-in token to the user
+func (r *RabbitMQAdapter) PublishMagicLinkRequested(user *models.User, token string, expiresAt time.Time) error {
+	return r.publish("user.magic_link_requested", "magic link requested", MagicLinkRequestedEvent{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// PublishEmailVerificationRequested asks notification-service to send the verification token to the user
+func (r *RabbitMQAdapter) PublishEmailVerificationRequested(user *models.User, token string, expiresAt time.Time) error {
+	return r.publish("user.email_verification_requested", "email verification requested", EmailVerificationRequestedEvent{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// PublishUserEmailChanged announces that the user's email changed from oldEmail to newEmail
+func (r *RabbitMQAdapter) PublishUserEmailChanged(userID uuid.UUID, oldEmail, newEmail string) error {
+	return r.publish("user.email_changed", "user email changed", UserEmailChangedEvent{
+		UserID:   userID,
+		OldEmail: oldEmail,
+		NewEmail: newEmail,
+	})
+}
+
+// PublishUserLocked announces that the account is locked after too many failed logins
+func (r *RabbitMQAdapter) PublishUserLocked(user *models.User, lockedUntil time.Time) error {
 	if user == nil {
 		return errors.New("user cannot be nil")
 	}
 
-	event := UserDeletedEvent{
-		UserID: user.ID,
+	return r.publish("user.locked", "user locked", UserLockedEvent{
+		UserID:      user.ID,
+		Email:       user.Email,
+		LockedUntil: lockedUntil,
+	})
+}
+
+// publish marshals event to JSON and publishes it with the routing key
+func (r *RabbitMQAdapter) publish(routingKey, name string, event any) error {
+	if r.publisher == nil {
+		return errors.New("publisher is not initialized")
 	}
 
 	body, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal user deleted event: %v", err)
+		return fmt.Errorf("failed to marshal %s event: %v", name, err)
 	}
 
 	err = r.publisher.Publish(
 		body,
-		[]string{"user.deleted"},
+		[]string{routingKey},
 		rabbitmq.WithPublishOptionsContentType("application/json"),
 		rabbitmq.WithPublishOptionsExchange(r.config.Exchange),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to publish user deleted event: %v", err)
+		return fmt.Errorf("failed to publish %s event: %v", name, err)
 	}
 
 	return nil
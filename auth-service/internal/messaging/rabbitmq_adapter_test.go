@@ -3,6 +3,7 @@ package messaging
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
@@ -198,6 +199,237 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserDeleted_NilUser() {
 	suite.Contains(err.Error(), "user cannot be nil")
 }
 
+// ===== PUBLISH TOKENS REVOKED TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishTokensRevoked_SingleToken() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","token_hash":"abc","jti":"def"}`), []string{"token.revoked"}, nil)
+
+	// Act
+	err := suite.adapter.PublishTokensRevoked(suite.testUser.ID, "abc", "def")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishTokensRevoked_AllUserTokens() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`"}`), []string{"token.revoked"}, nil)
+
+	// Act
+	err := suite.adapter.PublishTokensRevoked(suite.testUser.ID, "", "")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishTokensRevoked_PublisherError() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`"}`), []string{"token.revoked"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishTokensRevoked(suite.testUser.ID, "", "")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish token revoked event")
+}
+
+// ===== PUBLISH SESSION REVOKED TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishSessionRevoked_Success() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","session_id":"`+sessionID.String()+`"}`), []string{"token.revoked"}, nil)
+
+	// Act
+	err := suite.adapter.PublishSessionRevoked(suite.testUser.ID, sessionID)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishSessionRevoked_PublisherError() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","session_id":"`+sessionID.String()+`"}`), []string{"token.revoked"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishSessionRevoked(suite.testUser.ID, sessionID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish session revoked event")
+}
+
+// ===== PUBLISH USER TOKENS REVOKED TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserTokensRevoked_Success() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","revoked_at":"2024-01-02T03:04:05Z"}`), []string{"user.tokens_revoked"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserTokensRevoked(suite.testUser.ID, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserTokensRevoked_PublisherError() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","revoked_at":"2024-01-02T03:04:05Z"}`), []string{"user.tokens_revoked"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishUserTokensRevoked(suite.testUser.ID, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish user tokens revoked event")
+}
+
+// ===== PUBLISH PASSWORD RESET REQUESTED TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishPasswordResetRequested_Success() {
+	// Arrange
+	expiresAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"` + suite.testUser.Email +
+		`","token":"prt_token","expires_at":"2025-01-01T12:00:00Z"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.password_reset_requested"}, nil)
+
+	// Act
+	err := suite.adapter.PublishPasswordResetRequested(suite.testUser, "prt_token", expiresAt)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishPasswordResetRequested_PublisherError() {
+	// Arrange
+	expiresAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"` + suite.testUser.Email +
+		`","token":"prt_token","expires_at":"2025-01-01T12:00:00Z"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.password_reset_requested"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishPasswordResetRequested(suite.testUser, "prt_token", expiresAt)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish password reset requested event")
+}
+
+// ===== PUBLISH MAGIC LINK REQUESTED TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishMagicLinkRequested_Success() {
+	// Arrange
+	expiresAt := time.Date(2025, 1, 1, 12, 15, 0, 0, time.UTC)
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"` + suite.testUser.Email +
+		`","token":"mlt_token","expires_at":"2025-01-01T12:15:00Z"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.magic_link_requested"}, nil)
+
+	// Act
+	err := suite.adapter.PublishMagicLinkRequested(suite.testUser, "mlt_token", expiresAt)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishMagicLinkRequested_PublisherError() {
+	// Arrange
+	expiresAt := time.Date(2025, 1, 1, 12, 15, 0, 0, time.UTC)
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"` + suite.testUser.Email +
+		`","token":"mlt_token","expires_at":"2025-01-01T12:15:00Z"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.magic_link_requested"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishMagicLinkRequested(suite.testUser, "mlt_token", expiresAt)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish magic link requested event")
+}
+
+// ===== PUBLISH EMAIL VERIFICATION REQUESTED TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishEmailVerificationRequested_Success() {
+	// Arrange
+	expiresAt := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"` + suite.testUser.Email +
+		`","token":"evt_token","expires_at":"2025-01-02T12:00:00Z"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.email_verification_requested"}, nil)
+
+	// Act
+	err := suite.adapter.PublishEmailVerificationRequested(suite.testUser, "evt_token", expiresAt)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishEmailVerificationRequested_PublisherError() {
+	// Arrange
+	expiresAt := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"` + suite.testUser.Email +
+		`","token":"evt_token","expires_at":"2025-01-02T12:00:00Z"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.email_verification_requested"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishEmailVerificationRequested(suite.testUser, "evt_token", expiresAt)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish email verification requested event")
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserEmailChanged_Success() {
+	// Arrange
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() +
+		`","old_email":"old@example.com","new_email":"new@example.com"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.email_changed"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserEmailChanged(suite.testUser.ID, "old@example.com", "new@example.com")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserEmailChanged_PublisherError() {
+	// Arrange
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() +
+		`","old_email":"old@example.com","new_email":"new@example.com"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.email_changed"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishUserEmailChanged(suite.testUser.ID, "old@example.com", "new@example.com")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish user email changed event")
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserLocked_Success() {
+	// Arrange
+	lockedUntil := time.Date(2025, 1, 1, 12, 15, 0, 0, time.UTC)
+	expectedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"` + suite.testUser.Email +
+		`","locked_until":"2025-01-01T12:15:00Z"}`)
+	suite.mockPublisherPublish(expectedBody, []string{"user.locked"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserLocked(suite.testUser, lockedUntil)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserLocked_NilUser() {
+	// Act
+	err := suite.adapter.PublishUserLocked(nil, time.Now())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "user cannot be nil")
+}
+
 // ===== CLOSE TESTS =====
 
 func (suite *RabbitMQAdapterTestSuite) TestClose_Success() {
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessTokenPrefix marks personal access tokens so they can be told apart from JWTs
+const AccessTokenPrefix = "pat_"
+
+// Personal access token scopes
+const (
+	ScopeSubscriptionsRead  = "subscriptions:read"
+	ScopeSubscriptionsWrite = "subscriptions:write"
+	ScopeAdmin              = "admin"
+)
+
+// AccessTokenScopes lists every scope a personal access token may be granted
+var AccessTokenScopes = []string{ScopeSubscriptionsRead, ScopeSubscriptionsWrite, ScopeAdmin}
+
+// AccessToken is a long-lived personal access token.
+// Only the SHA-256 hash of the token is stored; scopes are space separated.
+type AccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Scopes     string     `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsValidScope reports whether scope is a known access token scope
+func IsValidScope(scope string) bool {
+	return slices.Contains(AccessTokenScopes, scope)
+}
+
+// ScopeList returns the scopes granted to the token
+func (t *AccessToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+// IsActive reports whether the token is neither revoked nor expired at now
+func (t *AccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsValidScope tests recognition of access token scopes
+func TestIsValidScope(t *testing.T) {
+	assert.True(t, IsValidScope(ScopeSubscriptionsRead))
+	assert.True(t, IsValidScope(ScopeSubscriptionsWrite))
+	assert.True(t, IsValidScope(ScopeAdmin))
+	assert.False(t, IsValidScope("subscriptions:delete"))
+	assert.False(t, IsValidScope(""))
+}
+
+// TestAccessTokenScopeList tests splitting of the stored scopes
+func TestAccessTokenScopeList(t *testing.T) {
+	token := AccessToken{Scopes: "subscriptions:read subscriptions:write"}
+
+	assert.Equal(t, []string{ScopeSubscriptionsRead, ScopeSubscriptionsWrite}, token.ScopeList())
+	assert.Empty(t, (&AccessToken{}).ScopeList())
+}
+
+// TestAccessTokenIsActive tests expiry and revocation of access tokens
+func TestAccessTokenIsActive(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	testCases := []struct {
+		name     string
+		token    AccessToken
+		expected bool
+	}{
+		{
+			name:     "active",
+			token:    AccessToken{ExpiresAt: now.Add(time.Hour)},
+			expected: true,
+		},
+		{
+			name:     "expired",
+			token:    AccessToken{ExpiresAt: now.Add(-time.Hour)},
+			expected: false,
+		},
+		{
+			name:     "revoked",
+			token:    AccessToken{ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.token.IsActive(now))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Services that delete the data of an account and acknowledge the deletion
+const (
+	DeletionServiceCore         = "core-service"
+	DeletionServiceNotification = "notification-service"
+)
+
+// DeletionServices lists every service that must acknowledge an account deletion
+var DeletionServices = []string{DeletionServiceCore, DeletionServiceNotification}
+
+// AccountDeletion tracks the deletion of an account across services.
+// user.deleted is published again until every service in DeletionServices acknowledged it.
+type AccountDeletion struct {
+	UserID          uuid.UUID  `json:"user_id" gorm:"primaryKey"`
+	RequestedAt     time.Time  `json:"requested_at"`
+	LastPublishedAt *time.Time `json:"last_published_at,omitempty"`
+	Attempts        int        `json:"attempts" gorm:"not null;default:0"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// AccountDeletionAck records that a service deleted the data of the account
+type AccountDeletionAck struct {
+	UserID         uuid.UUID `json:"user_id" gorm:"primaryKey"`
+	Service        string    `json:"service" gorm:"primaryKey"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+}
+
+// PendingDeletionServices returns the services of DeletionServices missing from acks
+func PendingDeletionServices(acks []AccountDeletionAck) []string {
+	acknowledged := make(map[string]bool, len(acks))
+	for _, ack := range acks {
+		acknowledged[ack.Service] = true
+	}
+
+	var pending []string
+	for _, service := range DeletionServices {
+		if !acknowledged[service] {
+			pending = append(pending, service)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPendingDeletionServices tests which services still have to acknowledge a deletion
+func TestPendingDeletionServices(t *testing.T) {
+	testCases := []struct {
+		name string
+		acks []AccountDeletionAck
+		want []string
+	}{
+		{name: "none acknowledged", want: []string{DeletionServiceCore, DeletionServiceNotification}},
+		{name: "one acknowledged", acks: []AccountDeletionAck{{Service: DeletionServiceCore}}, want: []string{DeletionServiceNotification}},
+		{name: "all acknowledged", acks: []AccountDeletionAck{{Service: DeletionServiceNotification}, {Service: DeletionServiceCore}}},
+		{name: "unknown service ignored", acks: []AccountDeletionAck{{Service: "billing-service"}, {Service: DeletionServiceCore}}, want: []string{DeletionServiceNotification}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, PendingDeletionServices(tc.acks))
+		})
+	}
+}
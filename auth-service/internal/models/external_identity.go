@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExternalIdentity links a user to an account at an external OpenID Connect identity provider.
+// The provider account is identified by the issuer and subject claims of its ID tokens, the
+// email can change at the provider and is only kept for reference.
+type ExternalIdentity struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Issuer      string     `json:"issuer" gorm:"uniqueIndex:idx_external_identities_issuer_subject"`
+	Subject     string     `json:"subject" gorm:"uniqueIndex:idx_external_identities_issuer_subject"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
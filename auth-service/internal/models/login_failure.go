@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Scopes of failed login attempts
+const (
+	LoginScopeAccount = "account" // Subject is the email the login was attempted for
+	LoginScopeIP      = "ip"      // Subject is the client IP the login came from
+)
+
+// LoginFailure counts consecutive failed logins for an email or a client IP.
+// It is deleted after a successful login or once the failures are old enough to be forgotten.
+type LoginFailure struct {
+	Scope        string     `json:"scope" gorm:"primaryKey"`
+	Subject      string     `json:"subject" gorm:"primaryKey"`
+	Failures     int        `json:"failures" gorm:"not null;default:0"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+}
+
+// IsLocked reports whether logins are refused at now because of a lockout
+func (f *LoginFailure) IsLocked(now time.Time) bool {
+	return f.LockedUntil != nil && now.Before(*f.LockedUntil)
+}
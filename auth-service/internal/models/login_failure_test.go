@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoginFailure_IsLocked tests whether a lockout is in effect
+func TestLoginFailure_IsLocked(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Minute)
+	past := now.Add(-time.Minute)
+
+	testCases := []struct {
+		name        string
+		lockedUntil *time.Time
+		want        bool
+	}{
+		{name: "never locked", lockedUntil: nil, want: false},
+		{name: "locked", lockedUntil: &future, want: true},
+		{name: "lockout over", lockedUntil: &past, want: false},
+		{name: "lockout ends now", lockedUntil: &now, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failure := &LoginFailure{Scope: LoginScopeAccount, Subject: "test@example.com", LockedUntil: tc.lockedUntil}
+			assert.Equal(t, tc.want, failure.IsLocked(now))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasskeyCeremonyPrefix marks passkey ceremony tokens so they can be told apart from other tokens
+const PasskeyCeremonyPrefix = "pkc_"
+
+// Passkey ceremony kinds
+const (
+	PasskeyCeremonyRegistration = "registration"
+	PasskeyCeremonyLogin        = "login"
+)
+
+// PasskeyCredential is a WebAuthn credential (passkey) registered by a user.
+// The private key never leaves the authenticator, only the public key is stored.
+type PasskeyCredential struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// CredentialID is the ID chosen by the authenticator, unique across all users
+	CredentialID []byte `json:"-"`
+	// PublicKey is the COSE-encoded credential public key
+	PublicKey       []byte `json:"-"`
+	AttestationType string `json:"attestation_type"`
+	AAGUID          []byte `json:"-"`
+	// SignCount is the last signature counter reported by the authenticator, a counter
+	// that does not increase reveals a cloned authenticator
+	SignCount int64 `json:"sign_count" gorm:"not null;default:0"`
+	// Transports is the comma-separated list of transports the authenticator supports (usb, nfc, internal, ...)
+	Transports     string     `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	BackupState    bool       `json:"backup_state"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+}
+
+// TransportList returns the transports as a slice
+func (c *PasskeyCredential) TransportList() []string {
+	if c.Transports == "" {
+		return nil
+	}
+	return strings.Split(c.Transports, ",")
+}
+
+// PasskeyCeremony keeps the state of a passkey registration or login between its begin and
+// finish steps. Only the SHA-256 hash of the ceremony token is stored.
+type PasskeyCeremony struct {
+	TokenHash string `json:"-" gorm:"primaryKey"`
+	Kind      string `json:"kind"`
+	// UserID is the user registering a passkey, nil for logins since the passkey names the user
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	// SessionData is the JSON-encoded WebAuthn session with the challenge
+	SessionData string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// IsActive reports whether the ceremony can still be finished at now
+func (c *PasskeyCeremony) IsActive(now time.Time) bool {
+	return now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPasskeyCredential_TransportList tests splitting the stored transports
+func TestPasskeyCredential_TransportList(t *testing.T) {
+	assert.Nil(t, (&PasskeyCredential{}).TransportList())
+	assert.Equal(t, []string{"internal"}, (&PasskeyCredential{Transports: "internal"}).TransportList())
+	assert.Equal(t, []string{"usb", "nfc"}, (&PasskeyCredential{Transports: "usb,nfc"}).TransportList())
+}
+
+// TestPasskeyCeremony_IsActive tests whether passkey ceremonies can still be finished
+func TestPasskeyCeremony_IsActive(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, (&PasskeyCeremony{ExpiresAt: now.Add(time.Minute)}).IsActive(now))
+	assert.False(t, (&PasskeyCeremony{ExpiresAt: now.Add(-time.Minute)}).IsActive(now))
+	assert.False(t, (&PasskeyCeremony{ExpiresAt: now}).IsActive(now))
+}
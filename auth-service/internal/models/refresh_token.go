@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenPrefix marks refresh tokens so they can be told apart from other tokens
+const RefreshTokenPrefix = "rt_"
+
+// RefreshToken is a single-use token exchanged for a new access token.
+// Tokens issued from one login share a family; only the SHA-256 hash of the token is stored.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	FamilyID  uuid.UUID  `json:"family_id"`
+	TokenHash string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsActive reports whether the token can still be exchanged at now
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// IsReused reports whether the token was already exchanged but its family is not revoked,
+// which means someone else holds a copy of it
+func (t *RefreshToken) IsReused() bool {
+	return t.UsedAt != nil && t.RevokedAt == nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRefreshTokenState tests whether refresh tokens can be exchanged or were reused
+func TestRefreshTokenState(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+
+	testCases := []struct {
+		name       string
+		token      RefreshToken
+		wantActive bool
+		wantReused bool
+	}{
+		{
+			name:       "active",
+			token:      RefreshToken{ExpiresAt: now.Add(time.Hour)},
+			wantActive: true,
+		},
+		{
+			name:  "expired",
+			token: RefreshToken{ExpiresAt: now.Add(-time.Hour)},
+		},
+		{
+			name:       "used",
+			token:      RefreshToken{ExpiresAt: now.Add(time.Hour), UsedAt: &earlier},
+			wantReused: true,
+		},
+		{
+			name:  "used and revoked with its family",
+			token: RefreshToken{ExpiresAt: now.Add(time.Hour), UsedAt: &earlier, RevokedAt: &earlier},
+		},
+		{
+			name:  "revoked",
+			token: RefreshToken{ExpiresAt: now.Add(time.Hour), RevokedAt: &earlier},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantActive, tc.token.IsActive(now))
+			assert.Equal(t, tc.wantReused, tc.token.IsReused())
+		})
+	}
+}
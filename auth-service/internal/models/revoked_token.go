@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedToken records a JWT revoked before its expiry by its jti claim.
+// The record is only needed until the token would have expired anyway.
+type RevokedToken struct {
+	JTI       uuid.UUID `json:"jti" gorm:"column:jti;primaryKey"`
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purposes of single-use tokens, a token only works for the purpose it was issued for
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeMagicLink         = "magic_link"
+)
+
+// Prefixes mark single-use tokens so they can be told apart from other tokens
+const (
+	PasswordResetTokenPrefix     = "prt_"
+	EmailVerificationTokenPrefix = "evt_"
+	MagicLinkTokenPrefix         = "mlt_"
+)
+
+// SingleUseToken is a token sent by email that works once, e.g. to set a new password.
+// Only the SHA-256 hash of the token is stored. Email is the address an email verification
+// token confirms; it differs from the user's current email when the token confirms an email change.
+type SingleUseToken struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	Email     string     `json:"email,omitempty"`
+	TokenHash string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+// IsActive reports whether the token can still be used at now
+func (t *SingleUseToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSingleUseTokenIsActive tests whether single-use tokens can still be used
+func TestSingleUseTokenIsActive(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+
+	testCases := []struct {
+		name  string
+		token SingleUseToken
+		want  bool
+	}{
+		{name: "active", token: SingleUseToken{ExpiresAt: now.Add(time.Hour)}, want: true},
+		{name: "expired", token: SingleUseToken{ExpiresAt: now.Add(-time.Hour)}},
+		{name: "expires now", token: SingleUseToken{ExpiresAt: now}},
+		{name: "used", token: SingleUseToken{ExpiresAt: now.Add(time.Hour), UsedAt: &earlier}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.token.IsActive(now))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorChallengePrefix marks login challenges so they can be told apart from other tokens
+const TwoFactorChallengePrefix = "tfc_"
+
+// TOTPCredential is the authenticator app secret of a user.
+// Two-factor login is enabled once the enrollment is confirmed with a first code.
+type TOTPCredential struct {
+	UserID      uuid.UUID  `json:"user_id" gorm:"primaryKey"`
+	Secret      string     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	// LastUsedStep is the time step of the last accepted code, so that a code works only once
+	LastUsedStep int64 `json:"-" gorm:"not null;default:0"`
+}
+
+// IsConfirmed reports whether the enrollment was confirmed and logins require a code
+func (c *TOTPCredential) IsConfirmed() bool {
+	return c.ConfirmedAt != nil
+}
+
+// RecoveryCode is a single-use code that replaces an authenticator code, for users who lost
+// their device. Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+// TwoFactorChallenge is issued by Login when the password was correct but the account needs
+// a second factor; the login is completed by presenting it with a code.
+// Only the SHA-256 hash of the challenge is stored.
+type TwoFactorChallenge struct {
+	TokenHash string    `json:"-" gorm:"primaryKey"`
+	UserID    uuid.UUID `json:"user_id"`
+	Attempts  int       `json:"attempts" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IsActive reports whether the challenge can still be used at now
+func (c *TwoFactorChallenge) IsActive(now time.Time) bool {
+	return now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTOTPCredential_IsConfirmed tests whether an enrollment enables two-factor login
+func TestTOTPCredential_IsConfirmed(t *testing.T) {
+	now := time.Now()
+
+	assert.False(t, (&TOTPCredential{}).IsConfirmed())
+	assert.True(t, (&TOTPCredential{ConfirmedAt: &now}).IsConfirmed())
+}
+
+// TestTwoFactorChallenge_IsActive tests whether login challenges can still be used
+func TestTwoFactorChallenge_IsActive(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		challenge TwoFactorChallenge
+		want      bool
+	}{
+		{name: "active", challenge: TwoFactorChallenge{ExpiresAt: now.Add(time.Minute)}, want: true},
+		{name: "expired", challenge: TwoFactorChallenge{ExpiresAt: now.Add(-time.Minute)}},
+		{name: "expires now", challenge: TwoFactorChallenge{ExpiresAt: now}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.challenge.IsActive(now))
+		})
+	}
+}
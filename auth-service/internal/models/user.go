@@ -7,11 +7,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// User roles carried in JWT claims
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
 type User struct {
-	ID        uuid.UUID      `json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
-	Email     string         `json:"email" validate:"required,email"`
-	Password  string         `json:"password" validate:"required,password"`
+	ID              uuid.UUID      `json:"id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty"`
+	Email           string         `json:"email" validate:"required,email"`
+	EmailNormalized string         `json:"-" gorm:"not null"` // canonical form of Email, accounts are looked up and kept unique by it
+	Password        string         `json:"password" validate:"required,password"`
+	Role            string         `json:"role" gorm:"default:user"`
+	TokenVersion    int            `json:"-" gorm:"not null;default:0"` // embedded in issued JWTs, bumping it revokes all of them
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty"`
+}
+
+// IsEmailVerified reports whether the user confirmed the email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/utils"
 	"github.com/google/uuid"
@@ -241,6 +242,14 @@ func TestUserModelIntegration(t *testing.T) {
 	})
 }
 
+// TestUserIsEmailVerified tests the email verification state of users
+func TestUserIsEmailVerified(t *testing.T) {
+	verifiedAt := time.Now()
+
+	assert.False(t, (&User{}).IsEmailVerified())
+	assert.True(t, (&User{EmailVerifiedAt: &verifiedAt}).IsEmailVerified())
+}
+
 // Helper functions
 
 // createTestUser creates a test user with the given email and password
@@ -0,0 +1,98 @@
+package passwords
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// prefixLength is the length of the SHA-1 hex prefix hashes are grouped by, as in the
+// k-anonymity range queries of Pwned Passwords
+const prefixLength = 5
+
+// defaultBreachedPasswords lists SHA-1 hashes of the most common passwords from public breaches
+//
+//go:embed breached_passwords.txt
+var defaultBreachedPasswords []byte
+
+// BreachedList holds SHA-1 hashes of breached passwords grouped by their hex prefix
+type BreachedList struct {
+	buckets map[string][]string // prefix -> sorted suffixes
+}
+
+// LoadBreachedList reads one uppercase or lowercase hex SHA-1 hash per line. A ":count" after
+// the hash, as in the Pwned Passwords downloads, is ignored, as are empty lines and # comments.
+func LoadBreachedList(r io.Reader) (*BreachedList, error) {
+	list := &BreachedList{buckets: make(map[string][]string)}
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(hash)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != 2*sha1.Size {
+			return nil, fmt.Errorf("line %d: not a SHA-1 hash", lineNumber)
+		}
+		prefix := hash[:prefixLength]
+		list.buckets[prefix] = append(list.buckets[prefix], hash[prefixLength:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, suffixes := range list.buckets {
+		slices.Sort(suffixes)
+	}
+	return list, nil
+}
+
+// LoadBreachedListFile reads a breached password list from path, see LoadBreachedList
+func LoadBreachedListFile(path string) (*BreachedList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list, err := LoadBreachedList(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
+
+// DefaultBreachedList returns the list shipped with the service
+func DefaultBreachedList() *BreachedList {
+	list, err := LoadBreachedList(bytes.NewReader(defaultBreachedPasswords))
+	if err != nil {
+		panic(fmt.Sprintf("embedded breached password list: %v", err))
+	}
+	return list
+}
+
+// Contains reports whether password is in the list
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	_, found := slices.BinarySearch(l.buckets[hash[:prefixLength]], hash[prefixLength:])
+	return found
+}
+
+// Len returns the number of hashes in the list
+func (l *BreachedList) Len() int {
+	count := 0
+	for _, suffixes := range l.buckets {
+		count += len(suffixes)
+	}
+	return count
+}
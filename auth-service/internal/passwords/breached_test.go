@@ -0,0 +1,86 @@
+package passwords_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SHA-1 of "password" and "letmein"
+const (
+	passwordSHA1 = "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8"
+	letmeinSHA1  = "B7A875FC1EA228B9061041B7CEC4BD3C52AB3CE3"
+)
+
+func TestLoadBreachedList(t *testing.T) {
+	// Arrange - lowercase hashes, Pwned Passwords counts, comments and blank lines
+	input := "# breached\n\n" + strings.ToLower(passwordSHA1) + ":9545824\n" + letmeinSHA1 + "\n"
+
+	// Act
+	list, err := passwords.LoadBreachedList(strings.NewReader(input))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains("password"))
+	assert.True(t, list.Contains("letmein"))
+	assert.False(t, list.Contains("Password"))
+	assert.False(t, list.Contains("correct horse battery staple"))
+}
+
+func TestLoadBreachedList_Invalid(t *testing.T) {
+	testCases := map[string]string{
+		"too short": passwordSHA1[:39],
+		"not hex":   "ZBAA61E4C9B93F3F0682250B6CF8331B7EE68FD8",
+		"sha-256":   strings.Repeat("A", 64),
+	}
+
+	for name, input := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			list, err := passwords.LoadBreachedList(strings.NewReader(input))
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, list)
+		})
+	}
+}
+
+func TestLoadBreachedListFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(passwordSHA1+"\n"), 0o600))
+
+	// Act
+	list, err := passwords.LoadBreachedListFile(path)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, list.Contains("password"))
+}
+
+func TestLoadBreachedListFile_Missing(t *testing.T) {
+	// Act
+	list, err := passwords.LoadBreachedListFile(filepath.Join(t.TempDir(), "missing.txt"))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, list)
+}
+
+func TestDefaultBreachedList(t *testing.T) {
+	// Act
+	list := passwords.DefaultBreachedList()
+
+	// Assert
+	assert.Greater(t, list.Len(), 100)
+	assert.True(t, list.Contains("123456"))
+	assert.True(t, list.Contains("P@ssw0rd123"))
+	assert.False(t, list.Contains("Tr0ub4dor&3x"))
+}
@@ -0,0 +1,206 @@
+// Package passwords hashes and verifies user passwords. Hashes are stored in the PHC string format
+// of their algorithm: "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>" for argon2id and the
+// modular crypt format "$2a$10$..." for bcrypt, so every hash records how it was computed.
+package passwords
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported algorithms, selected with PASSWORD_HASH_ALGORITHM
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+const (
+	// saltBytes is the length of generated argon2id salts (128 bits, as recommended by RFC 9106)
+	saltBytes = 16
+	// keyBytes is the length of argon2id keys
+	keyBytes = 32
+)
+
+var (
+	// ErrMismatchedPassword is returned by Verify when the password does not match the hash
+	ErrMismatchedPassword = errors.New("password does not match")
+	// ErrUnsupportedHash is returned for hashes of an unknown algorithm or in a malformed encoding
+	ErrUnsupportedHash = errors.New("unsupported password hash")
+)
+
+// encoding is the unpadded base64 alphabet of PHC strings
+var encoding = base64.RawStdEncoding
+
+// Params selects the algorithm and cost of new hashes
+type Params struct {
+	Algorithm   string // Argon2id or Bcrypt
+	BcryptCost  int    // bcrypt work factor, 4 to 31
+	Memory      uint32 // argon2id memory in KiB
+	Iterations  uint32 // argon2id passes over the memory
+	Parallelism uint8  // argon2id lanes
+}
+
+// DefaultParams returns argon2id with 64 MiB of memory and three passes (RFC 9106, section 4)
+func DefaultParams() Params {
+	return Params{
+		Algorithm:   Argon2id,
+		BcryptCost:  12,
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+	}
+}
+
+// Hasher hashes new passwords with the configured parameters
+type Hasher struct {
+	params Params
+}
+
+// NewHasher validates params and creates a Hasher
+func NewHasher(params Params) (*Hasher, error) {
+	switch params.Algorithm {
+	case Argon2id:
+		if params.Iterations < 1 {
+			return nil, errors.New("argon2id iterations must be at least 1")
+		}
+		if params.Parallelism < 1 {
+			return nil, errors.New("argon2id parallelism must be at least 1")
+		}
+		if params.Memory < 8*uint32(params.Parallelism) {
+			return nil, fmt.Errorf("argon2id memory must be at least %d KiB", 8*uint32(params.Parallelism))
+		}
+	case Bcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", params.Algorithm)
+	}
+	return &Hasher{params: params}, nil
+}
+
+// DefaultHasher returns a Hasher with DefaultParams
+func DefaultHasher() *Hasher {
+	return &Hasher{params: DefaultParams()}
+}
+
+// Hash returns the encoded hash of password
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.params.Algorithm == Bcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.params.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, saltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, keyBytes)
+	return encodeArgon2id(argon2id{
+		memory:      h.params.Memory,
+		iterations:  h.params.Iterations,
+		parallelism: h.params.Parallelism,
+		salt:        salt,
+		key:         key,
+	}), nil
+}
+
+// NeedsRehash reports whether encoded was computed with another algorithm or other parameters
+// than new hashes, so it should be replaced once the password is known
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if h.params.Algorithm == Bcrypt {
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != h.params.BcryptCost
+	}
+
+	hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return hash.memory != h.params.Memory ||
+		hash.iterations != h.params.Iterations ||
+		hash.parallelism != h.params.Parallelism ||
+		len(hash.salt) != saltBytes ||
+		len(hash.key) != keyBytes
+}
+
+// Verify checks password against a hash of any supported algorithm.
+// It returns ErrMismatchedPassword when the password is wrong.
+func Verify(encoded, password string) error {
+	if strings.HasPrefix(encoded, "$"+Argon2id+"$") {
+		hash, err := decodeArgon2id(encoded)
+		if err != nil {
+			return err
+		}
+		key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+		if subtle.ConstantTimeCompare(key, hash.key) != 1 {
+			return ErrMismatchedPassword
+		}
+		return nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return ErrMismatchedPassword
+	default:
+		return fmt.Errorf("%w: %v", ErrUnsupportedHash, err)
+	}
+}
+
+// argon2id is a decoded argon2id PHC string
+type argon2id struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func encodeArgon2id(hash argon2id) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		Argon2id, argon2.Version, hash.memory, hash.iterations, hash.parallelism,
+		encoding.EncodeToString(hash.salt), encoding.EncodeToString(hash.key))
+}
+
+func decodeArgon2id(encoded string) (argon2id, error) {
+	var hash argon2id
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != Argon2id {
+		return hash, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return hash, fmt.Errorf("%w: argon2 version %q", ErrUnsupportedHash, parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism); err != nil {
+		return hash, fmt.Errorf("%w: argon2id parameters %q", ErrUnsupportedHash, parts[3])
+	}
+	if hash.iterations < 1 || hash.parallelism < 1 {
+		return hash, fmt.Errorf("%w: argon2id parameters %q", ErrUnsupportedHash, parts[3])
+	}
+
+	var err error
+	if hash.salt, err = encoding.DecodeString(parts[4]); err != nil {
+		return hash, fmt.Errorf("%w: argon2id salt", ErrUnsupportedHash)
+	}
+	if hash.key, err = encoding.DecodeString(parts[5]); err != nil || len(hash.key) == 0 {
+		return hash, fmt.Errorf("%w: argon2id key", ErrUnsupportedHash)
+	}
+	return hash, nil
+}
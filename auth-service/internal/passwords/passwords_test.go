@@ -0,0 +1,154 @@
+package passwords_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheapArgon2id keeps the tests fast, real deployments use far more memory
+var cheapArgon2id = passwords.Params{Algorithm: passwords.Argon2id, Memory: 64, Iterations: 1, Parallelism: 1}
+
+// cheapBcrypt keeps the tests fast
+var cheapBcrypt = passwords.Params{Algorithm: passwords.Bcrypt, BcryptCost: bcrypt.MinCost}
+
+func newHasher(t *testing.T, params passwords.Params) *passwords.Hasher {
+	t.Helper()
+	hasher, err := passwords.NewHasher(params)
+	require.NoError(t, err)
+	return hasher
+}
+
+func TestHash_Argon2id(t *testing.T) {
+	// Arrange
+	hasher := newHasher(t, cheapArgon2id)
+
+	// Act
+	hash, err := hasher.Hash("correct horse")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+	assert.NoError(t, passwords.Verify(hash, "correct horse"))
+	assert.ErrorIs(t, passwords.Verify(hash, "wrong horse"), passwords.ErrMismatchedPassword)
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestHash_Argon2idSalted(t *testing.T) {
+	// Arrange
+	hasher := newHasher(t, cheapArgon2id)
+
+	// Act
+	first, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	second, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+
+	// Assert
+	assert.NotEqual(t, first, second)
+}
+
+func TestHash_Bcrypt(t *testing.T) {
+	// Arrange
+	hasher := newHasher(t, cheapBcrypt)
+
+	// Act
+	hash, err := hasher.Hash("correct horse")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$04$"), hash)
+	assert.NoError(t, passwords.Verify(hash, "correct horse"))
+	assert.ErrorIs(t, passwords.Verify(hash, "wrong horse"), passwords.ErrMismatchedPassword)
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestVerify_LegacyBcrypt(t *testing.T) {
+	// Arrange - hashes stored before argon2id was introduced
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	// Act & Assert
+	assert.NoError(t, passwords.Verify(string(hash), "correct horse"))
+	assert.ErrorIs(t, passwords.Verify(string(hash), "wrong horse"), passwords.ErrMismatchedPassword)
+}
+
+func TestVerify_MalformedHash(t *testing.T) {
+	testCases := []string{
+		"",
+		"plaintext",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$!!!",
+		"$scrypt$ln=16,r=8,p=1$c2FsdA$a2V5",
+	}
+
+	for _, encoded := range testCases {
+		t.Run(encoded, func(t *testing.T) {
+			// Act
+			err := passwords.Verify(encoded, "correct horse")
+
+			// Assert
+			assert.ErrorIs(t, err, passwords.ErrUnsupportedHash)
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	// Arrange
+	argon2idHasher := newHasher(t, cheapArgon2id)
+	bcryptHasher := newHasher(t, cheapBcrypt)
+	argon2idHash, err := argon2idHasher.Hash("correct horse")
+	require.NoError(t, err)
+	bcryptHash, err := bcryptHasher.Hash("correct horse")
+	require.NoError(t, err)
+
+	stronger := cheapArgon2id
+	stronger.Iterations = 2
+	costlier := cheapBcrypt
+	costlier.BcryptCost = bcrypt.MinCost + 1
+
+	// Act & Assert
+	assert.True(t, argon2idHasher.NeedsRehash(bcryptHash), "bcrypt hash under argon2id")
+	assert.True(t, bcryptHasher.NeedsRehash(argon2idHash), "argon2id hash under bcrypt")
+	assert.True(t, newHasher(t, stronger).NeedsRehash(argon2idHash), "more argon2id iterations")
+	assert.True(t, newHasher(t, costlier).NeedsRehash(bcryptHash), "higher bcrypt cost")
+	assert.True(t, argon2idHasher.NeedsRehash("garbage"))
+}
+
+func TestNewHasher_InvalidParams(t *testing.T) {
+	testCases := map[string]passwords.Params{
+		"unknown algorithm":    {Algorithm: "md5"},
+		"bcrypt cost too low":  {Algorithm: passwords.Bcrypt, BcryptCost: 3},
+		"bcrypt cost too high": {Algorithm: passwords.Bcrypt, BcryptCost: 32},
+		"no iterations":        {Algorithm: passwords.Argon2id, Memory: 64, Iterations: 0, Parallelism: 1},
+		"no parallelism":       {Algorithm: passwords.Argon2id, Memory: 64, Iterations: 1, Parallelism: 0},
+		"too little memory":    {Algorithm: passwords.Argon2id, Memory: 16, Iterations: 1, Parallelism: 4},
+	}
+
+	for name, params := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			hasher, err := passwords.NewHasher(params)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, hasher)
+		})
+	}
+}
+
+func TestDefaultParams(t *testing.T) {
+	// Act
+	hasher, err := passwords.NewHasher(passwords.DefaultParams())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, passwords.Argon2id, passwords.DefaultParams().Algorithm)
+	assert.NotNil(t, hasher)
+}
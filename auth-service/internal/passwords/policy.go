@@ -0,0 +1,120 @@
+package passwords
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Codes of policy violations, clients use them to show which rule failed
+const (
+	ViolationTooShort         = "too_short"
+	ViolationTooLong          = "too_long"
+	ViolationMissingLowercase = "missing_lowercase"
+	ViolationMissingUppercase = "missing_uppercase"
+	ViolationMissingDigit     = "missing_digit"
+	ViolationMissingSymbol    = "missing_symbol"
+	ViolationBreached         = "breached"
+)
+
+// Violation is a rule of the policy a password does not meet
+type Violation struct {
+	Code    string
+	Message string
+}
+
+// PolicyError is returned for passwords that do not meet the policy
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "password does not meet the policy: " + strings.Join(messages, "; ")
+}
+
+// Policy lists the requirements for new passwords. MinLength is counted in characters and
+// MaxLength in UTF-8 bytes, the unit bcrypt limits.
+type Policy struct {
+	MinLength     int
+	MaxLength     int // 0 allows any length
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// Breached rejects passwords known from data breaches, they are not checked when it is nil
+	Breached *BreachedList
+}
+
+// DefaultPolicy returns at least 10 characters and at most 72 bytes with lowercase and uppercase
+// letters, digits and symbols. 72 bytes is the most bcrypt can hash.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:     10,
+		MaxLength:     72,
+		RequireLower:  true,
+		RequireUpper:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+}
+
+// Check returns a *PolicyError listing every rule password does not meet, or nil
+func (p *Policy) Check(password string) error {
+	var violations []Violation
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, Violation{
+			Code:    ViolationTooShort,
+			Message: fmt.Sprintf("must be at least %d characters long", p.MinLength),
+		})
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		violations = append(violations, Violation{
+			Code:    ViolationTooLong,
+			Message: fmt.Sprintf("must be at most %d bytes long, non-ASCII characters take several bytes", p.MaxLength),
+		})
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsNumber(char):
+			hasDigit = true
+		case !unicode.IsLetter(char):
+			hasSymbol = true
+		}
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, Violation{Code: ViolationMissingLowercase, Message: "must contain a lowercase letter"})
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, Violation{Code: ViolationMissingUppercase, Message: "must contain an uppercase letter"})
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{Code: ViolationMissingDigit, Message: "must contain a digit"})
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{Code: ViolationMissingSymbol, Message: "must contain a symbol"})
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		violations = append(violations, Violation{
+			Code:    ViolationBreached,
+			Message: "appears in a list of breached passwords, choose another one",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
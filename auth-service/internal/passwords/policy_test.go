@@ -0,0 +1,87 @@
+package passwords_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func violationCodes(t *testing.T, err error) []string {
+	t.Helper()
+	var policyErr *passwords.PolicyError
+	require.True(t, errors.As(err, &policyErr), "expected *PolicyError, got %v", err)
+
+	codes := make([]string, 0, len(policyErr.Violations))
+	for _, violation := range policyErr.Violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func TestPolicyCheck_Default(t *testing.T) {
+	policy := passwords.DefaultPolicy()
+
+	testCases := []struct {
+		name     string
+		password string
+		expected []string
+	}{
+		{"valid", "Tr0ub4dor&3x", nil},
+		{"valid non-ascii", "Пароль-2024x", nil},
+		{"too short", "Ab1!", []string{passwords.ViolationTooShort}},
+		{"too long", strings.Repeat("Aa1!", 19), []string{passwords.ViolationTooLong}},
+		{"too long in bytes", strings.Repeat("Яя1!", 15), []string{passwords.ViolationTooLong}},
+		{"missing lowercase", "TR0UB4DOR&3X", []string{passwords.ViolationMissingLowercase}},
+		{"missing uppercase", "tr0ub4dor&3x", []string{passwords.ViolationMissingUppercase}},
+		{"missing digit", "Troubador&xx", []string{passwords.ViolationMissingDigit}},
+		{"missing symbol", "Tr0ub4dor3xx", []string{passwords.ViolationMissingSymbol}},
+		{"empty", "", []string{
+			passwords.ViolationTooShort,
+			passwords.ViolationMissingLowercase,
+			passwords.ViolationMissingUppercase,
+			passwords.ViolationMissingDigit,
+			passwords.ViolationMissingSymbol,
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := policy.Check(tc.password)
+
+			// Assert
+			if tc.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tc.expected, violationCodes(t, err))
+		})
+	}
+}
+
+func TestPolicyCheck_Relaxed(t *testing.T) {
+	// Arrange
+	policy := passwords.Policy{MinLength: 8}
+
+	// Act & Assert
+	assert.NoError(t, policy.Check("correct horse battery staple"))
+	assert.Equal(t, []string{passwords.ViolationTooShort}, violationCodes(t, policy.Check("horse")))
+}
+
+func TestPolicyCheck_Breached(t *testing.T) {
+	// Arrange
+	policy := passwords.DefaultPolicy()
+	policy.Breached = passwords.DefaultBreachedList()
+
+	// Act
+	err := policy.Check("Password123!")
+
+	// Assert
+	assert.Equal(t, []string{passwords.ViolationBreached}, violationCodes(t, err))
+	assert.Contains(t, err.Error(), "breached")
+	assert.NoError(t, policy.Check("Tr0ub4dor&3x"))
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type AccessTokenRepository struct {
+	DB IDatabase
+}
+
+func NewAccessTokenRepository(db IDatabase) *AccessTokenRepository {
+	return &AccessTokenRepository{DB: db}
+}
+
+func (r *AccessTokenRepository) CreateAccessToken(token *models.AccessToken) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+
+	if err := r.DB.Create(token).GetError(); err != nil {
+		return fmt.Errorf("cannot create access token for user_id=%s: %w", token.UserID, err)
+	}
+	return nil
+}
+
+func (r *AccessTokenRepository) GetAccessTokenByHash(tokenHash string) (*models.AccessToken, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var token models.AccessToken
+	err := r.DB.Where("token_hash = ?", tokenHash).First(&token).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetUserAccessToken returns the token with tokenID if it belongs to the user
+func (r *AccessTokenRepository) GetUserAccessToken(userID, tokenID uuid.UUID) (*models.AccessToken, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var token models.AccessToken
+	err := r.DB.Where("id = ? AND user_id = ?", tokenID, userID).First(&token).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListUserAccessTokens returns the tokens of a user that were not revoked, newest first
+func (r *AccessTokenRepository) ListUserAccessTokens(userID uuid.UUID) ([]models.AccessToken, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var tokens []models.AccessToken
+	err := r.DB.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at DESC").Find(&tokens).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeAccessToken marks a token of the user as revoked.
+// It reports false if the user has no such active token.
+func (r *AccessTokenRepository) RevokeAccessToken(userID, tokenID uuid.UUID, revokedAt time.Time) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Model(&models.AccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", revokedAt)
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *AccessTokenRepository) UpdateAccessTokenLastUsed(tokenID uuid.UUID, usedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.AccessToken{}).Where("id = ?", tokenID).Update("last_used_at", usedAt).GetError()
+}
@@ -0,0 +1,197 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type AccessTokenRepositoryTestSuite struct {
+	suite.Suite
+	repo   *repositories.AccessTokenRepository
+	userID uuid.UUID
+	now    time.Time
+}
+
+func (suite *AccessTokenRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.AccessToken{}))
+
+	suite.repo = repositories.NewAccessTokenRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// createToken stores an active token of userID with the given name
+func (suite *AccessTokenRepositoryTestSuite) createToken(userID uuid.UUID, name string, createdAt time.Time) *models.AccessToken {
+	token := &models.AccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: uuid.NewString(),
+		Scopes:    models.ScopeSubscriptionsRead,
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(time.Hour),
+	}
+	suite.Require().NoError(suite.repo.CreateAccessToken(token))
+	return token
+}
+
+// ===== TESTS =====
+
+func (suite *AccessTokenRepositoryTestSuite) TestCreateAccessToken_GeneratesID() {
+	// Act
+	token := suite.createToken(suite.userID, "ci", suite.now)
+
+	// Assert
+	suite.NotEqual(uuid.Nil, token.ID)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestGetAccessTokenByHash() {
+	// Arrange
+	created := suite.createToken(suite.userID, "ci", suite.now)
+
+	// Act
+	found, err := suite.repo.GetAccessTokenByHash(created.TokenHash)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(created.ID, found.ID)
+	suite.Equal(suite.userID, found.UserID)
+	suite.Equal(models.ScopeSubscriptionsRead, found.Scopes)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestGetAccessTokenByHash_NotFound() {
+	// Act
+	found, err := suite.repo.GetAccessTokenByHash("missing")
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(found)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestGetUserAccessToken() {
+	// Arrange
+	created := suite.createToken(suite.userID, "ci", suite.now)
+
+	// Act
+	found, err := suite.repo.GetUserAccessToken(suite.userID, created.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(created.TokenHash, found.TokenHash)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestGetUserAccessToken_OtherUsersToken() {
+	// Arrange
+	created := suite.createToken(uuid.New(), "foreign", suite.now)
+
+	// Act
+	found, err := suite.repo.GetUserAccessToken(suite.userID, created.ID)
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(found)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestListUserAccessTokens_NewestFirstWithoutRevoked() {
+	// Arrange
+	older := suite.createToken(suite.userID, "older", suite.now.Add(-time.Hour))
+	newer := suite.createToken(suite.userID, "newer", suite.now)
+	revoked := suite.createToken(suite.userID, "revoked", suite.now)
+	suite.createToken(uuid.New(), "foreign", suite.now)
+	_, err := suite.repo.RevokeAccessToken(suite.userID, revoked.ID, suite.now)
+	suite.Require().NoError(err)
+
+	// Act
+	tokens, err := suite.repo.ListUserAccessTokens(suite.userID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(tokens, 2)
+	suite.Equal(newer.ID, tokens[0].ID)
+	suite.Equal(older.ID, tokens[1].ID)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestRevokeAccessToken() {
+	// Arrange
+	token := suite.createToken(suite.userID, "ci", suite.now)
+
+	// Act
+	revoked, err := suite.repo.RevokeAccessToken(suite.userID, token.ID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(revoked)
+	found, err := suite.repo.GetAccessTokenByHash(token.TokenHash)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(found.RevokedAt)
+	suite.False(found.IsActive(suite.now))
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestRevokeAccessToken_OtherUsersToken() {
+	// Arrange
+	token := suite.createToken(uuid.New(), "foreign", suite.now)
+
+	// Act
+	revoked, err := suite.repo.RevokeAccessToken(suite.userID, token.ID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(revoked)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestRevokeAccessToken_AlreadyRevoked() {
+	// Arrange
+	token := suite.createToken(suite.userID, "ci", suite.now)
+	_, err := suite.repo.RevokeAccessToken(suite.userID, token.ID, suite.now)
+	suite.Require().NoError(err)
+
+	// Act
+	revoked, err := suite.repo.RevokeAccessToken(suite.userID, token.ID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(revoked)
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestUpdateAccessTokenLastUsed() {
+	// Arrange
+	token := suite.createToken(suite.userID, "ci", suite.now)
+	usedAt := suite.now.Add(time.Minute)
+
+	// Act
+	err := suite.repo.UpdateAccessTokenLastUsed(token.ID, usedAt)
+
+	// Assert
+	suite.Require().NoError(err)
+	found, err := suite.repo.GetAccessTokenByHash(token.TokenHash)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(found.LastUsedAt)
+	suite.True(usedAt.Equal(*found.LastUsedAt))
+}
+
+func (suite *AccessTokenRepositoryTestSuite) TestNilDatabase() {
+	// Arrange
+	repo := &repositories.AccessTokenRepository{DB: nil}
+
+	// Act
+	err := repo.CreateAccessToken(&models.AccessToken{})
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// Run tests
+func TestAccessTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenRepositoryTestSuite))
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AccountDeletionRepository struct {
+	DB IDatabase
+}
+
+func NewAccountDeletionRepository(db IDatabase) *AccountDeletionRepository {
+	return &AccountDeletionRepository{DB: db}
+}
+
+func (r *AccountDeletionRepository) CreateAccountDeletion(deletion *models.AccountDeletion) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := r.DB.Create(deletion).GetError(); err != nil {
+		return fmt.Errorf("cannot create account deletion of user_id=%s: %w", deletion.UserID, err)
+	}
+	return nil
+}
+
+func (r *AccountDeletionRepository) GetAccountDeletion(userID uuid.UUID) (*models.AccountDeletion, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var deletion models.AccountDeletion
+	err := r.DB.Where("user_id = ?", userID).First(&deletion).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+// ListPendingAccountDeletions lists the incomplete deletions that were not published since publishedBefore
+func (r *AccountDeletionRepository) ListPendingAccountDeletions(publishedBefore time.Time) ([]models.AccountDeletion, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var deletions []models.AccountDeletion
+	err := r.DB.Where("completed_at IS NULL AND (last_published_at IS NULL OR last_published_at < ?)", publishedBefore).
+		Order("requested_at").
+		Find(&deletions).
+		GetError()
+	if err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+// MarkAccountDeletionPublished records another publication of the user.deleted event
+func (r *AccountDeletionRepository) MarkAccountDeletionPublished(userID uuid.UUID, publishedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	err := r.DB.Model(&models.AccountDeletion{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"last_published_at": publishedAt,
+			"attempts":          gorm.Expr("attempts + 1"),
+		}).
+		GetError()
+	if err != nil {
+		return fmt.Errorf("cannot mark account deletion of user_id=%s as published: %w", userID, err)
+	}
+	return nil
+}
+
+// AcknowledgeAccountDeletion records that service deleted the data of the user.
+// Repeated acknowledgements keep the first one.
+func (r *AccountDeletionRepository) AcknowledgeAccountDeletion(userID uuid.UUID, service string, acknowledgedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	var count int64
+	err := r.DB.Model(&models.AccountDeletionAck{}).
+		Where("user_id = ? AND service = ?", userID, service).
+		Count(&count).
+		GetError()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	ack := &models.AccountDeletionAck{UserID: userID, Service: service, AcknowledgedAt: acknowledgedAt}
+	if err := r.DB.Create(ack).GetError(); err != nil {
+		return fmt.Errorf("cannot acknowledge account deletion of user_id=%s by %s: %w", userID, service, err)
+	}
+	return nil
+}
+
+func (r *AccountDeletionRepository) ListAccountDeletionAcks(userID uuid.UUID) ([]models.AccountDeletionAck, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var acks []models.AccountDeletionAck
+	err := r.DB.Where("user_id = ?", userID).Order("acknowledged_at").Find(&acks).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return acks, nil
+}
+
+// CompleteAccountDeletion marks the deletion as done, so that it is no longer retried
+func (r *AccountDeletionRepository) CompleteAccountDeletion(userID uuid.UUID, completedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	err := r.DB.Model(&models.AccountDeletion{}).
+		Where("user_id = ? AND completed_at IS NULL", userID).
+		Update("completed_at", completedAt).
+		GetError()
+	if err != nil {
+		return fmt.Errorf("cannot complete account deletion of user_id=%s: %w", userID, err)
+	}
+	return nil
+}
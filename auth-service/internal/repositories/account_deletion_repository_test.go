@@ -0,0 +1,140 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type AccountDeletionRepositoryTestSuite struct {
+	suite.Suite
+	repo   *repositories.AccountDeletionRepository
+	userID uuid.UUID
+	now    time.Time
+}
+
+func (suite *AccountDeletionRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.AccountDeletion{}, &models.AccountDeletionAck{}))
+
+	suite.repo = repositories.NewAccountDeletionRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// createDeletion stores a deletion of the user requested at requestedAt
+func (suite *AccountDeletionRepositoryTestSuite) createDeletion(userID uuid.UUID, requestedAt time.Time) {
+	suite.Require().NoError(suite.repo.CreateAccountDeletion(&models.AccountDeletion{UserID: userID, RequestedAt: requestedAt}))
+}
+
+// ===== TESTS =====
+
+func (suite *AccountDeletionRepositoryTestSuite) TestCreateAndGetAccountDeletion() {
+	// Arrange
+	suite.createDeletion(suite.userID, suite.now)
+
+	// Act
+	found, err := suite.repo.GetAccountDeletion(suite.userID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.userID, found.UserID)
+	suite.Zero(found.Attempts)
+	suite.Nil(found.LastPublishedAt)
+	suite.Nil(found.CompletedAt)
+}
+
+func (suite *AccountDeletionRepositoryTestSuite) TestGetAccountDeletion_NotFound() {
+	// Act
+	_, err := suite.repo.GetAccountDeletion(uuid.New())
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *AccountDeletionRepositoryTestSuite) TestMarkAccountDeletionPublished() {
+	// Arrange
+	suite.createDeletion(suite.userID, suite.now)
+
+	// Act
+	suite.Require().NoError(suite.repo.MarkAccountDeletionPublished(suite.userID, suite.now))
+	suite.Require().NoError(suite.repo.MarkAccountDeletionPublished(suite.userID, suite.now.Add(time.Minute)))
+
+	// Assert
+	found, err := suite.repo.GetAccountDeletion(suite.userID)
+	suite.Require().NoError(err)
+	suite.Equal(2, found.Attempts)
+	suite.Require().NotNil(found.LastPublishedAt)
+	suite.WithinDuration(suite.now.Add(time.Minute), *found.LastPublishedAt, time.Second)
+}
+
+func (suite *AccountDeletionRepositoryTestSuite) TestListPendingAccountDeletions() {
+	// Arrange
+	unpublished := uuid.New()
+	stale := uuid.New()
+	recent := uuid.New()
+	completed := uuid.New()
+	suite.createDeletion(unpublished, suite.now.Add(-3*time.Hour))
+	suite.createDeletion(stale, suite.now.Add(-2*time.Hour))
+	suite.createDeletion(recent, suite.now.Add(-time.Hour))
+	suite.createDeletion(completed, suite.now.Add(-time.Hour))
+	suite.Require().NoError(suite.repo.MarkAccountDeletionPublished(stale, suite.now.Add(-time.Hour)))
+	suite.Require().NoError(suite.repo.MarkAccountDeletionPublished(recent, suite.now))
+	suite.Require().NoError(suite.repo.CompleteAccountDeletion(completed, suite.now))
+
+	// Act
+	pending, err := suite.repo.ListPendingAccountDeletions(suite.now.Add(-time.Minute))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(pending, 2)
+	suite.Equal(unpublished, pending[0].UserID)
+	suite.Equal(stale, pending[1].UserID)
+}
+
+func (suite *AccountDeletionRepositoryTestSuite) TestAcknowledgeAccountDeletion_KeepsFirstAck() {
+	// Arrange
+	suite.createDeletion(suite.userID, suite.now)
+
+	// Act
+	suite.Require().NoError(suite.repo.AcknowledgeAccountDeletion(suite.userID, models.DeletionServiceCore, suite.now))
+	suite.Require().NoError(suite.repo.AcknowledgeAccountDeletion(suite.userID, models.DeletionServiceCore, suite.now.Add(time.Minute)))
+	suite.Require().NoError(suite.repo.AcknowledgeAccountDeletion(suite.userID, models.DeletionServiceNotification, suite.now.Add(time.Minute)))
+
+	// Assert
+	acks, err := suite.repo.ListAccountDeletionAcks(suite.userID)
+	suite.Require().NoError(err)
+	suite.Require().Len(acks, 2)
+	suite.Equal(models.DeletionServiceCore, acks[0].Service)
+	suite.WithinDuration(suite.now, acks[0].AcknowledgedAt, time.Second)
+	suite.Equal(models.DeletionServiceNotification, acks[1].Service)
+}
+
+func (suite *AccountDeletionRepositoryTestSuite) TestCompleteAccountDeletion_KeepsFirstCompletion() {
+	// Arrange
+	suite.createDeletion(suite.userID, suite.now)
+
+	// Act
+	suite.Require().NoError(suite.repo.CompleteAccountDeletion(suite.userID, suite.now))
+	suite.Require().NoError(suite.repo.CompleteAccountDeletion(suite.userID, suite.now.Add(time.Hour)))
+
+	// Assert
+	found, err := suite.repo.GetAccountDeletion(suite.userID)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(found.CompletedAt)
+	suite.WithinDuration(suite.now, *found.CompletedAt, time.Second)
+}
+
+// Run tests
+func TestAccountDeletionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountDeletionRepositoryTestSuite))
+}
@@ -0,0 +1,39 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestIsUnavailable(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil":                {err: nil, expected: false},
+		"record not found":   {err: gorm.ErrRecordNotFound, expected: false},
+		"query error":        {err: errors.New("syntax error"), expected: false},
+		"unique violation":   {err: &pgconn.PgError{Code: "23505"}, expected: false},
+		"connection failure": {err: &pgconn.PgError{Code: "08006"}, expected: true},
+		"admin shutdown":     {err: fmt.Errorf("cannot create user: %w", &pgconn.PgError{Code: "57P01"}), expected: true},
+		"network error":      {err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, expected: true},
+		"bad connection":     {err: fmt.Errorf("query failed: %w", driver.ErrBadConn), expected: true},
+		"deadline exceeded":  {err: context.DeadlineExceeded, expected: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tc.expected, repositories.IsUnavailable(tc.err))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type ExternalIdentityRepository struct {
+	DB IDatabase
+}
+
+func NewExternalIdentityRepository(db IDatabase) *ExternalIdentityRepository {
+	return &ExternalIdentityRepository{DB: db}
+}
+
+func (r *ExternalIdentityRepository) CreateExternalIdentity(identity *models.ExternalIdentity) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if identity.ID == uuid.Nil {
+		identity.ID = uuid.New()
+	}
+	if err := r.DB.Create(identity).GetError(); err != nil {
+		return fmt.Errorf("cannot link identity of issuer=%s to user_id=%s: %w", identity.Issuer, identity.UserID, err)
+	}
+	return nil
+}
+
+// GetExternalIdentity finds the identity with the issuer and subject claims of an ID token
+func (r *ExternalIdentityRepository) GetExternalIdentity(issuer, subject string) (*models.ExternalIdentity, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var identity models.ExternalIdentity
+	err := r.DB.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// RecordExternalLogin stores the time of a login with the identity and the email the provider reported
+func (r *ExternalIdentityRepository) RecordExternalLogin(id uuid.UUID, email string, at time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.ExternalIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"email":         email,
+			"last_login_at": at,
+		}).
+		GetError()
+}
+
+func (r *ExternalIdentityRepository) DeleteExternalIdentity(id uuid.UUID) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Where("id = ?", id).Delete(&models.ExternalIdentity{}).GetError()
+}
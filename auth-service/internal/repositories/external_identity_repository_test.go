@@ -0,0 +1,138 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type ExternalIdentityRepositoryTestSuite struct {
+	suite.Suite
+	repo   *repositories.ExternalIdentityRepository
+	userID uuid.UUID
+	now    time.Time
+}
+
+func (suite *ExternalIdentityRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.ExternalIdentity{}))
+
+	suite.repo = repositories.NewExternalIdentityRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// createIdentity links the subject of the test issuer to the user
+func (suite *ExternalIdentityRepositoryTestSuite) createIdentity(userID uuid.UUID, subject string) *models.ExternalIdentity {
+	identity := &models.ExternalIdentity{
+		UserID:    userID,
+		Issuer:    "https://idp.example.com",
+		Subject:   subject,
+		Email:     "test@example.com",
+		CreatedAt: suite.now,
+	}
+	suite.Require().NoError(suite.repo.CreateExternalIdentity(identity))
+	return identity
+}
+
+// ===== TESTS =====
+
+func (suite *ExternalIdentityRepositoryTestSuite) TestCreateAndGetExternalIdentity() {
+	// Arrange
+	created := suite.createIdentity(suite.userID, "subject-1")
+
+	// Act
+	found, err := suite.repo.GetExternalIdentity("https://idp.example.com", "subject-1")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEqual(uuid.Nil, created.ID)
+	suite.Equal(created.ID, found.ID)
+	suite.Equal(suite.userID, found.UserID)
+	suite.Nil(found.LastLoginAt)
+}
+
+func (suite *ExternalIdentityRepositoryTestSuite) TestGetExternalIdentity_OtherIssuer() {
+	// Arrange
+	suite.createIdentity(suite.userID, "subject-1")
+
+	// Act
+	found, err := suite.repo.GetExternalIdentity("https://other.example.com", "subject-1")
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(found)
+}
+
+func (suite *ExternalIdentityRepositoryTestSuite) TestCreateExternalIdentity_SubjectLinkedTwice() {
+	// Arrange
+	suite.createIdentity(suite.userID, "subject-1")
+
+	// Act
+	err := suite.repo.CreateExternalIdentity(&models.ExternalIdentity{
+		UserID:  uuid.New(),
+		Issuer:  "https://idp.example.com",
+		Subject: "subject-1",
+	})
+
+	// Assert
+	suite.Error(err)
+}
+
+func (suite *ExternalIdentityRepositoryTestSuite) TestRecordExternalLogin() {
+	// Arrange
+	identity := suite.createIdentity(suite.userID, "subject-1")
+
+	// Act
+	err := suite.repo.RecordExternalLogin(identity.ID, "renamed@example.com", suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	found, err := suite.repo.GetExternalIdentity(identity.Issuer, identity.Subject)
+	suite.Require().NoError(err)
+	suite.Equal("renamed@example.com", found.Email)
+	suite.Require().NotNil(found.LastLoginAt)
+	suite.True(suite.now.Equal(*found.LastLoginAt))
+}
+
+func (suite *ExternalIdentityRepositoryTestSuite) TestDeleteExternalIdentity() {
+	// Arrange
+	identity := suite.createIdentity(suite.userID, "subject-1")
+	other := suite.createIdentity(suite.userID, "subject-2")
+
+	// Act
+	err := suite.repo.DeleteExternalIdentity(identity.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetExternalIdentity(identity.Issuer, identity.Subject)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	_, err = suite.repo.GetExternalIdentity(other.Issuer, other.Subject)
+	suite.NoError(err)
+}
+
+func (suite *ExternalIdentityRepositoryTestSuite) TestNilDatabase() {
+	// Arrange
+	repo := &repositories.ExternalIdentityRepository{DB: nil}
+
+	// Act
+	_, err := repo.GetExternalIdentity("https://idp.example.com", "subject-1")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// Run tests
+func TestExternalIdentityRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ExternalIdentityRepositoryTestSuite))
+}
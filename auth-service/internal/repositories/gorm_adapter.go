@@ -1,12 +1,14 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormAdapter adapter for GORM DB
@@ -16,10 +18,7 @@ type GormAdapter struct {
 
 // NewGormAdapter creates a new adapter for GORM with config
 func NewGormAdapter(dbConfig config.DBConfig) (IDatabase, error) {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.DBName, dbConfig.SSLMode)
-
-	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dbConfig.ConnectionString()), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -39,6 +38,13 @@ func (g *GormAdapter) Create(value interface{}) IDatabase {
 	return &GormAdapter{db: g.db.Create(value)}
 }
 
+func (g *GormAdapter) Clauses(conds ...clause.Expression) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Clauses(conds...)}
+}
+
 func (g *GormAdapter) Where(query interface{}, args ...interface{}) IDatabase {
 	if g.db == nil {
 		return &GormAdapter{db: nil}
@@ -67,9 +73,83 @@ func (g *GormAdapter) Count(value *int64) IDatabase {
 	return &GormAdapter{db: g.db.Count(value)}
 }
 
+func (g *GormAdapter) Find(dest interface{}, conds ...interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Find(dest, conds...)}
+}
+
+func (g *GormAdapter) Order(value interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Order(value)}
+}
+
+func (g *GormAdapter) Limit(limit int) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Limit(limit)}
+}
+
+func (g *GormAdapter) Update(column string, value interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Update(column, value)}
+}
+
+func (g *GormAdapter) Updates(values interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Updates(values)}
+}
+
+func (g *GormAdapter) Delete(value interface{}, conds ...interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Delete(value, conds...)}
+}
+
+// RowsAffected returns the number of rows affected by the last statement
+func (g *GormAdapter) RowsAffected() int64 {
+	if g.db == nil {
+		return 0
+	}
+	return g.db.RowsAffected
+}
+
 func (g *GormAdapter) GetError() error {
 	if g.db == nil {
 		return errors.New("database is nil")
 	}
 	return g.db.Error
 }
+
+// Ping checks that the database answers
+func (g *GormAdapter) Ping(ctx context.Context) error {
+	if g.db == nil {
+		return errors.New("database is nil")
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes the connection pool
+func (g *GormAdapter) Close() error {
+	if g.db == nil {
+		return nil
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
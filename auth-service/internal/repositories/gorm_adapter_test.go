@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
@@ -267,6 +268,161 @@ func (suite *GormAdapterTestSuite) TestModelWithRealDB() {
 	suite.Require().NoError(result.GetError())
 }
 
+func (suite *GormAdapterTestSuite) TestFindWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	adapter.Create(&TestUser{Email: "user2@test.com"})
+	adapter.Create(&TestUser{Email: "user1@test.com"})
+
+	// Act
+	var users []TestUser
+	result := adapter.Order("email").Find(&users)
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	suite.Require().Len(users, 2)
+	suite.Equal("user1@test.com", users[0].Email)
+}
+
+func (suite *GormAdapterTestSuite) TestLimitWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	adapter.Create(&TestUser{Email: "user2@test.com"})
+	adapter.Create(&TestUser{Email: "user1@test.com"})
+
+	// Act
+	var users []TestUser
+	result := adapter.Order("email").Limit(1).Find(&users)
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	suite.Require().Len(users, 1)
+	suite.Equal("user1@test.com", users[0].Email)
+}
+
+func (suite *GormAdapterTestSuite) TestFindWithNilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	var users []TestUser
+	result := adapter.Find(&users)
+
+	// Assert
+	suite.Require().Error(result.GetError())
+	suite.Contains(result.GetError().Error(), "database is nil")
+}
+
+func (suite *GormAdapterTestSuite) TestUpdateWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	adapter.Create(&TestUser{Email: "old@test.com"})
+
+	// Act
+	result := adapter.Model(&TestUser{}).Where("email = ?", "old@test.com").Update("email", "new@test.com")
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	suite.Equal(int64(1), result.RowsAffected())
+}
+
+func (suite *GormAdapterTestSuite) TestUpdateWithNilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	result := adapter.Update("email", "new@test.com")
+
+	// Assert
+	suite.Require().Error(result.GetError())
+	suite.Zero(result.RowsAffected())
+}
+
+func (suite *GormAdapterTestSuite) TestUpdatesWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	adapter.Create(&TestUser{Email: "old@test.com"})
+
+	// Act
+	result := adapter.Model(&TestUser{}).Where("email = ?", "old@test.com").Updates(map[string]interface{}{"email": "new@test.com"})
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	suite.Equal(int64(1), result.RowsAffected())
+}
+
+func (suite *GormAdapterTestSuite) TestUpdatesWithNilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	result := adapter.Updates(map[string]interface{}{"email": "new@test.com"})
+
+	// Assert
+	suite.Require().Error(result.GetError())
+	suite.Zero(result.RowsAffected())
+}
+
+func (suite *GormAdapterTestSuite) TestDeleteWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	adapter.Create(&TestUser{Email: "old@test.com"})
+	adapter.Create(&TestUser{Email: "keep@test.com"})
+
+	// Act
+	result := adapter.Where("email = ?", "old@test.com").Delete(&TestUser{})
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	suite.Equal(int64(1), result.RowsAffected())
+}
+
+func (suite *GormAdapterTestSuite) TestDeleteWithNilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	result := adapter.Delete(&TestUser{})
+
+	// Assert
+	suite.Require().Error(result.GetError())
+	suite.Zero(result.RowsAffected())
+}
+
+// ===== CONNECTION TESTS =====
+
+func (suite *GormAdapterTestSuite) TestPingWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+
+	// Act
+	err := adapter.Ping(context.Background())
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *GormAdapterTestSuite) TestPingAfterClose() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	suite.Require().NoError(adapter.Close())
+
+	// Act
+	err := adapter.Ping(context.Background())
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *GormAdapterTestSuite) TestPingWithNilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act & Assert
+	suite.Require().Error(adapter.Ping(context.Background()))
+	suite.Require().NoError(adapter.Close())
+}
+
 // Run tests
 func TestGormAdapterTestSuite(t *testing.T) {
 	suite.Run(t, new(GormAdapterTestSuite))
@@ -1,24 +1,162 @@
 package repositories
 
-import "github.com/Koshsky/subs-service/auth-service/internal/models"
+import (
+	"context"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
 
 //go:generate mockery --name=IUserRepository --output=./mocks --outpkg=mocks --filename=IUserRepository.go
 type IUserRepository interface {
 	CreateUser(user *models.User) error
 	GetUserByEmail(email string) (*models.User, error)
+	GetUserByID(id uuid.UUID) (*models.User, error)
 	UserExists(email string) (bool, error)
+	IncrementTokenVersion(id uuid.UUID) error
+	UpdatePassword(id uuid.UUID, passwordHash string) error
+	RehashPassword(id uuid.UUID, currentHash, newHash string) (bool, error)
+	MarkEmailVerified(id uuid.UUID, verifiedAt time.Time) error
+	UpdateEmail(id uuid.UUID, email, emailNormalized string, verifiedAt time.Time) error
+	ListUsersAfter(afterID uuid.UUID, limit int) ([]models.User, error)
+	UpdateEmailNormalized(id uuid.UUID, emailNormalized string) error
+	DeleteUser(id uuid.UUID) error
+}
+
+//go:generate mockery --name=IAccessTokenRepository --output=./mocks --outpkg=mocks --filename=IAccessTokenRepository.go
+type IAccessTokenRepository interface {
+	CreateAccessToken(token *models.AccessToken) error
+	GetAccessTokenByHash(tokenHash string) (*models.AccessToken, error)
+	GetUserAccessToken(userID, tokenID uuid.UUID) (*models.AccessToken, error)
+	ListUserAccessTokens(userID uuid.UUID) ([]models.AccessToken, error)
+	RevokeAccessToken(userID, tokenID uuid.UUID, revokedAt time.Time) (bool, error)
+	UpdateAccessTokenLastUsed(tokenID uuid.UUID, usedAt time.Time) error
+}
+
+//go:generate mockery --name=IRefreshTokenRepository --output=./mocks --outpkg=mocks --filename=IRefreshTokenRepository.go
+type IRefreshTokenRepository interface {
+	CreateRefreshToken(token *models.RefreshToken) error
+	GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(tokenID uuid.UUID, usedAt time.Time) (bool, error)
+	RevokeRefreshTokenFamily(familyID uuid.UUID, revokedAt time.Time) error
+	RevokeUserRefreshTokens(userID uuid.UUID, revokedAt time.Time) error
+}
+
+//go:generate mockery --name=IRevokedTokenRepository --output=./mocks --outpkg=mocks --filename=IRevokedTokenRepository.go
+type IRevokedTokenRepository interface {
+	RevokeToken(token *models.RevokedToken) error
+	IsTokenRevoked(jti uuid.UUID) (bool, error)
+	DeleteExpiredRevokedTokens(before time.Time) error
+}
+
+//go:generate mockery --name=ISingleUseTokenRepository --output=./mocks --outpkg=mocks --filename=ISingleUseTokenRepository.go
+type ISingleUseTokenRepository interface {
+	CreateSingleUseToken(token *models.SingleUseToken) error
+	GetSingleUseTokenByHash(purpose, tokenHash string) (*models.SingleUseToken, error)
+	CountSingleUseTokensSince(purpose string, userID uuid.UUID, since time.Time) (int64, error)
+	MarkSingleUseTokenUsed(tokenID uuid.UUID, usedAt time.Time) (bool, error)
+	InvalidateUserSingleUseTokens(purpose string, userID uuid.UUID, usedAt time.Time) error
+}
+
+//go:generate mockery --name=IAccountDeletionRepository --output=./mocks --outpkg=mocks --filename=IAccountDeletionRepository.go
+type IAccountDeletionRepository interface {
+	CreateAccountDeletion(deletion *models.AccountDeletion) error
+	GetAccountDeletion(userID uuid.UUID) (*models.AccountDeletion, error)
+	ListPendingAccountDeletions(publishedBefore time.Time) ([]models.AccountDeletion, error)
+	MarkAccountDeletionPublished(userID uuid.UUID, publishedAt time.Time) error
+	AcknowledgeAccountDeletion(userID uuid.UUID, service string, acknowledgedAt time.Time) error
+	ListAccountDeletionAcks(userID uuid.UUID) ([]models.AccountDeletionAck, error)
+	CompleteAccountDeletion(userID uuid.UUID, completedAt time.Time) error
+}
+
+//go:generate mockery --name=ILoginFailureRepository --output=./mocks --outpkg=mocks --filename=ILoginFailureRepository.go
+type ILoginFailureRepository interface {
+	GetLoginFailure(scope, subject string) (*models.LoginFailure, error)
+	ReserveLoginAttempt(scope, subject string, current *models.LoginFailure, at time.Time) (bool, error)
+	ReleaseLoginAttempt(scope, subject string) error
+	LockLogin(scope, subject string, until time.Time) error
+	DeleteLoginFailure(scope, subject string) error
+	DeleteStaleLoginFailures(failedBefore, now time.Time) error
+}
+
+//go:generate mockery --name=ITwoFactorRepository --output=./mocks --outpkg=mocks --filename=ITwoFactorRepository.go
+type ITwoFactorRepository interface {
+	GetTOTPCredential(userID uuid.UUID) (*models.TOTPCredential, error)
+	SaveTOTPCredential(credential *models.TOTPCredential) error
+	ConfirmTOTPCredential(userID uuid.UUID, confirmedAt time.Time, step int64) (bool, error)
+	UseTOTPStep(userID uuid.UUID, step int64) (bool, error)
+	DeleteTOTPCredential(userID uuid.UUID) error
+	ReplaceRecoveryCodes(userID uuid.UUID, codes []models.RecoveryCode) error
+	UseRecoveryCode(userID uuid.UUID, codeHash string, usedAt time.Time) (bool, error)
+	CreateTwoFactorChallenge(challenge *models.TwoFactorChallenge) error
+	GetTwoFactorChallenge(tokenHash string) (*models.TwoFactorChallenge, error)
+	RecordTwoFactorChallengeAttempt(tokenHash string) error
+	DeleteTwoFactorChallenge(tokenHash string) (bool, error)
+	DeleteExpiredTwoFactorChallenges(before time.Time) error
+}
+
+//go:generate mockery --name=IPasskeyRepository --output=./mocks --outpkg=mocks --filename=IPasskeyRepository.go
+type IPasskeyRepository interface {
+	CreateCredential(credential *models.PasskeyCredential) error
+	GetCredentialsByUserID(userID uuid.UUID) ([]models.PasskeyCredential, error)
+	GetCredentialByCredentialID(credentialID []byte) (*models.PasskeyCredential, error)
+	UpdateCredentialUsage(id uuid.UUID, signCount int64, backupState bool, usedAt time.Time) error
+	CreateCeremony(ceremony *models.PasskeyCeremony) error
+	GetCeremony(tokenHash string) (*models.PasskeyCeremony, error)
+	DeleteCeremony(tokenHash string) (bool, error)
+	DeleteExpiredCeremonies(before time.Time) error
+}
+
+//go:generate mockery --name=IExternalIdentityRepository --output=./mocks --outpkg=mocks --filename=IExternalIdentityRepository.go
+type IExternalIdentityRepository interface {
+	CreateExternalIdentity(identity *models.ExternalIdentity) error
+	GetExternalIdentity(issuer, subject string) (*models.ExternalIdentity, error)
+	RecordExternalLogin(id uuid.UUID, email string, at time.Time) error
+	DeleteExternalIdentity(id uuid.UUID) error
+}
+
+//go:generate mockery --name=ISessionRepository --output=./mocks --outpkg=mocks --filename=ISessionRepository.go
+type ISessionRepository interface {
+	CreateSession(session *models.Session) error
+	GetSession(id uuid.UUID) (*models.Session, error)
+	ListUserSessions(userID uuid.UUID, seenAfter time.Time) ([]models.Session, error)
+	UpdateSessionLastSeen(id uuid.UUID, seenAt time.Time) error
+	RevokeSession(userID, id uuid.UUID, revokedAt time.Time) (bool, error)
+	RevokeUserSessions(userID uuid.UUID, revokedAt time.Time) error
 }
 
 //go:generate mockery --name=IDatabase --output=./mocks --outpkg=mocks --filename=IDatabase.go
 type IDatabase interface {
 	Create(value interface{}) IDatabase
+	Clauses(conds ...clause.Expression) IDatabase
 	Where(query interface{}, args ...interface{}) IDatabase
 	First(dest interface{}, conds ...interface{}) IDatabase
 	Model(value interface{}) IDatabase
 	Count(value *int64) IDatabase
+	Find(dest interface{}, conds ...interface{}) IDatabase
+	Order(value interface{}) IDatabase
+	Limit(limit int) IDatabase
+	Update(column string, value interface{}) IDatabase
+	Updates(values interface{}) IDatabase
+	Delete(value interface{}, conds ...interface{}) IDatabase
+	RowsAffected() int64
 	GetError() error
+	Ping(ctx context.Context) error
+	Close() error
 }
 
 // Interface compliance checks - will fail at compile time if interfaces are not implemented
 var _ IUserRepository = (*UserRepository)(nil)
+var _ IAccessTokenRepository = (*AccessTokenRepository)(nil)
+var _ IRefreshTokenRepository = (*RefreshTokenRepository)(nil)
+var _ IRevokedTokenRepository = (*RevokedTokenRepository)(nil)
+var _ ISingleUseTokenRepository = (*SingleUseTokenRepository)(nil)
+var _ IAccountDeletionRepository = (*AccountDeletionRepository)(nil)
+var _ ILoginFailureRepository = (*LoginFailureRepository)(nil)
+var _ ITwoFactorRepository = (*TwoFactorRepository)(nil)
+var _ IPasskeyRepository = (*PasskeyRepository)(nil)
+var _ IExternalIdentityRepository = (*ExternalIdentityRepository)(nil)
+var _ ISessionRepository = (*SessionRepository)(nil)
 var _ IDatabase = (*GormAdapter)(nil)
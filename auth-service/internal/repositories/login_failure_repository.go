@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginFailureRepository struct {
+	DB IDatabase
+}
+
+func NewLoginFailureRepository(db IDatabase) *LoginFailureRepository {
+	return &LoginFailureRepository{DB: db}
+}
+
+func (r *LoginFailureRepository) GetLoginFailure(scope, subject string) (*models.LoginFailure, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var failure models.LoginFailure
+	err := r.DB.Where("scope = ? AND subject = ?", scope, subject).First(&failure).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &failure, nil
+}
+
+// ReserveLoginAttempt counts a login attempt as failed before its outcome is known. current is the
+// record the attempt was admitted by, nil when the subject had none; the attempt is only counted while
+// the record is unchanged, so that concurrent attempts are admitted one at a time.
+// It reports whether the attempt was counted.
+func (r *LoginFailureRepository) ReserveLoginAttempt(scope, subject string, current *models.LoginFailure, at time.Time) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	var result IDatabase
+	if current == nil {
+		result = r.DB.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.LoginFailure{Scope: scope, Subject: subject, Failures: 1, LastFailedAt: at})
+	} else {
+		result = r.DB.Model(&models.LoginFailure{}).
+			Where("scope = ? AND subject = ? AND failures = ?", scope, subject, current.Failures).
+			Updates(map[string]interface{}{
+				"failures":       gorm.Expr("failures + 1"),
+				"last_failed_at": at,
+			})
+	}
+	if err := result.GetError(); err != nil {
+		return false, fmt.Errorf("cannot reserve login attempt for %s %s: %w", scope, subject, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ReleaseLoginAttempt takes back an attempt counted by ReserveLoginAttempt that did not fail
+func (r *LoginFailureRepository) ReleaseLoginAttempt(scope, subject string) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	err := r.DB.Model(&models.LoginFailure{}).
+		Where("scope = ? AND subject = ? AND failures > 0", scope, subject).
+		Update("failures", gorm.Expr("failures - 1")).
+		GetError()
+	if err != nil {
+		return fmt.Errorf("cannot release login attempt for %s %s: %w", scope, subject, err)
+	}
+	return nil
+}
+
+// LockLogin refuses logins for the subject until the given time
+func (r *LoginFailureRepository) LockLogin(scope, subject string, until time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	err := r.DB.Model(&models.LoginFailure{}).
+		Where("scope = ? AND subject = ?", scope, subject).
+		Update("locked_until", until).
+		GetError()
+	if err != nil {
+		return fmt.Errorf("cannot lock login for %s %s: %w", scope, subject, err)
+	}
+	return nil
+}
+
+// DeleteLoginFailure forgets the failed logins of the subject
+func (r *LoginFailureRepository) DeleteLoginFailure(scope, subject string) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Where("scope = ? AND subject = ?", scope, subject).Delete(&models.LoginFailure{}).GetError()
+}
+
+// DeleteStaleLoginFailures purges records whose last failure happened before failedBefore
+// and that are not locked at now
+func (r *LoginFailureRepository) DeleteStaleLoginFailures(failedBefore, now time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until <= ?)", failedBefore, now).
+		Delete(&models.LoginFailure{}).
+		GetError()
+}
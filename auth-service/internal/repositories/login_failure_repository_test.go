@@ -0,0 +1,230 @@
+package repositories_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type LoginFailureRepositoryTestSuite struct {
+	suite.Suite
+	repo  *repositories.LoginFailureRepository
+	email string
+	now   time.Time
+}
+
+func (suite *LoginFailureRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.LoginFailure{}))
+	// Every connection would open another in-memory database
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	suite.repo = repositories.NewLoginFailureRepository(repositories.NewGormAdapterFromDB(db))
+	suite.email = "test@example.com"
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// reserve counts a first failed login of a subject without a record
+func (suite *LoginFailureRepositoryTestSuite) reserve(scope, subject string) {
+	suite.reserveAt(scope, subject, suite.now)
+}
+
+// reserveAt counts a first failed login of a subject without a record at failedAt
+func (suite *LoginFailureRepositoryTestSuite) reserveAt(scope, subject string, failedAt time.Time) {
+	reserved, err := suite.repo.ReserveLoginAttempt(scope, subject, nil, failedAt)
+	suite.Require().NoError(err)
+	suite.Require().True(reserved)
+}
+
+// ===== TESTS =====
+
+func (suite *LoginFailureRepositoryTestSuite) TestReserveLoginAttempt_CountsAttempts() {
+	// Arrange
+	reserved, err := suite.repo.ReserveLoginAttempt(models.LoginScopeAccount, suite.email, nil, suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().True(reserved)
+	current, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+
+	// Act
+	reserved, err = suite.repo.ReserveLoginAttempt(models.LoginScopeAccount, suite.email, current, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(reserved)
+	failure, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	suite.Equal(2, failure.Failures)
+	suite.True(suite.now.Equal(failure.LastFailedAt))
+	suite.Nil(failure.LockedUntil)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestReserveLoginAttempt_RecordChanged() {
+	// Arrange - another attempt was counted since the record was read
+	suite.reserve(models.LoginScopeAccount, suite.email)
+	current, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	reserved, err := suite.repo.ReserveLoginAttempt(models.LoginScopeAccount, suite.email, current, suite.now)
+	suite.Require().NoError(err)
+	suite.Require().True(reserved)
+
+	// Act
+	reservedStale, errStale := suite.repo.ReserveLoginAttempt(models.LoginScopeAccount, suite.email, current, suite.now)
+	reservedMissing, errMissing := suite.repo.ReserveLoginAttempt(models.LoginScopeAccount, suite.email, nil, suite.now)
+
+	// Assert
+	suite.Require().NoError(errStale)
+	suite.False(reservedStale)
+	suite.Require().NoError(errMissing)
+	suite.False(reservedMissing)
+	failure, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	suite.Equal(2, failure.Failures)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestReserveLoginAttempt_Concurrent() {
+	// Arrange
+	const logins = 20
+	reservations := make(chan bool, logins)
+	var wg sync.WaitGroup
+
+	// Act - every attempt was admitted by the same record
+	for i := 0; i < logins; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reserved, err := suite.repo.ReserveLoginAttempt(models.LoginScopeAccount, suite.email, nil, suite.now)
+			suite.NoError(err)
+			reservations <- reserved
+		}()
+	}
+	wg.Wait()
+	close(reservations)
+
+	// Assert - only one of them is counted
+	counted := 0
+	for reserved := range reservations {
+		if reserved {
+			counted++
+		}
+	}
+	suite.Equal(1, counted)
+	failure, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	suite.Equal(1, failure.Failures)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestReserveLoginAttempt_ScopesAreSeparate() {
+	// Arrange
+	suite.reserve(models.LoginScopeAccount, suite.email)
+
+	// Act
+	reserved, err := suite.repo.ReserveLoginAttempt(models.LoginScopeIP, "192.0.2.1", nil, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(reserved)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestReleaseLoginAttempt() {
+	// Arrange
+	suite.reserve(models.LoginScopeAccount, suite.email)
+
+	// Act
+	err := suite.repo.ReleaseLoginAttempt(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	err = suite.repo.ReleaseLoginAttempt(models.LoginScopeAccount, suite.email)
+
+	// Assert - the count does not go below zero
+	suite.Require().NoError(err)
+	failure, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	suite.Zero(failure.Failures)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestGetLoginFailure_NotFound() {
+	// Act
+	_, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestLockLogin() {
+	// Arrange
+	suite.reserve(models.LoginScopeAccount, suite.email)
+	until := suite.now.Add(15 * time.Minute)
+
+	// Act
+	err := suite.repo.LockLogin(models.LoginScopeAccount, suite.email, until)
+
+	// Assert
+	suite.Require().NoError(err)
+	failure, err := suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(failure.LockedUntil)
+	suite.True(until.Equal(*failure.LockedUntil))
+	suite.True(failure.IsLocked(suite.now))
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestDeleteLoginFailure() {
+	// Arrange
+	suite.reserve(models.LoginScopeAccount, suite.email)
+
+	// Act
+	err := suite.repo.DeleteLoginFailure(models.LoginScopeAccount, suite.email)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestDeleteStaleLoginFailures_KeepsRecentAndLocked() {
+	// Arrange
+	old := suite.now.Add(-2 * time.Hour)
+	suite.reserveAt(models.LoginScopeAccount, "stale@example.com", old)
+	suite.reserveAt(models.LoginScopeAccount, "locked@example.com", old)
+	suite.Require().NoError(suite.repo.LockLogin(models.LoginScopeAccount, "locked@example.com", suite.now.Add(time.Minute)))
+	suite.reserve(models.LoginScopeAccount, suite.email)
+
+	// Act
+	err := suite.repo.DeleteStaleLoginFailures(suite.now.Add(-time.Hour), suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetLoginFailure(models.LoginScopeAccount, "stale@example.com")
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	_, err = suite.repo.GetLoginFailure(models.LoginScopeAccount, "locked@example.com")
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+}
+
+func (suite *LoginFailureRepositoryTestSuite) TestNilDatabase() {
+	// Arrange
+	repo := repositories.NewLoginFailureRepository(nil)
+
+	// Act
+	_, err := repo.ReserveLoginAttempt(models.LoginScopeAccount, suite.email, nil, suite.now)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// Run tests
+func TestLoginFailureRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginFailureRepositoryTestSuite))
+}
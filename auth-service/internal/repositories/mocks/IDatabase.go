@@ -3,8 +3,13 @@
 package mocks
 
 import (
-	repositories "github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	context "context"
+
+	clause "gorm.io/gorm/clause"
+
 	mock "github.com/stretchr/testify/mock"
+
+	repositories "github.com/Koshsky/subs-service/auth-service/internal/repositories"
 )
 
 // IDatabase is an autogenerated mock type for the IDatabase type
@@ -12,6 +17,50 @@ type IDatabase struct {
 	mock.Mock
 }
 
+// Clauses provides a mock function with given fields: conds
+func (_m *IDatabase) Clauses(conds ...clause.Expression) repositories.IDatabase {
+	_va := make([]interface{}, len(conds))
+	for _i := range conds {
+		_va[_i] = conds[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Clauses")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(...clause.Expression) repositories.IDatabase); ok {
+		r0 = rf(conds...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Close provides a mock function with no fields
+func (_m *IDatabase) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Count provides a mock function with given fields: value
 func (_m *IDatabase) Count(value *int64) repositories.IDatabase {
 	ret := _m.Called(value)
@@ -52,6 +101,52 @@ func (_m *IDatabase) Create(value interface{}) repositories.IDatabase {
 	return r0
 }
 
+// Delete provides a mock function with given fields: value, conds
+func (_m *IDatabase) Delete(value interface{}, conds ...interface{}) repositories.IDatabase {
+	var _ca []interface{}
+	_ca = append(_ca, value)
+	_ca = append(_ca, conds...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(interface{}, ...interface{}) repositories.IDatabase); ok {
+		r0 = rf(value, conds...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: dest, conds
+func (_m *IDatabase) Find(dest interface{}, conds ...interface{}) repositories.IDatabase {
+	var _ca []interface{}
+	_ca = append(_ca, dest)
+	_ca = append(_ca, conds...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(interface{}, ...interface{}) repositories.IDatabase); ok {
+		r0 = rf(dest, conds...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
 // First provides a mock function with given fields: dest, conds
 func (_m *IDatabase) First(dest interface{}, conds ...interface{}) repositories.IDatabase {
 	var _ca []interface{}
@@ -93,6 +188,26 @@ func (_m *IDatabase) GetError() error {
 	return r0
 }
 
+// Limit provides a mock function with given fields: limit
+func (_m *IDatabase) Limit(limit int) repositories.IDatabase {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Limit")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(int) repositories.IDatabase); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
 // Model provides a mock function with given fields: value
 func (_m *IDatabase) Model(value interface{}) repositories.IDatabase {
 	ret := _m.Called(value)
@@ -113,6 +228,102 @@ func (_m *IDatabase) Model(value interface{}) repositories.IDatabase {
 	return r0
 }
 
+// Order provides a mock function with given fields: value
+func (_m *IDatabase) Order(value interface{}) repositories.IDatabase {
+	ret := _m.Called(value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Order")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(interface{}) repositories.IDatabase); ok {
+		r0 = rf(value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *IDatabase) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RowsAffected provides a mock function with no fields
+func (_m *IDatabase) RowsAffected() int64 {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RowsAffected")
+	}
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: column, value
+func (_m *IDatabase) Update(column string, value interface{}) repositories.IDatabase {
+	ret := _m.Called(column, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(string, interface{}) repositories.IDatabase); ok {
+		r0 = rf(column, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Updates provides a mock function with given fields: values
+func (_m *IDatabase) Updates(values interface{}) repositories.IDatabase {
+	ret := _m.Called(values)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Updates")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(interface{}) repositories.IDatabase); ok {
+		r0 = rf(values)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
 // Where provides a mock function with given fields: query, args
 func (_m *IDatabase) Where(query interface{}, args ...interface{}) repositories.IDatabase {
 	var _ca []interface{}
@@ -5,6 +5,10 @@ package mocks
 import (
 	models "github.com/Koshsky/subs-service/auth-service/internal/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
 )
 
 // IUserRepository is an autogenerated mock type for the IUserRepository type
@@ -30,6 +34,24 @@ func (_m *IUserRepository) CreateUser(user *models.User) error {
 	return r0
 }
 
+// DeleteUser provides a mock function with given fields: id
+func (_m *IUserRepository) DeleteUser(id uuid.UUID) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetUserByEmail provides a mock function with given fields: email
 func (_m *IUserRepository) GetUserByEmail(email string) (*models.User, error) {
 	ret := _m.Called(email)
@@ -60,6 +82,184 @@ func (_m *IUserRepository) GetUserByEmail(email string) (*models.User, error) {
 	return r0, r1
 }
 
+// GetUserByID provides a mock function with given fields: id
+func (_m *IUserRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID) (*models.User, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uuid.UUID) *models.User); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uuid.UUID) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementTokenVersion provides a mock function with given fields: id
+func (_m *IUserRepository) IncrementTokenVersion(id uuid.UUID) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementTokenVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListUsersAfter provides a mock function with given fields: afterID, limit
+func (_m *IUserRepository) ListUsersAfter(afterID uuid.UUID, limit int) ([]models.User, error) {
+	ret := _m.Called(afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsersAfter")
+	}
+
+	var r0 []models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, int) ([]models.User, error)); ok {
+		return rf(afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(uuid.UUID, int) []models.User); ok {
+		r0 = rf(afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uuid.UUID, int) error); ok {
+		r1 = rf(afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkEmailVerified provides a mock function with given fields: id, verifiedAt
+func (_m *IUserRepository) MarkEmailVerified(id uuid.UUID, verifiedAt time.Time) error {
+	ret := _m.Called(id, verifiedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, time.Time) error); ok {
+		r0 = rf(id, verifiedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RehashPassword provides a mock function with given fields: id, currentHash, newHash
+func (_m *IUserRepository) RehashPassword(id uuid.UUID, currentHash string, newHash string) (bool, error) {
+	ret := _m.Called(id, currentHash, newHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RehashPassword")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string, string) (bool, error)); ok {
+		return rf(id, currentHash, newHash)
+	}
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string, string) bool); ok {
+		r0 = rf(id, currentHash, newHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uuid.UUID, string, string) error); ok {
+		r1 = rf(id, currentHash, newHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateEmail provides a mock function with given fields: id, email, emailNormalized, verifiedAt
+func (_m *IUserRepository) UpdateEmail(id uuid.UUID, email string, emailNormalized string, verifiedAt time.Time) error {
+	ret := _m.Called(id, email, emailNormalized, verifiedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string, string, time.Time) error); ok {
+		r0 = rf(id, email, emailNormalized, verifiedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEmailNormalized provides a mock function with given fields: id, emailNormalized
+func (_m *IUserRepository) UpdateEmailNormalized(id uuid.UUID, emailNormalized string) error {
+	ret := _m.Called(id, emailNormalized)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmailNormalized")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) error); ok {
+		r0 = rf(id, emailNormalized)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: id, passwordHash
+func (_m *IUserRepository) UpdatePassword(id uuid.UUID, passwordHash string) error {
+	ret := _m.Called(id, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, string) error); ok {
+		r0 = rf(id, passwordHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UserExists provides a mock function with given fields: email
 func (_m *IUserRepository) UserExists(email string) (bool, error) {
 	ret := _m.Called(email)
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type PasskeyRepository struct {
+	DB IDatabase
+}
+
+func NewPasskeyRepository(db IDatabase) *PasskeyRepository {
+	return &PasskeyRepository{DB: db}
+}
+
+func (r *PasskeyRepository) CreateCredential(credential *models.PasskeyCredential) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if credential.ID == uuid.Nil {
+		credential.ID = uuid.New()
+	}
+	if err := r.DB.Create(credential).GetError(); err != nil {
+		return fmt.Errorf("cannot create passkey for user_id=%s: %w", credential.UserID, err)
+	}
+	return nil
+}
+
+// GetCredentialsByUserID returns the passkeys of the user, oldest first
+func (r *PasskeyRepository) GetCredentialsByUserID(userID uuid.UUID) ([]models.PasskeyCredential, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var credentials []models.PasskeyCredential
+	err := r.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&credentials).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// GetCredentialByCredentialID finds a passkey by the ID the authenticator chose for it
+func (r *PasskeyRepository) GetCredentialByCredentialID(credentialID []byte) (*models.PasskeyCredential, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var credential models.PasskeyCredential
+	err := r.DB.Where("credential_id = ?", credentialID).First(&credential).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// UpdateCredentialUsage records a login with the passkey and the state the authenticator reported
+func (r *PasskeyRepository) UpdateCredentialUsage(id uuid.UUID, signCount int64, backupState bool, usedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.PasskeyCredential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":   signCount,
+			"backup_state": backupState,
+			"last_used_at": usedAt,
+		}).
+		GetError()
+}
+
+func (r *PasskeyRepository) CreateCeremony(ceremony *models.PasskeyCeremony) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := r.DB.Create(ceremony).GetError(); err != nil {
+		return fmt.Errorf("cannot create passkey %s ceremony: %w", ceremony.Kind, err)
+	}
+	return nil
+}
+
+func (r *PasskeyRepository) GetCeremony(tokenHash string) (*models.PasskeyCeremony, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var ceremony models.PasskeyCeremony
+	err := r.DB.Where("token_hash = ?", tokenHash).First(&ceremony).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &ceremony, nil
+}
+
+// DeleteCeremony deletes the ceremony.
+// It reports false if it was already deleted, so that a ceremony is finished only once.
+func (r *PasskeyRepository) DeleteCeremony(tokenHash string) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Where("token_hash = ?", tokenHash).Delete(&models.PasskeyCeremony{})
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// DeleteExpiredCeremonies purges ceremonies that expired before the given time
+func (r *PasskeyRepository) DeleteExpiredCeremonies(before time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Where("expires_at < ?", before).Delete(&models.PasskeyCeremony{}).GetError()
+}
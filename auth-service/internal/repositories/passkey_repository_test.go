@@ -0,0 +1,173 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PasskeyRepositoryTestSuite struct {
+	suite.Suite
+	repo   *repositories.PasskeyRepository
+	userID uuid.UUID
+	now    time.Time
+}
+
+func (suite *PasskeyRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.PasskeyCredential{}, &models.PasskeyCeremony{}))
+
+	suite.repo = repositories.NewPasskeyRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// createCredential stores a passkey of the suite user
+func (suite *PasskeyRepositoryTestSuite) createCredential(credentialID string, createdAt time.Time) *models.PasskeyCredential {
+	credential := &models.PasskeyCredential{
+		UserID:       suite.userID,
+		CredentialID: []byte(credentialID),
+		PublicKey:    []byte("public-key"),
+		Transports:   "internal,hybrid",
+		CreatedAt:    createdAt,
+	}
+	suite.Require().NoError(suite.repo.CreateCredential(credential))
+	return credential
+}
+
+// createCeremony stores a ceremony expiring at expiresAt
+func (suite *PasskeyRepositoryTestSuite) createCeremony(expiresAt time.Time) *models.PasskeyCeremony {
+	ceremony := &models.PasskeyCeremony{
+		TokenHash:   uuid.NewString(),
+		Kind:        models.PasskeyCeremonyLogin,
+		SessionData: `{"challenge":"abc"}`,
+		CreatedAt:   suite.now,
+		ExpiresAt:   expiresAt,
+	}
+	suite.Require().NoError(suite.repo.CreateCeremony(ceremony))
+	return ceremony
+}
+
+// ===== CREDENTIAL TESTS =====
+
+func (suite *PasskeyRepositoryTestSuite) TestCreateCredential_AssignsID() {
+	// Act
+	credential := suite.createCredential("credential-1", suite.now)
+
+	// Assert
+	suite.NotEqual(uuid.Nil, credential.ID)
+	found, err := suite.repo.GetCredentialByCredentialID([]byte("credential-1"))
+	suite.Require().NoError(err)
+	suite.Equal(credential.ID, found.ID)
+	suite.Equal(suite.userID, found.UserID)
+	suite.Equal([]string{"internal", "hybrid"}, found.TransportList())
+}
+
+func (suite *PasskeyRepositoryTestSuite) TestGetCredentialByCredentialID_NotFound() {
+	// Act
+	credential, err := suite.repo.GetCredentialByCredentialID([]byte("unknown"))
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(credential)
+}
+
+func (suite *PasskeyRepositoryTestSuite) TestGetCredentialsByUserID_OldestFirst() {
+	// Arrange
+	newer := suite.createCredential("credential-2", suite.now)
+	older := suite.createCredential("credential-1", suite.now.Add(-time.Hour))
+	otherUser := uuid.New()
+	suite.Require().NoError(suite.repo.CreateCredential(&models.PasskeyCredential{
+		UserID:       otherUser,
+		CredentialID: []byte("credential-3"),
+		PublicKey:    []byte("public-key"),
+		CreatedAt:    suite.now,
+	}))
+
+	// Act
+	credentials, err := suite.repo.GetCredentialsByUserID(suite.userID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(credentials, 2)
+	suite.Equal(older.ID, credentials[0].ID)
+	suite.Equal(newer.ID, credentials[1].ID)
+}
+
+func (suite *PasskeyRepositoryTestSuite) TestUpdateCredentialUsage() {
+	// Arrange
+	credential := suite.createCredential("credential-1", suite.now)
+
+	// Act
+	err := suite.repo.UpdateCredentialUsage(credential.ID, 7, true, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	found, err := suite.repo.GetCredentialByCredentialID([]byte("credential-1"))
+	suite.Require().NoError(err)
+	suite.Equal(int64(7), found.SignCount)
+	suite.True(found.BackupState)
+	suite.Require().NotNil(found.LastUsedAt)
+	suite.True(suite.now.Equal(*found.LastUsedAt))
+}
+
+// ===== CEREMONY TESTS =====
+
+func (suite *PasskeyRepositoryTestSuite) TestGetCeremony() {
+	// Arrange
+	ceremony := suite.createCeremony(suite.now.Add(time.Minute))
+
+	// Act
+	found, err := suite.repo.GetCeremony(ceremony.TokenHash)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(models.PasskeyCeremonyLogin, found.Kind)
+	suite.Nil(found.UserID)
+	suite.Equal(`{"challenge":"abc"}`, found.SessionData)
+}
+
+func (suite *PasskeyRepositoryTestSuite) TestDeleteCeremony_OnlyOnce() {
+	// Arrange
+	ceremony := suite.createCeremony(suite.now.Add(time.Minute))
+
+	// Act
+	first, err := suite.repo.DeleteCeremony(ceremony.TokenHash)
+	suite.Require().NoError(err)
+	second, err := suite.repo.DeleteCeremony(ceremony.TokenHash)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(first)
+	suite.False(second)
+}
+
+func (suite *PasskeyRepositoryTestSuite) TestDeleteExpiredCeremonies() {
+	// Arrange
+	expired := suite.createCeremony(suite.now.Add(-time.Minute))
+	active := suite.createCeremony(suite.now.Add(time.Minute))
+
+	// Act
+	err := suite.repo.DeleteExpiredCeremonies(suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetCeremony(expired.TokenHash)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	_, err = suite.repo.GetCeremony(active.TokenHash)
+	suite.NoError(err)
+}
+
+// Run tests
+func TestPasskeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PasskeyRepositoryTestSuite))
+}
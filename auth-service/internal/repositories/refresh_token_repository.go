@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type RefreshTokenRepository struct {
+	DB IDatabase
+}
+
+func NewRefreshTokenRepository(db IDatabase) *RefreshTokenRepository {
+	return &RefreshTokenRepository{DB: db}
+}
+
+func (r *RefreshTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+
+	if err := r.DB.Create(token).GetError(); err != nil {
+		return fmt.Errorf("cannot create refresh token for user_id=%s: %w", token.UserID, err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var token models.RefreshToken
+	err := r.DB.Where("token_hash = ?", tokenHash).First(&token).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed marks an active token as exchanged.
+// It reports false if the token was already used or revoked, so that of two
+// concurrent refreshes with the same token only one succeeds.
+func (r *RefreshTokenRepository) MarkRefreshTokenUsed(tokenID uuid.UUID, usedAt time.Time) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", tokenID).
+		Update("used_at", usedAt)
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// RevokeRefreshTokenFamily revokes every token issued from the same login
+func (r *RefreshTokenRepository) RevokeRefreshTokenFamily(familyID uuid.UUID, revokedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).
+		GetError()
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user
+func (r *RefreshTokenRepository) RevokeUserRefreshTokens(userID uuid.UUID, revokedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).
+		GetError()
+}
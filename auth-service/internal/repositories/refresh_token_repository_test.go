@@ -0,0 +1,159 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepositoryTestSuite struct {
+	suite.Suite
+	repo     *repositories.RefreshTokenRepository
+	userID   uuid.UUID
+	familyID uuid.UUID
+	now      time.Time
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.RefreshToken{}))
+
+	suite.repo = repositories.NewRefreshTokenRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.familyID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// createToken stores an active token of the given family
+func (suite *RefreshTokenRepositoryTestSuite) createToken(familyID uuid.UUID) *models.RefreshToken {
+	token := &models.RefreshToken{
+		UserID:    suite.userID,
+		FamilyID:  familyID,
+		TokenHash: uuid.NewString(),
+		CreatedAt: suite.now,
+		ExpiresAt: suite.now.Add(time.Hour),
+	}
+	suite.Require().NoError(suite.repo.CreateRefreshToken(token))
+	return token
+}
+
+// reload reads the stored state of token
+func (suite *RefreshTokenRepositoryTestSuite) reload(token *models.RefreshToken) *models.RefreshToken {
+	found, err := suite.repo.GetRefreshTokenByHash(token.TokenHash)
+	suite.Require().NoError(err)
+	return found
+}
+
+// ===== TESTS =====
+
+func (suite *RefreshTokenRepositoryTestSuite) TestCreateAndGetRefreshToken() {
+	// Arrange
+	created := suite.createToken(suite.familyID)
+
+	// Act
+	found, err := suite.repo.GetRefreshTokenByHash(created.TokenHash)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEqual(uuid.Nil, created.ID)
+	suite.Equal(created.ID, found.ID)
+	suite.Equal(suite.familyID, found.FamilyID)
+	suite.True(found.IsActive(suite.now))
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestGetRefreshTokenByHash_NotFound() {
+	// Act
+	found, err := suite.repo.GetRefreshTokenByHash("missing")
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(found)
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestMarkRefreshTokenUsed_OnlyOnce() {
+	// Arrange
+	token := suite.createToken(suite.familyID)
+
+	// Act
+	first, err := suite.repo.MarkRefreshTokenUsed(token.ID, suite.now)
+	suite.Require().NoError(err)
+	second, err := suite.repo.MarkRefreshTokenUsed(token.ID, suite.now.Add(time.Second))
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(first)
+	suite.False(second)
+	found := suite.reload(token)
+	suite.Require().NotNil(found.UsedAt)
+	suite.True(suite.now.Equal(*found.UsedAt))
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestRevokeRefreshTokenFamily() {
+	// Arrange
+	used := suite.createToken(suite.familyID)
+	_, err := suite.repo.MarkRefreshTokenUsed(used.ID, suite.now)
+	suite.Require().NoError(err)
+	current := suite.createToken(suite.familyID)
+	other := suite.createToken(uuid.New())
+
+	// Act
+	err = suite.repo.RevokeRefreshTokenFamily(suite.familyID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotNil(suite.reload(used).RevokedAt)
+	suite.NotNil(suite.reload(current).RevokedAt)
+	suite.Nil(suite.reload(other).RevokedAt)
+	marked, err := suite.repo.MarkRefreshTokenUsed(current.ID, suite.now)
+	suite.Require().NoError(err)
+	suite.False(marked, "revoked tokens cannot be exchanged")
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestRevokeUserRefreshTokens() {
+	// Arrange
+	first := suite.createToken(suite.familyID)
+	second := suite.createToken(uuid.New())
+	otherUsers := &models.RefreshToken{
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: uuid.NewString(),
+		CreatedAt: suite.now,
+		ExpiresAt: suite.now.Add(time.Hour),
+	}
+	suite.Require().NoError(suite.repo.CreateRefreshToken(otherUsers))
+
+	// Act
+	err := suite.repo.RevokeUserRefreshTokens(suite.userID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotNil(suite.reload(first).RevokedAt)
+	suite.NotNil(suite.reload(second).RevokedAt)
+	suite.Nil(suite.reload(otherUsers).RevokedAt)
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestNilDatabase() {
+	// Arrange
+	repo := &repositories.RefreshTokenRepository{DB: nil}
+
+	// Act
+	err := repo.CreateRefreshToken(&models.RefreshToken{})
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// Run tests
+func TestRefreshTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRepositoryTestSuite))
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type RevokedTokenRepository struct {
+	DB IDatabase
+}
+
+func NewRevokedTokenRepository(db IDatabase) *RevokedTokenRepository {
+	return &RevokedTokenRepository{DB: db}
+}
+
+func (r *RevokedTokenRepository) RevokeToken(token *models.RevokedToken) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := r.DB.Create(token).GetError(); err != nil {
+		return fmt.Errorf("cannot revoke token jti=%s: %w", token.JTI, err)
+	}
+	return nil
+}
+
+func (r *RevokedTokenRepository) IsTokenRevoked(jti uuid.UUID) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	var count int64
+	err := r.DB.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).GetError()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpiredRevokedTokens purges records of tokens that have expired anyway
+func (r *RevokedTokenRepository) DeleteExpiredRevokedTokens(before time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Where("expires_at < ?", before).Delete(&models.RevokedToken{}).GetError()
+}
@@ -0,0 +1,112 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type RevokedTokenRepositoryTestSuite struct {
+	suite.Suite
+	repo *repositories.RevokedTokenRepository
+	now  time.Time
+}
+
+func (suite *RevokedTokenRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.RevokedToken{}))
+
+	suite.repo = repositories.NewRevokedTokenRepository(repositories.NewGormAdapterFromDB(db))
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// revoke stores a revoked token expiring at expiresAt
+func (suite *RevokedTokenRepositoryTestSuite) revoke(expiresAt time.Time) *models.RevokedToken {
+	token := &models.RevokedToken{
+		JTI:       uuid.New(),
+		UserID:    uuid.New(),
+		ExpiresAt: expiresAt,
+		RevokedAt: suite.now,
+	}
+	suite.Require().NoError(suite.repo.RevokeToken(token))
+	return token
+}
+
+// ===== TESTS =====
+
+func (suite *RevokedTokenRepositoryTestSuite) TestRevokeToken() {
+	// Arrange
+	token := suite.revoke(suite.now.Add(time.Minute))
+
+	// Act
+	revoked, err := suite.repo.IsTokenRevoked(token.JTI)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(revoked)
+}
+
+func (suite *RevokedTokenRepositoryTestSuite) TestIsTokenRevoked_Unknown() {
+	// Act
+	revoked, err := suite.repo.IsTokenRevoked(uuid.New())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(revoked)
+}
+
+func (suite *RevokedTokenRepositoryTestSuite) TestRevokeToken_Twice() {
+	// Arrange
+	token := suite.revoke(suite.now.Add(time.Minute))
+
+	// Act
+	err := suite.repo.RevokeToken(&models.RevokedToken{JTI: token.JTI, UserID: token.UserID, ExpiresAt: token.ExpiresAt})
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *RevokedTokenRepositoryTestSuite) TestDeleteExpiredRevokedTokens() {
+	// Arrange
+	expired := suite.revoke(suite.now.Add(-time.Minute))
+	active := suite.revoke(suite.now.Add(time.Minute))
+
+	// Act
+	err := suite.repo.DeleteExpiredRevokedTokens(suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	revoked, err := suite.repo.IsTokenRevoked(expired.JTI)
+	suite.Require().NoError(err)
+	suite.False(revoked)
+	revoked, err = suite.repo.IsTokenRevoked(active.JTI)
+	suite.Require().NoError(err)
+	suite.True(revoked)
+}
+
+func (suite *RevokedTokenRepositoryTestSuite) TestNilDatabase() {
+	// Arrange
+	repo := &repositories.RevokedTokenRepository{DB: nil}
+
+	// Act
+	revoked, err := repo.IsTokenRevoked(uuid.New())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.False(revoked)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// Run tests
+func TestRevokedTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RevokedTokenRepositoryTestSuite))
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type SessionRepository struct {
+	DB IDatabase
+}
+
+func NewSessionRepository(db IDatabase) *SessionRepository {
+	return &SessionRepository{DB: db}
+}
+
+func (r *SessionRepository) CreateSession(session *models.Session) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if err := r.DB.Create(session).GetError(); err != nil {
+		return fmt.Errorf("cannot create session for user_id=%s: %w", session.UserID, err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) GetSession(id uuid.UUID) (*models.Session, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var session models.Session
+	err := r.DB.Where("id = ?", id).First(&session).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListUserSessions returns the sessions of a user that were not revoked and were seen
+// after seenAfter, most recently seen first
+func (r *SessionRepository) ListUserSessions(userID uuid.UUID, seenAfter time.Time) ([]models.Session, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var sessions []models.Session
+	err := r.DB.Where("user_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userID, seenAfter).
+		Order("last_seen_at DESC").
+		Find(&sessions).
+		GetError()
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepository) UpdateSessionLastSeen(id uuid.UUID, seenAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.Session{}).Where("id = ?", id).Update("last_seen_at", seenAt).GetError()
+}
+
+// RevokeSession marks a session of the user as revoked.
+// It reports false if the user has no such active session.
+func (r *SessionRepository) RevokeSession(userID, id uuid.UUID, revokedAt time.Time) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// RevokeUserSessions revokes every session of the user
+func (r *SessionRepository) RevokeUserSessions(userID uuid.UUID, revokedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).
+		GetError()
+}
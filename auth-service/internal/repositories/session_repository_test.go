@@ -0,0 +1,207 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type SessionRepositoryTestSuite struct {
+	suite.Suite
+	repo   *repositories.SessionRepository
+	userID uuid.UUID
+	now    time.Time
+}
+
+func (suite *SessionRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.Session{}))
+
+	suite.repo = repositories.NewSessionRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// createSession stores an active session of userID last seen at lastSeenAt
+func (suite *SessionRepositoryTestSuite) createSession(userID uuid.UUID, lastSeenAt time.Time) *models.Session {
+	session := &models.Session{
+		UserID:     userID,
+		UserAgent:  "Mozilla/5.0",
+		IP:         "203.0.113.7",
+		CreatedAt:  lastSeenAt,
+		LastSeenAt: lastSeenAt,
+	}
+	suite.Require().NoError(suite.repo.CreateSession(session))
+	return session
+}
+
+// ===== TESTS =====
+
+func (suite *SessionRepositoryTestSuite) TestCreateSession_GeneratesID() {
+	// Act
+	session := suite.createSession(suite.userID, suite.now)
+
+	// Assert
+	suite.NotEqual(uuid.Nil, session.ID)
+}
+
+func (suite *SessionRepositoryTestSuite) TestCreateSession_KeepsID() {
+	// Arrange
+	id := uuid.New()
+
+	// Act
+	err := suite.repo.CreateSession(&models.Session{ID: id, UserID: suite.userID, CreatedAt: suite.now, LastSeenAt: suite.now})
+
+	// Assert
+	suite.Require().NoError(err)
+	found, err := suite.repo.GetSession(id)
+	suite.Require().NoError(err)
+	suite.Equal(suite.userID, found.UserID)
+}
+
+func (suite *SessionRepositoryTestSuite) TestGetSession() {
+	// Arrange
+	created := suite.createSession(suite.userID, suite.now)
+
+	// Act
+	found, err := suite.repo.GetSession(created.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.userID, found.UserID)
+	suite.Equal("Mozilla/5.0", found.UserAgent)
+	suite.Equal("203.0.113.7", found.IP)
+	suite.Nil(found.RevokedAt)
+}
+
+func (suite *SessionRepositoryTestSuite) TestGetSession_NotFound() {
+	// Act
+	found, err := suite.repo.GetSession(uuid.New())
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(found)
+}
+
+func (suite *SessionRepositoryTestSuite) TestListUserSessions_RecentlySeenFirstWithoutRevokedAndIdle() {
+	// Arrange
+	older := suite.createSession(suite.userID, suite.now.Add(-time.Hour))
+	newer := suite.createSession(suite.userID, suite.now)
+	revoked := suite.createSession(suite.userID, suite.now)
+	suite.createSession(suite.userID, suite.now.Add(-48*time.Hour))
+	suite.createSession(uuid.New(), suite.now)
+	_, err := suite.repo.RevokeSession(suite.userID, revoked.ID, suite.now)
+	suite.Require().NoError(err)
+
+	// Act
+	sessions, err := suite.repo.ListUserSessions(suite.userID, suite.now.Add(-24*time.Hour))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(sessions, 2)
+	suite.Equal(newer.ID, sessions[0].ID)
+	suite.Equal(older.ID, sessions[1].ID)
+}
+
+func (suite *SessionRepositoryTestSuite) TestUpdateSessionLastSeen() {
+	// Arrange
+	session := suite.createSession(suite.userID, suite.now)
+	seenAt := suite.now.Add(time.Minute)
+
+	// Act
+	err := suite.repo.UpdateSessionLastSeen(session.ID, seenAt)
+
+	// Assert
+	suite.Require().NoError(err)
+	found, err := suite.repo.GetSession(session.ID)
+	suite.Require().NoError(err)
+	suite.True(seenAt.Equal(found.LastSeenAt))
+}
+
+func (suite *SessionRepositoryTestSuite) TestRevokeSession() {
+	// Arrange
+	session := suite.createSession(suite.userID, suite.now)
+
+	// Act
+	revoked, err := suite.repo.RevokeSession(suite.userID, session.ID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(revoked)
+	found, err := suite.repo.GetSession(session.ID)
+	suite.Require().NoError(err)
+	suite.NotNil(found.RevokedAt)
+}
+
+func (suite *SessionRepositoryTestSuite) TestRevokeSession_OtherUsersSession() {
+	// Arrange
+	session := suite.createSession(uuid.New(), suite.now)
+
+	// Act
+	revoked, err := suite.repo.RevokeSession(suite.userID, session.ID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(revoked)
+}
+
+func (suite *SessionRepositoryTestSuite) TestRevokeSession_AlreadyRevoked() {
+	// Arrange
+	session := suite.createSession(suite.userID, suite.now)
+	_, err := suite.repo.RevokeSession(suite.userID, session.ID, suite.now)
+	suite.Require().NoError(err)
+
+	// Act
+	revoked, err := suite.repo.RevokeSession(suite.userID, session.ID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(revoked)
+}
+
+func (suite *SessionRepositoryTestSuite) TestRevokeUserSessions() {
+	// Arrange
+	first := suite.createSession(suite.userID, suite.now)
+	second := suite.createSession(suite.userID, suite.now)
+	foreign := suite.createSession(uuid.New(), suite.now)
+
+	// Act
+	err := suite.repo.RevokeUserSessions(suite.userID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	for _, id := range []uuid.UUID{first.ID, second.ID} {
+		found, err := suite.repo.GetSession(id)
+		suite.Require().NoError(err)
+		suite.NotNil(found.RevokedAt)
+	}
+	found, err := suite.repo.GetSession(foreign.ID)
+	suite.Require().NoError(err)
+	suite.Nil(found.RevokedAt)
+}
+
+func (suite *SessionRepositoryTestSuite) TestNilDatabase() {
+	// Arrange
+	repo := &repositories.SessionRepository{DB: nil}
+
+	// Act
+	err := repo.CreateSession(&models.Session{})
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// Run tests
+func TestSessionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionRepositoryTestSuite))
+}
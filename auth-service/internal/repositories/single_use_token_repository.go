@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// SingleUseTokenRepository stores the tokens sent by email, such as password reset tokens.
+// Lookups and limits are per purpose, so a token of one kind never works for another.
+type SingleUseTokenRepository struct {
+	DB IDatabase
+}
+
+func NewSingleUseTokenRepository(db IDatabase) *SingleUseTokenRepository {
+	return &SingleUseTokenRepository{DB: db}
+}
+
+func (r *SingleUseTokenRepository) CreateSingleUseToken(token *models.SingleUseToken) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+
+	if err := r.DB.Create(token).GetError(); err != nil {
+		return fmt.Errorf("cannot create %s token for user_id=%s: %w", token.Purpose, token.UserID, err)
+	}
+	return nil
+}
+
+func (r *SingleUseTokenRepository) GetSingleUseTokenByHash(purpose, tokenHash string) (*models.SingleUseToken, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var token models.SingleUseToken
+	err := r.DB.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// CountSingleUseTokensSince counts the tokens issued to the user for purpose since the given time
+func (r *SingleUseTokenRepository) CountSingleUseTokensSince(purpose string, userID uuid.UUID, since time.Time) (int64, error) {
+	if r.DB == nil {
+		return 0, errors.New("database connection is not initialized")
+	}
+
+	var count int64
+	err := r.DB.Model(&models.SingleUseToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at >= ?", userID, purpose, since).
+		Count(&count).
+		GetError()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkSingleUseTokenUsed marks an unused token as used.
+// It reports false if the token was already used, so that a token works only once.
+func (r *SingleUseTokenRepository) MarkSingleUseTokenUsed(tokenID uuid.UUID, usedAt time.Time) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Model(&models.SingleUseToken{}).
+		Where("id = ? AND used_at IS NULL", tokenID).
+		Update("used_at", usedAt)
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// InvalidateUserSingleUseTokens marks every unused token of the user for purpose as used
+func (r *SingleUseTokenRepository) InvalidateUserSingleUseTokens(purpose string, userID uuid.UUID, usedAt time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.SingleUseToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", usedAt).
+		GetError()
+}
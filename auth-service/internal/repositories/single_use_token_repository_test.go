@@ -0,0 +1,161 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type SingleUseTokenRepositoryTestSuite struct {
+	suite.Suite
+	repo   *repositories.SingleUseTokenRepository
+	userID uuid.UUID
+	now    time.Time
+}
+
+func (suite *SingleUseTokenRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.SingleUseToken{}))
+
+	suite.repo = repositories.NewSingleUseTokenRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// createToken stores an unused password reset token of the user created at createdAt
+func (suite *SingleUseTokenRepositoryTestSuite) createToken(userID uuid.UUID, createdAt time.Time) *models.SingleUseToken {
+	return suite.createTokenFor(models.TokenPurposePasswordReset, userID, createdAt)
+}
+
+// createTokenFor stores an unused token for purpose of the user created at createdAt
+func (suite *SingleUseTokenRepositoryTestSuite) createTokenFor(purpose string, userID uuid.UUID, createdAt time.Time) *models.SingleUseToken {
+	token := &models.SingleUseToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: uuid.NewString(),
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(time.Hour),
+	}
+	suite.Require().NoError(suite.repo.CreateSingleUseToken(token))
+	return token
+}
+
+// reload reads the stored state of token
+func (suite *SingleUseTokenRepositoryTestSuite) reload(token *models.SingleUseToken) *models.SingleUseToken {
+	found, err := suite.repo.GetSingleUseTokenByHash(token.Purpose, token.TokenHash)
+	suite.Require().NoError(err)
+	return found
+}
+
+// ===== TESTS =====
+
+func (suite *SingleUseTokenRepositoryTestSuite) TestCreateAndGetSingleUseToken() {
+	// Arrange
+	created := suite.createToken(suite.userID, suite.now)
+
+	// Act
+	found, err := suite.repo.GetSingleUseTokenByHash(models.TokenPurposePasswordReset, created.TokenHash)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEqual(uuid.Nil, created.ID)
+	suite.Equal(created.ID, found.ID)
+	suite.True(found.IsActive(suite.now))
+}
+
+func (suite *SingleUseTokenRepositoryTestSuite) TestGetSingleUseTokenByHash_NotFound() {
+	// Act
+	found, err := suite.repo.GetSingleUseTokenByHash(models.TokenPurposePasswordReset, "missing")
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(found)
+}
+
+func (suite *SingleUseTokenRepositoryTestSuite) TestGetSingleUseTokenByHash_OtherPurpose() {
+	// Arrange
+	token := suite.createTokenFor(models.TokenPurposeEmailVerification, suite.userID, suite.now)
+
+	// Act
+	found, err := suite.repo.GetSingleUseTokenByHash(models.TokenPurposePasswordReset, token.TokenHash)
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(found)
+}
+
+func (suite *SingleUseTokenRepositoryTestSuite) TestCountSingleUseTokensSince() {
+	// Arrange
+	suite.createToken(suite.userID, suite.now.Add(-2*time.Hour))
+	suite.createToken(suite.userID, suite.now.Add(-time.Minute))
+	suite.createToken(suite.userID, suite.now)
+	suite.createToken(uuid.New(), suite.now)
+	suite.createTokenFor(models.TokenPurposeEmailVerification, suite.userID, suite.now)
+
+	// Act
+	count, err := suite.repo.CountSingleUseTokensSince(models.TokenPurposePasswordReset, suite.userID, suite.now.Add(-time.Hour))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), count)
+}
+
+func (suite *SingleUseTokenRepositoryTestSuite) TestMarkSingleUseTokenUsed_OnlyOnce() {
+	// Arrange
+	token := suite.createToken(suite.userID, suite.now)
+
+	// Act
+	first, err := suite.repo.MarkSingleUseTokenUsed(token.ID, suite.now)
+	suite.Require().NoError(err)
+	second, err := suite.repo.MarkSingleUseTokenUsed(token.ID, suite.now)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(first)
+	suite.False(second)
+	suite.False(suite.reload(token).IsActive(suite.now))
+}
+
+func (suite *SingleUseTokenRepositoryTestSuite) TestInvalidateUserSingleUseTokens() {
+	// Arrange
+	first := suite.createToken(suite.userID, suite.now)
+	second := suite.createToken(suite.userID, suite.now)
+	other := suite.createToken(uuid.New(), suite.now)
+	otherPurpose := suite.createTokenFor(models.TokenPurposeEmailVerification, suite.userID, suite.now)
+
+	// Act
+	err := suite.repo.InvalidateUserSingleUseTokens(models.TokenPurposePasswordReset, suite.userID, suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotNil(suite.reload(first).UsedAt)
+	suite.NotNil(suite.reload(second).UsedAt)
+	suite.Nil(suite.reload(other).UsedAt)
+	suite.Nil(suite.reload(otherPurpose).UsedAt)
+}
+
+func (suite *SingleUseTokenRepositoryTestSuite) TestNilDatabase() {
+	// Arrange
+	repo := &repositories.SingleUseTokenRepository{DB: nil}
+
+	// Act
+	err := repo.CreateSingleUseToken(&models.SingleUseToken{})
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// Run tests
+func TestSingleUseTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SingleUseTokenRepositoryTestSuite))
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository struct {
+	DB IDatabase
+}
+
+func NewTwoFactorRepository(db IDatabase) *TwoFactorRepository {
+	return &TwoFactorRepository{DB: db}
+}
+
+func (r *TwoFactorRepository) GetTOTPCredential(userID uuid.UUID) (*models.TOTPCredential, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var credential models.TOTPCredential
+	err := r.DB.Where("user_id = ?", userID).First(&credential).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// SaveTOTPCredential stores a new enrollment, replacing the previous credential of the user
+func (r *TwoFactorRepository) SaveTOTPCredential(credential *models.TOTPCredential) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := r.DB.Where("user_id = ?", credential.UserID).Delete(&models.TOTPCredential{}).GetError(); err != nil {
+		return fmt.Errorf("cannot replace TOTP credential for user_id=%s: %w", credential.UserID, err)
+	}
+	if err := r.DB.Create(credential).GetError(); err != nil {
+		return fmt.Errorf("cannot create TOTP credential for user_id=%s: %w", credential.UserID, err)
+	}
+	return nil
+}
+
+// ConfirmTOTPCredential enables an unconfirmed enrollment; step is the time step of the confirming code.
+// It reports false if the enrollment was already confirmed.
+func (r *TwoFactorRepository) ConfirmTOTPCredential(userID uuid.UUID, confirmedAt time.Time, step int64) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Model(&models.TOTPCredential{}).
+		Where("user_id = ? AND confirmed_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"confirmed_at":   confirmedAt,
+			"last_used_step": step,
+		})
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// UseTOTPStep records that a code of the given time step was accepted.
+// It reports false if a code of this or a later step was accepted before, so that a code works only once.
+func (r *TwoFactorRepository) UseTOTPStep(userID uuid.UUID, step int64) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Model(&models.TOTPCredential{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// DeleteTOTPCredential disables two-factor login of the user and deletes the recovery codes
+func (r *TwoFactorRepository) DeleteTOTPCredential(userID uuid.UUID) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := r.DB.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).GetError(); err != nil {
+		return fmt.Errorf("cannot delete recovery codes for user_id=%s: %w", userID, err)
+	}
+	if err := r.DB.Where("user_id = ?", userID).Delete(&models.TOTPCredential{}).GetError(); err != nil {
+		return fmt.Errorf("cannot delete TOTP credential for user_id=%s: %w", userID, err)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes stores new recovery codes of the user, the previous ones no longer work
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID uuid.UUID, codes []models.RecoveryCode) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := r.DB.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).GetError(); err != nil {
+		return fmt.Errorf("cannot delete recovery codes for user_id=%s: %w", userID, err)
+	}
+	for i := range codes {
+		codes[i].UserID = userID
+		if codes[i].ID == uuid.Nil {
+			codes[i].ID = uuid.New()
+		}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	if err := r.DB.Create(&codes).GetError(); err != nil {
+		return fmt.Errorf("cannot create recovery codes for user_id=%s: %w", userID, err)
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used.
+// It reports false if the user has no such unused code.
+func (r *TwoFactorRepository) UseRecoveryCode(userID uuid.UUID, codeHash string, usedAt time.Time) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *TwoFactorRepository) CreateTwoFactorChallenge(challenge *models.TwoFactorChallenge) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := r.DB.Create(challenge).GetError(); err != nil {
+		return fmt.Errorf("cannot create two-factor challenge for user_id=%s: %w", challenge.UserID, err)
+	}
+	return nil
+}
+
+func (r *TwoFactorRepository) GetTwoFactorChallenge(tokenHash string) (*models.TwoFactorChallenge, error) {
+	if r.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var challenge models.TwoFactorChallenge
+	err := r.DB.Where("token_hash = ?", tokenHash).First(&challenge).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// RecordTwoFactorChallengeAttempt counts a wrong code presented with the challenge
+func (r *TwoFactorRepository) RecordTwoFactorChallengeAttempt(tokenHash string) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Model(&models.TwoFactorChallenge{}).
+		Where("token_hash = ?", tokenHash).
+		Update("attempts", gorm.Expr("attempts + 1")).
+		GetError()
+}
+
+// DeleteTwoFactorChallenge deletes the challenge.
+// It reports false if it was already deleted, so that a challenge completes only one login.
+func (r *TwoFactorRepository) DeleteTwoFactorChallenge(tokenHash string) (bool, error) {
+	if r.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := r.DB.Where("token_hash = ?", tokenHash).Delete(&models.TwoFactorChallenge{})
+	if err := result.GetError(); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// DeleteExpiredTwoFactorChallenges purges challenges that expired before the given time
+func (r *TwoFactorRepository) DeleteExpiredTwoFactorChallenges(before time.Time) error {
+	if r.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	return r.DB.Where("expires_at < ?", before).Delete(&models.TwoFactorChallenge{}).GetError()
+}
@@ -0,0 +1,235 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepositoryTestSuite struct {
+	suite.Suite
+	repo   *repositories.TwoFactorRepository
+	userID uuid.UUID
+	now    time.Time
+}
+
+func (suite *TwoFactorRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.TOTPCredential{}, &models.RecoveryCode{}, &models.TwoFactorChallenge{}))
+
+	suite.repo = repositories.NewTwoFactorRepository(repositories.NewGormAdapterFromDB(db))
+	suite.userID = uuid.New()
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// enroll stores an unconfirmed credential of the suite user
+func (suite *TwoFactorRepositoryTestSuite) enroll(secret string) {
+	suite.Require().NoError(suite.repo.SaveTOTPCredential(&models.TOTPCredential{
+		UserID:    suite.userID,
+		Secret:    secret,
+		CreatedAt: suite.now,
+	}))
+}
+
+// createChallenge stores a challenge of the suite user expiring at expiresAt
+func (suite *TwoFactorRepositoryTestSuite) createChallenge(expiresAt time.Time) *models.TwoFactorChallenge {
+	challenge := &models.TwoFactorChallenge{
+		TokenHash: uuid.NewString(),
+		UserID:    suite.userID,
+		CreatedAt: suite.now,
+		ExpiresAt: expiresAt,
+	}
+	suite.Require().NoError(suite.repo.CreateTwoFactorChallenge(challenge))
+	return challenge
+}
+
+// ===== TOTP CREDENTIAL TESTS =====
+
+func (suite *TwoFactorRepositoryTestSuite) TestSaveTOTPCredential_ReplacesEnrollment() {
+	// Arrange
+	suite.enroll("FIRSTSECRET")
+
+	// Act
+	suite.enroll("SECONDSECRET")
+
+	// Assert
+	credential, err := suite.repo.GetTOTPCredential(suite.userID)
+	suite.Require().NoError(err)
+	suite.Equal("SECONDSECRET", credential.Secret)
+	suite.False(credential.IsConfirmed())
+}
+
+func (suite *TwoFactorRepositoryTestSuite) TestGetTOTPCredential_NotFound() {
+	// Act
+	credential, err := suite.repo.GetTOTPCredential(suite.userID)
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(credential)
+}
+
+func (suite *TwoFactorRepositoryTestSuite) TestConfirmTOTPCredential_OnlyOnce() {
+	// Arrange
+	suite.enroll("SECRET")
+
+	// Act
+	first, err := suite.repo.ConfirmTOTPCredential(suite.userID, suite.now, 100)
+	suite.Require().NoError(err)
+	second, err := suite.repo.ConfirmTOTPCredential(suite.userID, suite.now, 101)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(first)
+	suite.False(second)
+	credential, err := suite.repo.GetTOTPCredential(suite.userID)
+	suite.Require().NoError(err)
+	suite.True(credential.IsConfirmed())
+	suite.Equal(int64(100), credential.LastUsedStep)
+}
+
+func (suite *TwoFactorRepositoryTestSuite) TestUseTOTPStep_RejectsReplay() {
+	// Arrange
+	suite.enroll("SECRET")
+	_, err := suite.repo.ConfirmTOTPCredential(suite.userID, suite.now, 100)
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		name string
+		step int64
+		want bool
+	}{
+		{name: "later step", step: 101, want: true},
+		{name: "same step again", step: 101, want: false},
+		{name: "earlier step", step: 99, want: false},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Act
+			used, err := suite.repo.UseTOTPStep(suite.userID, tc.step)
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.Equal(tc.want, used)
+		})
+	}
+}
+
+func (suite *TwoFactorRepositoryTestSuite) TestDeleteTOTPCredential_DeletesRecoveryCodes() {
+	// Arrange
+	suite.enroll("SECRET")
+	suite.Require().NoError(suite.repo.ReplaceRecoveryCodes(suite.userID, []models.RecoveryCode{{CodeHash: "hash", CreatedAt: suite.now}}))
+
+	// Act
+	err := suite.repo.DeleteTOTPCredential(suite.userID)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetTOTPCredential(suite.userID)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	used, err := suite.repo.UseRecoveryCode(suite.userID, "hash", suite.now)
+	suite.Require().NoError(err)
+	suite.False(used)
+}
+
+// ===== RECOVERY CODE TESTS =====
+
+func (suite *TwoFactorRepositoryTestSuite) TestReplaceRecoveryCodes_InvalidatesPreviousCodes() {
+	// Arrange
+	suite.Require().NoError(suite.repo.ReplaceRecoveryCodes(suite.userID, []models.RecoveryCode{{CodeHash: "old", CreatedAt: suite.now}}))
+
+	// Act
+	err := suite.repo.ReplaceRecoveryCodes(suite.userID, []models.RecoveryCode{
+		{CodeHash: "new-1", CreatedAt: suite.now},
+		{CodeHash: "new-2", CreatedAt: suite.now},
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	used, err := suite.repo.UseRecoveryCode(suite.userID, "old", suite.now)
+	suite.Require().NoError(err)
+	suite.False(used)
+	used, err = suite.repo.UseRecoveryCode(suite.userID, "new-2", suite.now)
+	suite.Require().NoError(err)
+	suite.True(used)
+}
+
+func (suite *TwoFactorRepositoryTestSuite) TestUseRecoveryCode_OnlyOnceAndOnlyByOwner() {
+	// Arrange
+	suite.Require().NoError(suite.repo.ReplaceRecoveryCodes(suite.userID, []models.RecoveryCode{{CodeHash: "hash", CreatedAt: suite.now}}))
+
+	// Act
+	byOther, err := suite.repo.UseRecoveryCode(uuid.New(), "hash", suite.now)
+	suite.Require().NoError(err)
+	first, err := suite.repo.UseRecoveryCode(suite.userID, "hash", suite.now)
+	suite.Require().NoError(err)
+	second, err := suite.repo.UseRecoveryCode(suite.userID, "hash", suite.now)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.False(byOther)
+	suite.True(first)
+	suite.False(second)
+}
+
+// ===== CHALLENGE TESTS =====
+
+func (suite *TwoFactorRepositoryTestSuite) TestRecordTwoFactorChallengeAttempt() {
+	// Arrange
+	challenge := suite.createChallenge(suite.now.Add(time.Minute))
+
+	// Act
+	suite.Require().NoError(suite.repo.RecordTwoFactorChallengeAttempt(challenge.TokenHash))
+	suite.Require().NoError(suite.repo.RecordTwoFactorChallengeAttempt(challenge.TokenHash))
+
+	// Assert
+	found, err := suite.repo.GetTwoFactorChallenge(challenge.TokenHash)
+	suite.Require().NoError(err)
+	suite.Equal(2, found.Attempts)
+	suite.Equal(suite.userID, found.UserID)
+}
+
+func (suite *TwoFactorRepositoryTestSuite) TestDeleteTwoFactorChallenge_OnlyOnce() {
+	// Arrange
+	challenge := suite.createChallenge(suite.now.Add(time.Minute))
+
+	// Act
+	first, err := suite.repo.DeleteTwoFactorChallenge(challenge.TokenHash)
+	suite.Require().NoError(err)
+	second, err := suite.repo.DeleteTwoFactorChallenge(challenge.TokenHash)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(first)
+	suite.False(second)
+}
+
+func (suite *TwoFactorRepositoryTestSuite) TestDeleteExpiredTwoFactorChallenges() {
+	// Arrange
+	expired := suite.createChallenge(suite.now.Add(-time.Minute))
+	active := suite.createChallenge(suite.now.Add(time.Minute))
+
+	// Act
+	err := suite.repo.DeleteExpiredTwoFactorChallenges(suite.now)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, err = suite.repo.GetTwoFactorChallenge(expired.TokenHash)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	_, err = suite.repo.GetTwoFactorChallenge(active.TokenHash)
+	suite.NoError(err)
+}
+
+// Run tests
+func TestTwoFactorRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorRepositoryTestSuite))
+}
@@ -3,9 +3,11 @@ package repositories
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UserRepository struct {
@@ -34,28 +36,170 @@ func (ur *UserRepository) CreateUser(user *models.User) error {
 	return nil
 }
 
-func (ur *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+// GetUserByEmail finds the user by the canonical form of the email, see emails.Normalizer
+func (ur *UserRepository) GetUserByEmail(emailNormalized string) (*models.User, error) {
 	if ur.DB == nil {
 		return nil, errors.New("database connection is not initialized")
 	}
 
 	var user models.User
-	err := ur.DB.Where("email = ?", email).First(&user).GetError()
+	err := ur.DB.Where("email_normalized = ?", emailNormalized).First(&user).GetError()
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (ur *UserRepository) UserExists(email string) (bool, error) {
+func (ur *UserRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
+	if ur.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var user models.User
+	err := ur.DB.Where("id = ?", id).First(&user).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UserExists reports whether a user has the canonical form of the email, see emails.Normalizer
+func (ur *UserRepository) UserExists(emailNormalized string) (bool, error) {
 	if ur.DB == nil {
 		return false, errors.New("database connection is not initialized")
 	}
 
 	var count int64
-	err := ur.DB.Model(&models.User{}).Where("email = ?", email).Count(&count).GetError()
+	err := ur.DB.Model(&models.User{}).Where("email_normalized = ?", emailNormalized).Count(&count).GetError()
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+// IncrementTokenVersion invalidates every JWT issued to the user so far
+func (ur *UserRepository) IncrementTokenVersion(id uuid.UUID) error {
+	if ur.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := ur.DB.Model(&models.User{}).Where("id = ?", id).Update("token_version", gorm.Expr("token_version + 1"))
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot increment token version of user_id=%s: %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdatePassword replaces the stored password hash of the user
+func (ur *UserRepository) UpdatePassword(id uuid.UUID, passwordHash string) error {
+	if ur.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := ur.DB.Model(&models.User{}).Where("id = ?", id).Update("password", passwordHash)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot update password of user_id=%s: %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RehashPassword replaces the password hash of the user with a new hash of the same password.
+// It reports false without changing anything when the stored hash is no longer currentHash,
+// so that a rehash cannot undo a concurrent password change.
+func (ur *UserRepository) RehashPassword(id uuid.UUID, currentHash, newHash string) (bool, error) {
+	if ur.DB == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	result := ur.DB.Model(&models.User{}).Where("id = ? AND password = ?", id, currentHash).Update("password", newHash)
+	if err := result.GetError(); err != nil {
+		return false, fmt.Errorf("cannot rehash password of user_id=%s: %w", id, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// MarkEmailVerified records when the user confirmed the email address.
+// The first confirmation is kept if the address was already verified.
+func (ur *UserRepository) MarkEmailVerified(id uuid.UUID, verifiedAt time.Time) error {
+	if ur.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	err := ur.DB.Model(&models.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", verifiedAt).
+		GetError()
+	if err != nil {
+		return fmt.Errorf("cannot mark email of user_id=%s as verified: %w", id, err)
+	}
+	return nil
+}
+
+// UpdateEmail replaces the email of the user with a new address verified at verifiedAt
+func (ur *UserRepository) UpdateEmail(id uuid.UUID, email, emailNormalized string, verifiedAt time.Time) error {
+	if ur.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := ur.DB.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":             email,
+		"email_normalized":  emailNormalized,
+		"email_verified_at": verifiedAt,
+	})
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot update email of user_id=%s: %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListUsersAfter returns up to limit users with an ID greater than afterID ordered by ID,
+// so that all users can be walked in batches
+func (ur *UserRepository) ListUsersAfter(afterID uuid.UUID, limit int) ([]models.User, error) {
+	if ur.DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var users []models.User
+	err := ur.DB.Where("id > ?", afterID).Order("id").Limit(limit).Find(&users).GetError()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list users after id=%s: %w", afterID, err)
+	}
+	return users, nil
+}
+
+// UpdateEmailNormalized replaces the canonical form of the user's email
+func (ur *UserRepository) UpdateEmailNormalized(id uuid.UUID, emailNormalized string) error {
+	if ur.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := ur.DB.Model(&models.User{}).Where("id = ?", id).Update("email_normalized", emailNormalized)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot update normalized email of user_id=%s: %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteUser soft-deletes the user. Deleting an already deleted user succeeds.
+func (ur *UserRepository) DeleteUser(id uuid.UUID) error {
+	if ur.DB == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	if err := ur.DB.Delete(&models.User{}, "id = ?", id).GetError(); err != nil {
+		return fmt.Errorf("cannot delete user_id=%s: %w", id, err)
+	}
+	return nil
+}
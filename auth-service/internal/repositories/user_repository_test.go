@@ -3,6 +3,7 @@ package repositories_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
 	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
@@ -10,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 type UserRepositoryTestSuite struct {
@@ -44,9 +46,9 @@ func (suite *UserRepositoryTestSuite) mockCreateUser(user *models.User, err erro
 	suite.mockDB.On("GetError").Return(err)
 }
 
-// mockWhereEmail mocks DB.Where("email = ?", email)
+// mockWhereEmail mocks DB.Where("email_normalized = ?", email)
 func (suite *UserRepositoryTestSuite) mockWhereEmail(email string) {
-	suite.mockDB.On("Where", "email = ?", email).Return(suite.mockDB)
+	suite.mockDB.On("Where", "email_normalized = ?", email).Return(suite.mockDB)
 }
 
 // mockGetUserByEmail mocks DB.First(&user).GetError()
@@ -185,6 +187,39 @@ func (suite *UserRepositoryTestSuite) TestGetUserByEmail_UserNotFound() {
 	suite.mockDB.AssertExpectations(suite.T())
 }
 
+// ===== GET USER BY ID TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestGetUserByID_Success() {
+	// Arrange
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("First", mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		dest := args.Get(0).(*models.User)
+		*dest = *suite.testUser
+	}).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+
+	// Act
+	user, err := suite.userRepo.GetUserByID(suite.testUser.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().NotNil(user)
+	suite.Equal(suite.testUser.ID, user.ID)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetUserByID_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	user, err := repo.GetUserByID(suite.testUser.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Require().Nil(user)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
 // ===== USER EXISTS TESTS =====
 
 func (suite *UserRepositoryTestSuite) TestUserExists_Success() {
@@ -241,6 +276,298 @@ func (suite *UserRepositoryTestSuite) TestUserExists_NilDatabase() {
 	suite.Contains(err.Error(), "database connection is not initialized")
 }
 
+// ===== INCREMENT TOKEN VERSION TESTS =====
+
+// mockIncrementTokenVersion mocks DB.Model(&User{}).Where("id = ?", id).Update("token_version", expr)
+func (suite *UserRepositoryTestSuite) mockIncrementTokenVersion(id uuid.UUID, rowsAffected int64, err error) {
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", id).Return(suite.mockDB)
+	suite.mockDB.On("Update", "token_version", mock.Anything).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(err)
+	suite.mockDB.On("RowsAffected").Return(rowsAffected).Maybe()
+}
+
+func (suite *UserRepositoryTestSuite) TestIncrementTokenVersion_Success() {
+	// Arrange
+	suite.mockIncrementTokenVersion(suite.testUser.ID, 1, nil)
+
+	// Act
+	err := suite.userRepo.IncrementTokenVersion(suite.testUser.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestIncrementTokenVersion_UserNotFound() {
+	// Arrange
+	suite.mockIncrementTokenVersion(suite.testUser.ID, 0, nil)
+
+	// Act
+	err := suite.userRepo.IncrementTokenVersion(suite.testUser.ID)
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *UserRepositoryTestSuite) TestIncrementTokenVersion_DatabaseError() {
+	// Arrange
+	suite.mockIncrementTokenVersion(suite.testUser.ID, 0, errors.New("database error"))
+
+	// Act
+	err := suite.userRepo.IncrementTokenVersion(suite.testUser.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database error")
+}
+
+// ===== UPDATE PASSWORD TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_Success() {
+	// Arrange
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "password", "new-hash").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(1))
+
+	// Act
+	err := suite.userRepo.UpdatePassword(suite.testUser.ID, "new-hash")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	err := repo.UpdatePassword(suite.testUser.ID, "new-hash")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// ===== REHASH PASSWORD TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestRehashPassword_Success() {
+	// Arrange
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ? AND password = ?", suite.testUser.ID, "old-hash").Return(suite.mockDB)
+	suite.mockDB.On("Update", "password", "new-hash").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(1))
+
+	// Act
+	updated, err := suite.userRepo.RehashPassword(suite.testUser.ID, "old-hash", "new-hash")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(updated)
+}
+
+func (suite *UserRepositoryTestSuite) TestRehashPassword_PasswordChanged() {
+	// Arrange - the password was changed after the old hash was loaded
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ? AND password = ?", suite.testUser.ID, "old-hash").Return(suite.mockDB)
+	suite.mockDB.On("Update", "password", "new-hash").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(0))
+
+	// Act
+	updated, err := suite.userRepo.RehashPassword(suite.testUser.ID, "old-hash", "new-hash")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(updated)
+}
+
+func (suite *UserRepositoryTestSuite) TestRehashPassword_DatabaseError() {
+	// Arrange
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ? AND password = ?", suite.testUser.ID, "old-hash").Return(suite.mockDB)
+	suite.mockDB.On("Update", "password", "new-hash").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(errors.New("database error"))
+
+	// Act
+	updated, err := suite.userRepo.RehashPassword(suite.testUser.ID, "old-hash", "new-hash")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.False(updated)
+}
+
+// ===== MARK EMAIL VERIFIED TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestMarkEmailVerified_Success() {
+	// Arrange
+	verifiedAt := time.Now()
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ? AND email_verified_at IS NULL", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "email_verified_at", verifiedAt).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+
+	// Act
+	err := suite.userRepo.MarkEmailVerified(suite.testUser.ID, verifiedAt)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestMarkEmailVerified_DatabaseError() {
+	// Arrange
+	verifiedAt := time.Now()
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ? AND email_verified_at IS NULL", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "email_verified_at", verifiedAt).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(errors.New("database error"))
+
+	// Act
+	err := suite.userRepo.MarkEmailVerified(suite.testUser.ID, verifiedAt)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database error")
+}
+
+// ===== UPDATE EMAIL TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestUpdateEmail_Success() {
+	// Arrange
+	verifiedAt := time.Now()
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Updates", map[string]interface{}{
+		"email":             "n.ew+x@gmail.com",
+		"email_normalized":  "new@gmail.com",
+		"email_verified_at": verifiedAt,
+	}).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(1))
+
+	// Act
+	err := suite.userRepo.UpdateEmail(suite.testUser.ID, "n.ew+x@gmail.com", "new@gmail.com", verifiedAt)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdateEmail_UserNotFound() {
+	// Arrange
+	verifiedAt := time.Now()
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Updates", mock.Anything).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(0))
+
+	// Act
+	err := suite.userRepo.UpdateEmail(suite.testUser.ID, "new@example.com", "new@example.com", verifiedAt)
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+// ===== LIST USERS AFTER TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestListUsersAfter_Success() {
+	// Arrange
+	afterID := uuid.New()
+	suite.mockDB.On("Where", "id > ?", afterID).Return(suite.mockDB)
+	suite.mockDB.On("Order", "id").Return(suite.mockDB)
+	suite.mockDB.On("Limit", 100).Return(suite.mockDB)
+	suite.mockDB.On("Find", mock.AnythingOfType("*[]models.User")).Run(func(args mock.Arguments) {
+		dest := args.Get(0).(*[]models.User)
+		*dest = []models.User{*suite.testUser}
+	}).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+
+	// Act
+	users, err := suite.userRepo.ListUsersAfter(afterID, 100)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(users, 1)
+	suite.Equal(suite.testUser.ID, users[0].ID)
+}
+
+func (suite *UserRepositoryTestSuite) TestListUsersAfter_DatabaseError() {
+	// Arrange
+	suite.mockDB.On("Where", "id > ?", uuid.Nil).Return(suite.mockDB)
+	suite.mockDB.On("Order", "id").Return(suite.mockDB)
+	suite.mockDB.On("Limit", 100).Return(suite.mockDB)
+	suite.mockDB.On("Find", mock.AnythingOfType("*[]models.User")).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(errors.New("database error"))
+
+	// Act
+	users, err := suite.userRepo.ListUsersAfter(uuid.Nil, 100)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(users)
+}
+
+// ===== UPDATE EMAIL NORMALIZED TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestUpdateEmailNormalized_Success() {
+	// Arrange
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "email_normalized", "jdoe@gmail.com").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(1))
+
+	// Act
+	err := suite.userRepo.UpdateEmailNormalized(suite.testUser.ID, "jdoe@gmail.com")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdateEmailNormalized_DatabaseError() {
+	// Arrange - e.g. another account already has the normalized email
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "email_normalized", "jdoe@gmail.com").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(errors.New("duplicate key value violates unique constraint"))
+
+	// Act
+	err := suite.userRepo.UpdateEmailNormalized(suite.testUser.ID, "jdoe@gmail.com")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "duplicate key")
+}
+
+// ===== DELETE USER TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestDeleteUser_Success() {
+	// Arrange
+	suite.mockDB.On("Delete", mock.AnythingOfType("*models.User"), "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+
+	// Act
+	err := suite.userRepo.DeleteUser(suite.testUser.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestDeleteUser_DatabaseError() {
+	// Arrange
+	suite.mockDB.On("Delete", mock.AnythingOfType("*models.User"), "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(errors.New("connection lost"))
+
+	// Act
+	err := suite.userRepo.DeleteUser(suite.testUser.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "connection lost")
+}
+
 // Run tests
 func TestUserRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
@@ -2,23 +2,84 @@ package server
 
 import (
 	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
 	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Token types reported by ValidateToken
+const (
+	tokenTypeJWT = "jwt"
+	tokenTypePAT = "pat"
+)
+
+// Services are the services behind both versions of the auth API
+type Services struct {
+	AuthService        services.IAuthService
+	AccessTokens       services.IAccessTokenService
+	RefreshTokens      services.IRefreshTokenService
+	PasswordResets     services.IPasswordResetService
+	EmailVerifications services.IEmailVerificationService
+	Accounts           services.IAccountService
+	Deletions          services.IAccountDeletionService
+	TwoFactor          services.ITwoFactorService
+	Passkeys           services.IPasskeyService
+	MagicLinks         services.IMagicLinkService
+	OIDCLogins         services.IOIDCLoginService
+	Sessions           services.ISessionService
+}
+
+// AuthServer implements version 1 of the auth API, failures are reported in the
+// success and error fields of the responses
 type AuthServer struct {
 	authpb.UnimplementedAuthServiceServer
-	AuthService services.IAuthService
+	Services
 }
 
-func NewAuthServer(authService services.IAuthService) *AuthServer {
-	return &AuthServer{
-		AuthService: authService,
-	}
+func NewAuthServer(
+	authService services.IAuthService,
+	accessTokens services.IAccessTokenService,
+	refreshTokens services.IRefreshTokenService,
+	passwordResets services.IPasswordResetService,
+	emailVerifications services.IEmailVerificationService,
+	accounts services.IAccountService,
+	deletions services.IAccountDeletionService,
+	twoFactor services.ITwoFactorService,
+	passkeys services.IPasskeyService,
+	magicLinks services.IMagicLinkService,
+	oidcLogins services.IOIDCLoginService,
+	sessions services.ISessionService,
+) *AuthServer {
+	return &AuthServer{Services: Services{
+		AuthService:        authService,
+		AccessTokens:       accessTokens,
+		RefreshTokens:      refreshTokens,
+		PasswordResets:     passwordResets,
+		EmailVerifications: emailVerifications,
+		Accounts:           accounts,
+		Deletions:          deletions,
+		TwoFactor:          twoFactor,
+		Passkeys:           passkeys,
+		MagicLinks:         magicLinks,
+		OIDCLogins:         oidcLogins,
+		Sessions:           sessions,
+	}}
 }
 
 func (s *AuthServer) ValidateToken(ctx context.Context, req *authpb.TokenRequest) (*authpb.UserResponse, error) {
+	if strings.HasPrefix(req.Token, models.AccessTokenPrefix) {
+		return s.validateAccessToken(ctx, req.Token), nil
+	}
+
 	claims, err := s.AuthService.ValidateToken(ctx, req.Token)
 	if err != nil {
 		return &authpb.UserResponse{
@@ -43,11 +104,63 @@ func (s *AuthServer) ValidateToken(ctx context.Context, req *authpb.TokenRequest
 		}, nil
 	}
 
+	// Tokens issued before roles were introduced carry no role claim
+	role, ok := claims["role"].(string)
+	if !ok || role == "" {
+		role = models.RoleUser
+	}
+
+	readOnly, _ := claims["read_only"].(bool)
+	sessionID, _ := claims["sid"].(string)
+
+	response := &authpb.UserResponse{
+		UserId:    userIDStr,
+		Email:     email,
+		Valid:     true,
+		Role:      role,
+		TokenType: tokenTypeJWT,
+		ReadOnly:  readOnly,
+		SessionId: sessionID,
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		response.ExpiresAt = exp.Unix()
+	}
+	return response, nil
+}
+
+// validateAccessToken validates a personal access token.
+// The role is read from the user record so that demoting a user also limits their tokens.
+func (s *AuthServer) validateAccessToken(ctx context.Context, token string) *authpb.UserResponse {
+	if s.AccessTokens == nil {
+		return &authpb.UserResponse{
+			Valid: false,
+			Error: "personal access tokens are not supported",
+		}
+	}
+
+	accessToken, user, err := s.AccessTokens.ValidateToken(ctx, token)
+	if err != nil {
+		return &authpb.UserResponse{
+			Valid: false,
+			Error: err.Error(),
+		}
+	}
+
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+
 	return &authpb.UserResponse{
-		UserId: userIDStr,
-		Email:  email,
-		Valid:  true,
-	}, nil
+		UserId:    user.ID.String(),
+		Email:     user.Email,
+		Valid:     true,
+		Role:      role,
+		Scopes:    accessToken.ScopeList(),
+		TokenType: tokenTypePAT,
+		ExpiresAt: accessToken.ExpiresAt.Unix(),
+		ReadOnly:  s.AuthService.IsReadOnly(user),
+	}
 }
 
 func (s *AuthServer) Register(ctx context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error) {
@@ -55,11 +168,17 @@ func (s *AuthServer) Register(ctx context.Context, req *authpb.RegisterRequest)
 
 	if err != nil {
 		return &authpb.RegisterResponse{
-			Success: false,
-			Error:   err.Error(),
+			Success:            false,
+			Error:              err.Error(),
+			PasswordViolations: toPasswordViolationsPB(err),
 		}, nil
 	}
 
+	// The account is created either way, the user can ask for another email later
+	if err := s.EmailVerifications.SendVerificationEmail(ctx, user); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
 	response := &authpb.RegisterResponse{
 		UserId:  user.ID.String(),
 		Email:   user.Email,
@@ -70,8 +189,40 @@ func (s *AuthServer) Register(ctx context.Context, req *authpb.RegisterRequest)
 	return response, nil
 }
 
+// Login signs the user in. While failed attempts are throttled or the account is locked,
+// the response carries RetryAfter, and Locked for a locked account. Accounts with two-factor
+// authentication get SecondFactorRequired and a Challenge for VerifySecondFactor instead of tokens.
 func (s *AuthServer) Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error) {
-	token, user, err := s.AuthService.Login(ctx, req.Email, req.Password)
+	user, err := s.AuthService.Login(ctx, req.Email, req.Password, services.ClientIP(ctx))
+	var blocked *services.LoginBlockedError
+	if errors.As(err, &blocked) {
+		return &authpb.LoginResponse{
+			Success:    false,
+			Error:      err.Error(),
+			Locked:     errors.Is(err, services.ErrAccountLocked),
+			RetryAfter: retryAfterSeconds(blocked.RetryAt),
+		}, nil
+	}
+	var required *services.SecondFactorRequiredError
+	if errors.As(err, &required) {
+		return &authpb.LoginResponse{
+			UserId:               user.ID.String(),
+			Email:                user.Email,
+			Success:              false,
+			Message:              "Second factor required",
+			SecondFactorRequired: true,
+			Challenge:            required.Challenge,
+			ChallengeExpiresAt:   required.ExpiresAt.Unix(),
+		}, nil
+	}
+	if err != nil {
+		return &authpb.LoginResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	pair, err := s.RefreshTokens.IssueTokens(ctx, user)
 	if err != nil {
 		return &authpb.LoginResponse{
 			Success: false,
@@ -80,10 +231,813 @@ func (s *AuthServer) Login(ctx context.Context, req *authpb.LoginRequest) (*auth
 	}
 
 	return &authpb.LoginResponse{
-		Token:   token,
-		UserId:  user.ID.String(),
-		Email:   user.Email,
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		Success:          true,
+		Message:          "Successful login",
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// VerifySecondFactor completes a login that returned SecondFactorRequired with an
+// authenticator or recovery code. Wrong codes are limited like failed logins.
+func (s *AuthServer) VerifySecondFactor(ctx context.Context, req *authpb.VerifySecondFactorRequest) (*authpb.VerifySecondFactorResponse, error) {
+	if req.Challenge == "" || req.Code == "" {
+		return &authpb.VerifySecondFactorResponse{
+			Success: false,
+			Error:   "Challenge and code are required",
+		}, nil
+	}
+
+	pair, user, err := s.TwoFactor.VerifySecondFactor(ctx, req.Challenge, req.Code, services.ClientIP(ctx))
+	var blocked *services.LoginBlockedError
+	if errors.As(err, &blocked) {
+		return &authpb.VerifySecondFactorResponse{
+			Success:    false,
+			Error:      err.Error(),
+			Locked:     errors.Is(err, services.ErrAccountLocked),
+			RetryAfter: retryAfterSeconds(blocked.RetryAt),
+		}, nil
+	}
+	if err != nil {
+		return &authpb.VerifySecondFactorResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.VerifySecondFactorResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		Success:          true,
+		Message:          "Successful login",
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *AuthServer) Refresh(ctx context.Context, req *authpb.RefreshRequest) (*authpb.RefreshResponse, error) {
+	pair, _, err := s.RefreshTokens.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		return &authpb.RefreshResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.RefreshResponse{
+		Token:            pair.AccessToken,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+		Success:          true,
+	}, nil
+}
+
+// Logout revokes the access token and the login the refresh token belongs to.
+// Tokens that are already invalid are skipped, so logging out twice succeeds.
+func (s *AuthServer) Logout(ctx context.Context, req *authpb.LogoutRequest) (*authpb.LogoutResponse, error) {
+	if req.Token == "" && req.RefreshToken == "" {
+		return &authpb.LogoutResponse{
+			Success: false,
+			Error:   "No token to revoke",
+		}, nil
+	}
+
+	if req.Token != "" {
+		if err := s.AuthService.RevokeToken(ctx, req.Token); err != nil && !errors.Is(err, services.ErrInvalidToken) {
+			return &authpb.LogoutResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+	}
+
+	if req.RefreshToken != "" {
+		if err := s.RefreshTokens.RevokeRefreshToken(ctx, req.RefreshToken); err != nil && !errors.Is(err, services.ErrInvalidRefreshToken) {
+			return &authpb.LogoutResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+	}
+
+	return &authpb.LogoutResponse{
+		Success: true,
+		Message: "Logged out",
+	}, nil
+}
+
+// LogoutAll signs the user out everywhere: all refresh tokens are revoked first,
+// so that no new JWT can be issued, then all previously issued JWTs
+func (s *AuthServer) LogoutAll(ctx context.Context, req *authpb.LogoutAllRequest) (*authpb.LogoutAllResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.LogoutAllResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	if err := s.RefreshTokens.RevokeAllRefreshTokens(ctx, userID); err != nil {
+		return &authpb.LogoutAllResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	if err := s.AuthService.RevokeAllTokens(ctx, userID); err != nil {
+		return &authpb.LogoutAllResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.LogoutAllResponse{
 		Success: true,
-		Message: "Successful login",
+		Message: "Logged out from all sessions",
 	}, nil
 }
+
+// RequestPasswordReset emails a reset link to the account. The response is the same
+// for registered and unknown emails, so that it cannot be used to discover accounts.
+func (s *AuthServer) RequestPasswordReset(ctx context.Context, req *authpb.RequestPasswordResetRequest) (*authpb.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return &authpb.RequestPasswordResetResponse{
+			Success: false,
+			Error:   "Email is required",
+		}, nil
+	}
+
+	if err := s.PasswordResets.RequestPasswordReset(ctx, req.Email); err != nil {
+		log.Printf("Password reset request failed: %v", err)
+		return &authpb.RequestPasswordResetResponse{
+			Success: false,
+			Error:   "Failed to request password reset",
+		}, nil
+	}
+
+	return &authpb.RequestPasswordResetResponse{
+		Success: true,
+		Message: "If the email is registered, a password reset link has been sent",
+	}, nil
+}
+
+// ResetPassword sets a new password with a token from a password reset email
+func (s *AuthServer) ResetPassword(ctx context.Context, req *authpb.ResetPasswordRequest) (*authpb.ResetPasswordResponse, error) {
+	if err := s.PasswordResets.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		return &authpb.ResetPasswordResponse{
+			Success:            false,
+			Error:              resetPasswordError(err),
+			PasswordViolations: toPasswordViolationsPB(err),
+		}, nil
+	}
+
+	return &authpb.ResetPasswordResponse{
+		Success: true,
+		Message: "Password has been reset",
+	}, nil
+}
+
+// RequestMagicLink emails a sign-in link to the account. The response is the same
+// for registered and unknown emails, so that it cannot be used to discover accounts.
+func (s *AuthServer) RequestMagicLink(ctx context.Context, req *authpb.RequestMagicLinkRequest) (*authpb.RequestMagicLinkResponse, error) {
+	if req.Email == "" {
+		return &authpb.RequestMagicLinkResponse{
+			Success: false,
+			Error:   "Email is required",
+		}, nil
+	}
+
+	if err := s.MagicLinks.RequestMagicLink(ctx, req.Email); err != nil {
+		log.Printf("Magic link request failed: %v", err)
+		return &authpb.RequestMagicLinkResponse{
+			Success: false,
+			Error:   "Failed to request magic link",
+		}, nil
+	}
+
+	return &authpb.RequestMagicLinkResponse{
+		Success: true,
+		Message: "If the email is registered, a sign-in link has been sent",
+	}, nil
+}
+
+// ConsumeMagicLink signs the user in with a token from a magic link email. Accounts with
+// two-factor authentication get SecondFactorRequired and a Challenge for VerifySecondFactor instead of tokens.
+func (s *AuthServer) ConsumeMagicLink(ctx context.Context, req *authpb.ConsumeMagicLinkRequest) (*authpb.ConsumeMagicLinkResponse, error) {
+	if req.Token == "" {
+		return &authpb.ConsumeMagicLinkResponse{
+			Success: false,
+			Error:   "Token is required",
+		}, nil
+	}
+
+	pair, user, err := s.MagicLinks.ConsumeMagicLink(ctx, req.Token)
+	var required *services.SecondFactorRequiredError
+	if errors.As(err, &required) {
+		return &authpb.ConsumeMagicLinkResponse{
+			UserId:               user.ID.String(),
+			Email:                user.Email,
+			Success:              false,
+			Message:              "Second factor required",
+			SecondFactorRequired: true,
+			Challenge:            required.Challenge,
+			ChallengeExpiresAt:   required.ExpiresAt.Unix(),
+		}, nil
+	}
+	if err != nil {
+		return &authpb.ConsumeMagicLinkResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.ConsumeMagicLinkResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		Success:          true,
+		Message:          "Successful login",
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// VerifyEmail confirms the user's email address with a token from the verification email
+func (s *AuthServer) VerifyEmail(ctx context.Context, req *authpb.VerifyEmailRequest) (*authpb.VerifyEmailResponse, error) {
+	if err := s.EmailVerifications.VerifyEmail(ctx, req.Token); err != nil {
+		return &authpb.VerifyEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.VerifyEmailResponse{
+		Success: true,
+		Message: "Email verified",
+	}, nil
+}
+
+// ResendVerificationEmail sends a new verification link. The response is the same
+// for registered and unknown emails, so that it cannot be used to discover accounts.
+func (s *AuthServer) ResendVerificationEmail(ctx context.Context, req *authpb.ResendVerificationEmailRequest) (*authpb.ResendVerificationEmailResponse, error) {
+	if req.Email == "" {
+		return &authpb.ResendVerificationEmailResponse{
+			Success: false,
+			Error:   "Email is required",
+		}, nil
+	}
+
+	if err := s.EmailVerifications.ResendVerificationEmail(ctx, req.Email); err != nil {
+		log.Printf("Verification email request failed: %v", err)
+		return &authpb.ResendVerificationEmailResponse{
+			Success: false,
+			Error:   "Failed to send verification email",
+		}, nil
+	}
+
+	return &authpb.ResendVerificationEmailResponse{
+		Success: true,
+		Message: "If the email is registered and not verified yet, a verification link has been sent",
+	}, nil
+}
+
+// ChangePassword replaces the password of the signed-in user and returns new tokens
+// for the calling session; every other session of the user is revoked
+func (s *AuthServer) ChangePassword(ctx context.Context, req *authpb.ChangePasswordRequest) (*authpb.ChangePasswordResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.ChangePasswordResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	pair, err := s.Accounts.ChangePassword(ctx, userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		return &authpb.ChangePasswordResponse{
+			Success:            false,
+			Error:              err.Error(),
+			PasswordViolations: toPasswordViolationsPB(err),
+		}, nil
+	}
+
+	return &authpb.ChangePasswordResponse{
+		Success:          true,
+		Message:          "Password changed",
+		Token:            pair.AccessToken,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// ChangeEmail emails a verification link to the new address of the signed-in user.
+// The email is replaced once the link is used.
+func (s *AuthServer) ChangeEmail(ctx context.Context, req *authpb.ChangeEmailRequest) (*authpb.ChangeEmailResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.ChangeEmailResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+	if req.NewEmail == "" {
+		return &authpb.ChangeEmailResponse{
+			Success: false,
+			Error:   "Email is required",
+		}, nil
+	}
+
+	if err := s.Accounts.ChangeEmail(ctx, userID, req.CurrentPassword, req.NewEmail); err != nil {
+		return &authpb.ChangeEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.ChangeEmailResponse{
+		Success: true,
+		Message: "A verification link has been sent to the new email address",
+	}, nil
+}
+
+// DeleteAccount deletes the signed-in user's account. The user's data in other
+// services is deleted asynchronously after the user.deleted event.
+func (s *AuthServer) DeleteAccount(ctx context.Context, req *authpb.DeleteAccountRequest) (*authpb.DeleteAccountResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.DeleteAccountResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	if err := s.Deletions.DeleteAccount(ctx, userID, req.CurrentPassword); err != nil {
+		return &authpb.DeleteAccountResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.DeleteAccountResponse{
+		Success: true,
+		Message: "Account deleted",
+	}, nil
+}
+
+// EnrollTOTP starts the enrollment of an authenticator app for the signed-in user.
+// Logins need a code once the enrollment is confirmed with ConfirmTOTP.
+func (s *AuthServer) EnrollTOTP(ctx context.Context, req *authpb.EnrollTOTPRequest) (*authpb.EnrollTOTPResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.EnrollTOTPResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	enrollment, err := s.TwoFactor.EnrollTOTP(ctx, userID)
+	if err != nil {
+		return &authpb.EnrollTOTPResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.EnrollTOTPResponse{
+		Success:    true,
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication with a first code from the authenticator app
+// and returns the recovery codes
+func (s *AuthServer) ConfirmTOTP(ctx context.Context, req *authpb.ConfirmTOTPRequest) (*authpb.ConfirmTOTPResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.ConfirmTOTPResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	codes, err := s.TwoFactor.ConfirmTOTP(ctx, userID, req.Code)
+	if err != nil {
+		return &authpb.ConfirmTOTPResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.ConfirmTOTPResponse{
+		Success:       true,
+		Message:       "Two-factor authentication enabled",
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// DisableTOTP turns two-factor authentication off for the signed-in user
+func (s *AuthServer) DisableTOTP(ctx context.Context, req *authpb.DisableTOTPRequest) (*authpb.DisableTOTPResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.DisableTOTPResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	if err := s.TwoFactor.DisableTOTP(ctx, userID, req.CurrentPassword, req.Code); err != nil {
+		return &authpb.DisableTOTPResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.DisableTOTPResponse{
+		Success: true,
+		Message: "Two-factor authentication disabled",
+	}, nil
+}
+
+// BeginPasskeyRegistration returns the WebAuthn creation options for a new passkey of the
+// signed-in user, together with the ceremony token FinishPasskeyRegistration needs
+func (s *AuthServer) BeginPasskeyRegistration(ctx context.Context, req *authpb.BeginPasskeyRegistrationRequest) (*authpb.BeginPasskeyRegistrationResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.BeginPasskeyRegistrationResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	ceremony, options, err := s.Passkeys.BeginRegistration(ctx, userID)
+	if err != nil {
+		return &authpb.BeginPasskeyRegistrationResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.BeginPasskeyRegistrationResponse{
+		Success:  true,
+		Ceremony: ceremony,
+		Options:  string(options),
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies the authenticator's registration response and stores the passkey
+func (s *AuthServer) FinishPasskeyRegistration(ctx context.Context, req *authpb.FinishPasskeyRegistrationRequest) (*authpb.FinishPasskeyRegistrationResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.FinishPasskeyRegistrationResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+	if req.Ceremony == "" || req.Credential == "" {
+		return &authpb.FinishPasskeyRegistrationResponse{
+			Success: false,
+			Error:   "Ceremony and credential are required",
+		}, nil
+	}
+
+	if err := s.Passkeys.FinishRegistration(ctx, userID, req.Ceremony, []byte(req.Credential)); err != nil {
+		return &authpb.FinishPasskeyRegistrationResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.FinishPasskeyRegistrationResponse{
+		Success: true,
+		Message: "Passkey registered",
+	}, nil
+}
+
+// BeginPasskeyLogin returns the WebAuthn request options of a passwordless login. The user
+// is not known yet, the authenticator offers the passkeys it holds for the relying party.
+func (s *AuthServer) BeginPasskeyLogin(ctx context.Context, req *authpb.BeginPasskeyLoginRequest) (*authpb.BeginPasskeyLoginResponse, error) {
+	ceremony, options, err := s.Passkeys.BeginLogin(ctx)
+	if err != nil {
+		return &authpb.BeginPasskeyLoginResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.BeginPasskeyLoginResponse{
+		Success:  true,
+		Ceremony: ceremony,
+		Options:  string(options),
+	}, nil
+}
+
+// FinishPasskeyLogin verifies the assertion of a passkey and issues tokens for its owner
+func (s *AuthServer) FinishPasskeyLogin(ctx context.Context, req *authpb.FinishPasskeyLoginRequest) (*authpb.FinishPasskeyLoginResponse, error) {
+	if req.Ceremony == "" || req.Credential == "" {
+		return &authpb.FinishPasskeyLoginResponse{
+			Success: false,
+			Error:   "Ceremony and credential are required",
+		}, nil
+	}
+
+	pair, user, err := s.Passkeys.FinishLogin(ctx, req.Ceremony, []byte(req.Credential))
+	if err != nil {
+		return &authpb.FinishPasskeyLoginResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.FinishPasskeyLoginResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		Success:          true,
+		Message:          "Successful login",
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// LoginWithOIDC signs in with an account at an OpenID Connect identity provider.
+// core-service validated the ID token, the identity is linked to an account on first use.
+func (s *AuthServer) LoginWithOIDC(ctx context.Context, req *authpb.LoginWithOIDCRequest) (*authpb.LoginWithOIDCResponse, error) {
+	pair, user, err := s.OIDCLogins.LoginWithOIDC(ctx, services.OIDCIdentity{
+		Issuer:        req.Issuer,
+		Subject:       req.Subject,
+		Email:         req.Email,
+		EmailVerified: req.EmailVerified,
+	})
+	var required *services.SecondFactorRequiredError
+	if errors.As(err, &required) {
+		return &authpb.LoginWithOIDCResponse{
+			UserId:               user.ID.String(),
+			Email:                user.Email,
+			Success:              false,
+			Message:              "Second factor required",
+			SecondFactorRequired: true,
+			Challenge:            required.Challenge,
+			ChallengeExpiresAt:   required.ExpiresAt.Unix(),
+		}, nil
+	}
+	if err != nil {
+		return &authpb.LoginWithOIDCResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.LoginWithOIDCResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		Success:          true,
+		Message:          "Successful login",
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *AuthServer) CreateAccessToken(ctx context.Context, req *authpb.CreateAccessTokenRequest) (*authpb.CreateAccessTokenResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.CreateAccessTokenResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, accessToken, err := s.AccessTokens.CreateToken(ctx, userID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		return &authpb.CreateAccessTokenResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.CreateAccessTokenResponse{
+		Token:       token,
+		AccessToken: toAccessTokenPB(accessToken),
+		Success:     true,
+	}, nil
+}
+
+func (s *AuthServer) ListAccessTokens(ctx context.Context, req *authpb.ListAccessTokensRequest) (*authpb.ListAccessTokensResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.ListAccessTokensResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	tokens, err := s.AccessTokens.ListTokens(ctx, userID)
+	if err != nil {
+		return &authpb.ListAccessTokensResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	response := &authpb.ListAccessTokensResponse{
+		Tokens:  make([]*authpb.AccessToken, 0, len(tokens)),
+		Success: true,
+	}
+	for i := range tokens {
+		response.Tokens = append(response.Tokens, toAccessTokenPB(&tokens[i]))
+	}
+	return response, nil
+}
+
+func (s *AuthServer) RevokeAccessToken(ctx context.Context, req *authpb.RevokeAccessTokenRequest) (*authpb.RevokeAccessTokenResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.RevokeAccessTokenResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+	tokenID, err := uuid.Parse(req.TokenId)
+	if err != nil {
+		return &authpb.RevokeAccessTokenResponse{
+			Success: false,
+			Error:   "Invalid token ID",
+		}, nil
+	}
+
+	if err := s.AccessTokens.RevokeToken(ctx, userID, tokenID); err != nil {
+		return &authpb.RevokeAccessTokenResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.RevokeAccessTokenResponse{
+		Success: true,
+		Message: "Access token revoked",
+	}, nil
+}
+
+// ListSessions returns the login sessions of the user, one per device
+func (s *AuthServer) ListSessions(ctx context.Context, req *authpb.ListSessionsRequest) (*authpb.ListSessionsResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.ListSessionsResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+
+	sessions, err := s.Sessions.ListSessions(ctx, userID)
+	if err != nil {
+		return &authpb.ListSessionsResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	response := &authpb.ListSessionsResponse{
+		Sessions: make([]*authpb.Session, 0, len(sessions)),
+		Success:  true,
+	}
+	for i := range sessions {
+		response.Sessions = append(response.Sessions, toSessionPB(&sessions[i]))
+	}
+	return response, nil
+}
+
+// RevokeSession signs the user out of one device
+func (s *AuthServer) RevokeSession(ctx context.Context, req *authpb.RevokeSessionRequest) (*authpb.RevokeSessionResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &authpb.RevokeSessionResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		}, nil
+	}
+	sessionID, err := uuid.Parse(req.SessionId)
+	if err != nil {
+		return &authpb.RevokeSessionResponse{
+			Success: false,
+			Error:   "Invalid session ID",
+		}, nil
+	}
+
+	if err := s.Sessions.RevokeSession(ctx, userID, sessionID); err != nil {
+		return &authpb.RevokeSessionResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &authpb.RevokeSessionResponse{
+		Success: true,
+		Message: "Session revoked",
+	}, nil
+}
+
+// GetJWKS returns the public keys JWTs can be verified with
+func (s *AuthServer) GetJWKS(ctx context.Context, req *authpb.GetJWKSRequest) (*authpb.GetJWKSResponse, error) {
+	jwks := s.AuthService.PublicKeys()
+	resp := &authpb.GetJWKSResponse{Keys: make([]*authpb.JSONWebKey, 0, len(jwks.Keys))}
+	for _, key := range jwks.Keys {
+		resp.Keys = append(resp.Keys, &authpb.JSONWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Alg: key.Alg,
+			Use: key.Use,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return resp, nil
+}
+
+// toAccessTokenPB converts token metadata to its protobuf representation
+func toAccessTokenPB(token *models.AccessToken) *authpb.AccessToken {
+	pb := &authpb.AccessToken{
+		Id:        token.ID.String(),
+		Name:      token.Name,
+		Scopes:    token.ScopeList(),
+		CreatedAt: token.CreatedAt.Unix(),
+		ExpiresAt: token.ExpiresAt.Unix(),
+	}
+	if token.LastUsedAt != nil {
+		pb.LastUsedAt = token.LastUsedAt.Unix()
+	}
+	return pb
+}
+
+// toSessionPB converts a session to its protobuf representation
+func toSessionPB(session *models.Session) *authpb.Session {
+	return &authpb.Session{
+		Id:         session.ID.String(),
+		UserAgent:  session.UserAgent,
+		Ip:         session.IP,
+		CreatedAt:  session.CreatedAt.Unix(),
+		LastSeenAt: session.LastSeenAt.Unix(),
+	}
+}
+
+// toPasswordViolationsPB lists the password policy rules err reports as broken, nil for other errors
+func toPasswordViolationsPB(err error) []*authpb.PasswordViolation {
+	var policyErr *passwords.PolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+
+	violations := make([]*authpb.PasswordViolation, 0, len(policyErr.Violations))
+	for _, violation := range policyErr.Violations {
+		violations = append(violations, &authpb.PasswordViolation{
+			Code:    violation.Code,
+			Message: violation.Message,
+		})
+	}
+	return violations
+}
+
+// resetPasswordError is the message of a failed password reset for the client. Only invalid
+// tokens and passwords are explained, other errors are logged so that database errors do not reach clients.
+func resetPasswordError(err error) string {
+	var policyErr *passwords.PolicyError
+	if errors.As(err, &policyErr) || isAny(err, services.ErrInvalidResetToken, services.ErrEmptyPassword) {
+		return err.Error()
+	}
+
+	log.Printf("Password reset failed: %v", err)
+	return "Failed to reset password"
+}
+
+// tokenExpiry reads the exp claim of a token this service has just issued.
+// It returns 0 if the token carries no expiry.
+func tokenExpiry(token string) int64 {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return 0
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return 0
+	}
+	return exp.Unix()
+}
+
+// retryAfterSeconds is the number of whole seconds until retryAt, at least 1
+func retryAfterSeconds(retryAt time.Time) int64 {
+	seconds := int64(math.Ceil(time.Until(retryAt).Seconds()))
+	return max(seconds, 1)
+}
@@ -4,25 +4,42 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
 	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
 	"github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
 )
 
 type AuthServerTestSuite struct {
 	suite.Suite
-	mockAuthService *mocks.IAuthService
-	authServer      *server.AuthServer
-	ctx             context.Context
-	token           string
-	invalidToken    string
-	email           string
-	password        string
+	mockAuthService   *mocks.IAuthService
+	mockAccessTokens  *mocks.IAccessTokenService
+	mockRefreshTokens *mocks.IRefreshTokenService
+	mockResets        *mocks.IPasswordResetService
+	mockVerifications *mocks.IEmailVerificationService
+	mockAccounts      *mocks.IAccountService
+	mockDeletions     *mocks.IAccountDeletionService
+	mockTwoFactor     *mocks.ITwoFactorService
+	mockPasskeys      *mocks.IPasskeyService
+	mockMagicLinks    *mocks.IMagicLinkService
+	mockOIDCLogins    *mocks.IOIDCLoginService
+	mockSessions      *mocks.ISessionService
+	authServer        *server.AuthServer
+	ctx               context.Context
+	token             string
+	invalidToken      string
+	email             string
+	password          string
 }
 
 func (suite *AuthServerTestSuite) SetupSuite() {
@@ -34,12 +51,47 @@ func (suite *AuthServerTestSuite) SetupSuite() {
 
 func (suite *AuthServerTestSuite) SetupTest() {
 	suite.mockAuthService = new(mocks.IAuthService)
-	suite.authServer = server.NewAuthServer(suite.mockAuthService)
+	suite.mockAccessTokens = new(mocks.IAccessTokenService)
+	suite.mockRefreshTokens = new(mocks.IRefreshTokenService)
+	suite.mockResets = new(mocks.IPasswordResetService)
+	suite.mockVerifications = new(mocks.IEmailVerificationService)
+	suite.mockAccounts = new(mocks.IAccountService)
+	suite.mockDeletions = new(mocks.IAccountDeletionService)
+	suite.mockTwoFactor = new(mocks.ITwoFactorService)
+	suite.mockPasskeys = new(mocks.IPasskeyService)
+	suite.mockMagicLinks = new(mocks.IMagicLinkService)
+	suite.mockOIDCLogins = new(mocks.IOIDCLoginService)
+	suite.mockSessions = new(mocks.ISessionService)
+	suite.authServer = server.NewAuthServer(
+		suite.mockAuthService,
+		suite.mockAccessTokens,
+		suite.mockRefreshTokens,
+		suite.mockResets,
+		suite.mockVerifications,
+		suite.mockAccounts,
+		suite.mockDeletions,
+		suite.mockTwoFactor,
+		suite.mockPasskeys,
+		suite.mockMagicLinks,
+		suite.mockOIDCLogins,
+		suite.mockSessions,
+	)
 	suite.ctx = context.Background()
 }
 
 func (suite *AuthServerTestSuite) TearDownTest() {
 	suite.mockAuthService.AssertExpectations(suite.T())
+	suite.mockAccessTokens.AssertExpectations(suite.T())
+	suite.mockRefreshTokens.AssertExpectations(suite.T())
+	suite.mockResets.AssertExpectations(suite.T())
+	suite.mockVerifications.AssertExpectations(suite.T())
+	suite.mockAccounts.AssertExpectations(suite.T())
+	suite.mockDeletions.AssertExpectations(suite.T())
+	suite.mockTwoFactor.AssertExpectations(suite.T())
+	suite.mockPasskeys.AssertExpectations(suite.T())
+	suite.mockMagicLinks.AssertExpectations(suite.T())
+	suite.mockOIDCLogins.AssertExpectations(suite.T())
+	suite.mockSessions.AssertExpectations(suite.T())
 }
 
 // ===== VALIDATE TOKEN TESTS =====
@@ -47,9 +99,12 @@ func (suite *AuthServerTestSuite) TearDownTest() {
 func (suite *AuthServerTestSuite) TestValidateToken_Success() {
 	// Arrange
 	req := &authpb.TokenRequest{Token: suite.token}
+	expiresAt := time.Now().Add(time.Hour).Unix()
 	expectedClaims := jwt.MapClaims{
 		"user_id": "test-user-id",
 		"email":   suite.email,
+		"role":    "admin",
+		"exp":     float64(expiresAt),
 	}
 	suite.mockAuthService.On("ValidateToken", suite.ctx, suite.token).Return(expectedClaims, nil)
 
@@ -62,9 +117,68 @@ func (suite *AuthServerTestSuite) TestValidateToken_Success() {
 	suite.True(response.Valid)
 	suite.Equal("test-user-id", response.UserId)
 	suite.Equal("test@example.com", response.Email)
+	suite.Equal("admin", response.Role)
+	suite.Equal("jwt", response.TokenType)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Empty(response.Scopes)
 	suite.Empty(response.Error)
 }
 
+func (suite *AuthServerTestSuite) TestValidateToken_MissingRoleDefaultsToUser() {
+	// Arrange
+	req := &authpb.TokenRequest{Token: suite.token}
+	expectedClaims := jwt.MapClaims{
+		"user_id": "test-user-id",
+		"email":   suite.email,
+	}
+	suite.mockAuthService.On("ValidateToken", suite.ctx, suite.token).Return(expectedClaims, nil)
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, req)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Valid)
+	suite.Equal("user", response.Role)
+}
+
+func (suite *AuthServerTestSuite) TestValidateToken_ReadOnly() {
+	// Arrange
+	req := &authpb.TokenRequest{Token: suite.token}
+	expectedClaims := jwt.MapClaims{
+		"user_id":   "test-user-id",
+		"email":     suite.email,
+		"read_only": true,
+	}
+	suite.mockAuthService.On("ValidateToken", suite.ctx, suite.token).Return(expectedClaims, nil)
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, req)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Valid)
+	suite.True(response.ReadOnly)
+}
+
+func (suite *AuthServerTestSuite) TestValidateToken_SessionID() {
+	// Arrange
+	sessionID := uuid.NewString()
+	suite.mockAuthService.On("ValidateToken", suite.ctx, suite.token).Return(jwt.MapClaims{
+		"user_id": "test-user-id",
+		"email":   suite.email,
+		"sid":     sessionID,
+	}, nil)
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, &authpb.TokenRequest{Token: suite.token})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Valid)
+	suite.Equal(sessionID, response.SessionId)
+}
+
 func (suite *AuthServerTestSuite) TestValidateToken_InvalidToken() {
 	// Arrange
 	req := &authpb.TokenRequest{Token: suite.invalidToken}
@@ -139,6 +253,7 @@ func (suite *AuthServerTestSuite) TestRegister_Success() {
 	}
 
 	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).Return(expectedUser, nil)
+	suite.mockVerifications.On("SendVerificationEmail", suite.ctx, expectedUser).Return(nil)
 
 	// Act
 	response, err := suite.authServer.Register(suite.ctx, req)
@@ -153,6 +268,27 @@ func (suite *AuthServerTestSuite) TestRegister_Success() {
 	suite.Empty(response.Error)
 }
 
+func (suite *AuthServerTestSuite) TestRegister_VerificationEmailErrorIgnored() {
+	// Arrange
+	req := &authpb.RegisterRequest{
+		Email:    suite.email,
+		Password: suite.password,
+	}
+	expectedUser := &models.User{
+		ID:    uuid.New(),
+		Email: suite.email,
+	}
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).Return(expectedUser, nil)
+	suite.mockVerifications.On("SendVerificationEmail", suite.ctx, expectedUser).Return(errors.New("broker down"))
+
+	// Act
+	response, err := suite.authServer.Register(suite.ctx, req)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
 func (suite *AuthServerTestSuite) TestRegister_Error() {
 	// Arrange
 	req := &authpb.RegisterRequest{
@@ -173,12 +309,35 @@ func (suite *AuthServerTestSuite) TestRegister_Error() {
 	suite.Empty(response.Email)
 	suite.Empty(response.Message)
 	suite.Equal("user already exists", response.Error)
+	suite.Empty(response.PasswordViolations)
+}
+
+func (suite *AuthServerTestSuite) TestRegister_PasswordPolicyViolations() {
+	// Arrange
+	policyErr := &passwords.PolicyError{Violations: []passwords.Violation{
+		{Code: passwords.ViolationTooShort, Message: "must be at least 10 characters long"},
+		{Code: passwords.ViolationBreached, Message: "appears in a list of breached passwords, choose another one"},
+	}}
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, "123456").Return(nil, policyErr)
+
+	// Act
+	response, err := suite.authServer.Register(suite.ctx, &authpb.RegisterRequest{Email: suite.email, Password: "123456"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(policyErr.Error(), response.Error)
+	suite.Require().Len(response.PasswordViolations, 2)
+	suite.Equal(passwords.ViolationTooShort, response.PasswordViolations[0].Code)
+	suite.Equal("must be at least 10 characters long", response.PasswordViolations[0].Message)
+	suite.Equal(passwords.ViolationBreached, response.PasswordViolations[1].Code)
 }
 
 // ===== LOGIN TESTS =====
 
 func (suite *AuthServerTestSuite) TestLogin_Success() {
 	// Arrange
+	ctx := metadata.NewIncomingContext(suite.ctx, metadata.Pairs(services.ClientIPMetadataKey, "192.0.2.1"))
 	req := &authpb.LoginRequest{
 		Email:    suite.email,
 		Password: suite.password,
@@ -187,12 +346,17 @@ func (suite *AuthServerTestSuite) TestLogin_Success() {
 		ID:    uuid.New(),
 		Email: suite.email,
 	}
-	expectedToken := "jwt.token.here"
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	expectedToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL).Truncate(time.Second)
 
-	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password).Return(expectedToken, expectedUser, nil)
+	suite.mockAuthService.On("Login", ctx, suite.email, suite.password, "192.0.2.1").Return(expectedUser, nil)
+	suite.mockRefreshTokens.On("IssueTokens", ctx, expectedUser).
+		Return(&services.TokenPair{AccessToken: expectedToken, RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}, nil)
 
 	// Act
-	response, err := suite.authServer.Login(suite.ctx, req)
+	response, err := suite.authServer.Login(ctx, req)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -202,9 +366,28 @@ func (suite *AuthServerTestSuite) TestLogin_Success() {
 	suite.Equal(expectedUser.ID.String(), response.UserId)
 	suite.Equal(suite.email, response.Email)
 	suite.Equal("Successful login", response.Message)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_refresh", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
 	suite.Empty(response.Error)
 }
 
+func (suite *AuthServerTestSuite) TestLogin_RefreshTokenError() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password, "").Return(user, nil)
+	suite.mockRefreshTokens.On("IssueTokens", suite.ctx, user).Return(nil, errors.New("database is down"))
+
+	// Act
+	response, err := suite.authServer.Login(suite.ctx, &authpb.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Empty(response.Token)
+	suite.Equal("database is down", response.Error)
+}
+
 func (suite *AuthServerTestSuite) TestLogin_Error() {
 	// Arrange
 	req := &authpb.LoginRequest{
@@ -212,7 +395,7 @@ func (suite *AuthServerTestSuite) TestLogin_Error() {
 		Password: "wrongpassword",
 	}
 	expectedError := errors.New("invalid credentials")
-	suite.mockAuthService.On("Login", suite.ctx, suite.email, "wrongpassword").Return("", nil, expectedError)
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, "wrongpassword", "").Return(nil, expectedError)
 
 	// Act
 	response, err := suite.authServer.Login(suite.ctx, req)
@@ -226,9 +409,1210 @@ func (suite *AuthServerTestSuite) TestLogin_Error() {
 	suite.Empty(response.Email)
 	suite.Empty(response.Message)
 	suite.Equal("invalid credentials", response.Error)
+	suite.False(response.Locked)
+	suite.Zero(response.RetryAfter)
+}
+
+func (suite *AuthServerTestSuite) TestLogin_AccountLocked() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(suite.ctx, metadata.Pairs(services.ClientIPMetadataKey, "192.0.2.1"))
+	blocked := &services.LoginBlockedError{Err: services.ErrAccountLocked, RetryAt: time.Now().Add(10 * time.Minute)}
+	suite.mockAuthService.On("Login", ctx, suite.email, suite.password, "192.0.2.1").Return(nil, blocked)
+
+	// Act
+	response, err := suite.authServer.Login(ctx, &authpb.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.True(response.Locked)
+	suite.InDelta(600, response.RetryAfter, 1)
+	suite.Equal(services.ErrAccountLocked.Error(), response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestLogin_Throttled() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(suite.ctx, metadata.Pairs(services.ClientIPMetadataKey, "192.0.2.1"))
+	blocked := &services.LoginBlockedError{Err: services.ErrLoginThrottled, RetryAt: time.Now().Add(1500 * time.Millisecond)}
+	suite.mockAuthService.On("Login", ctx, suite.email, suite.password, "192.0.2.1").Return(nil, blocked)
+
+	// Act
+	response, err := suite.authServer.Login(ctx, &authpb.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.False(response.Locked)
+	suite.Equal(int64(2), response.RetryAfter)
+}
+
+func (suite *AuthServerTestSuite) TestLogin_SecondFactorRequired() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(services.TwoFactorChallengeTTL)
+	required := &services.SecondFactorRequiredError{Challenge: "tfc_challenge", ExpiresAt: expiresAt}
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password, "").Return(user, required)
+
+	// Act
+	response, err := suite.authServer.Login(suite.ctx, &authpb.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.True(response.SecondFactorRequired)
+	suite.Equal("tfc_challenge", response.Challenge)
+	suite.Equal(expiresAt.Unix(), response.ChallengeExpiresAt)
+	suite.Equal(user.ID.String(), response.UserId)
+	suite.Empty(response.Token)
+	suite.Empty(response.Error)
+}
+
+// ===== VERIFY SECOND FACTOR TESTS =====
+
+func (suite *AuthServerTestSuite) TestVerifySecondFactor_Success() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(suite.ctx, metadata.Pairs(services.ClientIPMetadataKey, "192.0.2.1"))
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL).Truncate(time.Second)
+	pair := &services.TokenPair{AccessToken: token, RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}
+	suite.mockTwoFactor.On("VerifySecondFactor", ctx, "tfc_challenge", "123456", "192.0.2.1").Return(pair, user, nil)
+
+	// Act
+	response, err := suite.authServer.VerifySecondFactor(ctx, &authpb.VerifySecondFactorRequest{
+		Challenge: "tfc_challenge",
+		Code:      "123456",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal(token, response.Token)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_refresh", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
+	suite.Equal(user.ID.String(), response.UserId)
+}
+
+func (suite *AuthServerTestSuite) TestVerifySecondFactor_InvalidCode() {
+	// Arrange
+	suite.mockTwoFactor.On("VerifySecondFactor", suite.ctx, "tfc_challenge", "000000", "").
+		Return(nil, nil, services.ErrInvalidSecondFactor)
+
+	// Act
+	response, err := suite.authServer.VerifySecondFactor(suite.ctx, &authpb.VerifySecondFactorRequest{Challenge: "tfc_challenge", Code: "000000"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrInvalidSecondFactor.Error(), response.Error)
+	suite.Zero(response.RetryAfter)
+}
+
+func (suite *AuthServerTestSuite) TestVerifySecondFactor_AccountLocked() {
+	// Arrange
+	blocked := &services.LoginBlockedError{Err: services.ErrAccountLocked, RetryAt: time.Now().Add(10 * time.Minute)}
+	suite.mockTwoFactor.On("VerifySecondFactor", suite.ctx, "tfc_challenge", "123456", "").Return(nil, nil, blocked)
+
+	// Act
+	response, err := suite.authServer.VerifySecondFactor(suite.ctx, &authpb.VerifySecondFactorRequest{Challenge: "tfc_challenge", Code: "123456"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.True(response.Locked)
+	suite.InDelta(600, response.RetryAfter, 1)
+}
+
+func (suite *AuthServerTestSuite) TestVerifySecondFactor_MissingCode() {
+	// Act
+	response, err := suite.authServer.VerifySecondFactor(suite.ctx, &authpb.VerifySecondFactorRequest{Challenge: "tfc_challenge"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Challenge and code are required", response.Error)
+}
+
+// ===== TWO-FACTOR MANAGEMENT TESTS =====
+
+func (suite *AuthServerTestSuite) TestEnrollTOTP_Success() {
+	// Arrange
+	userID := uuid.New()
+	enrollment := &services.TOTPEnrollment{Secret: "SECRET", URI: "otpauth://totp/subs-service:test@example.com?secret=SECRET"}
+	suite.mockTwoFactor.On("EnrollTOTP", suite.ctx, userID).Return(enrollment, nil)
+
+	// Act
+	response, err := suite.authServer.EnrollTOTP(suite.ctx, &authpb.EnrollTOTPRequest{UserId: userID.String()})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("SECRET", response.Secret)
+	suite.Equal(enrollment.URI, response.OtpauthUri)
+}
+
+func (suite *AuthServerTestSuite) TestEnrollTOTP_InvalidUserID() {
+	// Act
+	response, err := suite.authServer.EnrollTOTP(suite.ctx, &authpb.EnrollTOTPRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid user ID", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestConfirmTOTP_ReturnsRecoveryCodes() {
+	// Arrange
+	userID := uuid.New()
+	codes := []string{"aaaaa-bbbbb", "ccccc-ddddd"}
+	suite.mockTwoFactor.On("ConfirmTOTP", suite.ctx, userID, "123456").Return(codes, nil)
+
+	// Act
+	response, err := suite.authServer.ConfirmTOTP(suite.ctx, &authpb.ConfirmTOTPRequest{UserId: userID.String(), Code: "123456"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal(codes, response.RecoveryCodes)
+}
+
+func (suite *AuthServerTestSuite) TestConfirmTOTP_InvalidCode() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockTwoFactor.On("ConfirmTOTP", suite.ctx, userID, "000000").Return(nil, services.ErrInvalidSecondFactor)
+
+	// Act
+	response, err := suite.authServer.ConfirmTOTP(suite.ctx, &authpb.ConfirmTOTPRequest{UserId: userID.String(), Code: "000000"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Empty(response.RecoveryCodes)
+	suite.Equal(services.ErrInvalidSecondFactor.Error(), response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestDisableTOTP() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockTwoFactor.On("DisableTOTP", suite.ctx, userID, "current-password", "123456").Return(nil)
+
+	// Act
+	response, err := suite.authServer.DisableTOTP(suite.ctx, &authpb.DisableTOTPRequest{
+		UserId:          userID.String(),
+		CurrentPassword: "current-password",
+		Code:            "123456",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+// ===== PASSKEY TESTS =====
+
+func (suite *AuthServerTestSuite) TestBeginPasskeyRegistration_Success() {
+	// Arrange
+	userID := uuid.New()
+	options := []byte(`{"publicKey":{"challenge":"abc"}}`)
+	suite.mockPasskeys.On("BeginRegistration", suite.ctx, userID).Return("pkc_ceremony", options, nil)
+
+	// Act
+	response, err := suite.authServer.BeginPasskeyRegistration(suite.ctx, &authpb.BeginPasskeyRegistrationRequest{
+		UserId: userID.String(),
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("pkc_ceremony", response.Ceremony)
+	suite.JSONEq(string(options), response.Options)
+}
+
+func (suite *AuthServerTestSuite) TestBeginPasskeyRegistration_InvalidUserID() {
+	// Act
+	response, err := suite.authServer.BeginPasskeyRegistration(suite.ctx, &authpb.BeginPasskeyRegistrationRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid user ID", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestFinishPasskeyRegistration_Success() {
+	// Arrange
+	userID := uuid.New()
+	credential := `{"id":"cred"}`
+	suite.mockPasskeys.On("FinishRegistration", suite.ctx, userID, "pkc_ceremony", []byte(credential)).Return(nil)
+
+	// Act
+	response, err := suite.authServer.FinishPasskeyRegistration(suite.ctx, &authpb.FinishPasskeyRegistrationRequest{
+		UserId:     userID.String(),
+		Ceremony:   "pkc_ceremony",
+		Credential: credential,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("Passkey registered", response.Message)
+}
+
+func (suite *AuthServerTestSuite) TestFinishPasskeyRegistration_InvalidCeremony() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockPasskeys.On("FinishRegistration", suite.ctx, userID, "pkc_expired", mock.Anything).
+		Return(services.ErrInvalidPasskeyCeremony)
+
+	// Act
+	response, err := suite.authServer.FinishPasskeyRegistration(suite.ctx, &authpb.FinishPasskeyRegistrationRequest{
+		UserId:     userID.String(),
+		Ceremony:   "pkc_expired",
+		Credential: `{"id":"cred"}`,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrInvalidPasskeyCeremony.Error(), response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestFinishPasskeyRegistration_MissingCredential() {
+	// Act
+	response, err := suite.authServer.FinishPasskeyRegistration(suite.ctx, &authpb.FinishPasskeyRegistrationRequest{
+		UserId:   uuid.New().String(),
+		Ceremony: "pkc_ceremony",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Ceremony and credential are required", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestBeginPasskeyLogin_Success() {
+	// Arrange
+	options := []byte(`{"publicKey":{"challenge":"abc"}}`)
+	suite.mockPasskeys.On("BeginLogin", suite.ctx).Return("pkc_ceremony", options, nil)
+
+	// Act
+	response, err := suite.authServer.BeginPasskeyLogin(suite.ctx, &authpb.BeginPasskeyLoginRequest{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("pkc_ceremony", response.Ceremony)
+	suite.JSONEq(string(options), response.Options)
+}
+
+func (suite *AuthServerTestSuite) TestFinishPasskeyLogin_Success() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL).Truncate(time.Second)
+	pair := &services.TokenPair{AccessToken: token, RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}
+	credential := `{"id":"cred"}`
+	suite.mockPasskeys.On("FinishLogin", suite.ctx, "pkc_ceremony", []byte(credential)).Return(pair, user, nil)
+
+	// Act
+	response, err := suite.authServer.FinishPasskeyLogin(suite.ctx, &authpb.FinishPasskeyLoginRequest{
+		Ceremony:   "pkc_ceremony",
+		Credential: credential,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("Successful login", response.Message)
+	suite.Equal(token, response.Token)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_refresh", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
+	suite.Equal(user.ID.String(), response.UserId)
+	suite.Equal(suite.email, response.Email)
+}
+
+func (suite *AuthServerTestSuite) TestFinishPasskeyLogin_InvalidPasskey() {
+	// Arrange
+	suite.mockPasskeys.On("FinishLogin", suite.ctx, "pkc_ceremony", mock.Anything).Return(nil, nil, services.ErrInvalidPasskey)
+
+	// Act
+	response, err := suite.authServer.FinishPasskeyLogin(suite.ctx, &authpb.FinishPasskeyLoginRequest{
+		Ceremony:   "pkc_ceremony",
+		Credential: `{"id":"cred"}`,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrInvalidPasskey.Error(), response.Error)
+	suite.Empty(response.Token)
+}
+
+// ===== OIDC LOGIN TESTS =====
+
+func (suite *AuthServerTestSuite) TestLoginWithOIDC_Success() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL).Truncate(time.Second)
+	pair := &services.TokenPair{AccessToken: token, RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}
+	identity := services.OIDCIdentity{
+		Issuer:        "https://idp.example.com",
+		Subject:       "subject-1",
+		Email:         suite.email,
+		EmailVerified: true,
+	}
+	suite.mockOIDCLogins.On("LoginWithOIDC", suite.ctx, identity).Return(pair, user, nil)
+
+	// Act
+	response, err := suite.authServer.LoginWithOIDC(suite.ctx, &authpb.LoginWithOIDCRequest{
+		Issuer:        "https://idp.example.com",
+		Subject:       "subject-1",
+		Email:         suite.email,
+		EmailVerified: true,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal(token, response.Token)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_refresh", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
+	suite.Equal(user.ID.String(), response.UserId)
+}
+
+func (suite *AuthServerTestSuite) TestLoginWithOIDC_SecondFactorRequired() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(services.TwoFactorChallengeTTL)
+	required := &services.SecondFactorRequiredError{Challenge: "tfc_challenge", ExpiresAt: expiresAt}
+	suite.mockOIDCLogins.On("LoginWithOIDC", suite.ctx, mock.AnythingOfType("services.OIDCIdentity")).Return(nil, user, required)
+
+	// Act
+	response, err := suite.authServer.LoginWithOIDC(suite.ctx, &authpb.LoginWithOIDCRequest{
+		Issuer:  "https://idp.example.com",
+		Subject: "subject-1",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.True(response.SecondFactorRequired)
+	suite.Equal("tfc_challenge", response.Challenge)
+	suite.Equal(expiresAt.Unix(), response.ChallengeExpiresAt)
+	suite.Empty(response.Token)
+}
+
+func (suite *AuthServerTestSuite) TestLoginWithOIDC_EmailNotVerified() {
+	// Arrange
+	suite.mockOIDCLogins.On("LoginWithOIDC", suite.ctx, mock.AnythingOfType("services.OIDCIdentity")).Return(nil, nil, services.ErrOIDCEmailNotVerified)
+
+	// Act
+	response, err := suite.authServer.LoginWithOIDC(suite.ctx, &authpb.LoginWithOIDCRequest{
+		Issuer:  "https://idp.example.com",
+		Subject: "subject-1",
+		Email:   suite.email,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrOIDCEmailNotVerified.Error(), response.Error)
+	suite.Empty(response.Token)
+}
+
+// Run tests
+// ===== PERSONAL ACCESS TOKEN TESTS =====
+
+func (suite *AuthServerTestSuite) TestValidateToken_AccessToken() {
+	// Arrange
+	token := models.AccessTokenPrefix + "secret"
+	user := &models.User{ID: uuid.New(), Email: suite.email, Role: models.RoleSupport}
+	accessToken := &models.AccessToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Scopes:    "subscriptions:read admin",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	suite.mockAccessTokens.On("ValidateToken", suite.ctx, token).Return(accessToken, user, nil)
+	suite.mockAuthService.On("IsReadOnly", user).Return(true)
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, &authpb.TokenRequest{Token: token})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Valid)
+	suite.True(response.ReadOnly)
+	suite.Equal(user.ID.String(), response.UserId)
+	suite.Equal(suite.email, response.Email)
+	suite.Equal(models.RoleSupport, response.Role)
+	suite.Equal("pat", response.TokenType)
+	suite.Equal([]string{models.ScopeSubscriptionsRead, models.ScopeAdmin}, response.Scopes)
+	suite.Equal(accessToken.ExpiresAt.Unix(), response.ExpiresAt)
+	suite.mockAuthService.AssertNotCalled(suite.T(), "ValidateToken", suite.ctx, token)
+}
+
+func (suite *AuthServerTestSuite) TestValidateToken_InvalidAccessToken() {
+	// Arrange
+	token := models.AccessTokenPrefix + "revoked"
+	suite.mockAccessTokens.On("ValidateToken", suite.ctx, token).Return(nil, nil, errors.New("invalid access token"))
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, &authpb.TokenRequest{Token: token})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Valid)
+	suite.Equal("invalid access token", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestCreateAccessToken_Success() {
+	// Arrange
+	userID := uuid.New()
+	createdAt := time.Now().Truncate(time.Second)
+	accessToken := &models.AccessToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      "ci",
+		Scopes:    models.ScopeSubscriptionsRead,
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(30 * 24 * time.Hour),
+	}
+	suite.mockAccessTokens.On("CreateToken", suite.ctx, userID, "ci", []string{models.ScopeSubscriptionsRead}, 30*24*time.Hour).
+		Return("pat_secret", accessToken, nil)
+
+	// Act
+	response, err := suite.authServer.CreateAccessToken(suite.ctx, &authpb.CreateAccessTokenRequest{
+		UserId:        userID.String(),
+		Name:          "ci",
+		Scopes:        []string{models.ScopeSubscriptionsRead},
+		ExpiresInDays: 30,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("pat_secret", response.Token)
+	suite.Equal(accessToken.ID.String(), response.AccessToken.Id)
+	suite.Equal(accessToken.ExpiresAt.Unix(), response.AccessToken.ExpiresAt)
+	suite.Zero(response.AccessToken.LastUsedAt)
+}
+
+func (suite *AuthServerTestSuite) TestCreateAccessToken_InvalidUserID() {
+	// Act
+	response, err := suite.authServer.CreateAccessToken(suite.ctx, &authpb.CreateAccessTokenRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid user ID", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestCreateAccessToken_ServiceError() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockAccessTokens.On("CreateToken", suite.ctx, userID, "ci", []string{"bogus"}, time.Duration(0)).
+		Return("", nil, errors.New(`unknown scope "bogus"`))
+
+	// Act
+	response, err := suite.authServer.CreateAccessToken(suite.ctx, &authpb.CreateAccessTokenRequest{
+		UserId: userID.String(),
+		Name:   "ci",
+		Scopes: []string{"bogus"},
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Contains(response.Error, "unknown scope")
+}
+
+func (suite *AuthServerTestSuite) TestListAccessTokens_Success() {
+	// Arrange
+	userID := uuid.New()
+	lastUsed := time.Now().Truncate(time.Second)
+	tokens := []models.AccessToken{
+		{ID: uuid.New(), Name: "ci", Scopes: models.ScopeSubscriptionsRead, LastUsedAt: &lastUsed},
+		{ID: uuid.New(), Name: "backup", Scopes: models.ScopeSubscriptionsWrite},
+	}
+	suite.mockAccessTokens.On("ListTokens", suite.ctx, userID).Return(tokens, nil)
+
+	// Act
+	response, err := suite.authServer.ListAccessTokens(suite.ctx, &authpb.ListAccessTokensRequest{UserId: userID.String()})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Require().Len(response.Tokens, 2)
+	suite.Equal("ci", response.Tokens[0].Name)
+	suite.Equal(lastUsed.Unix(), response.Tokens[0].LastUsedAt)
+	suite.Equal([]string{models.ScopeSubscriptionsWrite}, response.Tokens[1].Scopes)
+}
+
+func (suite *AuthServerTestSuite) TestRevokeAccessToken_Success() {
+	// Arrange
+	userID, tokenID := uuid.New(), uuid.New()
+	suite.mockAccessTokens.On("RevokeToken", suite.ctx, userID, tokenID).Return(nil)
+
+	// Act
+	response, err := suite.authServer.RevokeAccessToken(suite.ctx, &authpb.RevokeAccessTokenRequest{
+		UserId:  userID.String(),
+		TokenId: tokenID.String(),
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestRevokeAccessToken_InvalidTokenID() {
+	// Act
+	response, err := suite.authServer.RevokeAccessToken(suite.ctx, &authpb.RevokeAccessTokenRequest{
+		UserId:  uuid.NewString(),
+		TokenId: "not-a-uuid",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid token ID", response.Error)
+}
+
+// ===== SESSION TESTS =====
+
+func (suite *AuthServerTestSuite) TestListSessions_Success() {
+	// Arrange
+	userID := uuid.New()
+	lastSeen := time.Now().Truncate(time.Second)
+	sessions := []models.Session{
+		{ID: uuid.New(), UserAgent: "Mozilla/5.0", IP: "203.0.113.7", CreatedAt: lastSeen.Add(-time.Hour), LastSeenAt: lastSeen},
+		{ID: uuid.New(), UserAgent: "curl/8.0", IP: "198.51.100.1"},
+	}
+	suite.mockSessions.On("ListSessions", suite.ctx, userID).Return(sessions, nil)
+
+	// Act
+	response, err := suite.authServer.ListSessions(suite.ctx, &authpb.ListSessionsRequest{UserId: userID.String()})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Require().Len(response.Sessions, 2)
+	suite.Equal(sessions[0].ID.String(), response.Sessions[0].Id)
+	suite.Equal("Mozilla/5.0", response.Sessions[0].UserAgent)
+	suite.Equal("203.0.113.7", response.Sessions[0].Ip)
+	suite.Equal(lastSeen.Add(-time.Hour).Unix(), response.Sessions[0].CreatedAt)
+	suite.Equal(lastSeen.Unix(), response.Sessions[0].LastSeenAt)
+	suite.Equal("curl/8.0", response.Sessions[1].UserAgent)
+}
+
+func (suite *AuthServerTestSuite) TestListSessions_InvalidUserID() {
+	// Act
+	response, err := suite.authServer.ListSessions(suite.ctx, &authpb.ListSessionsRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid user ID", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestRevokeSession_Success() {
+	// Arrange
+	userID, sessionID := uuid.New(), uuid.New()
+	suite.mockSessions.On("RevokeSession", suite.ctx, userID, sessionID).Return(nil)
+
+	// Act
+	response, err := suite.authServer.RevokeSession(suite.ctx, &authpb.RevokeSessionRequest{
+		UserId:    userID.String(),
+		SessionId: sessionID.String(),
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("Session revoked", response.Message)
+}
+
+func (suite *AuthServerTestSuite) TestRevokeSession_NotFound() {
+	// Arrange
+	userID, sessionID := uuid.New(), uuid.New()
+	suite.mockSessions.On("RevokeSession", suite.ctx, userID, sessionID).Return(errors.New("session not found"))
+
+	// Act
+	response, err := suite.authServer.RevokeSession(suite.ctx, &authpb.RevokeSessionRequest{
+		UserId:    userID.String(),
+		SessionId: sessionID.String(),
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("session not found", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestRevokeSession_InvalidSessionID() {
+	// Act
+	response, err := suite.authServer.RevokeSession(suite.ctx, &authpb.RevokeSessionRequest{
+		UserId:    uuid.NewString(),
+		SessionId: "not-a-uuid",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid session ID", response.Error)
+}
+
+// ===== REFRESH TESTS =====
+
+func (suite *AuthServerTestSuite) TestRefresh_Success() {
+	// Arrange
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	accessToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL)
+	suite.mockRefreshTokens.On("Refresh", suite.ctx, "rt_old").Return(&services.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     "rt_new",
+		RefreshExpiresAt: refreshExpiresAt,
+	}, &models.User{ID: uuid.New()}, nil)
+
+	// Act
+	response, err := suite.authServer.Refresh(suite.ctx, &authpb.RefreshRequest{RefreshToken: "rt_old"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal(accessToken, response.Token)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_new", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
+}
+
+func (suite *AuthServerTestSuite) TestRefresh_InvalidToken() {
+	// Arrange
+	suite.mockRefreshTokens.On("Refresh", suite.ctx, "rt_used").Return(nil, nil, services.ErrInvalidRefreshToken)
+
+	// Act
+	response, err := suite.authServer.Refresh(suite.ctx, &authpb.RefreshRequest{RefreshToken: "rt_used"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Empty(response.Token)
+	suite.Equal("invalid refresh token", response.Error)
+}
+
+// ===== LOGOUT TESTS =====
+
+func (suite *AuthServerTestSuite) TestLogout_RevokesBothTokens() {
+	// Arrange
+	suite.mockAuthService.On("RevokeToken", suite.ctx, "jwt-token").Return(nil)
+	suite.mockRefreshTokens.On("RevokeRefreshToken", suite.ctx, "rt_current").Return(nil)
+
+	// Act
+	response, err := suite.authServer.Logout(suite.ctx, &authpb.LogoutRequest{Token: "jwt-token", RefreshToken: "rt_current"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestLogout_SkipsInvalidTokens() {
+	// Arrange
+	suite.mockAuthService.On("RevokeToken", suite.ctx, "expired").Return(services.ErrInvalidToken)
+	suite.mockRefreshTokens.On("RevokeRefreshToken", suite.ctx, "rt_unknown").Return(services.ErrInvalidRefreshToken)
+
+	// Act
+	response, err := suite.authServer.Logout(suite.ctx, &authpb.LogoutRequest{Token: "expired", RefreshToken: "rt_unknown"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestLogout_RevocationError() {
+	// Arrange
+	suite.mockAuthService.On("RevokeToken", suite.ctx, "jwt-token").Return(errors.New("database is down"))
+
+	// Act
+	response, err := suite.authServer.Logout(suite.ctx, &authpb.LogoutRequest{Token: "jwt-token", RefreshToken: "rt_current"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("database is down", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestLogout_NoTokens() {
+	// Act
+	response, err := suite.authServer.Logout(suite.ctx, &authpb.LogoutRequest{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestLogoutAll_Success() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockRefreshTokens.On("RevokeAllRefreshTokens", suite.ctx, userID).Return(nil)
+	suite.mockAuthService.On("RevokeAllTokens", suite.ctx, userID).Return(nil)
+
+	// Act
+	response, err := suite.authServer.LogoutAll(suite.ctx, &authpb.LogoutAllRequest{UserId: userID.String()})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestLogoutAll_RefreshRevocationError() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockRefreshTokens.On("RevokeAllRefreshTokens", suite.ctx, userID).Return(errors.New("database is down"))
+
+	// Act
+	response, err := suite.authServer.LogoutAll(suite.ctx, &authpb.LogoutAllRequest{UserId: userID.String()})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.mockAuthService.AssertNotCalled(suite.T(), "RevokeAllTokens", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServerTestSuite) TestLogoutAll_InvalidUserID() {
+	// Act
+	response, err := suite.authServer.LogoutAll(suite.ctx, &authpb.LogoutAllRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid user ID", response.Error)
+}
+
+// ===== PASSWORD RESET TESTS =====
+
+func (suite *AuthServerTestSuite) TestRequestPasswordReset_Success() {
+	// Arrange
+	suite.mockResets.On("RequestPasswordReset", suite.ctx, suite.email).Return(nil)
+
+	// Act
+	response, err := suite.authServer.RequestPasswordReset(suite.ctx, &authpb.RequestPasswordResetRequest{Email: suite.email})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Contains(response.Message, "If the email is registered")
+}
+
+func (suite *AuthServerTestSuite) TestRequestPasswordReset_HidesErrorDetails() {
+	// Arrange
+	suite.mockResets.On("RequestPasswordReset", suite.ctx, suite.email).Return(errors.New("database is down"))
+
+	// Act
+	response, err := suite.authServer.RequestPasswordReset(suite.ctx, &authpb.RequestPasswordResetRequest{Email: suite.email})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Failed to request password reset", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestRequestPasswordReset_EmptyEmail() {
+	// Act
+	response, err := suite.authServer.RequestPasswordReset(suite.ctx, &authpb.RequestPasswordResetRequest{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestResetPassword_Success() {
+	// Arrange
+	suite.mockResets.On("ResetPassword", suite.ctx, "prt_token", suite.password).Return(nil)
+
+	// Act
+	response, err := suite.authServer.ResetPassword(suite.ctx, &authpb.ResetPasswordRequest{Token: "prt_token", NewPassword: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestResetPassword_InvalidToken() {
+	// Arrange
+	suite.mockResets.On("ResetPassword", suite.ctx, "prt_used", suite.password).Return(services.ErrInvalidResetToken)
+
+	// Act
+	response, err := suite.authServer.ResetPassword(suite.ctx, &authpb.ResetPasswordRequest{Token: "prt_used", NewPassword: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrInvalidResetToken.Error(), response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestResetPassword_PasswordPolicyViolations() {
+	// Arrange
+	policyErr := &passwords.PolicyError{Violations: []passwords.Violation{
+		{Code: passwords.ViolationMissingDigit, Message: "must contain a digit"},
+	}}
+	suite.mockResets.On("ResetPassword", suite.ctx, "prt_token", "weak").Return(policyErr)
+
+	// Act
+	response, err := suite.authServer.ResetPassword(suite.ctx, &authpb.ResetPasswordRequest{Token: "prt_token", NewPassword: "weak"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Require().Len(response.PasswordViolations, 1)
+	suite.Equal(passwords.ViolationMissingDigit, response.PasswordViolations[0].Code)
+}
+
+func (suite *AuthServerTestSuite) TestResetPassword_InternalErrorHidden() {
+	// Arrange
+	suite.mockResets.On("ResetPassword", suite.ctx, "prt_token", suite.password).
+		Return(errors.New(`failed to update password: pq: relation "users" does not exist`))
+
+	// Act
+	response, err := suite.authServer.ResetPassword(suite.ctx, &authpb.ResetPasswordRequest{Token: "prt_token", NewPassword: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Failed to reset password", response.Error)
+}
+
+// ===== MAGIC LINK TESTS =====
+
+func (suite *AuthServerTestSuite) TestRequestMagicLink_Success() {
+	// Arrange
+	suite.mockMagicLinks.On("RequestMagicLink", suite.ctx, suite.email).Return(nil)
+
+	// Act
+	response, err := suite.authServer.RequestMagicLink(suite.ctx, &authpb.RequestMagicLinkRequest{Email: suite.email})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Contains(response.Message, "If the email is registered")
+}
+
+func (suite *AuthServerTestSuite) TestRequestMagicLink_HidesErrorDetails() {
+	// Arrange
+	suite.mockMagicLinks.On("RequestMagicLink", suite.ctx, suite.email).Return(errors.New("database is down"))
+
+	// Act
+	response, err := suite.authServer.RequestMagicLink(suite.ctx, &authpb.RequestMagicLinkRequest{Email: suite.email})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Failed to request magic link", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestRequestMagicLink_EmptyEmail() {
+	// Act
+	response, err := suite.authServer.RequestMagicLink(suite.ctx, &authpb.RequestMagicLinkRequest{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestConsumeMagicLink_Success() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL).Truncate(time.Second)
+	pair := &services.TokenPair{AccessToken: token, RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}
+	suite.mockMagicLinks.On("ConsumeMagicLink", suite.ctx, "mlt_token").Return(pair, user, nil)
+
+	// Act
+	response, err := suite.authServer.ConsumeMagicLink(suite.ctx, &authpb.ConsumeMagicLinkRequest{Token: "mlt_token"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal(token, response.Token)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_refresh", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
+	suite.Equal(user.ID.String(), response.UserId)
+}
+
+func (suite *AuthServerTestSuite) TestConsumeMagicLink_SecondFactorRequired() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(services.TwoFactorChallengeTTL)
+	required := &services.SecondFactorRequiredError{Challenge: "tfc_challenge", ExpiresAt: expiresAt}
+	suite.mockMagicLinks.On("ConsumeMagicLink", suite.ctx, "mlt_token").Return(nil, user, required)
+
+	// Act
+	response, err := suite.authServer.ConsumeMagicLink(suite.ctx, &authpb.ConsumeMagicLinkRequest{Token: "mlt_token"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.True(response.SecondFactorRequired)
+	suite.Equal("tfc_challenge", response.Challenge)
+	suite.Equal(expiresAt.Unix(), response.ChallengeExpiresAt)
+	suite.Empty(response.Token)
+}
+
+func (suite *AuthServerTestSuite) TestConsumeMagicLink_InvalidToken() {
+	// Arrange
+	suite.mockMagicLinks.On("ConsumeMagicLink", suite.ctx, "mlt_used").Return(nil, nil, services.ErrInvalidMagicLink)
+
+	// Act
+	response, err := suite.authServer.ConsumeMagicLink(suite.ctx, &authpb.ConsumeMagicLinkRequest{Token: "mlt_used"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrInvalidMagicLink.Error(), response.Error)
+}
+
+// ===== EMAIL VERIFICATION TESTS =====
+
+func (suite *AuthServerTestSuite) TestVerifyEmail_Success() {
+	// Arrange
+	suite.mockVerifications.On("VerifyEmail", suite.ctx, "evt_token").Return(nil)
+
+	// Act
+	response, err := suite.authServer.VerifyEmail(suite.ctx, &authpb.VerifyEmailRequest{Token: "evt_token"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestVerifyEmail_InvalidToken() {
+	// Arrange
+	suite.mockVerifications.On("VerifyEmail", suite.ctx, "evt_used").Return(services.ErrInvalidVerificationToken)
+
+	// Act
+	response, err := suite.authServer.VerifyEmail(suite.ctx, &authpb.VerifyEmailRequest{Token: "evt_used"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrInvalidVerificationToken.Error(), response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestResendVerificationEmail_Success() {
+	// Arrange
+	suite.mockVerifications.On("ResendVerificationEmail", suite.ctx, suite.email).Return(nil)
+
+	// Act
+	response, err := suite.authServer.ResendVerificationEmail(suite.ctx, &authpb.ResendVerificationEmailRequest{Email: suite.email})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestResendVerificationEmail_HidesErrorDetails() {
+	// Arrange
+	suite.mockVerifications.On("ResendVerificationEmail", suite.ctx, suite.email).Return(errors.New("database is down"))
+
+	// Act
+	response, err := suite.authServer.ResendVerificationEmail(suite.ctx, &authpb.ResendVerificationEmailRequest{Email: suite.email})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Failed to send verification email", response.Error)
+}
+
+// ===== CREDENTIAL CHANGE TESTS =====
+
+func (suite *AuthServerTestSuite) TestChangePassword_Success() {
+	// Arrange
+	userID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	accessToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL)
+	suite.mockAccounts.On("ChangePassword", suite.ctx, userID, suite.password, "new-password").Return(&services.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     "rt_new",
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil)
+
+	// Act
+	response, err := suite.authServer.ChangePassword(suite.ctx, &authpb.ChangePasswordRequest{
+		UserId:          userID.String(),
+		CurrentPassword: suite.password,
+		NewPassword:     "new-password",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal(accessToken, response.Token)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_new", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
+}
+
+func (suite *AuthServerTestSuite) TestChangePassword_IncorrectPassword() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockAccounts.On("ChangePassword", suite.ctx, userID, "wrong", "new-password").Return(nil, services.ErrIncorrectPassword)
+
+	// Act
+	response, err := suite.authServer.ChangePassword(suite.ctx, &authpb.ChangePasswordRequest{
+		UserId:          userID.String(),
+		CurrentPassword: "wrong",
+		NewPassword:     "new-password",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrIncorrectPassword.Error(), response.Error)
+	suite.Empty(response.PasswordViolations)
+}
+
+func (suite *AuthServerTestSuite) TestChangePassword_PasswordPolicyViolations() {
+	// Arrange
+	userID := uuid.New()
+	policyErr := &passwords.PolicyError{Violations: []passwords.Violation{
+		{Code: passwords.ViolationMissingSymbol, Message: "must contain a symbol"},
+	}}
+	suite.mockAccounts.On("ChangePassword", suite.ctx, userID, suite.password, "Password1234").Return(nil, policyErr)
+
+	// Act
+	response, err := suite.authServer.ChangePassword(suite.ctx, &authpb.ChangePasswordRequest{
+		UserId:          userID.String(),
+		CurrentPassword: suite.password,
+		NewPassword:     "Password1234",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Require().Len(response.PasswordViolations, 1)
+	suite.Equal(passwords.ViolationMissingSymbol, response.PasswordViolations[0].Code)
+	suite.Equal("must contain a symbol", response.PasswordViolations[0].Message)
+}
+
+func (suite *AuthServerTestSuite) TestChangePassword_InvalidUserID() {
+	// Act
+	response, err := suite.authServer.ChangePassword(suite.ctx, &authpb.ChangePasswordRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid user ID", response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestChangeEmail_Success() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockAccounts.On("ChangeEmail", suite.ctx, userID, suite.password, "new@example.com").Return(nil)
+
+	// Act
+	response, err := suite.authServer.ChangeEmail(suite.ctx, &authpb.ChangeEmailRequest{
+		UserId:          userID.String(),
+		CurrentPassword: suite.password,
+		NewEmail:        "new@example.com",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+}
+
+func (suite *AuthServerTestSuite) TestChangeEmail_EmailTaken() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockAccounts.On("ChangeEmail", suite.ctx, userID, suite.password, "taken@example.com").Return(services.ErrEmailTaken)
+
+	// Act
+	response, err := suite.authServer.ChangeEmail(suite.ctx, &authpb.ChangeEmailRequest{
+		UserId:          userID.String(),
+		CurrentPassword: suite.password,
+		NewEmail:        "taken@example.com",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrEmailTaken.Error(), response.Error)
+}
+
+// ===== DELETE ACCOUNT TESTS =====
+
+func (suite *AuthServerTestSuite) TestDeleteAccount_Success() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockDeletions.On("DeleteAccount", suite.ctx, userID, suite.password).Return(nil)
+
+	// Act
+	response, err := suite.authServer.DeleteAccount(suite.ctx, &authpb.DeleteAccountRequest{
+		UserId:          userID.String(),
+		CurrentPassword: suite.password,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal("Account deleted", response.Message)
+}
+
+func (suite *AuthServerTestSuite) TestDeleteAccount_IncorrectPassword() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockDeletions.On("DeleteAccount", suite.ctx, userID, "wrong").Return(services.ErrIncorrectPassword)
+
+	// Act
+	response, err := suite.authServer.DeleteAccount(suite.ctx, &authpb.DeleteAccountRequest{
+		UserId:          userID.String(),
+		CurrentPassword: "wrong",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal(services.ErrIncorrectPassword.Error(), response.Error)
+}
+
+func (suite *AuthServerTestSuite) TestDeleteAccount_InvalidUserID() {
+	// Act
+	response, err := suite.authServer.DeleteAccount(suite.ctx, &authpb.DeleteAccountRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(response.Success)
+	suite.Equal("Invalid user ID", response.Error)
+}
+
+// ===== GET JWKS TESTS =====
+
+func (suite *AuthServerTestSuite) TestGetJWKS_Success() {
+	// Arrange
+	suite.mockAuthService.On("PublicKeys").Return(jwtkeys.JWKS{Keys: []jwtkeys.JWK{
+		{Kty: "OKP", Kid: "ed-1", Alg: "EdDSA", Use: "sig", Crv: "Ed25519", X: "x-value"},
+	}})
+
+	// Act
+	response, err := suite.authServer.GetJWKS(suite.ctx, &authpb.GetJWKSRequest{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Keys, 1)
+	suite.Equal("ed-1", response.Keys[0].Kid)
+	suite.Equal("Ed25519", response.Keys[0].Crv)
+	suite.Equal("x-value", response.Keys[0].X)
 }
 
-// Run tests
 func TestAuthServerTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthServerTestSuite))
 }
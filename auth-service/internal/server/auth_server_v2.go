@@ -0,0 +1,701 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	authpbv2 "github.com/Koshsky/subs-service/auth-service/internal/authpb/v2"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthServerV2 implements version 2 of the auth API, failures are returned as gRPC status
+// errors with google.rpc error details, see statusError
+type AuthServerV2 struct {
+	authpbv2.UnimplementedAuthServiceServer
+	Services
+}
+
+func NewAuthServerV2(services Services) *AuthServerV2 {
+	return &AuthServerV2{Services: services}
+}
+
+// ValidateToken returns the user a JWT or personal access token belongs to.
+// Invalid, expired and revoked tokens are reported as UNAUTHENTICATED.
+func (s *AuthServerV2) ValidateToken(ctx context.Context, req *authpbv2.ValidateTokenRequest) (*authpbv2.ValidateTokenResponse, error) {
+	if req.Token == "" {
+		return nil, invalidArgument("token", "REQUIRED", "Token is required")
+	}
+	if strings.HasPrefix(req.Token, models.AccessTokenPrefix) {
+		return s.validateAccessToken(ctx, req.Token)
+	}
+
+	claims, err := s.AuthService.ValidateToken(ctx, req.Token)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	userIDStr, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid user ID in token")
+	}
+
+	email, ok := claims["email"].(string)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid email in token")
+	}
+
+	// Tokens issued before roles were introduced carry no role claim
+	role, ok := claims["role"].(string)
+	if !ok || role == "" {
+		role = models.RoleUser
+	}
+
+	readOnly, _ := claims["read_only"].(bool)
+	sessionID, _ := claims["sid"].(string)
+
+	response := &authpbv2.ValidateTokenResponse{
+		UserId:    userIDStr,
+		Email:     email,
+		Role:      role,
+		TokenType: tokenTypeJWT,
+		ReadOnly:  readOnly,
+		SessionId: sessionID,
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		response.ExpiresAt = exp.Unix()
+	}
+	return response, nil
+}
+
+// validateAccessToken validates a personal access token.
+// The role is read from the user record so that demoting a user also limits their tokens.
+func (s *AuthServerV2) validateAccessToken(ctx context.Context, token string) (*authpbv2.ValidateTokenResponse, error) {
+	if s.AccessTokens == nil {
+		return nil, status.Error(codes.Unauthenticated, "personal access tokens are not supported")
+	}
+
+	accessToken, user, err := s.AccessTokens.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+
+	return &authpbv2.ValidateTokenResponse{
+		UserId:    user.ID.String(),
+		Email:     user.Email,
+		Role:      role,
+		Scopes:    accessToken.ScopeList(),
+		TokenType: tokenTypePAT,
+		ExpiresAt: accessToken.ExpiresAt.Unix(),
+		ReadOnly:  s.AuthService.IsReadOnly(user),
+	}, nil
+}
+
+func (s *AuthServerV2) Register(ctx context.Context, req *authpbv2.RegisterRequest) (*authpbv2.RegisterResponse, error) {
+	user, err := s.AuthService.Register(ctx, req.Email, req.Password)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	// The account is created either way, the user can ask for another email later
+	if err := s.EmailVerifications.SendVerificationEmail(ctx, user); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
+	return &authpbv2.RegisterResponse{
+		UserId: user.ID.String(),
+		Email:  user.Email,
+	}, nil
+}
+
+// Login signs the user in. While failed attempts are throttled or the account is locked,
+// RESOURCE_EXHAUSTED is returned with RetryInfo. Accounts with two-factor authentication
+// get SecondFactorRequired and a Challenge for VerifySecondFactor instead of tokens.
+func (s *AuthServerV2) Login(ctx context.Context, req *authpbv2.LoginRequest) (*authpbv2.LoginResponse, error) {
+	user, err := s.AuthService.Login(ctx, req.Email, req.Password, services.ClientIP(ctx))
+	var required *services.SecondFactorRequiredError
+	if errors.As(err, &required) {
+		return &authpbv2.LoginResponse{
+			UserId:               user.ID.String(),
+			Email:                user.Email,
+			SecondFactorRequired: true,
+			Challenge:            required.Challenge,
+			ChallengeExpiresAt:   required.ExpiresAt.Unix(),
+		}, nil
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	pair, err := s.RefreshTokens.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.LoginResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// VerifySecondFactor completes a login that returned SecondFactorRequired with an
+// authenticator or recovery code. Wrong codes are limited like failed logins.
+func (s *AuthServerV2) VerifySecondFactor(ctx context.Context, req *authpbv2.VerifySecondFactorRequest) (*authpbv2.VerifySecondFactorResponse, error) {
+	if req.Challenge == "" {
+		return nil, invalidArgument("challenge", "REQUIRED", "Challenge is required")
+	}
+	if req.Code == "" {
+		return nil, invalidArgument("code", "REQUIRED", "Code is required")
+	}
+
+	pair, user, err := s.TwoFactor.VerifySecondFactor(ctx, req.Challenge, req.Code, services.ClientIP(ctx))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.VerifySecondFactorResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *AuthServerV2) Refresh(ctx context.Context, req *authpbv2.RefreshRequest) (*authpbv2.RefreshResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, invalidArgument("refresh_token", "REQUIRED", "Refresh token is required")
+	}
+
+	pair, _, err := s.RefreshTokens.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.RefreshResponse{
+		Token:            pair.AccessToken,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// Logout revokes the access token and the login the refresh token belongs to.
+// Tokens that are already invalid are skipped, so logging out twice succeeds.
+func (s *AuthServerV2) Logout(ctx context.Context, req *authpbv2.LogoutRequest) (*authpbv2.LogoutResponse, error) {
+	if req.Token == "" && req.RefreshToken == "" {
+		return nil, invalidArgument("token", "REQUIRED", "No token to revoke")
+	}
+
+	if req.Token != "" {
+		if err := s.AuthService.RevokeToken(ctx, req.Token); err != nil && !errors.Is(err, services.ErrInvalidToken) {
+			return nil, statusError(err)
+		}
+	}
+
+	if req.RefreshToken != "" {
+		if err := s.RefreshTokens.RevokeRefreshToken(ctx, req.RefreshToken); err != nil && !errors.Is(err, services.ErrInvalidRefreshToken) {
+			return nil, statusError(err)
+		}
+	}
+
+	return &authpbv2.LogoutResponse{}, nil
+}
+
+// LogoutAll signs the user out everywhere: all refresh tokens are revoked first,
+// so that no new JWT can be issued, then all previously issued JWTs
+func (s *AuthServerV2) LogoutAll(ctx context.Context, req *authpbv2.LogoutAllRequest) (*authpbv2.LogoutAllResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RefreshTokens.RevokeAllRefreshTokens(ctx, userID); err != nil {
+		return nil, statusError(err)
+	}
+	if err := s.AuthService.RevokeAllTokens(ctx, userID); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.LogoutAllResponse{}, nil
+}
+
+// RequestPasswordReset emails a reset link to the account. The response is the same
+// for registered and unknown emails, so that it cannot be used to discover accounts.
+func (s *AuthServerV2) RequestPasswordReset(ctx context.Context, req *authpbv2.RequestPasswordResetRequest) (*authpbv2.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return nil, invalidArgument("email", "REQUIRED", "Email is required")
+	}
+
+	if err := s.PasswordResets.RequestPasswordReset(ctx, req.Email); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.RequestPasswordResetResponse{}, nil
+}
+
+// ResetPassword sets a new password with a token from a password reset email
+func (s *AuthServerV2) ResetPassword(ctx context.Context, req *authpbv2.ResetPasswordRequest) (*authpbv2.ResetPasswordResponse, error) {
+	if err := s.PasswordResets.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		return nil, newCredentialFields.statusError(err)
+	}
+
+	return &authpbv2.ResetPasswordResponse{}, nil
+}
+
+// RequestMagicLink emails a sign-in link to the account. The response is the same
+// for registered and unknown emails, so that it cannot be used to discover accounts.
+func (s *AuthServerV2) RequestMagicLink(ctx context.Context, req *authpbv2.RequestMagicLinkRequest) (*authpbv2.RequestMagicLinkResponse, error) {
+	if req.Email == "" {
+		return nil, invalidArgument("email", "REQUIRED", "Email is required")
+	}
+
+	if err := s.MagicLinks.RequestMagicLink(ctx, req.Email); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.RequestMagicLinkResponse{}, nil
+}
+
+// ConsumeMagicLink signs the user in with a token from a magic link email. Accounts with
+// two-factor authentication get SecondFactorRequired and a Challenge for VerifySecondFactor instead of tokens.
+func (s *AuthServerV2) ConsumeMagicLink(ctx context.Context, req *authpbv2.ConsumeMagicLinkRequest) (*authpbv2.ConsumeMagicLinkResponse, error) {
+	if req.Token == "" {
+		return nil, invalidArgument("token", "REQUIRED", "Token is required")
+	}
+
+	pair, user, err := s.MagicLinks.ConsumeMagicLink(ctx, req.Token)
+	var required *services.SecondFactorRequiredError
+	if errors.As(err, &required) {
+		return &authpbv2.ConsumeMagicLinkResponse{
+			UserId:               user.ID.String(),
+			Email:                user.Email,
+			SecondFactorRequired: true,
+			Challenge:            required.Challenge,
+			ChallengeExpiresAt:   required.ExpiresAt.Unix(),
+		}, nil
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.ConsumeMagicLinkResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// VerifyEmail confirms the user's email address with a token from the verification email
+func (s *AuthServerV2) VerifyEmail(ctx context.Context, req *authpbv2.VerifyEmailRequest) (*authpbv2.VerifyEmailResponse, error) {
+	if err := s.EmailVerifications.VerifyEmail(ctx, req.Token); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.VerifyEmailResponse{}, nil
+}
+
+// ResendVerificationEmail sends a new verification link. The response is the same
+// for registered and unknown emails, so that it cannot be used to discover accounts.
+func (s *AuthServerV2) ResendVerificationEmail(ctx context.Context, req *authpbv2.ResendVerificationEmailRequest) (*authpbv2.ResendVerificationEmailResponse, error) {
+	if req.Email == "" {
+		return nil, invalidArgument("email", "REQUIRED", "Email is required")
+	}
+
+	if err := s.EmailVerifications.ResendVerificationEmail(ctx, req.Email); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.ResendVerificationEmailResponse{}, nil
+}
+
+// ChangePassword replaces the password of the signed-in user and returns new tokens
+// for the calling session; every other session of the user is revoked
+func (s *AuthServerV2) ChangePassword(ctx context.Context, req *authpbv2.ChangePasswordRequest) (*authpbv2.ChangePasswordResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := s.Accounts.ChangePassword(ctx, userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		return nil, newCredentialFields.statusError(err)
+	}
+
+	return &authpbv2.ChangePasswordResponse{
+		Token:            pair.AccessToken,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// ChangeEmail emails a verification link to the new address of the signed-in user.
+// The email is replaced once the link is used.
+func (s *AuthServerV2) ChangeEmail(ctx context.Context, req *authpbv2.ChangeEmailRequest) (*authpbv2.ChangeEmailResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if req.NewEmail == "" {
+		return nil, invalidArgument("new_email", "REQUIRED", "Email is required")
+	}
+
+	if err := s.Accounts.ChangeEmail(ctx, userID, req.CurrentPassword, req.NewEmail); err != nil {
+		return nil, newCredentialFields.statusError(err)
+	}
+
+	return &authpbv2.ChangeEmailResponse{}, nil
+}
+
+// DeleteAccount deletes the signed-in user's account. The user's data in other
+// services is deleted asynchronously after the user.deleted event.
+func (s *AuthServerV2) DeleteAccount(ctx context.Context, req *authpbv2.DeleteAccountRequest) (*authpbv2.DeleteAccountResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Deletions.DeleteAccount(ctx, userID, req.CurrentPassword); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.DeleteAccountResponse{}, nil
+}
+
+// EnrollTOTP starts the enrollment of an authenticator app for the signed-in user.
+// Logins need a code once the enrollment is confirmed with ConfirmTOTP.
+func (s *AuthServerV2) EnrollTOTP(ctx context.Context, req *authpbv2.EnrollTOTPRequest) (*authpbv2.EnrollTOTPResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.TwoFactor.EnrollTOTP(ctx, userID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.EnrollTOTPResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication with a first code from the authenticator app
+// and returns the recovery codes. A wrong code is INVALID_ARGUMENT rather than UNAUTHENTICATED,
+// the caller is signed in.
+func (s *AuthServerV2) ConfirmTOTP(ctx context.Context, req *authpbv2.ConfirmTOTPRequest) (*authpbv2.ConfirmTOTPResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.TwoFactor.ConfirmTOTP(ctx, userID, req.Code)
+	if errors.Is(err, services.ErrInvalidSecondFactor) {
+		return nil, invalidArgument("code", "INVALID_CODE", err.Error())
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP turns two-factor authentication off for the signed-in user
+func (s *AuthServerV2) DisableTOTP(ctx context.Context, req *authpbv2.DisableTOTPRequest) (*authpbv2.DisableTOTPResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.TwoFactor.DisableTOTP(ctx, userID, req.CurrentPassword, req.Code)
+	if errors.Is(err, services.ErrInvalidSecondFactor) {
+		return nil, invalidArgument("code", "INVALID_CODE", err.Error())
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.DisableTOTPResponse{}, nil
+}
+
+// BeginPasskeyRegistration returns the WebAuthn creation options for a new passkey of the
+// signed-in user, together with the ceremony token FinishPasskeyRegistration needs
+func (s *AuthServerV2) BeginPasskeyRegistration(ctx context.Context, req *authpbv2.BeginPasskeyRegistrationRequest) (*authpbv2.BeginPasskeyRegistrationResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ceremony, options, err := s.Passkeys.BeginRegistration(ctx, userID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.BeginPasskeyRegistrationResponse{
+		Ceremony: ceremony,
+		Options:  string(options),
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies the authenticator's registration response and stores the passkey.
+// A rejected credential is INVALID_ARGUMENT rather than UNAUTHENTICATED, the caller is signed in.
+func (s *AuthServerV2) FinishPasskeyRegistration(ctx context.Context, req *authpbv2.FinishPasskeyRegistrationRequest) (*authpbv2.FinishPasskeyRegistrationResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Ceremony == "" {
+		return nil, invalidArgument("ceremony", "REQUIRED", "Ceremony is required")
+	}
+	if req.Credential == "" {
+		return nil, invalidArgument("credential", "REQUIRED", "Credential is required")
+	}
+
+	err = s.Passkeys.FinishRegistration(ctx, userID, req.Ceremony, []byte(req.Credential))
+	switch {
+	case errors.Is(err, services.ErrInvalidPasskeyCeremony):
+		return nil, invalidArgument("ceremony", "INVALID_CEREMONY", err.Error())
+	case errors.Is(err, services.ErrInvalidPasskey):
+		return nil, invalidArgument("credential", "INVALID_CREDENTIAL", err.Error())
+	case err != nil:
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.FinishPasskeyRegistrationResponse{}, nil
+}
+
+// BeginPasskeyLogin returns the WebAuthn request options of a passwordless login. The user
+// is not known yet, the authenticator offers the passkeys it holds for the relying party.
+func (s *AuthServerV2) BeginPasskeyLogin(ctx context.Context, req *authpbv2.BeginPasskeyLoginRequest) (*authpbv2.BeginPasskeyLoginResponse, error) {
+	ceremony, options, err := s.Passkeys.BeginLogin(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.BeginPasskeyLoginResponse{
+		Ceremony: ceremony,
+		Options:  string(options),
+	}, nil
+}
+
+// FinishPasskeyLogin verifies the assertion of a passkey and issues tokens for its owner
+func (s *AuthServerV2) FinishPasskeyLogin(ctx context.Context, req *authpbv2.FinishPasskeyLoginRequest) (*authpbv2.FinishPasskeyLoginResponse, error) {
+	if req.Ceremony == "" {
+		return nil, invalidArgument("ceremony", "REQUIRED", "Ceremony is required")
+	}
+	if req.Credential == "" {
+		return nil, invalidArgument("credential", "REQUIRED", "Credential is required")
+	}
+
+	pair, user, err := s.Passkeys.FinishLogin(ctx, req.Ceremony, []byte(req.Credential))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.FinishPasskeyLoginResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+// LoginWithOIDC signs in with an account at an OpenID Connect identity provider.
+// core-service validated the ID token, the identity is linked to an account on first use.
+func (s *AuthServerV2) LoginWithOIDC(ctx context.Context, req *authpbv2.LoginWithOIDCRequest) (*authpbv2.LoginWithOIDCResponse, error) {
+	pair, user, err := s.OIDCLogins.LoginWithOIDC(ctx, services.OIDCIdentity{
+		Issuer:        req.Issuer,
+		Subject:       req.Subject,
+		Email:         req.Email,
+		EmailVerified: req.EmailVerified,
+	})
+	var required *services.SecondFactorRequiredError
+	if errors.As(err, &required) {
+		return &authpbv2.LoginWithOIDCResponse{
+			UserId:               user.ID.String(),
+			Email:                user.Email,
+			SecondFactorRequired: true,
+			Challenge:            required.Challenge,
+			ChallengeExpiresAt:   required.ExpiresAt.Unix(),
+		}, nil
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.LoginWithOIDCResponse{
+		Token:            pair.AccessToken,
+		UserId:           user.ID.String(),
+		Email:            user.Email,
+		ExpiresAt:        tokenExpiry(pair.AccessToken),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *AuthServerV2) CreateAccessToken(ctx context.Context, req *authpbv2.CreateAccessTokenRequest) (*authpbv2.CreateAccessTokenResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, accessToken, err := s.AccessTokens.CreateToken(ctx, userID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.CreateAccessTokenResponse{
+		Token:       token,
+		AccessToken: toAccessTokenPBV2(accessToken),
+	}, nil
+}
+
+func (s *AuthServerV2) ListAccessTokens(ctx context.Context, req *authpbv2.ListAccessTokensRequest) (*authpbv2.ListAccessTokensResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.AccessTokens.ListTokens(ctx, userID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &authpbv2.ListAccessTokensResponse{Tokens: make([]*authpbv2.AccessToken, 0, len(tokens))}
+	for i := range tokens {
+		response.Tokens = append(response.Tokens, toAccessTokenPBV2(&tokens[i]))
+	}
+	return response, nil
+}
+
+func (s *AuthServerV2) RevokeAccessToken(ctx context.Context, req *authpbv2.RevokeAccessTokenRequest) (*authpbv2.RevokeAccessTokenResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	tokenID, err := uuid.Parse(req.TokenId)
+	if err != nil {
+		return nil, invalidArgument("token_id", "INVALID_UUID", "Invalid token ID")
+	}
+
+	if err := s.AccessTokens.RevokeToken(ctx, userID, tokenID); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.RevokeAccessTokenResponse{}, nil
+}
+
+// ListSessions returns the login sessions of the user, one per device
+func (s *AuthServerV2) ListSessions(ctx context.Context, req *authpbv2.ListSessionsRequest) (*authpbv2.ListSessionsResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.Sessions.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &authpbv2.ListSessionsResponse{Sessions: make([]*authpbv2.Session, 0, len(sessions))}
+	for i := range sessions {
+		response.Sessions = append(response.Sessions, toSessionPBV2(&sessions[i]))
+	}
+	return response, nil
+}
+
+// RevokeSession signs the user out of one device
+func (s *AuthServerV2) RevokeSession(ctx context.Context, req *authpbv2.RevokeSessionRequest) (*authpbv2.RevokeSessionResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	sessionID, err := uuid.Parse(req.SessionId)
+	if err != nil {
+		return nil, invalidArgument("session_id", "INVALID_UUID", "Invalid session ID")
+	}
+
+	if err := s.Sessions.RevokeSession(ctx, userID, sessionID); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &authpbv2.RevokeSessionResponse{}, nil
+}
+
+// GetJWKS returns the public keys JWTs can be verified with
+func (s *AuthServerV2) GetJWKS(ctx context.Context, req *authpbv2.GetJWKSRequest) (*authpbv2.GetJWKSResponse, error) {
+	jwks := s.AuthService.PublicKeys()
+	resp := &authpbv2.GetJWKSResponse{Keys: make([]*authpbv2.JSONWebKey, 0, len(jwks.Keys))}
+	for _, key := range jwks.Keys {
+		resp.Keys = append(resp.Keys, &authpbv2.JSONWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Alg: key.Alg,
+			Use: key.Use,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return resp, nil
+}
+
+// parseUserID parses the user ID of a request, an invalid ID is INVALID_ARGUMENT
+func parseUserID(value string) (uuid.UUID, error) {
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidArgument("user_id", "INVALID_UUID", "Invalid user ID")
+	}
+	return userID, nil
+}
+
+// toAccessTokenPBV2 converts token metadata to its version 2 protobuf representation
+func toAccessTokenPBV2(token *models.AccessToken) *authpbv2.AccessToken {
+	pb := &authpbv2.AccessToken{
+		Id:        token.ID.String(),
+		Name:      token.Name,
+		Scopes:    token.ScopeList(),
+		CreatedAt: token.CreatedAt.Unix(),
+		ExpiresAt: token.ExpiresAt.Unix(),
+	}
+	if token.LastUsedAt != nil {
+		pb.LastUsedAt = token.LastUsedAt.Unix()
+	}
+	return pb
+}
+
+// toSessionPBV2 converts a session to its version 2 protobuf representation
+func toSessionPBV2(session *models.Session) *authpbv2.Session {
+	return &authpbv2.Session{
+		Id:         session.ID.String(),
+		UserAgent:  session.UserAgent,
+		Ip:         session.IP,
+		CreatedAt:  session.CreatedAt.Unix(),
+		LastSeenAt: session.LastSeenAt.Unix(),
+	}
+}
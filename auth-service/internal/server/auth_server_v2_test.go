@@ -0,0 +1,720 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	authpbv2 "github.com/Koshsky/subs-service/auth-service/internal/authpb/v2"
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type AuthServerV2TestSuite struct {
+	suite.Suite
+	mockAuthService   *mocks.IAuthService
+	mockAccessTokens  *mocks.IAccessTokenService
+	mockRefreshTokens *mocks.IRefreshTokenService
+	mockResets        *mocks.IPasswordResetService
+	mockVerifications *mocks.IEmailVerificationService
+	mockAccounts      *mocks.IAccountService
+	mockTwoFactor     *mocks.ITwoFactorService
+	mockPasskeys      *mocks.IPasskeyService
+	mockMagicLinks    *mocks.IMagicLinkService
+	mockSessions      *mocks.ISessionService
+	authServer        *server.AuthServerV2
+	ctx               context.Context
+	email             string
+	password          string
+}
+
+func (suite *AuthServerV2TestSuite) SetupTest() {
+	suite.mockAuthService = new(mocks.IAuthService)
+	suite.mockAccessTokens = new(mocks.IAccessTokenService)
+	suite.mockRefreshTokens = new(mocks.IRefreshTokenService)
+	suite.mockResets = new(mocks.IPasswordResetService)
+	suite.mockVerifications = new(mocks.IEmailVerificationService)
+	suite.mockAccounts = new(mocks.IAccountService)
+	suite.mockTwoFactor = new(mocks.ITwoFactorService)
+	suite.mockPasskeys = new(mocks.IPasskeyService)
+	suite.mockMagicLinks = new(mocks.IMagicLinkService)
+	suite.mockSessions = new(mocks.ISessionService)
+	suite.authServer = server.NewAuthServerV2(server.Services{
+		AuthService:        suite.mockAuthService,
+		AccessTokens:       suite.mockAccessTokens,
+		RefreshTokens:      suite.mockRefreshTokens,
+		PasswordResets:     suite.mockResets,
+		EmailVerifications: suite.mockVerifications,
+		Accounts:           suite.mockAccounts,
+		TwoFactor:          suite.mockTwoFactor,
+		Passkeys:           suite.mockPasskeys,
+		MagicLinks:         suite.mockMagicLinks,
+		Sessions:           suite.mockSessions,
+	})
+	suite.ctx = context.Background()
+	suite.email = "test@example.com"
+	suite.password = "password123"
+}
+
+func (suite *AuthServerV2TestSuite) TearDownTest() {
+	suite.mockAuthService.AssertExpectations(suite.T())
+	suite.mockAccessTokens.AssertExpectations(suite.T())
+	suite.mockRefreshTokens.AssertExpectations(suite.T())
+	suite.mockResets.AssertExpectations(suite.T())
+	suite.mockVerifications.AssertExpectations(suite.T())
+	suite.mockAccounts.AssertExpectations(suite.T())
+	suite.mockTwoFactor.AssertExpectations(suite.T())
+	suite.mockPasskeys.AssertExpectations(suite.T())
+	suite.mockMagicLinks.AssertExpectations(suite.T())
+	suite.mockSessions.AssertExpectations(suite.T())
+}
+
+// requireCode asserts the gRPC code of err and returns its status
+func (suite *AuthServerV2TestSuite) requireCode(err error, code codes.Code) *status.Status {
+	suite.Require().Error(err)
+	st, ok := status.FromError(err)
+	suite.Require().True(ok, "not a gRPC status: %v", err)
+	suite.Require().Equal(code, st.Code(), st.Message())
+	return st
+}
+
+// fieldViolations returns the google.rpc.BadRequest field violations of an INVALID_ARGUMENT error
+func (suite *AuthServerV2TestSuite) fieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	st := suite.requireCode(err, codes.InvalidArgument)
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			return badRequest.FieldViolations
+		}
+	}
+	suite.FailNow("no BadRequest details")
+	return nil
+}
+
+// ===== VALIDATE TOKEN TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestValidateToken_Success() {
+	// Arrange
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	suite.mockAuthService.On("ValidateToken", suite.ctx, "valid.jwt.token").Return(jwt.MapClaims{
+		"user_id": "test-user-id",
+		"email":   suite.email,
+		"exp":     float64(expiresAt),
+		"sid":     "session-id",
+	}, nil)
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, &authpbv2.ValidateTokenRequest{Token: "valid.jwt.token"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("test-user-id", response.UserId)
+	suite.Equal(suite.email, response.Email)
+	suite.Equal(models.RoleUser, response.Role)
+	suite.Equal("jwt", response.TokenType)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("session-id", response.SessionId)
+}
+
+func (suite *AuthServerV2TestSuite) TestValidateToken_InvalidToken() {
+	// Arrange
+	suite.mockAuthService.On("ValidateToken", suite.ctx, "invalid.jwt.token").
+		Return(nil, fmt.Errorf("%w: token is expired", services.ErrInvalidToken))
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, &authpbv2.ValidateTokenRequest{Token: "invalid.jwt.token"})
+
+	// Assert
+	suite.Nil(response)
+	suite.requireCode(err, codes.Unauthenticated)
+}
+
+func (suite *AuthServerV2TestSuite) TestValidateToken_RevocationCheckUnavailable() {
+	// Arrange
+	suite.mockAuthService.On("ValidateToken", suite.ctx, "valid.jwt.token").
+		Return(nil, fmt.Errorf("failed to check token revocation: %w", &pgconn.ConnectError{}))
+
+	// Act
+	_, err := suite.authServer.ValidateToken(suite.ctx, &authpbv2.ValidateTokenRequest{Token: "valid.jwt.token"})
+
+	// Assert
+	st := suite.requireCode(err, codes.Unavailable)
+	suite.NotContains(st.Message(), "revocation")
+}
+
+func (suite *AuthServerV2TestSuite) TestValidateToken_MissingUserID() {
+	// Arrange
+	suite.mockAuthService.On("ValidateToken", suite.ctx, "valid.jwt.token").Return(jwt.MapClaims{"email": suite.email}, nil)
+
+	// Act
+	_, err := suite.authServer.ValidateToken(suite.ctx, &authpbv2.ValidateTokenRequest{Token: "valid.jwt.token"})
+
+	// Assert
+	suite.requireCode(err, codes.Unauthenticated)
+}
+
+func (suite *AuthServerV2TestSuite) TestValidateToken_EmptyToken() {
+	// Act
+	_, err := suite.authServer.ValidateToken(suite.ctx, &authpbv2.ValidateTokenRequest{})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("token", violations[0].Field)
+	suite.Equal("REQUIRED", violations[0].Reason)
+}
+
+func (suite *AuthServerV2TestSuite) TestValidateToken_AccessToken() {
+	// Arrange
+	token := models.AccessTokenPrefix + "secret"
+	user := &models.User{ID: uuid.New(), Email: suite.email, Role: models.RoleAdmin}
+	accessToken := &models.AccessToken{Scopes: "subscriptions:read", ExpiresAt: time.Now().Add(24 * time.Hour)}
+	suite.mockAccessTokens.On("ValidateToken", suite.ctx, token).Return(accessToken, user, nil)
+	suite.mockAuthService.On("IsReadOnly", user).Return(false)
+
+	// Act
+	response, err := suite.authServer.ValidateToken(suite.ctx, &authpbv2.ValidateTokenRequest{Token: token})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(user.ID.String(), response.UserId)
+	suite.Equal(models.RoleAdmin, response.Role)
+	suite.Equal("pat", response.TokenType)
+	suite.Equal([]string{"subscriptions:read"}, response.Scopes)
+}
+
+func (suite *AuthServerV2TestSuite) TestValidateToken_InvalidAccessToken() {
+	// Arrange
+	token := models.AccessTokenPrefix + "unknown"
+	suite.mockAccessTokens.On("ValidateToken", suite.ctx, token).Return(nil, nil, services.ErrInvalidAccessToken)
+
+	// Act
+	_, err := suite.authServer.ValidateToken(suite.ctx, &authpbv2.ValidateTokenRequest{Token: token})
+
+	// Assert
+	suite.requireCode(err, codes.Unauthenticated)
+}
+
+// ===== REGISTER TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestRegister_Success() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).Return(user, nil)
+	suite.mockVerifications.On("SendVerificationEmail", suite.ctx, user).Return(errors.New("smtp unavailable"))
+
+	// Act
+	response, err := suite.authServer.Register(suite.ctx, &authpbv2.RegisterRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(user.ID.String(), response.UserId)
+	suite.Equal(suite.email, response.Email)
+}
+
+func (suite *AuthServerV2TestSuite) TestRegister_UserExists() {
+	// Arrange
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).Return(nil, services.ErrUserExists)
+
+	// Act
+	_, err := suite.authServer.Register(suite.ctx, &authpbv2.RegisterRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.requireCode(err, codes.AlreadyExists)
+}
+
+func (suite *AuthServerV2TestSuite) TestRegister_DatabaseUnavailable() {
+	// Arrange
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).
+		Return(nil, fmt.Errorf("failed to check user: %w", &pgconn.PgError{Code: "57P01"}))
+
+	// Act
+	_, err := suite.authServer.Register(suite.ctx, &authpbv2.RegisterRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.requireCode(err, codes.Unavailable)
+}
+
+func (suite *AuthServerV2TestSuite) TestRegister_InternalErrorHidesDetails() {
+	// Arrange
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).
+		Return(nil, errors.New("failed to hash password: out of memory"))
+
+	// Act
+	_, err := suite.authServer.Register(suite.ctx, &authpbv2.RegisterRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	st := suite.requireCode(err, codes.Internal)
+	suite.Equal("internal error", st.Message())
+}
+
+func (suite *AuthServerV2TestSuite) TestRegister_PasswordPolicyViolations() {
+	// Arrange
+	policyErr := &passwords.PolicyError{Violations: []passwords.Violation{
+		{Code: passwords.ViolationTooShort, Message: "must be at least 10 characters long"},
+		{Code: passwords.ViolationBreached, Message: "appears in a list of breached passwords, choose another one"},
+	}}
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, "123456").Return(nil, policyErr)
+
+	// Act
+	_, err := suite.authServer.Register(suite.ctx, &authpbv2.RegisterRequest{Email: suite.email, Password: "123456"})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 2)
+	suite.Equal("password", violations[0].Field)
+	suite.Equal("TOO_SHORT", violations[0].Reason)
+	suite.Equal("must be at least 10 characters long", violations[0].Description)
+	suite.Equal("BREACHED", violations[1].Reason)
+}
+
+func (suite *AuthServerV2TestSuite) TestRegister_InvalidEmail() {
+	// Arrange
+	suite.mockAuthService.On("Register", suite.ctx, "not-an-email", suite.password).Return(nil, emails.ErrInvalidEmail)
+
+	// Act
+	_, err := suite.authServer.Register(suite.ctx, &authpbv2.RegisterRequest{Email: "not-an-email", Password: suite.password})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("email", violations[0].Field)
+	suite.Equal("INVALID_EMAIL", violations[0].Reason)
+}
+
+// ===== LOGIN TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestLogin_Success() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(suite.ctx, metadata.Pairs(services.ClientIPMetadataKey, "192.0.2.1"))
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	accessToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt}).SignedString([]byte("secret"))
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL)
+	suite.mockAuthService.On("Login", ctx, suite.email, suite.password, "192.0.2.1").Return(user, nil)
+	suite.mockRefreshTokens.On("IssueTokens", ctx, user).
+		Return(&services.TokenPair{AccessToken: accessToken, RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}, nil)
+
+	// Act
+	response, err := suite.authServer.Login(ctx, &authpbv2.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(accessToken, response.Token)
+	suite.Equal(user.ID.String(), response.UserId)
+	suite.Equal(expiresAt, response.ExpiresAt)
+	suite.Equal("rt_refresh", response.RefreshToken)
+	suite.Equal(refreshExpiresAt.Unix(), response.RefreshExpiresAt)
+	suite.False(response.SecondFactorRequired)
+}
+
+func (suite *AuthServerV2TestSuite) TestLogin_InvalidCredentials() {
+	// Arrange
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, "wrongpassword", "").Return(nil, services.ErrInvalidCredentials)
+
+	// Act
+	response, err := suite.authServer.Login(suite.ctx, &authpbv2.LoginRequest{Email: suite.email, Password: "wrongpassword"})
+
+	// Assert
+	suite.Nil(response)
+	st := suite.requireCode(err, codes.Unauthenticated)
+	suite.Equal(services.ErrInvalidCredentials.Error(), st.Message())
+}
+
+func (suite *AuthServerV2TestSuite) TestLogin_DatabaseUnavailable() {
+	// Arrange
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password, "").
+		Return(nil, fmt.Errorf("failed to get user: %w", &pgconn.ConnectError{}))
+
+	// Act
+	_, err := suite.authServer.Login(suite.ctx, &authpbv2.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.requireCode(err, codes.Unavailable)
+}
+
+func (suite *AuthServerV2TestSuite) TestLogin_AccountLocked() {
+	// Arrange
+	blocked := &services.LoginBlockedError{Err: services.ErrAccountLocked, RetryAt: time.Now().Add(10 * time.Minute)}
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password, "").Return(nil, blocked)
+
+	// Act
+	_, err := suite.authServer.Login(suite.ctx, &authpbv2.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	st := suite.requireCode(err, codes.ResourceExhausted)
+	var (
+		errorInfo *errdetails.ErrorInfo
+		retryInfo *errdetails.RetryInfo
+	)
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			errorInfo = d
+		case *errdetails.RetryInfo:
+			retryInfo = d
+		}
+	}
+	suite.Require().NotNil(errorInfo)
+	suite.Equal(server.ReasonAccountLocked, errorInfo.Reason)
+	suite.Require().NotNil(retryInfo)
+	suite.InDelta(600, retryInfo.RetryDelay.AsDuration().Seconds(), 1)
+}
+
+func (suite *AuthServerV2TestSuite) TestLogin_Throttled() {
+	// Arrange
+	blocked := &services.LoginBlockedError{Err: services.ErrLoginThrottled, RetryAt: time.Now().Add(1500 * time.Millisecond)}
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password, "").Return(nil, blocked)
+
+	// Act
+	_, err := suite.authServer.Login(suite.ctx, &authpbv2.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	st := suite.requireCode(err, codes.ResourceExhausted)
+	suite.Require().Len(st.Details(), 2)
+	errorInfo, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	suite.Require().True(ok)
+	suite.Equal(server.ReasonLoginThrottled, errorInfo.Reason)
+	retryInfo, ok := st.Details()[1].(*errdetails.RetryInfo)
+	suite.Require().True(ok)
+	suite.Equal(2*time.Second, retryInfo.RetryDelay.AsDuration())
+}
+
+func (suite *AuthServerV2TestSuite) TestLogin_SecondFactorRequired() {
+	// Arrange
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	expiresAt := time.Now().Add(services.TwoFactorChallengeTTL)
+	required := &services.SecondFactorRequiredError{Challenge: "tfc_challenge", ExpiresAt: expiresAt}
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password, "").Return(user, required)
+
+	// Act
+	response, err := suite.authServer.Login(suite.ctx, &authpbv2.LoginRequest{Email: suite.email, Password: suite.password})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.SecondFactorRequired)
+	suite.Equal("tfc_challenge", response.Challenge)
+	suite.Equal(expiresAt.Unix(), response.ChallengeExpiresAt)
+	suite.Empty(response.Token)
+}
+
+// ===== VERIFY SECOND FACTOR TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestVerifySecondFactor_InvalidCode() {
+	// Arrange
+	suite.mockTwoFactor.On("VerifySecondFactor", suite.ctx, "tfc_challenge", "000000", "").Return(nil, nil, services.ErrInvalidSecondFactor)
+
+	// Act
+	_, err := suite.authServer.VerifySecondFactor(suite.ctx, &authpbv2.VerifySecondFactorRequest{Challenge: "tfc_challenge", Code: "000000"})
+
+	// Assert
+	suite.requireCode(err, codes.Unauthenticated)
+}
+
+func (suite *AuthServerV2TestSuite) TestVerifySecondFactor_MissingCode() {
+	// Act
+	_, err := suite.authServer.VerifySecondFactor(suite.ctx, &authpbv2.VerifySecondFactorRequest{Challenge: "tfc_challenge"})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("code", violations[0].Field)
+}
+
+// ===== REFRESH AND LOGOUT TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestRefresh_InvalidToken() {
+	// Arrange
+	suite.mockRefreshTokens.On("Refresh", suite.ctx, "rt_unknown").Return(nil, nil, services.ErrInvalidRefreshToken)
+
+	// Act
+	_, err := suite.authServer.Refresh(suite.ctx, &authpbv2.RefreshRequest{RefreshToken: "rt_unknown"})
+
+	// Assert
+	suite.requireCode(err, codes.Unauthenticated)
+}
+
+func (suite *AuthServerV2TestSuite) TestLogout_SkipsInvalidTokens() {
+	// Arrange
+	suite.mockAuthService.On("RevokeToken", suite.ctx, "expired.jwt.token").Return(services.ErrInvalidToken)
+	suite.mockRefreshTokens.On("RevokeRefreshToken", suite.ctx, "rt_unknown").Return(services.ErrInvalidRefreshToken)
+
+	// Act
+	response, err := suite.authServer.Logout(suite.ctx, &authpbv2.LogoutRequest{Token: "expired.jwt.token", RefreshToken: "rt_unknown"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotNil(response)
+}
+
+func (suite *AuthServerV2TestSuite) TestLogoutAll_InvalidUserID() {
+	// Act
+	_, err := suite.authServer.LogoutAll(suite.ctx, &authpbv2.LogoutAllRequest{UserId: "not-a-uuid"})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("user_id", violations[0].Field)
+	suite.Equal("INVALID_UUID", violations[0].Reason)
+}
+
+// ===== ACCOUNT TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestResetPassword_InvalidToken() {
+	// Arrange
+	suite.mockResets.On("ResetPassword", suite.ctx, "expired", "new-password-123").Return(services.ErrInvalidResetToken)
+
+	// Act
+	_, err := suite.authServer.ResetPassword(suite.ctx, &authpbv2.ResetPasswordRequest{Token: "expired", NewPassword: "new-password-123"})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("token", violations[0].Field)
+	suite.Equal("INVALID_TOKEN", violations[0].Reason)
+}
+
+func (suite *AuthServerV2TestSuite) TestRequestPasswordReset_HidesErrorDetails() {
+	// Arrange
+	suite.mockResets.On("RequestPasswordReset", suite.ctx, suite.email).Return(errors.New("failed to publish event: channel closed"))
+
+	// Act
+	_, err := suite.authServer.RequestPasswordReset(suite.ctx, &authpbv2.RequestPasswordResetRequest{Email: suite.email})
+
+	// Assert
+	st := suite.requireCode(err, codes.Internal)
+	suite.NotContains(st.Message(), "channel closed")
+}
+
+func (suite *AuthServerV2TestSuite) TestChangePassword_IncorrectPassword() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockAccounts.On("ChangePassword", suite.ctx, userID, "wrong", "new-password-123").Return(nil, services.ErrIncorrectPassword)
+
+	// Act
+	_, err := suite.authServer.ChangePassword(suite.ctx, &authpbv2.ChangePasswordRequest{
+		UserId:          userID.String(),
+		CurrentPassword: "wrong",
+		NewPassword:     "new-password-123",
+	})
+
+	// Assert
+	suite.requireCode(err, codes.PermissionDenied)
+}
+
+func (suite *AuthServerV2TestSuite) TestChangePassword_PolicyViolationsReportNewPassword() {
+	// Arrange
+	userID := uuid.New()
+	policyErr := &passwords.PolicyError{Violations: []passwords.Violation{
+		{Code: passwords.ViolationTooShort, Message: "must be at least 10 characters long"},
+	}}
+	suite.mockAccounts.On("ChangePassword", suite.ctx, userID, suite.password, "short").Return(nil, policyErr)
+
+	// Act
+	_, err := suite.authServer.ChangePassword(suite.ctx, &authpbv2.ChangePasswordRequest{
+		UserId:          userID.String(),
+		CurrentPassword: suite.password,
+		NewPassword:     "short",
+	})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("new_password", violations[0].Field)
+}
+
+func (suite *AuthServerV2TestSuite) TestChangeEmail_Taken() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockAccounts.On("ChangeEmail", suite.ctx, userID, suite.password, "taken@example.com").Return(services.ErrEmailTaken)
+
+	// Act
+	_, err := suite.authServer.ChangeEmail(suite.ctx, &authpbv2.ChangeEmailRequest{
+		UserId:          userID.String(),
+		CurrentPassword: suite.password,
+		NewEmail:        "taken@example.com",
+	})
+
+	// Assert
+	suite.requireCode(err, codes.AlreadyExists)
+}
+
+func (suite *AuthServerV2TestSuite) TestResendVerificationEmail_TooMany() {
+	// Arrange
+	suite.mockVerifications.On("ResendVerificationEmail", suite.ctx, suite.email).Return(services.ErrTooManyVerificationEmails)
+
+	// Act
+	_, err := suite.authServer.ResendVerificationEmail(suite.ctx, &authpbv2.ResendVerificationEmailRequest{Email: suite.email})
+
+	// Assert
+	suite.requireCode(err, codes.ResourceExhausted)
+}
+
+// ===== TWO-FACTOR AND PASSKEY TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestConfirmTOTP_InvalidCode() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockTwoFactor.On("ConfirmTOTP", suite.ctx, userID, "000000").Return(nil, services.ErrInvalidSecondFactor)
+
+	// Act
+	_, err := suite.authServer.ConfirmTOTP(suite.ctx, &authpbv2.ConfirmTOTPRequest{UserId: userID.String(), Code: "000000"})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("code", violations[0].Field)
+}
+
+func (suite *AuthServerV2TestSuite) TestConfirmTOTP_NotEnrolled() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockTwoFactor.On("ConfirmTOTP", suite.ctx, userID, "123456").Return(nil, services.ErrTwoFactorNotEnrolled)
+
+	// Act
+	_, err := suite.authServer.ConfirmTOTP(suite.ctx, &authpbv2.ConfirmTOTPRequest{UserId: userID.String(), Code: "123456"})
+
+	// Assert
+	suite.requireCode(err, codes.FailedPrecondition)
+}
+
+func (suite *AuthServerV2TestSuite) TestFinishPasskeyRegistration_InvalidCeremony() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockPasskeys.On("FinishRegistration", suite.ctx, userID, "pkc_expired", mock.Anything).Return(services.ErrInvalidPasskeyCeremony)
+
+	// Act
+	_, err := suite.authServer.FinishPasskeyRegistration(suite.ctx, &authpbv2.FinishPasskeyRegistrationRequest{
+		UserId:     userID.String(),
+		Ceremony:   "pkc_expired",
+		Credential: "{}",
+	})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("ceremony", violations[0].Field)
+}
+
+func (suite *AuthServerV2TestSuite) TestFinishPasskeyLogin_InvalidPasskey() {
+	// Arrange
+	suite.mockPasskeys.On("FinishLogin", suite.ctx, "pkc_ceremony", mock.Anything).Return(nil, nil, services.ErrInvalidPasskey)
+
+	// Act
+	_, err := suite.authServer.FinishPasskeyLogin(suite.ctx, &authpbv2.FinishPasskeyLoginRequest{Ceremony: "pkc_ceremony", Credential: "{}"})
+
+	// Assert
+	suite.requireCode(err, codes.Unauthenticated)
+}
+
+// ===== ACCESS TOKEN AND SESSION TESTS =====
+
+func (suite *AuthServerV2TestSuite) TestCreateAccessToken_InvalidLifetime() {
+	// Arrange
+	userID := uuid.New()
+	fieldErr := &services.AccessTokenFieldError{Field: "ttl", Message: "token lifetime cannot exceed 365 days"}
+	suite.mockAccessTokens.On("CreateToken", suite.ctx, userID, "ci", []string{"subscriptions:read"}, 400*24*time.Hour).
+		Return("", nil, fieldErr)
+
+	// Act
+	_, err := suite.authServer.CreateAccessToken(suite.ctx, &authpbv2.CreateAccessTokenRequest{
+		UserId:        userID.String(),
+		Name:          "ci",
+		Scopes:        []string{"subscriptions:read"},
+		ExpiresInDays: 400,
+	})
+
+	// Assert
+	violations := suite.fieldViolations(err)
+	suite.Require().Len(violations, 1)
+	suite.Equal("expires_in_days", violations[0].Field)
+	suite.Equal("token lifetime cannot exceed 365 days", violations[0].Description)
+}
+
+func (suite *AuthServerV2TestSuite) TestCreateAccessToken_AdminScopeNotAllowed() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockAccessTokens.On("CreateToken", suite.ctx, userID, "ci", []string{"admin"}, time.Duration(0)).
+		Return("", nil, services.ErrAdminScopeNotAllowed)
+
+	// Act
+	_, err := suite.authServer.CreateAccessToken(suite.ctx, &authpbv2.CreateAccessTokenRequest{
+		UserId: userID.String(),
+		Name:   "ci",
+		Scopes: []string{"admin"},
+	})
+
+	// Assert
+	suite.requireCode(err, codes.PermissionDenied)
+}
+
+func (suite *AuthServerV2TestSuite) TestRevokeAccessToken_NotFound() {
+	// Arrange
+	userID, tokenID := uuid.New(), uuid.New()
+	suite.mockAccessTokens.On("RevokeToken", suite.ctx, userID, tokenID).Return(services.ErrAccessTokenNotFound)
+
+	// Act
+	_, err := suite.authServer.RevokeAccessToken(suite.ctx, &authpbv2.RevokeAccessTokenRequest{UserId: userID.String(), TokenId: tokenID.String()})
+
+	// Assert
+	suite.requireCode(err, codes.NotFound)
+}
+
+func (suite *AuthServerV2TestSuite) TestListSessions_Success() {
+	// Arrange
+	userID := uuid.New()
+	session := models.Session{ID: uuid.New(), UserAgent: "Firefox", IP: "192.0.2.1", CreatedAt: time.Now(), LastSeenAt: time.Now()}
+	suite.mockSessions.On("ListSessions", suite.ctx, userID).Return([]models.Session{session}, nil)
+
+	// Act
+	response, err := suite.authServer.ListSessions(suite.ctx, &authpbv2.ListSessionsRequest{UserId: userID.String()})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Sessions, 1)
+	suite.Equal(session.ID.String(), response.Sessions[0].Id)
+	suite.Equal("Firefox", response.Sessions[0].UserAgent)
+}
+
+func (suite *AuthServerV2TestSuite) TestRevokeSession_NotFound() {
+	// Arrange
+	userID, sessionID := uuid.New(), uuid.New()
+	suite.mockSessions.On("RevokeSession", suite.ctx, userID, sessionID).Return(services.ErrSessionNotFound)
+
+	// Act
+	_, err := suite.authServer.RevokeSession(suite.ctx, &authpbv2.RevokeSessionRequest{UserId: userID.String(), SessionId: sessionID.String()})
+
+	// Assert
+	suite.requireCode(err, codes.NotFound)
+}
+
+func (suite *AuthServerV2TestSuite) TestEnrollTOTP_UserNotFound() {
+	// Arrange
+	userID := uuid.New()
+	suite.mockTwoFactor.On("EnrollTOTP", suite.ctx, userID).Return(nil, fmt.Errorf("failed to get user: %w", gorm.ErrRecordNotFound))
+
+	// Act
+	_, err := suite.authServer.EnrollTOTP(suite.ctx, &authpbv2.EnrollTOTPRequest{UserId: userID.String()})
+
+	// Assert
+	suite.requireCode(err, codes.NotFound)
+}
+
+func TestAuthServerV2TestSuite(t *testing.T) {
+	suite.Run(t, new(AuthServerV2TestSuite))
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	authpbv2 "github.com/Koshsky/subs-service/auth-service/internal/authpb/v2"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthCheckInterval is how often the dependencies of the service are checked
+const HealthCheckInterval = 10 * time.Second
+
+// healthCheckTimeout bounds a single dependency check, a check hanging longer counts as failed
+const healthCheckTimeout = 3 * time.Second
+
+// healthServices are the services whose status is reported besides the overall "" status
+var healthServices = []string{
+	authpb.AuthService_ServiceDesc.ServiceName,
+	authpbv2.AuthService_ServiceDesc.ServiceName,
+}
+
+// ErrDegraded marks a failed check the service keeps serving through, e.g. missing event publishing.
+// It is logged but leaves the status SERVING.
+var ErrDegraded = errors.New("degraded")
+
+// HealthCheck reports an error while a dependency of the service is unavailable
+type HealthCheck func(ctx context.Context) error
+
+// HealthMonitor sets the grpc.health.v1 status of the auth services from dependency checks:
+// NOT_SERVING while any dependency is unavailable, SERVING otherwise or when it is only degraded
+type HealthMonitor struct {
+	server  *health.Server
+	checks  map[string]HealthCheck
+	failing map[string]bool
+}
+
+// NewHealthMonitor reports the result of checks, keyed by dependency name, to server
+func NewHealthMonitor(server *health.Server, checks map[string]HealthCheck) *HealthMonitor {
+	return &HealthMonitor{
+		server:  server,
+		checks:  checks,
+		failing: make(map[string]bool),
+	}
+}
+
+// Check runs every check once and updates the status. Changes of a dependency are logged.
+func (m *HealthMonitor) Check(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_SERVING
+	for _, name := range slices.Sorted(maps.Keys(m.checks)) {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := m.checks[name](checkCtx)
+		cancel()
+
+		degraded := errors.Is(err, ErrDegraded)
+		switch {
+		case err != nil && !m.failing[name] && degraded:
+			log.Printf("Health check %s failed, still SERVING: %v", name, err)
+		case err != nil && !m.failing[name]:
+			log.Printf("Health check %s failed, reporting NOT_SERVING: %v", name, err)
+		case err == nil && m.failing[name]:
+			log.Printf("Health check %s recovered", name)
+		}
+		m.failing[name] = err != nil
+		if err != nil && !degraded {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+
+	m.server.SetServingStatus("", status)
+	for _, service := range healthServices {
+		m.server.SetServingStatus(service, status)
+	}
+}
+
+// Run checks the dependencies right away and then every interval until ctx is done
+func (m *HealthMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
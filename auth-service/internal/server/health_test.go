@@ -0,0 +1,83 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// servingStatus asks the health server for the status of service
+func servingStatus(t *testing.T, healthServer *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func TestHealthMonitor(t *testing.T) {
+	// Arrange
+	healthServer := health.NewServer()
+	var databaseErr error
+	monitor := server.NewHealthMonitor(healthServer, map[string]server.HealthCheck{
+		"database": func(context.Context) error { return databaseErr },
+		"rabbitmq": func(context.Context) error { return nil },
+	})
+	services := []string{"", "authpb.AuthService", "authpb.v2.AuthService"}
+
+	// Act & Assert - every dependency is available
+	monitor.Check(context.Background())
+	for _, service := range services {
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, healthServer, service), service)
+	}
+
+	// Act & Assert - the database goes down
+	databaseErr = errors.New("connection refused")
+	monitor.Check(context.Background())
+	for _, service := range services {
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, healthServer, service), service)
+	}
+
+	// Act & Assert - the database is back
+	databaseErr = nil
+	monitor.Check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, healthServer, ""))
+}
+
+func TestHealthMonitor_Degraded(t *testing.T) {
+	// Arrange
+	healthServer := health.NewServer()
+	monitor := server.NewHealthMonitor(healthServer, map[string]server.HealthCheck{
+		"database": func(context.Context) error { return nil },
+		"rabbitmq": func(context.Context) error {
+			return fmt.Errorf("%w: RabbitMQ is not connected", server.ErrDegraded)
+		},
+	})
+
+	// Act
+	monitor.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, healthServer, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, healthServer, "authpb.AuthService"))
+}
+
+func TestHealthMonitor_ShutdownWins(t *testing.T) {
+	// Arrange
+	healthServer := health.NewServer()
+	monitor := server.NewHealthMonitor(healthServer, map[string]server.HealthCheck{
+		"database": func(context.Context) error { return nil },
+	})
+
+	// Act
+	healthServer.Shutdown()
+	monitor.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, healthServer, ""))
+}
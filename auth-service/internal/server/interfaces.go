@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	authpbv2 "github.com/Koshsky/subs-service/auth-service/internal/authpb/v2"
 )
 
 // IAuthServer defines the interface for authentication server operations
@@ -13,4 +14,67 @@ type IAuthServer interface {
 	ValidateToken(ctx context.Context, req *authpb.TokenRequest) (*authpb.UserResponse, error)
 	Register(ctx context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error)
 	Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error)
+	VerifySecondFactor(ctx context.Context, req *authpb.VerifySecondFactorRequest) (*authpb.VerifySecondFactorResponse, error)
+	Refresh(ctx context.Context, req *authpb.RefreshRequest) (*authpb.RefreshResponse, error)
+	Logout(ctx context.Context, req *authpb.LogoutRequest) (*authpb.LogoutResponse, error)
+	LogoutAll(ctx context.Context, req *authpb.LogoutAllRequest) (*authpb.LogoutAllResponse, error)
+	RequestPasswordReset(ctx context.Context, req *authpb.RequestPasswordResetRequest) (*authpb.RequestPasswordResetResponse, error)
+	ResetPassword(ctx context.Context, req *authpb.ResetPasswordRequest) (*authpb.ResetPasswordResponse, error)
+	RequestMagicLink(ctx context.Context, req *authpb.RequestMagicLinkRequest) (*authpb.RequestMagicLinkResponse, error)
+	ConsumeMagicLink(ctx context.Context, req *authpb.ConsumeMagicLinkRequest) (*authpb.ConsumeMagicLinkResponse, error)
+	VerifyEmail(ctx context.Context, req *authpb.VerifyEmailRequest) (*authpb.VerifyEmailResponse, error)
+	ResendVerificationEmail(ctx context.Context, req *authpb.ResendVerificationEmailRequest) (*authpb.ResendVerificationEmailResponse, error)
+	ChangePassword(ctx context.Context, req *authpb.ChangePasswordRequest) (*authpb.ChangePasswordResponse, error)
+	ChangeEmail(ctx context.Context, req *authpb.ChangeEmailRequest) (*authpb.ChangeEmailResponse, error)
+	DeleteAccount(ctx context.Context, req *authpb.DeleteAccountRequest) (*authpb.DeleteAccountResponse, error)
+	EnrollTOTP(ctx context.Context, req *authpb.EnrollTOTPRequest) (*authpb.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, req *authpb.ConfirmTOTPRequest) (*authpb.ConfirmTOTPResponse, error)
+	DisableTOTP(ctx context.Context, req *authpb.DisableTOTPRequest) (*authpb.DisableTOTPResponse, error)
+	BeginPasskeyRegistration(ctx context.Context, req *authpb.BeginPasskeyRegistrationRequest) (*authpb.BeginPasskeyRegistrationResponse, error)
+	FinishPasskeyRegistration(ctx context.Context, req *authpb.FinishPasskeyRegistrationRequest) (*authpb.FinishPasskeyRegistrationResponse, error)
+	BeginPasskeyLogin(ctx context.Context, req *authpb.BeginPasskeyLoginRequest) (*authpb.BeginPasskeyLoginResponse, error)
+	FinishPasskeyLogin(ctx context.Context, req *authpb.FinishPasskeyLoginRequest) (*authpb.FinishPasskeyLoginResponse, error)
+	LoginWithOIDC(ctx context.Context, req *authpb.LoginWithOIDCRequest) (*authpb.LoginWithOIDCResponse, error)
+	CreateAccessToken(ctx context.Context, req *authpb.CreateAccessTokenRequest) (*authpb.CreateAccessTokenResponse, error)
+	ListAccessTokens(ctx context.Context, req *authpb.ListAccessTokensRequest) (*authpb.ListAccessTokensResponse, error)
+	RevokeAccessToken(ctx context.Context, req *authpb.RevokeAccessTokenRequest) (*authpb.RevokeAccessTokenResponse, error)
+	ListSessions(ctx context.Context, req *authpb.ListSessionsRequest) (*authpb.ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, req *authpb.RevokeSessionRequest) (*authpb.RevokeSessionResponse, error)
+	GetJWKS(ctx context.Context, req *authpb.GetJWKSRequest) (*authpb.GetJWKSResponse, error)
+}
+
+// IAuthServerV2 defines the interface for version 2 of the authentication server
+//
+//go:generate mockery --name=IAuthServerV2 --output=mocks --outpkg=mocks
+type IAuthServerV2 interface {
+	ValidateToken(ctx context.Context, req *authpbv2.ValidateTokenRequest) (*authpbv2.ValidateTokenResponse, error)
+	Register(ctx context.Context, req *authpbv2.RegisterRequest) (*authpbv2.RegisterResponse, error)
+	Login(ctx context.Context, req *authpbv2.LoginRequest) (*authpbv2.LoginResponse, error)
+	VerifySecondFactor(ctx context.Context, req *authpbv2.VerifySecondFactorRequest) (*authpbv2.VerifySecondFactorResponse, error)
+	Refresh(ctx context.Context, req *authpbv2.RefreshRequest) (*authpbv2.RefreshResponse, error)
+	Logout(ctx context.Context, req *authpbv2.LogoutRequest) (*authpbv2.LogoutResponse, error)
+	LogoutAll(ctx context.Context, req *authpbv2.LogoutAllRequest) (*authpbv2.LogoutAllResponse, error)
+	RequestPasswordReset(ctx context.Context, req *authpbv2.RequestPasswordResetRequest) (*authpbv2.RequestPasswordResetResponse, error)
+	ResetPassword(ctx context.Context, req *authpbv2.ResetPasswordRequest) (*authpbv2.ResetPasswordResponse, error)
+	RequestMagicLink(ctx context.Context, req *authpbv2.RequestMagicLinkRequest) (*authpbv2.RequestMagicLinkResponse, error)
+	ConsumeMagicLink(ctx context.Context, req *authpbv2.ConsumeMagicLinkRequest) (*authpbv2.ConsumeMagicLinkResponse, error)
+	VerifyEmail(ctx context.Context, req *authpbv2.VerifyEmailRequest) (*authpbv2.VerifyEmailResponse, error)
+	ResendVerificationEmail(ctx context.Context, req *authpbv2.ResendVerificationEmailRequest) (*authpbv2.ResendVerificationEmailResponse, error)
+	ChangePassword(ctx context.Context, req *authpbv2.ChangePasswordRequest) (*authpbv2.ChangePasswordResponse, error)
+	ChangeEmail(ctx context.Context, req *authpbv2.ChangeEmailRequest) (*authpbv2.ChangeEmailResponse, error)
+	DeleteAccount(ctx context.Context, req *authpbv2.DeleteAccountRequest) (*authpbv2.DeleteAccountResponse, error)
+	EnrollTOTP(ctx context.Context, req *authpbv2.EnrollTOTPRequest) (*authpbv2.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, req *authpbv2.ConfirmTOTPRequest) (*authpbv2.ConfirmTOTPResponse, error)
+	DisableTOTP(ctx context.Context, req *authpbv2.DisableTOTPRequest) (*authpbv2.DisableTOTPResponse, error)
+	BeginPasskeyRegistration(ctx context.Context, req *authpbv2.BeginPasskeyRegistrationRequest) (*authpbv2.BeginPasskeyRegistrationResponse, error)
+	FinishPasskeyRegistration(ctx context.Context, req *authpbv2.FinishPasskeyRegistrationRequest) (*authpbv2.FinishPasskeyRegistrationResponse, error)
+	BeginPasskeyLogin(ctx context.Context, req *authpbv2.BeginPasskeyLoginRequest) (*authpbv2.BeginPasskeyLoginResponse, error)
+	FinishPasskeyLogin(ctx context.Context, req *authpbv2.FinishPasskeyLoginRequest) (*authpbv2.FinishPasskeyLoginResponse, error)
+	LoginWithOIDC(ctx context.Context, req *authpbv2.LoginWithOIDCRequest) (*authpbv2.LoginWithOIDCResponse, error)
+	CreateAccessToken(ctx context.Context, req *authpbv2.CreateAccessTokenRequest) (*authpbv2.CreateAccessTokenResponse, error)
+	ListAccessTokens(ctx context.Context, req *authpbv2.ListAccessTokensRequest) (*authpbv2.ListAccessTokensResponse, error)
+	RevokeAccessToken(ctx context.Context, req *authpbv2.RevokeAccessTokenRequest) (*authpbv2.RevokeAccessTokenResponse, error)
+	ListSessions(ctx context.Context, req *authpbv2.ListSessionsRequest) (*authpbv2.ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, req *authpbv2.RevokeSessionRequest) (*authpbv2.RevokeSessionResponse, error)
+	GetJWKS(ctx context.Context, req *authpbv2.GetJWKSRequest) (*authpbv2.GetJWKSResponse, error)
 }
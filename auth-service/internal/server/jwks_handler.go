@@ -0,0 +1,29 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+)
+
+// JWKSPath is the well-known path the key set is served on
+const JWKSPath = "/.well-known/jwks.json"
+
+// jwksMaxAge lets clients cache the key set for a while; rotated keys are
+// published before they start signing, so a stale copy only lags behind
+const jwksMaxAge = "public, max-age=300"
+
+// NewJWKSHandler returns an HTTP handler serving the public JWT verification keys
+func NewJWKSHandler(authService services.IAuthService) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+JWKSPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", jwksMaxAge)
+		if err := json.NewEncoder(w).Encode(authService.PublicKeys()); err != nil {
+			log.Printf("Failed to write JWKS response: %v", err)
+		}
+	})
+	return mux
+}
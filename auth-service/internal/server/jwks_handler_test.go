@@ -0,0 +1,50 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	// Arrange
+	authService := mocks.NewIAuthService(t)
+	authService.On("PublicKeys").Return(jwtkeys.JWKS{Keys: []jwtkeys.JWK{
+		{Kty: "RSA", Kid: "rsa-1", Alg: "RS256", Use: "sig", N: "n-value", E: "AQAB"},
+	}})
+	handler := server.NewJWKSHandler(authService)
+
+	// Act
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, server.JWKSPath, nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age=")
+
+	var jwks jwtkeys.JWKS
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "rsa-1", jwks.Keys[0].Kid)
+	assert.Equal(t, "n-value", jwks.Keys[0].N)
+}
+
+func TestJWKSHandler_MethodNotAllowed(t *testing.T) {
+	// Arrange
+	handler := server.NewJWKSHandler(mocks.NewIAuthService(t))
+
+	// Act
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, server.JWKSPath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -15,28 +15,598 @@ type IAuthServer struct {
 	mock.Mock
 }
 
+// BeginPasskeyLogin provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) BeginPasskeyLogin(ctx context.Context, req *authpb.BeginPasskeyLoginRequest) (*authpb.BeginPasskeyLoginResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginPasskeyLogin")
+	}
+
+	var r0 *authpb.BeginPasskeyLoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.BeginPasskeyLoginRequest) (*authpb.BeginPasskeyLoginResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.BeginPasskeyLoginRequest) *authpb.BeginPasskeyLoginResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.BeginPasskeyLoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.BeginPasskeyLoginRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginPasskeyRegistration provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) BeginPasskeyRegistration(ctx context.Context, req *authpb.BeginPasskeyRegistrationRequest) (*authpb.BeginPasskeyRegistrationResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginPasskeyRegistration")
+	}
+
+	var r0 *authpb.BeginPasskeyRegistrationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.BeginPasskeyRegistrationRequest) (*authpb.BeginPasskeyRegistrationResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.BeginPasskeyRegistrationRequest) *authpb.BeginPasskeyRegistrationResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.BeginPasskeyRegistrationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.BeginPasskeyRegistrationRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangeEmail provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ChangeEmail(ctx context.Context, req *authpb.ChangeEmailRequest) (*authpb.ChangeEmailResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeEmail")
+	}
+
+	var r0 *authpb.ChangeEmailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ChangeEmailRequest) (*authpb.ChangeEmailResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ChangeEmailRequest) *authpb.ChangeEmailResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ChangeEmailResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ChangeEmailRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangePassword provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ChangePassword(ctx context.Context, req *authpb.ChangePasswordRequest) (*authpb.ChangePasswordResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 *authpb.ChangePasswordResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ChangePasswordRequest) (*authpb.ChangePasswordResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ChangePasswordRequest) *authpb.ChangePasswordResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ChangePasswordResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ChangePasswordRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmTOTP provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ConfirmTOTP(ctx context.Context, req *authpb.ConfirmTOTPRequest) (*authpb.ConfirmTOTPResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 *authpb.ConfirmTOTPResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ConfirmTOTPRequest) (*authpb.ConfirmTOTPResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ConfirmTOTPRequest) *authpb.ConfirmTOTPResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ConfirmTOTPResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ConfirmTOTPRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConsumeMagicLink provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ConsumeMagicLink(ctx context.Context, req *authpb.ConsumeMagicLinkRequest) (*authpb.ConsumeMagicLinkResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeMagicLink")
+	}
+
+	var r0 *authpb.ConsumeMagicLinkResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ConsumeMagicLinkRequest) (*authpb.ConsumeMagicLinkResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ConsumeMagicLinkRequest) *authpb.ConsumeMagicLinkResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ConsumeMagicLinkResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ConsumeMagicLinkRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAccessToken provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) CreateAccessToken(ctx context.Context, req *authpb.CreateAccessTokenRequest) (*authpb.CreateAccessTokenResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAccessToken")
+	}
+
+	var r0 *authpb.CreateAccessTokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.CreateAccessTokenRequest) (*authpb.CreateAccessTokenResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.CreateAccessTokenRequest) *authpb.CreateAccessTokenResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.CreateAccessTokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.CreateAccessTokenRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccount provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) DeleteAccount(ctx context.Context, req *authpb.DeleteAccountRequest) (*authpb.DeleteAccountResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 *authpb.DeleteAccountResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.DeleteAccountRequest) (*authpb.DeleteAccountResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.DeleteAccountRequest) *authpb.DeleteAccountResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.DeleteAccountResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.DeleteAccountRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableTOTP provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) DisableTOTP(ctx context.Context, req *authpb.DisableTOTPRequest) (*authpb.DisableTOTPResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 *authpb.DisableTOTPResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.DisableTOTPRequest) (*authpb.DisableTOTPResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.DisableTOTPRequest) *authpb.DisableTOTPResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.DisableTOTPResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.DisableTOTPRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnrollTOTP provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) EnrollTOTP(ctx context.Context, req *authpb.EnrollTOTPRequest) (*authpb.EnrollTOTPResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTOTP")
+	}
+
+	var r0 *authpb.EnrollTOTPResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.EnrollTOTPRequest) (*authpb.EnrollTOTPResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.EnrollTOTPRequest) *authpb.EnrollTOTPResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.EnrollTOTPResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.EnrollTOTPRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishPasskeyLogin provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) FinishPasskeyLogin(ctx context.Context, req *authpb.FinishPasskeyLoginRequest) (*authpb.FinishPasskeyLoginResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishPasskeyLogin")
+	}
+
+	var r0 *authpb.FinishPasskeyLoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.FinishPasskeyLoginRequest) (*authpb.FinishPasskeyLoginResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.FinishPasskeyLoginRequest) *authpb.FinishPasskeyLoginResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.FinishPasskeyLoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.FinishPasskeyLoginRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishPasskeyRegistration provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) FinishPasskeyRegistration(ctx context.Context, req *authpb.FinishPasskeyRegistrationRequest) (*authpb.FinishPasskeyRegistrationResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishPasskeyRegistration")
+	}
+
+	var r0 *authpb.FinishPasskeyRegistrationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.FinishPasskeyRegistrationRequest) (*authpb.FinishPasskeyRegistrationResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.FinishPasskeyRegistrationRequest) *authpb.FinishPasskeyRegistrationResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.FinishPasskeyRegistrationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.FinishPasskeyRegistrationRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJWKS provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) GetJWKS(ctx context.Context, req *authpb.GetJWKSRequest) (*authpb.GetJWKSResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJWKS")
+	}
+
+	var r0 *authpb.GetJWKSResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.GetJWKSRequest) (*authpb.GetJWKSResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.GetJWKSRequest) *authpb.GetJWKSResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.GetJWKSResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.GetJWKSRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAccessTokens provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ListAccessTokens(ctx context.Context, req *authpb.ListAccessTokensRequest) (*authpb.ListAccessTokensResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAccessTokens")
+	}
+
+	var r0 *authpb.ListAccessTokensResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ListAccessTokensRequest) (*authpb.ListAccessTokensResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ListAccessTokensRequest) *authpb.ListAccessTokensResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ListAccessTokensResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ListAccessTokensRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ListSessions(ctx context.Context, req *authpb.ListSessionsRequest) (*authpb.ListSessionsResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 *authpb.ListSessionsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ListSessionsRequest) (*authpb.ListSessionsResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ListSessionsRequest) *authpb.ListSessionsResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ListSessionsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ListSessionsRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Login provides a mock function with given fields: ctx, req
 func (_m *IAuthServer) Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error) {
 	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for Login")
+	}
+
+	var r0 *authpb.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LoginRequest) (*authpb.LoginResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LoginRequest) *authpb.LoginResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.LoginRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginWithOIDC provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) LoginWithOIDC(ctx context.Context, req *authpb.LoginWithOIDCRequest) (*authpb.LoginWithOIDCResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginWithOIDC")
 	}
 
-	var r0 *authpb.LoginResponse
+	var r0 *authpb.LoginWithOIDCResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LoginRequest) (*authpb.LoginResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LoginWithOIDCRequest) (*authpb.LoginWithOIDCResponse, error)); ok {
 		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LoginRequest) *authpb.LoginResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LoginWithOIDCRequest) *authpb.LoginWithOIDCResponse); ok {
 		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*authpb.LoginResponse)
+			r0 = ret.Get(0).(*authpb.LoginWithOIDCResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *authpb.LoginRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.LoginWithOIDCRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) Logout(ctx context.Context, req *authpb.LogoutRequest) (*authpb.LogoutResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 *authpb.LogoutResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LogoutRequest) (*authpb.LogoutResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LogoutRequest) *authpb.LogoutResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.LogoutResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.LogoutRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LogoutAll provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) LogoutAll(ctx context.Context, req *authpb.LogoutAllRequest) (*authpb.LogoutAllResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogoutAll")
+	}
+
+	var r0 *authpb.LogoutAllResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LogoutAllRequest) (*authpb.LogoutAllResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.LogoutAllRequest) *authpb.LogoutAllResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.LogoutAllResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.LogoutAllRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) Refresh(ctx context.Context, req *authpb.RefreshRequest) (*authpb.RefreshResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 *authpb.RefreshResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RefreshRequest) (*authpb.RefreshResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RefreshRequest) *authpb.RefreshResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.RefreshResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.RefreshRequest) error); ok {
 		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -75,6 +645,186 @@ func (_m *IAuthServer) Register(ctx context.Context, req *authpb.RegisterRequest
 	return r0, r1
 }
 
+// RequestMagicLink provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) RequestMagicLink(ctx context.Context, req *authpb.RequestMagicLinkRequest) (*authpb.RequestMagicLinkResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestMagicLink")
+	}
+
+	var r0 *authpb.RequestMagicLinkResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RequestMagicLinkRequest) (*authpb.RequestMagicLinkResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RequestMagicLinkRequest) *authpb.RequestMagicLinkResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.RequestMagicLinkResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.RequestMagicLinkRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestPasswordReset provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) RequestPasswordReset(ctx context.Context, req *authpb.RequestPasswordResetRequest) (*authpb.RequestPasswordResetResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 *authpb.RequestPasswordResetResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RequestPasswordResetRequest) (*authpb.RequestPasswordResetResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RequestPasswordResetRequest) *authpb.RequestPasswordResetResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.RequestPasswordResetResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.RequestPasswordResetRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResendVerificationEmail provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ResendVerificationEmail(ctx context.Context, req *authpb.ResendVerificationEmailRequest) (*authpb.ResendVerificationEmailResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerificationEmail")
+	}
+
+	var r0 *authpb.ResendVerificationEmailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ResendVerificationEmailRequest) (*authpb.ResendVerificationEmailResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ResendVerificationEmailRequest) *authpb.ResendVerificationEmailResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ResendVerificationEmailResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ResendVerificationEmailRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) ResetPassword(ctx context.Context, req *authpb.ResetPasswordRequest) (*authpb.ResetPasswordResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 *authpb.ResetPasswordResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ResetPasswordRequest) (*authpb.ResetPasswordResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.ResetPasswordRequest) *authpb.ResetPasswordResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.ResetPasswordResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.ResetPasswordRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAccessToken provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) RevokeAccessToken(ctx context.Context, req *authpb.RevokeAccessTokenRequest) (*authpb.RevokeAccessTokenResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAccessToken")
+	}
+
+	var r0 *authpb.RevokeAccessTokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RevokeAccessTokenRequest) (*authpb.RevokeAccessTokenResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RevokeAccessTokenRequest) *authpb.RevokeAccessTokenResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.RevokeAccessTokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.RevokeAccessTokenRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) RevokeSession(ctx context.Context, req *authpb.RevokeSessionRequest) (*authpb.RevokeSessionResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 *authpb.RevokeSessionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RevokeSessionRequest) (*authpb.RevokeSessionResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.RevokeSessionRequest) *authpb.RevokeSessionResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.RevokeSessionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.RevokeSessionRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateToken provides a mock function with given fields: ctx, req
 func (_m *IAuthServer) ValidateToken(ctx context.Context, req *authpb.TokenRequest) (*authpb.UserResponse, error) {
 	ret := _m.Called(ctx, req)
@@ -105,6 +855,66 @@ func (_m *IAuthServer) ValidateToken(ctx context.Context, req *authpb.TokenReque
 	return r0, r1
 }
 
+// VerifyEmail provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) VerifyEmail(ctx context.Context, req *authpb.VerifyEmailRequest) (*authpb.VerifyEmailResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 *authpb.VerifyEmailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.VerifyEmailRequest) (*authpb.VerifyEmailResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.VerifyEmailRequest) *authpb.VerifyEmailResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.VerifyEmailResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.VerifyEmailRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifySecondFactor provides a mock function with given fields: ctx, req
+func (_m *IAuthServer) VerifySecondFactor(ctx context.Context, req *authpb.VerifySecondFactorRequest) (*authpb.VerifySecondFactorResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifySecondFactor")
+	}
+
+	var r0 *authpb.VerifySecondFactorResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.VerifySecondFactorRequest) (*authpb.VerifySecondFactorResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authpb.VerifySecondFactorRequest) *authpb.VerifySecondFactorResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authpb.VerifySecondFactorResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authpb.VerifySecondFactorRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIAuthServer creates a new instance of IAuthServer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIAuthServer(t interface {
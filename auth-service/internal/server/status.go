@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"gorm.io/gorm"
+)
+
+// errorDomain is the google.rpc.ErrorInfo domain of errors returned by this service
+const errorDomain = "auth-service"
+
+// ErrorInfo reasons of RESOURCE_EXHAUSTED login errors
+const (
+	ReasonAccountLocked  = "ACCOUNT_LOCKED"
+	ReasonLoginThrottled = "LOGIN_THROTTLED"
+)
+
+// requestFields names the request fields password and email errors are reported for
+type requestFields struct {
+	password string
+	email    string
+}
+
+var (
+	// credentialFields are the fields of Register
+	credentialFields = requestFields{password: "password", email: "email"}
+	// newCredentialFields are the fields of ResetPassword, ChangePassword and ChangeEmail
+	newCredentialFields = requestFields{password: "new_password", email: "new_email"}
+)
+
+// accessTokenFields maps services.AccessTokenFieldError fields to CreateAccessTokenRequest fields
+var accessTokenFields = map[string]string{
+	"name":   "name",
+	"scopes": "scopes",
+	"ttl":    "expires_in_days",
+}
+
+// statusError converts a service error to the status returned by version 2 of the auth API
+func statusError(err error) error {
+	return credentialFields.statusError(err)
+}
+
+// statusError converts a service error to a gRPC status, invalid passwords and emails are
+// reported for the fields f. Errors without a more specific code are logged and reported
+// as INTERNAL without their message.
+func (f requestFields) statusError(err error) error {
+	var (
+		blocked    *services.LoginBlockedError
+		policyErr  *passwords.PolicyError
+		tokenField *services.AccessTokenFieldError
+	)
+
+	switch {
+	case errors.As(err, &blocked):
+		return loginBlockedStatus(blocked)
+	case errors.As(err, &policyErr):
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(policyErr.Violations))
+		for _, violation := range policyErr.Violations {
+			violations = append(violations, fieldViolation(f.password, strings.ToUpper(violation.Code), violation.Message))
+		}
+		return badRequestStatus(err.Error(), violations...)
+	case errors.Is(err, services.ErrEmptyPassword):
+		return invalidArgument(f.password, "REQUIRED", err.Error())
+	case errors.Is(err, services.ErrEmptyEmail):
+		return invalidArgument(f.email, "REQUIRED", err.Error())
+	case errors.Is(err, emails.ErrInvalidEmail):
+		return invalidArgument(f.email, "INVALID_EMAIL", err.Error())
+	case errors.Is(err, emails.ErrDisposableEmail):
+		return invalidArgument(f.email, "DISPOSABLE_EMAIL", err.Error())
+	case errors.Is(err, services.ErrEmailUnchanged):
+		return invalidArgument(f.email, "UNCHANGED", err.Error())
+	case errors.As(err, &tokenField):
+		return invalidArgument(accessTokenFields[tokenField.Field], "INVALID", tokenField.Message)
+	case isAny(err, services.ErrInvalidResetToken, services.ErrInvalidVerificationToken):
+		return invalidArgument("token", "INVALID_TOKEN", err.Error())
+	case errors.Is(err, services.ErrInvalidOIDCIdentity):
+		return status.Error(codes.InvalidArgument, err.Error())
+
+	case isAny(err, services.ErrUserExists, services.ErrEmailTaken, services.ErrTwoFactorEnabled):
+		return status.Error(codes.AlreadyExists, err.Error())
+
+	case isAny(err,
+		services.ErrInvalidCredentials,
+		services.ErrInvalidToken,
+		services.ErrTokenRevoked,
+		services.ErrSessionRevoked,
+		services.ErrInvalidAccessToken,
+		services.ErrInvalidRefreshToken,
+		services.ErrInvalidChallenge,
+		services.ErrInvalidSecondFactor,
+		services.ErrInvalidMagicLink,
+		services.ErrInvalidPasskey,
+		services.ErrInvalidPasskeyCeremony,
+	):
+		return status.Error(codes.Unauthenticated, err.Error())
+
+	case isAny(err,
+		services.ErrIncorrectPassword,
+		services.ErrAdminScopeNotAllowed,
+		services.ErrEmailNotVerified,
+		services.ErrOIDCEmailNotVerified,
+		services.ErrOIDCAccountNotVerified,
+	):
+		return status.Error(codes.PermissionDenied, err.Error())
+
+	case isAny(err, services.ErrAccessTokenNotFound, services.ErrSessionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, "user not found")
+
+	case isAny(err, services.ErrTwoFactorNotEnabled, services.ErrTwoFactorNotEnrolled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+
+	case errors.Is(err, services.ErrTooManyVerificationEmails):
+		return status.Error(codes.ResourceExhausted, err.Error())
+
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case repositories.IsUnavailable(err):
+		log.Printf("Auth request failed, database unavailable: %v", err)
+		return status.Error(codes.Unavailable, "service temporarily unavailable")
+	default:
+		log.Printf("Auth request failed: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// invalidArgument returns INVALID_ARGUMENT with a single field violation
+func invalidArgument(field, reason, description string) error {
+	return badRequestStatus(description, fieldViolation(field, reason, description))
+}
+
+// badRequestStatus returns INVALID_ARGUMENT listing the field violations in google.rpc.BadRequest
+func badRequestStatus(message string, violations ...*errdetails.BadRequest_FieldViolation) error {
+	return withDetails(status.New(codes.InvalidArgument, message), &errdetails.BadRequest{FieldViolations: violations})
+}
+
+func fieldViolation(field, reason, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Reason:      reason,
+		Description: description,
+	}
+}
+
+// loginBlockedStatus returns RESOURCE_EXHAUSTED telling when the next login attempt is accepted
+func loginBlockedStatus(blocked *services.LoginBlockedError) error {
+	reason := ReasonLoginThrottled
+	if errors.Is(blocked, services.ErrAccountLocked) {
+		reason = ReasonAccountLocked
+	}
+	retryDelay := time.Duration(retryAfterSeconds(blocked.RetryAt)) * time.Second
+
+	return withDetails(status.New(codes.ResourceExhausted, blocked.Error()),
+		&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)},
+	)
+}
+
+// withDetails attaches details to st, the status is returned without them if they cannot be encoded
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		log.Printf("Failed to attach error details: %v", err)
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// isAny reports whether err matches any of targets
+func isAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultAccessTokenTTL is used when a token is created without an explicit lifetime
+	DefaultAccessTokenTTL = 90 * 24 * time.Hour
+	// MaxAccessTokenTTL is the longest lifetime a personal access token may have
+	MaxAccessTokenTTL = 365 * 24 * time.Hour
+
+	maxAccessTokenNameLength = 100
+	// lastUsedGranularity limits how often last_used_at is written for a busy token
+	lastUsedGranularity = time.Minute
+)
+
+var (
+	// ErrInvalidAccessToken is returned for unknown, expired and revoked personal access tokens
+	ErrInvalidAccessToken = errors.New("invalid access token")
+	// ErrAccessTokenNotFound is returned when revoking a token the user does not have
+	ErrAccessTokenNotFound = errors.New("access token not found")
+	// ErrAdminScopeNotAllowed is returned when a user without a privileged role asks for the admin scope
+	ErrAdminScopeNotAllowed = errors.New("admin scope requires the support or admin role")
+)
+
+// AccessTokenFieldError is returned by CreateToken for a name, scope list or lifetime that cannot be used
+type AccessTokenFieldError struct {
+	Field   string // "name", "scopes" or "ttl"
+	Message string
+}
+
+func (e *AccessTokenFieldError) Error() string {
+	return e.Message
+}
+
+// AccessTokenService manages personal access tokens
+type AccessTokenService struct {
+	tokenRepo     repositories.IAccessTokenRepository
+	userRepo      repositories.IUserRepository
+	messageBroker messaging.IMessageBroker
+	now           func() time.Time
+}
+
+// NewAccessTokenService creates a new AccessTokenService instance
+func NewAccessTokenService(tokenRepo repositories.IAccessTokenRepository, userRepo repositories.IUserRepository, messageBroker messaging.IMessageBroker) *AccessTokenService {
+	return &AccessTokenService{
+		tokenRepo:     tokenRepo,
+		userRepo:      userRepo,
+		messageBroker: messageBroker,
+		now:           time.Now,
+	}
+}
+
+// CreateToken issues a new personal access token for the user.
+// The plaintext token is returned only here; a ttl of 0 selects DefaultAccessTokenTTL.
+func (s *AccessTokenService) CreateToken(ctx context.Context, userID uuid.UUID, name string, scopes []string, ttl time.Duration) (string, *models.AccessToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAccessTokenNameLength {
+		return "", nil, &AccessTokenFieldError{Field: "name", Message: fmt.Sprintf("token name must be between 1 and %d characters", maxAccessTokenNameLength)}
+	}
+
+	if ttl == 0 {
+		ttl = DefaultAccessTokenTTL
+	}
+	if ttl < 0 || ttl > MaxAccessTokenTTL {
+		return "", nil, &AccessTokenFieldError{Field: "ttl", Message: fmt.Sprintf("token lifetime must not exceed %d days", int(MaxAccessTokenTTL.Hours()/24))}
+	}
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if slices.Contains(scopes, models.ScopeAdmin) && user.Role != models.RoleSupport && user.Role != models.RoleAdmin {
+		return "", nil, ErrAdminScopeNotAllowed
+	}
+
+	plaintext, err := utils.GenerateOpaqueToken(models.AccessTokenPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := s.now().UTC()
+	token := &models.AccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: utils.HashToken(plaintext),
+		Scopes:    strings.Join(scopes, " "),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.tokenRepo.CreateAccessToken(token); err != nil {
+		return "", nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	return plaintext, token, nil
+}
+
+// ListTokens returns the user's tokens that were not revoked
+func (s *AccessTokenService) ListTokens(ctx context.Context, userID uuid.UUID) ([]models.AccessToken, error) {
+	tokens, err := s.tokenRepo.ListUserAccessTokens(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes one of the user's tokens
+func (s *AccessTokenService) RevokeToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	token, err := s.tokenRepo.GetUserAccessToken(userID, tokenID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAccessTokenNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	revoked, err := s.tokenRepo.RevokeAccessToken(userID, tokenID, s.now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	if !revoked {
+		return ErrAccessTokenNotFound
+	}
+
+	// Drop cached validations of the token in other services, they are keyed by its hash
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishTokensRevoked(userID, token.TokenHash, ""); err != nil {
+			log.Printf("Failed to publish token revoked event: %v", err)
+		}
+	}
+	return nil
+}
+
+// ValidateToken resolves a plaintext personal access token to the token and its owner
+func (s *AccessTokenService) ValidateToken(ctx context.Context, plaintext string) (*models.AccessToken, *models.User, error) {
+	if !strings.HasPrefix(plaintext, models.AccessTokenPrefix) {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	token, err := s.tokenRepo.GetAccessTokenByHash(utils.HashToken(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidAccessToken
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	now := s.now().UTC()
+	if !token.IsActive(now) {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	user, err := s.userRepo.GetUserByID(token.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidAccessToken
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedGranularity {
+		if err := s.tokenRepo.UpdateAccessTokenLastUsed(token.ID, now); err != nil {
+			// Usage tracking must not break authentication
+			log.Printf("Failed to update last use of access token %s: %v", token.ID, err)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, user, nil
+}
+
+// normalizeScopes validates requested scopes and removes duplicates
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, &AccessTokenFieldError{Field: "scopes", Message: "at least one scope is required"}
+	}
+
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			return nil, &AccessTokenFieldError{Field: "scopes", Message: fmt.Sprintf("unknown scope %q", scope)}
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,319 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AccessTokenServiceTestSuite struct {
+	suite.Suite
+	mockTokenRepo *repositoryMocks.IAccessTokenRepository
+	mockUserRepo  *repositoryMocks.IUserRepository
+	mockBroker    *messagingMocks.IMessageBroker
+	service       *services.AccessTokenService
+	ctx           context.Context
+	user          *models.User
+}
+
+func (suite *AccessTokenServiceTestSuite) SetupTest() {
+	suite.mockTokenRepo = repositoryMocks.NewIAccessTokenRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.service = services.NewAccessTokenService(suite.mockTokenRepo, suite.mockUserRepo, suite.mockBroker)
+	suite.ctx = context.Background()
+	suite.user = &models.User{
+		ID:    uuid.New(),
+		Email: "test@example.com",
+		Role:  models.RoleUser,
+	}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// mockGetUserByID mock userRepo.GetUserByID(id)
+func (suite *AccessTokenServiceTestSuite) mockGetUserByID(user *models.User, err error) {
+	suite.mockUserRepo.On("GetUserByID", user.ID).Return(user, err)
+}
+
+// mockGetAccessTokenByHash mock tokenRepo.GetAccessTokenByHash for the plaintext token
+func (suite *AccessTokenServiceTestSuite) mockGetAccessTokenByHash(plaintext string, token *models.AccessToken, err error) {
+	suite.mockTokenRepo.On("GetAccessTokenByHash", utils.HashToken(plaintext)).Return(token, err)
+}
+
+// activeToken returns a stored token of suite.user expiring in an hour
+func (suite *AccessTokenServiceTestSuite) activeToken() *models.AccessToken {
+	return &models.AccessToken{
+		ID:        uuid.New(),
+		UserID:    suite.user.ID,
+		Name:      "ci",
+		Scopes:    models.ScopeSubscriptionsRead,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// ===== CREATE TOKEN TESTS =====
+
+func (suite *AccessTokenServiceTestSuite) TestCreateToken_Success() {
+	// Arrange
+	suite.mockGetUserByID(suite.user, nil)
+	var stored *models.AccessToken
+	suite.mockTokenRepo.On("CreateAccessToken", mock.AnythingOfType("*models.AccessToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.AccessToken)
+	}).Return(nil)
+
+	// Act
+	plaintext, token, err := suite.service.CreateToken(suite.ctx, suite.user.ID, " ci ",
+		[]string{models.ScopeSubscriptionsRead, models.ScopeSubscriptionsWrite, models.ScopeSubscriptionsRead}, 0)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(plaintext, models.AccessTokenPrefix))
+	suite.Equal(stored, token)
+	suite.Equal("ci", token.Name)
+	suite.Equal("subscriptions:read subscriptions:write", token.Scopes)
+	suite.Equal(utils.HashToken(plaintext), token.TokenHash)
+	suite.NotContains(token.TokenHash, plaintext)
+	suite.WithinDuration(time.Now().Add(services.DefaultAccessTokenTTL), token.ExpiresAt, time.Minute)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestCreateToken_InvalidInput() {
+	testCases := []struct {
+		name      string
+		tokenName string
+		scopes    []string
+		ttl       time.Duration
+		field     string
+		errorText string
+	}{
+		{"empty name", " ", []string{models.ScopeSubscriptionsRead}, 0, "name", "token name"},
+		{"no scopes", "ci", nil, 0, "scopes", "at least one scope"},
+		{"unknown scope", "ci", []string{"subscriptions:delete"}, 0, "scopes", "unknown scope"},
+		{"lifetime too long", "ci", []string{models.ScopeSubscriptionsRead}, services.MaxAccessTokenTTL + time.Hour, "ttl", "lifetime"},
+		{"negative lifetime", "ci", []string{models.ScopeSubscriptionsRead}, -time.Hour, "ttl", "lifetime"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Act
+			plaintext, token, err := suite.service.CreateToken(suite.ctx, suite.user.ID, tc.tokenName, tc.scopes, tc.ttl)
+
+			// Assert
+			var fieldErr *services.AccessTokenFieldError
+			suite.Require().ErrorAs(err, &fieldErr)
+			suite.Equal(tc.field, fieldErr.Field)
+			suite.Contains(err.Error(), tc.errorText)
+			suite.Empty(plaintext)
+			suite.Nil(token)
+		})
+	}
+}
+
+func (suite *AccessTokenServiceTestSuite) TestCreateToken_AdminScopeRequiresRole() {
+	// Arrange
+	suite.mockGetUserByID(suite.user, nil)
+
+	// Act
+	_, token, err := suite.service.CreateToken(suite.ctx, suite.user.ID, "ci", []string{models.ScopeAdmin}, 0)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrAdminScopeNotAllowed)
+	suite.Nil(token)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestCreateToken_AdminScopeForSupport() {
+	// Arrange
+	suite.user.Role = models.RoleSupport
+	suite.mockGetUserByID(suite.user, nil)
+	suite.mockTokenRepo.On("CreateAccessToken", mock.AnythingOfType("*models.AccessToken")).Return(nil)
+
+	// Act
+	_, token, err := suite.service.CreateToken(suite.ctx, suite.user.ID, "ci", []string{models.ScopeAdmin}, 0)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(models.ScopeAdmin, token.Scopes)
+}
+
+// ===== REVOKE TOKEN TESTS =====
+
+func (suite *AccessTokenServiceTestSuite) TestRevokeToken_PublishesRevocation() {
+	// Arrange
+	token := suite.activeToken()
+	token.TokenHash = utils.HashToken(models.AccessTokenPrefix + "secret")
+	suite.mockTokenRepo.On("GetUserAccessToken", suite.user.ID, token.ID).Return(token, nil)
+	suite.mockTokenRepo.On("RevokeAccessToken", suite.user.ID, token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockBroker.On("PublishTokensRevoked", suite.user.ID, token.TokenHash, "").Return(nil)
+
+	// Act
+	err := suite.service.RevokeToken(suite.ctx, suite.user.ID, token.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestRevokeToken_PublishErrorIgnored() {
+	// Arrange
+	token := suite.activeToken()
+	token.TokenHash = utils.HashToken(models.AccessTokenPrefix + "secret")
+	suite.mockTokenRepo.On("GetUserAccessToken", suite.user.ID, token.ID).Return(token, nil)
+	suite.mockTokenRepo.On("RevokeAccessToken", suite.user.ID, token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockBroker.On("PublishTokensRevoked", suite.user.ID, token.TokenHash, "").Return(errors.New("broker down"))
+
+	// Act
+	err := suite.service.RevokeToken(suite.ctx, suite.user.ID, token.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestRevokeToken_NotFound() {
+	// Arrange
+	tokenID := uuid.New()
+	suite.mockTokenRepo.On("GetUserAccessToken", suite.user.ID, tokenID).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	err := suite.service.RevokeToken(suite.ctx, suite.user.ID, tokenID)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrAccessTokenNotFound)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestRevokeToken_AlreadyRevoked() {
+	// Arrange
+	token := suite.activeToken()
+	suite.mockTokenRepo.On("GetUserAccessToken", suite.user.ID, token.ID).Return(token, nil)
+	suite.mockTokenRepo.On("RevokeAccessToken", suite.user.ID, token.ID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Act
+	err := suite.service.RevokeToken(suite.ctx, suite.user.ID, token.ID)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrAccessTokenNotFound)
+}
+
+// ===== VALIDATE TOKEN TESTS =====
+
+func (suite *AccessTokenServiceTestSuite) TestValidateToken_Success() {
+	// Arrange
+	plaintext := models.AccessTokenPrefix + "secret"
+	stored := suite.activeToken()
+	suite.mockGetAccessTokenByHash(plaintext, stored, nil)
+	suite.mockGetUserByID(suite.user, nil)
+	suite.mockTokenRepo.On("UpdateAccessTokenLastUsed", stored.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	token, user, err := suite.service.ValidateToken(suite.ctx, plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(stored.ID, token.ID)
+	suite.Equal(suite.user, user)
+	suite.NotNil(token.LastUsedAt)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestValidateToken_RecentlyUsedSkipsUpdate() {
+	// Arrange
+	plaintext := models.AccessTokenPrefix + "secret"
+	stored := suite.activeToken()
+	lastUsed := time.Now().Add(-time.Second)
+	stored.LastUsedAt = &lastUsed
+	suite.mockGetAccessTokenByHash(plaintext, stored, nil)
+	suite.mockGetUserByID(suite.user, nil)
+
+	// Act
+	_, _, err := suite.service.ValidateToken(suite.ctx, plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "UpdateAccessTokenLastUsed", mock.Anything, mock.Anything)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestValidateToken_LastUsedFailureIgnored() {
+	// Arrange
+	plaintext := models.AccessTokenPrefix + "secret"
+	stored := suite.activeToken()
+	suite.mockGetAccessTokenByHash(plaintext, stored, nil)
+	suite.mockGetUserByID(suite.user, nil)
+	suite.mockTokenRepo.On("UpdateAccessTokenLastUsed", stored.ID, mock.AnythingOfType("time.Time")).Return(errors.New("database error"))
+
+	// Act
+	token, _, err := suite.service.ValidateToken(suite.ctx, plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotNil(token)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestValidateToken_Rejected() {
+	revokedAt := time.Now().Add(-time.Minute)
+	expired := suite.activeToken()
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	revoked := suite.activeToken()
+	revoked.RevokedAt = &revokedAt
+
+	testCases := []struct {
+		name   string
+		stored *models.AccessToken
+		err    error
+	}{
+		{"unknown", nil, gorm.ErrRecordNotFound},
+		{"expired", expired, nil},
+		{"revoked", revoked, nil},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			plaintext := models.AccessTokenPrefix + tc.name
+			suite.mockGetAccessTokenByHash(plaintext, tc.stored, tc.err)
+
+			// Act
+			token, user, err := suite.service.ValidateToken(suite.ctx, plaintext)
+
+			// Assert
+			suite.Require().ErrorIs(err, services.ErrInvalidAccessToken)
+			suite.Nil(token)
+			suite.Nil(user)
+		})
+	}
+}
+
+func (suite *AccessTokenServiceTestSuite) TestValidateToken_DatabaseError() {
+	// Arrange
+	plaintext := models.AccessTokenPrefix + "secret"
+	suite.mockGetAccessTokenByHash(plaintext, nil, errors.New("connection refused"))
+
+	// Act
+	token, user, err := suite.service.ValidateToken(suite.ctx, plaintext)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.NotErrorIs(err, services.ErrInvalidAccessToken)
+	suite.Nil(token)
+	suite.Nil(user)
+}
+
+func (suite *AccessTokenServiceTestSuite) TestValidateToken_WrongPrefix() {
+	// Act
+	_, _, err := suite.service.ValidateToken(suite.ctx, "header.payload.signature")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidAccessToken)
+}
+
+func TestAccessTokenServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenServiceTestSuite))
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountDeletionRetryInterval is how long a service has to acknowledge an account deletion
+// before user.deleted is published again
+const AccountDeletionRetryInterval = 5 * time.Minute
+
+// AccountDeletionService deletes accounts and tracks the deletion of their data in other services.
+// The user is soft-deleted right away; core-service and notification-service delete their data
+// on user.deleted and acknowledge it, and the event is republished until all of them did.
+type AccountDeletionService struct {
+	deletionRepo  repositories.IAccountDeletionRepository
+	userRepo      repositories.IUserRepository
+	authService   IAuthService
+	refreshTokens IRefreshTokenService
+	messageBroker messaging.IMessageBroker
+	now           func() time.Time
+}
+
+// NewAccountDeletionService creates a new AccountDeletionService instance
+func NewAccountDeletionService(
+	deletionRepo repositories.IAccountDeletionRepository,
+	userRepo repositories.IUserRepository,
+	authService IAuthService,
+	refreshTokens IRefreshTokenService,
+	messageBroker messaging.IMessageBroker,
+) *AccountDeletionService {
+	return &AccountDeletionService{
+		deletionRepo:  deletionRepo,
+		userRepo:      userRepo,
+		authService:   authService,
+		refreshTokens: refreshTokens,
+		messageBroker: messageBroker,
+		now:           time.Now,
+	}
+}
+
+// DeleteAccount deletes the user's account after checking the current password.
+// Every session of the user is revoked. The deletion is recorded before the user is deleted,
+// so that RetryPendingDeletions finishes it if a later step fails.
+func (s *AccountDeletionService) DeleteAccount(ctx context.Context, userID uuid.UUID, currentPassword string) error {
+	user, err := checkCurrentPassword(s.userRepo, userID, currentPassword)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	deletion := &models.AccountDeletion{UserID: user.ID, RequestedAt: now}
+	if err := s.deletionRepo.CreateAccountDeletion(deletion); err != nil {
+		return fmt.Errorf("failed to record account deletion: %w", err)
+	}
+
+	if err := s.userRepo.DeleteUser(user.ID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	if err := s.refreshTokens.RevokeAllRefreshTokens(ctx, user.ID); err != nil {
+		log.Printf("Failed to revoke refresh tokens of deleted user %s: %v", user.ID, err)
+	}
+	if err := s.authService.RevokeAllTokens(ctx, user.ID); err != nil {
+		log.Printf("Failed to revoke tokens of deleted user %s: %v", user.ID, err)
+	}
+
+	s.publish(user.ID, now)
+	return nil
+}
+
+// AcknowledgeDeletion records that service deleted the data of the user and
+// completes the deletion once every service in models.DeletionServices did
+func (s *AccountDeletionService) AcknowledgeDeletion(ctx context.Context, userID uuid.UUID, service string) error {
+	if !slices.Contains(models.DeletionServices, service) {
+		log.Printf("Ignoring account deletion acknowledgement from unknown service %q", service)
+		return nil
+	}
+
+	deletion, err := s.deletionRepo.GetAccountDeletion(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Ignoring account deletion acknowledgement for user %s without a deletion", userID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get account deletion: %w", err)
+	}
+	if deletion.CompletedAt != nil {
+		return nil
+	}
+
+	now := s.now().UTC()
+	if err := s.deletionRepo.AcknowledgeAccountDeletion(userID, service, now); err != nil {
+		return fmt.Errorf("failed to acknowledge account deletion: %w", err)
+	}
+
+	acks, err := s.deletionRepo.ListAccountDeletionAcks(userID)
+	if err != nil {
+		return fmt.Errorf("failed to list account deletion acknowledgements: %w", err)
+	}
+	if len(models.PendingDeletionServices(acks)) > 0 {
+		return nil
+	}
+
+	if err := s.deletionRepo.CompleteAccountDeletion(userID, now); err != nil {
+		return fmt.Errorf("failed to complete account deletion: %w", err)
+	}
+	log.Printf("Account deletion of user %s completed", userID)
+	return nil
+}
+
+// RetryPendingDeletions publishes user.deleted again for deletions that some service has not
+// acknowledged within AccountDeletionRetryInterval. The user is deleted again first, in case
+// DeleteAccount failed after recording the deletion.
+func (s *AccountDeletionService) RetryPendingDeletions(ctx context.Context) error {
+	now := s.now().UTC()
+	deletions, err := s.deletionRepo.ListPendingAccountDeletions(now.Add(-AccountDeletionRetryInterval))
+	if err != nil {
+		return fmt.Errorf("failed to list pending account deletions: %w", err)
+	}
+
+	for _, deletion := range deletions {
+		acks, err := s.deletionRepo.ListAccountDeletionAcks(deletion.UserID)
+		if err != nil {
+			log.Printf("Failed to list account deletion acknowledgements of user %s: %v", deletion.UserID, err)
+			continue
+		}
+		log.Printf("Account deletion of user %s is pending in %s after %d attempts, retrying",
+			deletion.UserID, strings.Join(models.PendingDeletionServices(acks), ", "), deletion.Attempts)
+
+		if err := s.userRepo.DeleteUser(deletion.UserID); err != nil {
+			log.Printf("Failed to delete user %s: %v", deletion.UserID, err)
+			continue
+		}
+		s.publish(deletion.UserID, now)
+	}
+	return nil
+}
+
+// Run retries pending deletions every interval until ctx is cancelled
+func (s *AccountDeletionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RetryPendingDeletions(ctx); err != nil {
+				log.Printf("Failed to retry account deletions: %v", err)
+			}
+		}
+	}
+}
+
+// publish asks the other services to delete the user's data. Failures are logged:
+// the deletion stays pending and is published again by RetryPendingDeletions.
+func (s *AccountDeletionService) publish(userID uuid.UUID, now time.Time) {
+	if s.messageBroker == nil {
+		log.Printf("Message broker is not initialized, deletion of user %s stays pending", userID)
+		return
+	}
+
+	if err := s.messageBroker.PublishUserDeleted(&models.User{ID: userID}); err != nil {
+		log.Printf("Failed to publish deletion of user %s: %v", userID, err)
+		return
+	}
+	if err := s.deletionRepo.MarkAccountDeletionPublished(userID, now); err != nil {
+		log.Printf("Failed to record publication of deletion of user %s: %v", userID, err)
+	}
+}
@@ -0,0 +1,238 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type AccountDeletionServiceTestSuite struct {
+	suite.Suite
+	mockDeletionRepo  *repositoryMocks.IAccountDeletionRepository
+	mockUserRepo      *repositoryMocks.IUserRepository
+	mockAuthService   *serviceMocks.IAuthService
+	mockRefreshTokens *serviceMocks.IRefreshTokenService
+	mockBroker        *messagingMocks.IMessageBroker
+	service           *services.AccountDeletionService
+	ctx               context.Context
+	user              *models.User
+}
+
+func (suite *AccountDeletionServiceTestSuite) SetupTest() {
+	suite.mockDeletionRepo = repositoryMocks.NewIAccountDeletionRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockAuthService = serviceMocks.NewIAuthService(suite.T())
+	suite.mockRefreshTokens = serviceMocks.NewIRefreshTokenService(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.service = services.NewAccountDeletionService(suite.mockDeletionRepo, suite.mockUserRepo, suite.mockAuthService, suite.mockRefreshTokens, suite.mockBroker)
+	suite.ctx = context.Background()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("current-password"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Password: string(hashedPassword), Role: models.RoleUser}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// mockPublish mocks publishing user.deleted for the suite user and recording it
+func (suite *AccountDeletionServiceTestSuite) mockPublish() {
+	suite.mockBroker.On("PublishUserDeleted", &models.User{ID: suite.user.ID}).Return(nil)
+	suite.mockDeletionRepo.On("MarkAccountDeletionPublished", suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+}
+
+// ===== DELETE ACCOUNT TESTS =====
+
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_Success() {
+	// Arrange
+	var recorded *models.AccountDeletion
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockDeletionRepo.On("CreateAccountDeletion", mock.AnythingOfType("*models.AccountDeletion")).Run(func(args mock.Arguments) {
+		recorded = args.Get(0).(*models.AccountDeletion)
+	}).Return(nil)
+	suite.mockUserRepo.On("DeleteUser", suite.user.ID).Return(nil)
+	suite.mockRefreshTokens.On("RevokeAllRefreshTokens", suite.ctx, suite.user.ID).Return(nil)
+	suite.mockAuthService.On("RevokeAllTokens", suite.ctx, suite.user.ID).Return(nil)
+	suite.mockPublish()
+
+	// Act
+	err := suite.service.DeleteAccount(suite.ctx, suite.user.ID, "current-password")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user.ID, recorded.UserID)
+	suite.Nil(recorded.CompletedAt)
+}
+
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_IncorrectPassword() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+
+	// Act
+	err := suite.service.DeleteAccount(suite.ctx, suite.user.ID, "wrong-password")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrIncorrectPassword)
+}
+
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_PublishFailureLeavesDeletionPending() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockDeletionRepo.On("CreateAccountDeletion", mock.AnythingOfType("*models.AccountDeletion")).Return(nil)
+	suite.mockUserRepo.On("DeleteUser", suite.user.ID).Return(nil)
+	suite.mockRefreshTokens.On("RevokeAllRefreshTokens", suite.ctx, suite.user.ID).Return(nil)
+	suite.mockAuthService.On("RevokeAllTokens", suite.ctx, suite.user.ID).Return(nil)
+	suite.mockBroker.On("PublishUserDeleted", &models.User{ID: suite.user.ID}).Return(errors.New("broker down"))
+
+	// Act
+	err := suite.service.DeleteAccount(suite.ctx, suite.user.ID, "current-password")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockDeletionRepo.AssertNotCalled(suite.T(), "MarkAccountDeletionPublished", mock.Anything, mock.Anything)
+}
+
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_DeleteUserError() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockDeletionRepo.On("CreateAccountDeletion", mock.AnythingOfType("*models.AccountDeletion")).Return(nil)
+	suite.mockUserRepo.On("DeleteUser", suite.user.ID).Return(errors.New("db error"))
+
+	// Act
+	err := suite.service.DeleteAccount(suite.ctx, suite.user.ID, "current-password")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to delete user")
+}
+
+// ===== ACKNOWLEDGE DELETION TESTS =====
+
+func (suite *AccountDeletionServiceTestSuite) TestAcknowledgeDeletion_WaitsForOtherServices() {
+	// Arrange
+	suite.mockDeletionRepo.On("GetAccountDeletion", suite.user.ID).Return(&models.AccountDeletion{UserID: suite.user.ID}, nil)
+	suite.mockDeletionRepo.On("AcknowledgeAccountDeletion", suite.user.ID, models.DeletionServiceCore, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockDeletionRepo.On("ListAccountDeletionAcks", suite.user.ID).Return([]models.AccountDeletionAck{
+		{UserID: suite.user.ID, Service: models.DeletionServiceCore},
+	}, nil)
+
+	// Act
+	err := suite.service.AcknowledgeDeletion(suite.ctx, suite.user.ID, models.DeletionServiceCore)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockDeletionRepo.AssertNotCalled(suite.T(), "CompleteAccountDeletion", mock.Anything, mock.Anything)
+}
+
+func (suite *AccountDeletionServiceTestSuite) TestAcknowledgeDeletion_CompletesWhenAllAcknowledged() {
+	// Arrange
+	suite.mockDeletionRepo.On("GetAccountDeletion", suite.user.ID).Return(&models.AccountDeletion{UserID: suite.user.ID}, nil)
+	suite.mockDeletionRepo.On("AcknowledgeAccountDeletion", suite.user.ID, models.DeletionServiceNotification, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockDeletionRepo.On("ListAccountDeletionAcks", suite.user.ID).Return([]models.AccountDeletionAck{
+		{UserID: suite.user.ID, Service: models.DeletionServiceCore},
+		{UserID: suite.user.ID, Service: models.DeletionServiceNotification},
+	}, nil)
+	suite.mockDeletionRepo.On("CompleteAccountDeletion", suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := suite.service.AcknowledgeDeletion(suite.ctx, suite.user.ID, models.DeletionServiceNotification)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AccountDeletionServiceTestSuite) TestAcknowledgeDeletion_Ignored() {
+	testCases := []struct {
+		name    string
+		service string
+		setup   func()
+	}{
+		{name: "unknown service", service: "billing-service", setup: func() {}},
+		{name: "unknown deletion", service: models.DeletionServiceCore, setup: func() {
+			suite.mockDeletionRepo.On("GetAccountDeletion", suite.user.ID).Return(nil, gorm.ErrRecordNotFound)
+		}},
+		{name: "completed deletion", service: models.DeletionServiceCore, setup: func() {
+			completedAt := time.Now()
+			suite.mockDeletionRepo.On("GetAccountDeletion", suite.user.ID).
+				Return(&models.AccountDeletion{UserID: suite.user.ID, CompletedAt: &completedAt}, nil)
+		}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			suite.SetupTest()
+			tc.setup()
+
+			// Act
+			err := suite.service.AcknowledgeDeletion(suite.ctx, suite.user.ID, tc.service)
+
+			// Assert
+			suite.Require().NoError(err)
+		})
+	}
+}
+
+func (suite *AccountDeletionServiceTestSuite) TestAcknowledgeDeletion_StoreError() {
+	// Arrange
+	suite.mockDeletionRepo.On("GetAccountDeletion", suite.user.ID).Return(&models.AccountDeletion{UserID: suite.user.ID}, nil)
+	suite.mockDeletionRepo.On("AcknowledgeAccountDeletion", suite.user.ID, models.DeletionServiceCore, mock.AnythingOfType("time.Time")).
+		Return(errors.New("db error"))
+
+	// Act
+	err := suite.service.AcknowledgeDeletion(suite.ctx, suite.user.ID, models.DeletionServiceCore)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "db error")
+}
+
+// ===== RETRY PENDING DELETIONS TESTS =====
+
+func (suite *AccountDeletionServiceTestSuite) TestRetryPendingDeletions_RepublishesPending() {
+	// Arrange
+	var publishedBefore time.Time
+	suite.mockDeletionRepo.On("ListPendingAccountDeletions", mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		publishedBefore = args.Get(0).(time.Time)
+	}).Return([]models.AccountDeletion{{UserID: suite.user.ID, Attempts: 1}}, nil)
+	suite.mockDeletionRepo.On("ListAccountDeletionAcks", suite.user.ID).Return([]models.AccountDeletionAck{
+		{UserID: suite.user.ID, Service: models.DeletionServiceCore},
+	}, nil)
+	suite.mockUserRepo.On("DeleteUser", suite.user.ID).Return(nil)
+	suite.mockPublish()
+
+	// Act
+	err := suite.service.RetryPendingDeletions(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.WithinDuration(time.Now().Add(-services.AccountDeletionRetryInterval), publishedBefore, time.Second)
+}
+
+func (suite *AccountDeletionServiceTestSuite) TestRetryPendingDeletions_ListError() {
+	// Arrange
+	suite.mockDeletionRepo.On("ListPendingAccountDeletions", mock.AnythingOfType("time.Time")).Return(nil, errors.New("db error"))
+
+	// Act
+	err := suite.service.RetryPendingDeletions(suite.ctx)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to list pending account deletions")
+}
+
+// Run tests
+func TestAccountDeletionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountDeletionServiceTestSuite))
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrIncorrectPassword is returned when the current password given to change or delete the account does not match
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	// ErrEmailTaken is returned when the new email already belongs to another account
+	ErrEmailTaken = errors.New("email is already in use")
+	// ErrEmailUnchanged is returned when the new email has the canonical form of the current one
+	ErrEmailUnchanged = errors.New("new email must differ from the current one")
+	// ErrEmptyPassword is returned when a new password is empty
+	ErrEmptyPassword = errors.New("password cannot be empty")
+	// ErrEmptyEmail is returned when a new email is empty
+	ErrEmptyEmail = errors.New("email cannot be empty")
+)
+
+// AccountService lets signed-in users change their credentials
+type AccountService struct {
+	userRepo           repositories.IUserRepository
+	authService        IAuthService
+	refreshTokens      IRefreshTokenService
+	emailVerifications IEmailVerificationService
+	// Passwords hashes new passwords
+	Passwords *passwords.Hasher
+	// PasswordPolicy rejects weak new passwords, any password is accepted when it is nil
+	PasswordPolicy *passwords.Policy
+	// Emails normalizes new emails
+	Emails *emails.Normalizer
+}
+
+// NewAccountService creates a new AccountService instance
+func NewAccountService(
+	userRepo repositories.IUserRepository,
+	authService IAuthService,
+	refreshTokens IRefreshTokenService,
+	emailVerifications IEmailVerificationService,
+) *AccountService {
+	return &AccountService{
+		userRepo:           userRepo,
+		authService:        authService,
+		refreshTokens:      refreshTokens,
+		emailVerifications: emailVerifications,
+		Passwords:          passwords.DefaultHasher(),
+		Emails:             emails.DefaultNormalizer(),
+	}
+}
+
+// ChangePassword replaces the user's password after checking the current one.
+// Every session of the user is revoked; the returned tokens keep the calling session signed in.
+func (s *AccountService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) (*TokenPair, error) {
+	if newPassword == "" {
+		return nil, ErrEmptyPassword
+	}
+	if s.PasswordPolicy != nil {
+		if err := s.PasswordPolicy.Check(newPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := checkCurrentPassword(s.userRepo, userID, currentPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.Passwords.Hash(newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %v", err)
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return nil, fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.refreshTokens.RevokeAllRefreshTokens(ctx, user.ID); err != nil {
+		log.Printf("Failed to revoke refresh tokens of user %s after password change: %v", user.ID, err)
+	}
+	if err := s.authService.RevokeAllTokens(ctx, user.ID); err != nil {
+		log.Printf("Failed to revoke tokens of user %s after password change: %v", user.ID, err)
+	}
+
+	// Reload the user so the new tokens carry the bumped token version
+	user, err = s.userRepo.GetUserByID(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	pair, err := s.refreshTokens.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// ChangeEmail sends a verification link to newEmail after checking the current password.
+// The email is replaced once the new address is confirmed.
+func (s *AccountService) ChangeEmail(ctx context.Context, userID uuid.UUID, currentPassword, newEmail string) error {
+	if newEmail == "" {
+		return ErrEmptyEmail
+	}
+	address, err := s.Emails.Normalize(newEmail)
+	if err != nil {
+		return err
+	}
+	if err := s.Emails.CheckDomain(address); err != nil {
+		return err
+	}
+	emailNormalized := s.Emails.Canonical(address)
+
+	user, err := checkCurrentPassword(s.userRepo, userID, currentPassword)
+	if err != nil {
+		return err
+	}
+	if emailNormalized == user.EmailNormalized {
+		return ErrEmailUnchanged
+	}
+
+	exists, err := s.userRepo.UserExists(emailNormalized)
+	if err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if exists {
+		return ErrEmailTaken
+	}
+
+	return s.emailVerifications.RequestEmailChange(ctx, user, address)
+}
+
+// checkCurrentPassword loads the user and verifies that password is the user's current password
+func checkCurrentPassword(userRepo repositories.IUserRepository, userID uuid.UUID, password string) (*models.User, error) {
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := passwords.Verify(user.Password, password); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+	return user, nil
+}
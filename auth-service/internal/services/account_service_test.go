@@ -0,0 +1,225 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AccountServiceTestSuite struct {
+	suite.Suite
+	mockUserRepo           *repositoryMocks.IUserRepository
+	mockAuthService        *serviceMocks.IAuthService
+	mockRefreshTokens      *serviceMocks.IRefreshTokenService
+	mockEmailVerifications *serviceMocks.IEmailVerificationService
+	service                *services.AccountService
+	ctx                    context.Context
+	user                   *models.User
+}
+
+func (suite *AccountServiceTestSuite) SetupTest() {
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockAuthService = serviceMocks.NewIAuthService(suite.T())
+	suite.mockRefreshTokens = serviceMocks.NewIRefreshTokenService(suite.T())
+	suite.mockEmailVerifications = serviceMocks.NewIEmailVerificationService(suite.T())
+	suite.service = services.NewAccountService(suite.mockUserRepo, suite.mockAuthService, suite.mockRefreshTokens, suite.mockEmailVerifications)
+	suite.ctx = context.Background()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("current-password"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", EmailNormalized: "test@example.com", Password: string(hashedPassword), Role: models.RoleUser}
+}
+
+// ===== CHANGE PASSWORD TESTS =====
+
+func (suite *AccountServiceTestSuite) TestChangePassword_Success() {
+	// Arrange
+	var newHash string
+	reloaded := &models.User{ID: suite.user.ID, Email: suite.user.Email, TokenVersion: 1}
+	refreshExpiresAt := time.Now().Add(time.Hour)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil).Once()
+	suite.mockUserRepo.On("UpdatePassword", suite.user.ID, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		newHash = args.String(1)
+	}).Return(nil)
+	suite.mockRefreshTokens.On("RevokeAllRefreshTokens", suite.ctx, suite.user.ID).Return(nil)
+	suite.mockAuthService.On("RevokeAllTokens", suite.ctx, suite.user.ID).Return(nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(reloaded, nil).Once()
+	suite.mockRefreshTokens.On("IssueTokens", suite.ctx, reloaded).
+		Return(&services.TokenPair{AccessToken: "access-token", RefreshToken: "rt_token", RefreshExpiresAt: refreshExpiresAt}, nil)
+
+	// Act
+	pair, err := suite.service.ChangePassword(suite.ctx, suite.user.ID, "current-password", "new-password")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("access-token", pair.AccessToken)
+	suite.Equal("rt_token", pair.RefreshToken)
+	suite.Equal(refreshExpiresAt, pair.RefreshExpiresAt)
+	suite.True(strings.HasPrefix(newHash, "$argon2id$"))
+	suite.NoError(passwords.Verify(newHash, "new-password"))
+}
+
+func (suite *AccountServiceTestSuite) TestChangePassword_IncorrectPassword() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+
+	// Act
+	pair, err := suite.service.ChangePassword(suite.ctx, suite.user.ID, "wrong-password", "new-password")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrIncorrectPassword)
+	suite.Nil(pair)
+}
+
+func (suite *AccountServiceTestSuite) TestChangePassword_EmptyPassword() {
+	// Act
+	pair, err := suite.service.ChangePassword(suite.ctx, suite.user.ID, "current-password", "")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "password cannot be empty")
+	suite.Nil(pair)
+}
+
+func (suite *AccountServiceTestSuite) TestChangePassword_PasswordPolicyViolation() {
+	// Arrange
+	suite.service.PasswordPolicy = &passwords.Policy{MinLength: 16}
+
+	// Act
+	pair, err := suite.service.ChangePassword(suite.ctx, suite.user.ID, "current-password", "new-password")
+
+	// Assert
+	var policyErr *passwords.PolicyError
+	suite.Require().ErrorAs(err, &policyErr)
+	suite.Equal(passwords.ViolationTooShort, policyErr.Violations[0].Code)
+	suite.Nil(pair)
+}
+
+func (suite *AccountServiceTestSuite) TestChangePassword_UpdateError() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("UpdatePassword", suite.user.ID, mock.AnythingOfType("string")).Return(errors.New("db error"))
+
+	// Act
+	pair, err := suite.service.ChangePassword(suite.ctx, suite.user.ID, "current-password", "new-password")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to update password")
+	suite.Nil(pair)
+}
+
+// ===== CHANGE EMAIL TESTS =====
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_Success() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("UserExists", "new@example.com").Return(false, nil)
+	suite.mockEmailVerifications.On("RequestEmailChange", suite.ctx, suite.user, "new@example.com").Return(nil)
+
+	// Act
+	err := suite.service.ChangeEmail(suite.ctx, suite.user.ID, "current-password", "new@example.com")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_IncorrectPassword() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+
+	// Act
+	err := suite.service.ChangeEmail(suite.ctx, suite.user.ID, "wrong-password", "new@example.com")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrIncorrectPassword)
+}
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_EmailTaken() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("UserExists", "new@example.com").Return(true, nil)
+
+	// Act
+	err := suite.service.ChangeEmail(suite.ctx, suite.user.ID, "current-password", "new@example.com")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrEmailTaken)
+}
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_SameEmail() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+
+	// Act
+	err := suite.service.ChangeEmail(suite.ctx, suite.user.ID, "current-password", suite.user.Email)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "must differ")
+}
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_Normalized() {
+	// Arrange
+	suite.service.Emails = gmailNormalizer()
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("UserExists", "jdoe@gmail.com").Return(false, nil)
+	suite.mockEmailVerifications.On("RequestEmailChange", suite.ctx, suite.user, "j.doe+subs@gmail.com").Return(nil)
+
+	// Act
+	err := suite.service.ChangeEmail(suite.ctx, suite.user.ID, "current-password", " J.Doe+Subs@Gmail.com ")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_SameEmailDifferentCase() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+
+	// Act
+	err := suite.service.ChangeEmail(suite.ctx, suite.user.ID, "current-password", "TEST@example.com")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "must differ")
+}
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_InvalidEmail() {
+	// Act
+	err := suite.service.ChangeEmail(suite.ctx, suite.user.ID, "current-password", "not-an-email")
+
+	// Assert
+	suite.Require().ErrorIs(err, emails.ErrInvalidEmail)
+}
+
+func (suite *AccountServiceTestSuite) TestChangeEmail_DisposableEmail() {
+	// Arrange
+	disposable, err := emails.LoadDomainList(strings.NewReader("mailinator.com\n"))
+	suite.Require().NoError(err)
+	suite.service.Emails.Disposable = disposable
+
+	// Act
+	err = suite.service.ChangeEmail(suite.ctx, suite.user.ID, "current-password", "new@mailinator.com")
+
+	// Assert
+	suite.Require().ErrorIs(err, emails.ErrDisposableEmail)
+}
+
+// Run tests
+func TestAccountServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountServiceTestSuite))
+}
@@ -4,64 +4,125 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"maps"
+	"slices"
 	"time"
 
-	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
 	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
 	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JWTTokenTTL is how long issued JWTs are valid; clients renew them with a refresh token
+const JWTTokenTTL = 15 * time.Minute
+
+var (
+	// ErrInvalidToken is returned for JWTs that cannot be parsed, are expired or carry unusable claims
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenRevoked is returned for JWTs revoked by logout or by signing out everywhere
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrEmailNotVerified is returned by Login for unverified accounts under EmailVerificationLogin
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	// ErrUserExists is returned by Register when an account has the canonical form of the email
+	ErrUserExists = errors.New("user already exists")
+	// ErrInvalidCredentials is returned by Login for unknown emails and wrong passwords alike
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Email verification policies, selected with EMAIL_VERIFICATION_POLICY
+const (
+	EmailVerificationOff      = "off"       // unverified accounts are fully usable
+	EmailVerificationLogin    = "login"     // unverified accounts cannot log in
+	EmailVerificationReadOnly = "read_only" // unverified accounts can only read
 )
 
 // AuthService implements authentication business logic
 type AuthService struct {
 	userRepo      repositories.IUserRepository
+	revokedTokens repositories.IRevokedTokenRepository
 	messageBroker messaging.IMessageBroker
-	JWTSecret     []byte
+	Keys          *jwtkeys.KeyRing
+	// EmailVerificationPolicy restricts accounts with an unverified email, EmailVerificationOff by default
+	EmailVerificationPolicy string
+	// Lockout limits failed logins, logins are not limited when it is nil
+	Lockout ILoginLockoutService
+	// TwoFactor asks for a second factor when the user enabled it, logins need the password only when it is nil
+	TwoFactor ITwoFactorService
+	// Sessions rejects tokens of revoked sessions, tokens are only revoked by jti and token version when it is nil
+	Sessions ISessionService
+	// Passwords hashes new passwords, logins rehash passwords whose hash it considers outdated
+	Passwords *passwords.Hasher
+	// PasswordPolicy rejects weak passwords at registration, any password is accepted when it is nil
+	PasswordPolicy *passwords.Policy
+	// Emails normalizes the emails of new accounts and of logins
+	Emails *emails.Normalizer
+	now    func() time.Time
 }
 
-// NewAuthService creates a new AuthService instance
-func NewAuthService(userRepo repositories.IUserRepository, messageBroker messaging.IMessageBroker, cfg *config.Config) *AuthService {
-	if cfg == nil || cfg.JWTSecret == "" {
-		return &AuthService{
-			userRepo:      userRepo,
-			messageBroker: messageBroker,
-			JWTSecret:     nil,
-		}
-	}
+// NewAuthService creates a new AuthService instance signing tokens with keys
+func NewAuthService(userRepo repositories.IUserRepository, revokedTokens repositories.IRevokedTokenRepository, messageBroker messaging.IMessageBroker, keys *jwtkeys.KeyRing) *AuthService {
 	return &AuthService{
 		userRepo:      userRepo,
+		revokedTokens: revokedTokens,
 		messageBroker: messageBroker,
-		JWTSecret:     []byte(cfg.JWTSecret),
+		Keys:          keys,
+		Passwords:     passwords.DefaultHasher(),
+		Emails:        emails.DefaultNormalizer(),
+		now:           time.Now,
 	}
 }
 
-// Register registers a new user
+// Register registers a new user. The email is stored normalized, accounts are unique
+// by its canonical form.
 func (s *AuthService) Register(ctx context.Context, email, password string) (*models.User, error) {
 	if s.userRepo == nil {
 		return nil, errors.New("user repository is not initialized")
 	}
 
+	address, err := s.Emails.Normalize(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Emails.CheckDomain(address); err != nil {
+		return nil, err
+	}
+	emailNormalized := s.Emails.Canonical(address)
+
+	if s.PasswordPolicy != nil {
+		if err := s.PasswordPolicy.Check(password); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if user already exists
-	exists, err := s.userRepo.UserExists(email)
+	exists, err := s.userRepo.UserExists(emailNormalized)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check user existence: %w", err)
 	}
 	if exists {
-		return nil, errors.New("user already exists")
+		return nil, ErrUserExists
 	}
 
 	// Hash password in service layer
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := s.Passwords.Hash(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %v", err)
 	}
 
 	// Create new user with hashed password
 	user := &models.User{
-		Email:    email,
-		Password: string(hashedPassword),
+		Email:           address,
+		EmailNormalized: emailNormalized,
+		Password:        hashedPassword,
+		Role:            models.RoleUser,
 	}
 
 	err = s.userRepo.CreateUser(user)
@@ -81,66 +142,382 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*mo
 	return user, nil
 }
 
-// Login authenticates a user and returns JWT token
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, *models.User, error) {
+// Login authenticates a user, the caller issues the tokens of the new session.
+// clientIP is the address of the end user; failed logins are limited per canonical email and per clientIP.
+// For users with two-factor authentication it returns a *SecondFactorRequiredError instead.
+func (s *AuthService) Login(ctx context.Context, email, password, clientIP string) (*models.User, error) {
 	if s.userRepo == nil {
-		return "", nil, errors.New("user repository is not initialized")
+		return nil, errors.New("user repository is not initialized")
+	}
+	email = s.Emails.Canonical(email)
+
+	attempt, err := beginLogin(ctx, s.Lockout, email, clientIP)
+	if err != nil {
+		return nil, err
 	}
+	defer attempt.release()
 
 	user, err := s.userRepo.GetUserByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		attempt.failed(nil)
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid credentials: %v", err)
+		// Not counted as a failed attempt, the user did nothing wrong
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	// Compare password with hashed password in service layer
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = passwords.Verify(user.Password, password)
+	if err != nil {
+		attempt.failed(user)
+		return nil, ErrInvalidCredentials
+	}
+	s.rehashPassword(user, password)
+
+	if s.EmailVerificationPolicy == EmailVerificationLogin && !user.IsEmailVerified() {
+		attempt.succeeded()
+		return nil, ErrEmailNotVerified
+	}
+
+	if s.TwoFactor != nil {
+		enabled, err := s.TwoFactor.IsEnabled(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			// Failed logins are only forgotten once the second factor is verified as well, until then the attempt is released
+			challenge, expiresAt, err := s.TwoFactor.CreateChallenge(ctx, user)
+			if err != nil {
+				return nil, err
+			}
+			return user, &SecondFactorRequiredError{Challenge: challenge, ExpiresAt: expiresAt}
+		}
+	}
+
+	attempt.succeeded()
+	return user, nil
+}
+
+// rehashPassword replaces a hash computed with another algorithm or weaker parameters than
+// new hashes while the password is known, so stored hashes migrate as users log in.
+// Failures are only logged, the old hash keeps working.
+func (s *AuthService) rehashPassword(user *models.User, password string) {
+	if !s.Passwords.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := s.Passwords.Hash(password)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid credentials: %v", err)
+		log.Printf("Failed to rehash password of user %s: %v", user.ID, err)
+		return
 	}
+	updated, err := s.userRepo.RehashPassword(user.ID, user.Password, hashedPassword)
+	if err != nil {
+		log.Printf("Failed to rehash password of user %s: %v", user.ID, err)
+		return
+	}
+	if updated {
+		user.Password = hashedPassword
+	}
+}
+
+// ErrEmailConflicts is returned by NormalizeStoredEmails when the current Emails rules give
+// several accounts the same canonical email
+var ErrEmailConflicts = errors.New("several accounts have the same canonical email")
+
+// EmailConflict is a canonical email that the current Emails rules give to several accounts
+type EmailConflict struct {
+	EmailNormalized string
+	Users           []EmailOwner
+}
+
+// EmailOwner is one of the accounts of an EmailConflict
+type EmailOwner struct {
+	ID    uuid.UUID
+	Email string
+}
+
+// FindEmailConflicts returns the canonical emails that the current Emails rules give to several
+// accounts, ordered by canonical email. Such accounts have to be merged or get another email
+// before the rules can be applied to the stored emails.
+func (s *AuthService) FindEmailConflicts(ctx context.Context) ([]EmailConflict, error) {
+	owners := make(map[string][]EmailOwner)
+	err := s.walkUsers(ctx, func(user *models.User) error {
+		emailNormalized := s.Emails.Canonical(user.Email)
+		owners[emailNormalized] = append(owners[emailNormalized], EmailOwner{ID: user.ID, Email: user.Email})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []EmailConflict
+	for _, emailNormalized := range slices.Sorted(maps.Keys(owners)) {
+		if len(owners[emailNormalized]) > 1 {
+			conflicts = append(conflicts, EmailConflict{EmailNormalized: emailNormalized, Users: owners[emailNormalized]})
+		}
+	}
+	return conflicts, nil
+}
 
-	token, err := s.GenerateJWTToken(user)
+// NormalizeStoredEmails applies the current Emails rules to the canonical email of every user,
+// e.g. after the provider rules changed. When the rules give several accounts the same canonical
+// email nothing is updated and ErrEmailConflicts is returned, see FindEmailConflicts.
+// It returns the number of updated users.
+func (s *AuthService) NormalizeStoredEmails(ctx context.Context) (int, error) {
+	conflicts, err := s.FindEmailConflicts(ctx)
 	if err != nil {
-		return "", nil, err
+		return 0, err
+	}
+	if len(conflicts) > 0 {
+		return 0, fmt.Errorf("%w: %d emails belong to several accounts", ErrEmailConflicts, len(conflicts))
 	}
 
-	return token, user, nil
+	updated := 0
+	err = s.walkUsers(ctx, func(user *models.User) error {
+		emailNormalized := s.Emails.Canonical(user.Email)
+		if emailNormalized == user.EmailNormalized {
+			return nil
+		}
+		if err := s.userRepo.UpdateEmailNormalized(user.ID, emailNormalized); err != nil {
+			return fmt.Errorf("failed to normalize email of user %s: %w", user.ID, err)
+		}
+		updated++
+		return nil
+	})
+	return updated, err
 }
 
-// ValidateToken validates JWT token and returns claims
+// emailNormalizationBatchSize is how many users walkUsers loads at once
+const emailNormalizationBatchSize = 500
+
+// walkUsers calls fn for every user, loading them in batches
+func (s *AuthService) walkUsers(ctx context.Context, fn func(user *models.User) error) error {
+	afterID := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		users, err := s.userRepo.ListUsersAfter(afterID, emailNormalizationBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+		for i := range users {
+			if err := fn(&users[i]); err != nil {
+				return err
+			}
+		}
+		if len(users) < emailNormalizationBatchSize {
+			return nil
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// ValidateToken validates JWT token and returns claims.
+// Tokens revoked by jti, issued before the user's current token version or belonging
+// to a revoked session are rejected.
 func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotRevoked(claims); err != nil {
+		return nil, err
+	}
+
+	// Tokens issued before sessions were recorded carry no sid
+	if sessionID, err := uuid.Parse(stringClaim(claims, "sid")); err == nil && s.Sessions != nil {
+		if err := s.Sessions.CheckSession(ctx, sessionID); err != nil {
+			return nil, err
 		}
-		return s.JWTSecret, nil
+	}
+	return claims, nil
+}
+
+// RevokeToken revokes a single JWT until it expires and tells token caches to drop it.
+// Tokens that are already invalid, including revoked ones, are reported as ErrInvalidToken.
+func (s *AuthService) RevokeToken(ctx context.Context, tokenString string) error {
+	if s.revokedTokens == nil {
+		return errors.New("revoked token repository is not initialized")
+	}
+
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return err
+	}
+	err = s.checkNotRevoked(claims)
+	if errors.Is(err, ErrTokenRevoked) {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	jti, err := uuid.Parse(stringClaim(claims, "jti"))
+	if err != nil {
+		return fmt.Errorf("%w: token has no jti", ErrInvalidToken)
+	}
+	userID, err := uuid.Parse(stringClaim(claims, "user_id"))
+	if err != nil {
+		return fmt.Errorf("%w: invalid user ID in token", ErrInvalidToken)
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return fmt.Errorf("%w: token has no expiry", ErrInvalidToken)
+	}
+
+	now := s.now().UTC()
+	err = s.revokedTokens.RevokeToken(&models.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: exp.Time,
+		RevokedAt: now,
 	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
 
+	// Revocation records are only needed until the tokens expire
+	if err := s.revokedTokens.DeleteExpiredRevokedTokens(now); err != nil {
+		log.Printf("Failed to purge expired revoked tokens: %v", err)
+	}
+
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishTokensRevoked(userID, utils.HashToken(tokenString), jti.String()); err != nil {
+			log.Printf("Failed to publish token revoked event: %v", err)
+		}
+	}
+	return nil
+}
+
+// RevokeAllTokens revokes every JWT issued to the user so far by bumping the user's
+// token version, and tells token caches to drop all of the user's tokens
+func (s *AuthService) RevokeAllTokens(ctx context.Context, userID uuid.UUID) error {
+	if s.userRepo == nil {
+		return errors.New("user repository is not initialized")
+	}
+
+	revokedAt := s.now().UTC()
+	if err := s.userRepo.IncrementTokenVersion(userID); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishUserTokensRevoked(userID, revokedAt); err != nil {
+			log.Printf("Failed to publish user tokens revoked event: %v", err)
+		}
+	}
+	return nil
+}
+
+// parseToken verifies the signature and expiry of a JWT and returns its claims
+func (s *AuthService) parseToken(tokenString string) (jwt.MapClaims, error) {
+	if s.Keys == nil {
+		return nil, errors.New("JWT signing key is not configured")
+	}
+
+	token, err := jwt.Parse(tokenString, s.Keys.Keyfunc, jwt.WithValidMethods(s.Keys.Algorithms()))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse token: %v", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		return claims, nil
 	}
 
-	return nil, errors.New("invalid token")
+	return nil, ErrInvalidToken
 }
 
-// GenerateJWTToken generates JWT token for user
-func (s *AuthService) GenerateJWTToken(user *models.User) (string, error) {
+// checkNotRevoked rejects tokens revoked by jti and tokens carrying an older token version
+// than the user's. Tokens issued before revocation support have neither claim and count as version 0.
+func (s *AuthService) checkNotRevoked(claims jwt.MapClaims) error {
+	if jti, err := uuid.Parse(stringClaim(claims, "jti")); err == nil && s.revokedTokens != nil {
+		revoked, err := s.revokedTokens.IsTokenRevoked(jti)
+		if err != nil {
+			return fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+
+	if s.userRepo == nil {
+		return errors.New("user repository is not initialized")
+	}
+	userID, err := uuid.Parse(stringClaim(claims, "user_id"))
+	if err != nil {
+		return fmt.Errorf("%w: invalid user ID in token", ErrInvalidToken)
+	}
+	user, err := s.userRepo.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: token owner does not exist", ErrInvalidToken)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load token owner: %w", err)
+	}
+
+	version, _ := claims["token_version"].(float64)
+	if int(version) < user.TokenVersion {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// stringClaim returns a string claim or "" if it is missing
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// GenerateJWTToken generates JWT token for user. The token carries sessionID as the sid
+// claim unless it is uuid.Nil.
+func (s *AuthService) GenerateJWTToken(user *models.User, sessionID uuid.UUID) (string, error) {
 	if user == nil {
 		return "", errors.New("user cannot be nil")
 	}
-	if s.JWTSecret == nil {
-		return "", errors.New("JWT secret is not configured")
+	if s.Keys == nil {
+		return "", errors.New("JWT signing key is not configured")
+	}
+
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
 	}
 
+	// iat lets services verifying tokens locally refuse tokens issued before a revocation
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"email":   user.Email,
-		"user_id": user.ID.String(),
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"jti":           uuid.NewString(),
+		"email":         user.Email,
+		"user_id":       user.ID.String(),
+		"role":          role,
+		"token_version": user.TokenVersion,
+		"iat":           now.Unix(),
+		"exp":           now.Add(JWTTokenTTL).Unix(),
+	}
+	if sessionID != uuid.Nil {
+		claims["sid"] = sessionID.String()
 	}
+	if s.IsReadOnly(user) {
+		claims["read_only"] = true
+	}
+
+	return s.Keys.Sign(claims)
+}
+
+// IsReadOnly reports whether the user may only read because the email is not verified yet
+func (s *AuthService) IsReadOnly(user *models.User) bool {
+	return s.EmailVerificationPolicy == EmailVerificationReadOnly && !user.IsEmailVerified()
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.JWTSecret)
+// PublicKeys returns the public keys tokens can be verified with
+func (s *AuthService) PublicKeys() jwtkeys.JWKS {
+	if s.Keys == nil {
+		return jwtkeys.JWKS{Keys: []jwtkeys.JWK{}}
+	}
+	return s.Keys.JWKS()
 }
@@ -2,66 +2,91 @@ package services_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
 	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
 	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
 	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type AuthServiceTestSuite struct {
 	suite.Suite
 	mockUserRepo      *repositoryMocks.IUserRepository
+	mockRevokedTokens *repositoryMocks.IRevokedTokenRepository
 	mockMessageBroker *messagingMocks.IMessageBroker
 	authService       *services.AuthService
 	ctx               context.Context
-	config            *config.Config
+	keys              *jwtkeys.KeyRing
+	secret            []byte
 	email             string
 	password          string
 	wrongPassword     string
-	hashedPassword    []byte
+	clientIP          string
+	hasher            *passwords.Hasher
+	hashedPassword    string
 	wrongSecret       []byte
 	testUser          *models.User // пользователь для тестов с хешированным паролем
 }
 
 func (suite *AuthServiceTestSuite) SetupSuite() {
-	suite.config = &config.Config{
-		JWTSecret: "test-secret",
-	}
+	suite.secret = []byte("test-secret")
+	suite.keys, _ = jwtkeys.NewKeyRing(jwtkeys.NewHMACKey("", suite.secret))
 	suite.email = "test@example.com"
 	suite.password = "password123"
 	suite.wrongPassword = "wrongpassword"
+	suite.clientIP = "192.0.2.1"
 	suite.wrongSecret = []byte("wrong-secret-key")
-	suite.hashedPassword, _ = bcrypt.GenerateFromPassword([]byte(suite.password), bcrypt.DefaultCost)
+	// Cheap argon2id parameters keep the tests fast
+	suite.hasher, _ = passwords.NewHasher(passwords.Params{Algorithm: passwords.Argon2id, Memory: 64, Iterations: 1, Parallelism: 1})
+	suite.hashedPassword, _ = suite.hasher.Hash(suite.password)
 }
 
 func (suite *AuthServiceTestSuite) SetupTest() {
 	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockRevokedTokens = repositoryMocks.NewIRevokedTokenRepository(suite.T())
 	suite.mockMessageBroker = messagingMocks.NewIMessageBroker(suite.T())
 
-	suite.authService = services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, suite.config)
+	suite.authService = services.NewAuthService(suite.mockUserRepo, suite.mockRevokedTokens, suite.mockMessageBroker, suite.keys)
+	suite.authService.Passwords = suite.hasher
 	suite.ctx = context.Background()
 
 	// testUser с хешированным паролем (как в БД)
 	suite.testUser = &models.User{
 		ID:       uuid.New(),
 		Email:    suite.email,
-		Password: string(suite.hashedPassword),
+		Password: suite.hashedPassword,
 	}
 }
 
 // ===== HELPER FUNCTIONS =====
 
+// gmailNormalizer returns a Normalizer with the provider rules of Gmail, which are off by default
+func gmailNormalizer() *emails.Normalizer {
+	return emails.NewNormalizer(emails.Rules{
+		IgnoreDotsDomains: []string{"gmail.com"},
+		SubaddressDomains: []string{"gmail.com"},
+	})
+}
+
 // mockUserExists mock userRepo.UserExists(email)
 func (suite *AuthServiceTestSuite) mockUserExists(email string, exists bool, err error) {
 	suite.mockUserRepo.On("UserExists", email).Return(exists, err)
@@ -87,6 +112,12 @@ func (suite *AuthServiceTestSuite) mockPublishUserCreated(err error) {
 	suite.mockMessageBroker.On("PublishUserCreated", mock.AnythingOfType("*models.User")).Return(err)
 }
 
+// mockTokenOwner mocks the revocation checks of a valid token of user
+func (suite *AuthServiceTestSuite) mockTokenOwner(user *models.User) {
+	suite.mockRevokedTokens.On("IsTokenRevoked", mock.AnythingOfType("uuid.UUID")).Return(false, nil)
+	suite.mockUserRepo.On("GetUserByID", user.ID).Return(user, nil)
+}
+
 // ===== REGISTER TESTS =====
 
 func (suite *AuthServiceTestSuite) TestRegister_Success() {
@@ -103,14 +134,16 @@ func (suite *AuthServiceTestSuite) TestRegister_Success() {
 	suite.Require().NotNil(returnedUser)
 	suite.Equal(suite.email, returnedUser.Email)
 	suite.NotEqual(uuid.Nil, returnedUser.ID)
+	suite.Equal(models.RoleUser, returnedUser.Role)
 	// Verify password is hashed
 	suite.NotEqual(suite.password, returnedUser.Password)
-	suite.Require().NoError(bcrypt.CompareHashAndPassword([]byte(returnedUser.Password), []byte(suite.password)))
+	suite.True(strings.HasPrefix(returnedUser.Password, "$argon2id$"))
+	suite.Require().NoError(passwords.Verify(returnedUser.Password, suite.password))
 }
 
 func (suite *AuthServiceTestSuite) TestRegister_NilUserRepository() {
 	// Arrange
-	suite.authService = services.NewAuthService(nil, suite.mockMessageBroker, suite.config)
+	suite.authService = services.NewAuthService(nil, suite.mockRevokedTokens, suite.mockMessageBroker, suite.keys)
 
 	// Act
 	user, err := suite.authService.Register(suite.ctx, suite.email, suite.password)
@@ -129,9 +162,8 @@ func (suite *AuthServiceTestSuite) TestRegister_UserAlreadyExists() {
 	user, err := suite.authService.Register(suite.ctx, suite.email, suite.password)
 
 	// Assert
-	suite.Require().Error(err)
+	suite.Require().ErrorIs(err, services.ErrUserExists)
 	suite.Require().Nil(user)
-	suite.Contains(err.Error(), "user already exists")
 }
 
 func (suite *AuthServiceTestSuite) TestRegister_UserExistsError() {
@@ -182,6 +214,7 @@ func (suite *AuthServiceTestSuite) TestRegister_PublishError() {
 
 func (suite *AuthServiceTestSuite) TestRegister_PasswordHashingError() {
 	// Arrange
+	suite.authService.Passwords, _ = passwords.NewHasher(passwords.Params{Algorithm: passwords.Bcrypt, BcryptCost: bcrypt.MinCost})
 	password := strings.Repeat("a", 100) // This should cause bcrypt to fail
 	suite.mockUserExists(suite.email, false, nil)
 
@@ -194,6 +227,107 @@ func (suite *AuthServiceTestSuite) TestRegister_PasswordHashingError() {
 	suite.Contains(err.Error(), "failed to hash password")
 }
 
+func (suite *AuthServiceTestSuite) TestRegister_PasswordPolicyViolation() {
+	// Arrange
+	policy := passwords.DefaultPolicy()
+	policy.Breached = passwords.DefaultBreachedList()
+	suite.authService.PasswordPolicy = &policy
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, suite.email, "Password123!")
+
+	// Assert
+	var policyErr *passwords.PolicyError
+	suite.Require().ErrorAs(err, &policyErr)
+	suite.Require().Len(policyErr.Violations, 1)
+	suite.Equal(passwords.ViolationBreached, policyErr.Violations[0].Code)
+	suite.Nil(user)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_PasswordPolicyMet() {
+	// Arrange
+	policy := passwords.DefaultPolicy()
+	policy.Breached = passwords.DefaultBreachedList()
+	suite.authService.PasswordPolicy = &policy
+	suite.mockUserExists(suite.email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, suite.email, "Tr0ub4dor&3x")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().NotNil(user)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_NormalizesEmail() {
+	// Arrange
+	suite.authService.Emails = gmailNormalizer()
+	suite.mockUserExists("jdoe@gmail.com", false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, " J.Doe+Subs@Gmail.com ", suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("j.doe+subs@gmail.com", user.Email)
+	suite.Equal("jdoe@gmail.com", user.EmailNormalized)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_KeepsDotsAndTagsByDefault() {
+	// Arrange
+	suite.mockUserExists("j.doe+subs@gmail.com", false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, " J.Doe+Subs@Gmail.com ", suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("j.doe+subs@gmail.com", user.Email)
+	suite.Equal("j.doe+subs@gmail.com", user.EmailNormalized)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_SameEmailDifferentCase() {
+	// Arrange
+	suite.mockUserExists(suite.email, true, nil)
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, "TEST@Example.com", suite.password)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "user already exists")
+	suite.Nil(user)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_InvalidEmail() {
+	// Act
+	user, err := suite.authService.Register(suite.ctx, "not-an-email", suite.password)
+
+	// Assert
+	suite.Require().ErrorIs(err, emails.ErrInvalidEmail)
+	suite.Nil(user)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_DisposableEmail() {
+	// Arrange
+	disposable, err := emails.LoadDomainList(strings.NewReader("mailinator.com\n"))
+	suite.Require().NoError(err)
+	suite.authService.Emails.Disposable = disposable
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, "test@mailinator.com", suite.password)
+
+	// Assert
+	suite.Require().ErrorIs(err, emails.ErrDisposableEmail)
+	suite.Nil(user)
+}
+
 // ===== LOGIN TESTS =====
 
 func (suite *AuthServiceTestSuite) TestLogin_Success() {
@@ -201,48 +335,152 @@ func (suite *AuthServiceTestSuite) TestLogin_Success() {
 	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
 
 	// Act
-	token, returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
 
 	// Assert
 	suite.Require().NoError(err)
-	suite.Require().NotEmpty(token)
-	suite.Require().NotNil(returnedUser)
+	suite.Equal(suite.testUser, returnedUser)
+}
 
-	// Validate JWT token structure
-	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+func (suite *AuthServiceTestSuite) TestLogin_CanonicalEmail() {
+	// Arrange
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, " Test@Example.COM", suite.password, suite.clientIP)
+
+	// Assert
 	suite.Require().NoError(err)
-	suite.Require().NotNil(claims)
-	suite.Equal(returnedUser.ID.String(), claims["user_id"])
-	suite.Equal(returnedUser.Email, claims["email"])
+	suite.Equal(suite.testUser, returnedUser)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_RehashesOutdatedHash() {
+	// Arrange - a bcrypt hash stored before argon2id was introduced
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(suite.password), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.testUser.Password = string(bcryptHash)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	var newHash string
+	suite.mockUserRepo.On("RehashPassword", suite.testUser.ID, string(bcryptHash), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		newHash = args.String(2)
+	}).Return(true, nil)
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(newHash, "$argon2id$"))
+	suite.NoError(passwords.Verify(newHash, suite.password))
+	suite.Equal(newHash, returnedUser.Password)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_RehashesWeakerParameters() {
+	// Arrange
+	weaker, err := passwords.NewHasher(passwords.Params{Algorithm: passwords.Argon2id, Memory: 32, Iterations: 1, Parallelism: 1})
+	suite.Require().NoError(err)
+	weakHash, err := weaker.Hash(suite.password)
+	suite.Require().NoError(err)
+	suite.testUser.Password = weakHash
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockUserRepo.On("RehashPassword", suite.testUser.ID, weakHash, mock.AnythingOfType("string")).Return(true, nil)
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(suite.hasher.NeedsRehash(returnedUser.Password))
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_RehashSkippedAfterPasswordChange() {
+	// Arrange - the password was changed concurrently, the stored hash is no longer the one loaded
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(suite.password), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.testUser.Password = string(bcryptHash)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockUserRepo.On("RehashPassword", suite.testUser.ID, string(bcryptHash), mock.AnythingOfType("string")).Return(false, nil)
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(string(bcryptHash), returnedUser.Password)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_RehashErrorIgnored() {
+	// Arrange
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(suite.password), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.testUser.Password = string(bcryptHash)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockUserRepo.On("RehashPassword", suite.testUser.ID, string(bcryptHash), mock.AnythingOfType("string")).Return(false, errors.New("database down"))
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser, returnedUser)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_WrongPasswordNotRehashed() {
+	// Arrange
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(suite.password), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.testUser.Password = string(bcryptHash)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+
+	// Act
+	_, err = suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword, suite.clientIP)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "RehashPassword", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func (suite *AuthServiceTestSuite) TestLogin_NilUserRepository() {
 	// Arrange
-	suite.authService = services.NewAuthService(nil, suite.mockMessageBroker, suite.config)
+	suite.authService = services.NewAuthService(nil, suite.mockRevokedTokens, suite.mockMessageBroker, suite.keys)
 
 	// Act
-	token, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+	user, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
 
 	// Assert
 	suite.Require().Error(err)
-	suite.Require().Empty(token)
 	suite.Require().Nil(user)
 	suite.Contains(err.Error(), "user repository is not initialized")
 }
 
-func (suite *AuthServiceTestSuite) TestLogin_ValidationError() {
+func (suite *AuthServiceTestSuite) TestLogin_UnknownEmail() {
 	// Arrange
-	expectedError := errors.New("invalid credentials")
-	suite.mockGetUserByEmail(suite.email, nil, expectedError)
+	suite.mockGetUserByEmail(suite.email, nil, gorm.ErrRecordNotFound)
 
 	// Act
-	token, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+	user, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
 
 	// Assert
-	suite.Require().Error(err)
-	suite.Require().Empty(token)
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
 	suite.Require().Nil(user)
-	suite.Contains(err.Error(), "invalid credentials")
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_DatabaseError() {
+	// Arrange - a database outage is neither reported as invalid credentials nor counted as a failed login
+	lockout := serviceMocks.NewILoginLockoutService(suite.T())
+	suite.authService.Lockout = lockout
+	lockout.On("CheckLogin", suite.ctx, suite.email, suite.clientIP).Return(nil)
+	lockout.On("ReleaseLogin", suite.ctx, suite.email, suite.clientIP).Return()
+	suite.mockGetUserByEmail(suite.email, nil, errors.New("connection refused"))
+
+	// Act
+	user, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.NotErrorIs(err, services.ErrInvalidCredentials)
+	suite.Contains(err.Error(), "failed to get user")
+	suite.Nil(user)
 }
 
 func (suite *AuthServiceTestSuite) TestLogin_InvalidPassword() {
@@ -250,40 +488,272 @@ func (suite *AuthServiceTestSuite) TestLogin_InvalidPassword() {
 	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
 
 	// Act
-	token, returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword, suite.clientIP)
 
 	// Assert
-	suite.Require().Error(err)
-	suite.Require().Empty(token)
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
 	suite.Require().Nil(returnedUser)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_UnverifiedEmailBlocked() {
+	// Arrange
+	suite.authService.EmailVerificationPolicy = services.EmailVerificationLogin
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrEmailNotVerified)
+	suite.Nil(returnedUser)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_VerifiedEmailAllowed() {
+	// Arrange
+	verifiedAt := time.Now()
+	suite.testUser.EmailVerifiedAt = &verifiedAt
+	suite.authService.EmailVerificationPolicy = services.EmailVerificationLogin
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser, returnedUser)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_LockoutBlocksBeforePasswordCheck() {
+	// Arrange
+	lockout := serviceMocks.NewILoginLockoutService(suite.T())
+	suite.authService.Lockout = lockout
+	blocked := &services.LoginBlockedError{Err: services.ErrAccountLocked, RetryAt: time.Now().Add(time.Minute)}
+	lockout.On("CheckLogin", suite.ctx, suite.email, suite.clientIP).Return(blocked)
+
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrAccountLocked)
+	suite.Nil(returnedUser)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_LockoutRecordsFailure() {
+	// Arrange
+	lockout := serviceMocks.NewILoginLockoutService(suite.T())
+	suite.authService.Lockout = lockout
+	lockout.On("CheckLogin", suite.ctx, suite.email, suite.clientIP).Return(nil)
+	lockout.On("RecordFailedLogin", suite.ctx, suite.email, suite.clientIP, suite.testUser).Return()
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+
+	// Act
+	_, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword, suite.clientIP)
+
+	// Assert
+	suite.Require().Error(err)
 	suite.Contains(err.Error(), "invalid credentials")
 }
 
-func (suite *AuthServiceTestSuite) TestLogin_TokenGenerationError() {
+func (suite *AuthServiceTestSuite) TestLogin_LockoutRecordsUnknownEmail() {
 	// Arrange
+	lockout := serviceMocks.NewILoginLockoutService(suite.T())
+	suite.authService.Lockout = lockout
+	lockout.On("CheckLogin", suite.ctx, suite.email, suite.clientIP).Return(nil)
+	lockout.On("RecordFailedLogin", suite.ctx, suite.email, suite.clientIP, (*models.User)(nil)).Return()
+	suite.mockGetUserByEmail(suite.email, nil, gorm.ErrRecordNotFound)
+
+	// Act
+	_, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_LockoutResetOnSuccess() {
+	// Arrange
+	lockout := serviceMocks.NewILoginLockoutService(suite.T())
+	suite.authService.Lockout = lockout
+	lockout.On("CheckLogin", suite.ctx, suite.email, suite.clientIP).Return(nil)
+	lockout.On("RecordSuccessfulLogin", suite.ctx, suite.email, suite.clientIP).Return()
 	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
 
-	// Create AuthService with empty JWTSecret to cause token generation error
-	cfg := &config.Config{JWTSecret: ""}
-	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+	// Act
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser, returnedUser)
+}
+
+// ===== TWO-FACTOR LOGIN TESTS =====
+
+func (suite *AuthServiceTestSuite) TestLogin_SecondFactorRequired() {
+	// Arrange
+	lockout := serviceMocks.NewILoginLockoutService(suite.T())
+	twoFactor := serviceMocks.NewITwoFactorService(suite.T())
+	suite.authService.Lockout = lockout
+	suite.authService.TwoFactor = twoFactor
+	expiresAt := time.Now().Add(services.TwoFactorChallengeTTL)
+	lockout.On("CheckLogin", suite.ctx, suite.email, suite.clientIP).Return(nil)
+	lockout.On("ReleaseLogin", suite.ctx, suite.email, suite.clientIP).Return()
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	twoFactor.On("IsEnabled", suite.ctx, suite.testUser.ID).Return(true, nil)
+	twoFactor.On("CreateChallenge", suite.ctx, suite.testUser).Return("tfc_challenge", expiresAt, nil)
+
+	// Act
+	user, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	var required *services.SecondFactorRequiredError
+	suite.Require().ErrorAs(err, &required)
+	suite.ErrorIs(err, services.ErrSecondFactorRequired)
+	suite.Equal("tfc_challenge", required.Challenge)
+	suite.Equal(expiresAt, required.ExpiresAt)
+	suite.Equal(suite.testUser, user)
+	lockout.AssertNotCalled(suite.T(), "RecordSuccessfulLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_TwoFactorNotEnabled() {
+	// Arrange
+	twoFactor := serviceMocks.NewITwoFactorService(suite.T())
+	suite.authService.TwoFactor = twoFactor
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	twoFactor.On("IsEnabled", suite.ctx, suite.testUser.ID).Return(false, nil)
 
 	// Act
-	token, returnedUser, err := authService.Login(suite.ctx, suite.email, suite.password)
+	returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser, returnedUser)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_WrongPasswordSkipsSecondFactor() {
+	// Arrange
+	twoFactor := serviceMocks.NewITwoFactorService(suite.T())
+	suite.authService.TwoFactor = twoFactor
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+
+	// Act
+	_, err := suite.authService.Login(suite.ctx, suite.email, "wrong-password", suite.clientIP)
 
 	// Assert
 	suite.Require().Error(err)
-	suite.Require().Empty(token)
-	suite.Require().Nil(returnedUser)
-	suite.Contains(err.Error(), "JWT secret is not configured")
+	suite.Contains(err.Error(), "invalid credentials")
+}
+
+// ===== NORMALIZE STORED EMAILS TESTS =====
+
+func (suite *AuthServiceTestSuite) TestNormalizeStoredEmails() {
+	// Arrange
+	suite.authService.Emails = gmailNormalizer()
+	current := models.User{ID: uuid.New(), Email: "alice@example.com", EmailNormalized: "alice@example.com"}
+	outdated := models.User{ID: uuid.New(), Email: "j.doe+subs@gmail.com", EmailNormalized: "j.doe+subs@gmail.com"}
+	suite.mockUserRepo.On("ListUsersAfter", uuid.Nil, 500).Return([]models.User{current, outdated}, nil)
+	suite.mockUserRepo.On("UpdateEmailNormalized", outdated.ID, "jdoe@gmail.com").Return(nil)
+
+	// Act
+	updated, err := suite.authService.NormalizeStoredEmails(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(1, updated)
+}
+
+func (suite *AuthServiceTestSuite) TestNormalizeStoredEmails_Conflicts() {
+	// Arrange - both emails become alice@gmail.com
+	suite.authService.Emails = gmailNormalizer()
+	first := models.User{ID: uuid.New(), Email: "alice@gmail.com", EmailNormalized: "alice@gmail.com"}
+	second := models.User{ID: uuid.New(), Email: "a.lice@gmail.com", EmailNormalized: "a.lice@gmail.com"}
+	outdated := models.User{ID: uuid.New(), Email: "j.doe+subs@gmail.com", EmailNormalized: "j.doe+subs@gmail.com"}
+	suite.mockUserRepo.On("ListUsersAfter", uuid.Nil, 500).Return([]models.User{first, second, outdated}, nil)
+
+	// Act
+	updated, err := suite.authService.NormalizeStoredEmails(suite.ctx)
+
+	// Assert - no email is updated while some accounts conflict
+	suite.Require().ErrorIs(err, services.ErrEmailConflicts)
+	suite.Equal(0, updated)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "UpdateEmailNormalized", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestNormalizeStoredEmails_UpdateError() {
+	// Arrange
+	suite.authService.Emails = gmailNormalizer()
+	outdated := models.User{ID: uuid.New(), Email: "j.doe+subs@gmail.com", EmailNormalized: "j.doe+subs@gmail.com"}
+	suite.mockUserRepo.On("ListUsersAfter", uuid.Nil, 500).Return([]models.User{outdated}, nil)
+	suite.mockUserRepo.On("UpdateEmailNormalized", outdated.ID, "jdoe@gmail.com").Return(errors.New("duplicate key value"))
+
+	// Act
+	updated, err := suite.authService.NormalizeStoredEmails(suite.ctx)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to normalize email of user")
+	suite.Equal(0, updated)
+}
+
+func (suite *AuthServiceTestSuite) TestFindEmailConflicts() {
+	// Arrange
+	suite.authService.Emails = gmailNormalizer()
+	first := models.User{ID: uuid.New(), Email: "a.lice@gmail.com", EmailNormalized: "a.lice@gmail.com"}
+	other := models.User{ID: uuid.New(), Email: "bob@example.com", EmailNormalized: "bob@example.com"}
+	second := models.User{ID: uuid.New(), Email: "alice+subs@gmail.com", EmailNormalized: "alice+subs@gmail.com"}
+	suite.mockUserRepo.On("ListUsersAfter", uuid.Nil, 500).Return([]models.User{first, other, second}, nil)
+
+	// Act
+	conflicts, err := suite.authService.FindEmailConflicts(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal([]services.EmailConflict{{
+		EmailNormalized: "alice@gmail.com",
+		Users: []services.EmailOwner{
+			{ID: first.ID, Email: first.Email},
+			{ID: second.ID, Email: second.Email},
+		},
+	}}, conflicts)
+}
+
+func (suite *AuthServiceTestSuite) TestNormalizeStoredEmails_Batches() {
+	// Arrange
+	batch := make([]models.User, 500)
+	for i := range batch {
+		email := fmt.Sprintf("user%d@example.com", i)
+		batch[i] = models.User{ID: uuid.New(), Email: email, EmailNormalized: email}
+	}
+	suite.mockUserRepo.On("ListUsersAfter", uuid.Nil, 500).Return(batch, nil)
+	suite.mockUserRepo.On("ListUsersAfter", batch[499].ID, 500).Return([]models.User{}, nil)
+
+	// Act
+	updated, err := suite.authService.NormalizeStoredEmails(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(0, updated)
+}
+
+func (suite *AuthServiceTestSuite) TestNormalizeStoredEmails_ListError() {
+	// Arrange
+	suite.mockUserRepo.On("ListUsersAfter", uuid.Nil, 500).Return(nil, errors.New("database error"))
+
+	// Act
+	updated, err := suite.authService.NormalizeStoredEmails(suite.ctx)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to list users")
+	suite.Equal(0, updated)
 }
 
 // ===== JWT TOKEN TESTS =====
 
 func (suite *AuthServiceTestSuite) TestGenerateJWTToken_Success() {
 	// Arrange
+	suite.mockTokenOwner(suite.testUser)
 
 	// Act
-	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -295,11 +765,64 @@ func (suite *AuthServiceTestSuite) TestGenerateJWTToken_Success() {
 	suite.Require().NotNil(claims)
 	suite.Equal(suite.testUser.ID.String(), claims["user_id"])
 	suite.Equal(suite.testUser.Email, claims["email"])
+	suite.Equal(models.RoleUser, claims["role"])
+}
+
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_IncludesRole() {
+	// Arrange
+	suite.testUser.Role = models.RoleSupport
+	suite.mockTokenOwner(suite.testUser)
+
+	// Act
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+
+	// Assert
+	suite.Require().NoError(err)
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+	suite.Require().NoError(err)
+	suite.Equal(models.RoleSupport, claims["role"])
+}
+
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_ReadOnlyUntilEmailVerified() {
+	// Arrange
+	suite.authService.EmailVerificationPolicy = services.EmailVerificationReadOnly
+	suite.mockTokenOwner(suite.testUser)
+
+	// Act
+	unverifiedToken, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	verifiedAt := time.Now()
+	suite.testUser.EmailVerifiedAt = &verifiedAt
+	verifiedToken, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+
+	// Assert
+	claims, err := suite.authService.ValidateToken(suite.ctx, unverifiedToken)
+	suite.Require().NoError(err)
+	suite.Equal(true, claims["read_only"])
+	claims, err = suite.authService.ValidateToken(suite.ctx, verifiedToken)
+	suite.Require().NoError(err)
+	suite.NotContains(claims, "read_only")
+}
+
+func (suite *AuthServiceTestSuite) TestIsReadOnly() {
+	verifiedAt := time.Now()
+	verified := &models.User{EmailVerifiedAt: &verifiedAt}
+	unverified := &models.User{}
+
+	suite.False(suite.authService.IsReadOnly(unverified), "policy off")
+
+	suite.authService.EmailVerificationPolicy = services.EmailVerificationLogin
+	suite.False(suite.authService.IsReadOnly(unverified), "login policy")
+
+	suite.authService.EmailVerificationPolicy = services.EmailVerificationReadOnly
+	suite.True(suite.authService.IsReadOnly(unverified))
+	suite.False(suite.authService.IsReadOnly(verified))
 }
 
 func (suite *AuthServiceTestSuite) TestGenerateJWTToken_NilUser() {
 	// Act
-	token, err := suite.authService.GenerateJWTToken(nil)
+	token, err := suite.authService.GenerateJWTToken(nil, uuid.Nil)
 
 	// Assert
 	suite.Require().Error(err)
@@ -307,25 +830,26 @@ func (suite *AuthServiceTestSuite) TestGenerateJWTToken_NilUser() {
 	suite.Contains(err.Error(), "user cannot be nil")
 }
 
-func (suite *AuthServiceTestSuite) TestGenerateJWTToken_NilSecret() {
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_NilKeys() {
 	// Arrange
-	// Manually set JWTSecret to nil after creation for test
-	suite.authService.JWTSecret = nil
+	// Manually unset keys after creation for test
+	suite.authService.Keys = nil
 
 	// Act
-	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
 
 	// Assert
 	suite.Require().Error(err)
 	suite.Require().Empty(token)
-	suite.Contains(err.Error(), "JWT secret is not configured")
+	suite.Contains(err.Error(), "JWT signing key is not configured")
 }
 
 // ===== VALIDATE TOKEN TESTS =====
 
 func (suite *AuthServiceTestSuite) TestValidateToken_Success() {
 	// Arrange
-	token, _ := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.mockTokenOwner(suite.testUser)
+	token, _ := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
 
 	// Act
 	claims, err := suite.authService.ValidateToken(suite.ctx, token)
@@ -339,7 +863,7 @@ func (suite *AuthServiceTestSuite) TestValidateToken_Success() {
 
 func (suite *AuthServiceTestSuite) TestValidateToken_InvalidClaims() {
 	// Arrange
-	token, _ := suite.authService.GenerateJWTToken(suite.testUser)
+	token, _ := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
 
 	parts := strings.Split(token, ".")
 	if len(parts) >= 2 {
@@ -392,17 +916,388 @@ func (suite *AuthServiceTestSuite) TestValidateToken_ExpiredToken() {
 		"exp":     time.Now().Add(-1 * time.Hour).Unix(), // Expired 1 hour ago
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	expiredToken, _ := token.SignedString(suite.authService.JWTSecret)
+	expiredToken, _ := token.SignedString(suite.secret)
 
 	// Act
 	claims, err := suite.authService.ValidateToken(suite.ctx, expiredToken)
 
 	// Assert
-	suite.Require().Error(err)
+	suite.Require().ErrorIs(err, services.ErrInvalidToken)
 	suite.Require().Nil(claims)
 	suite.Contains(err.Error(), "token is expired")
 }
 
+func (suite *AuthServiceTestSuite) TestValidateToken_RevokedJTI() {
+	// Arrange
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	suite.mockRevokedTokens.On("IsTokenRevoked", mock.AnythingOfType("uuid.UUID")).Return(true, nil)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrTokenRevoked)
+	suite.Nil(claims)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_OlderTokenVersion() {
+	// Arrange
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	signedOut := *suite.testUser
+	signedOut.TokenVersion = 1
+	suite.mockTokenOwner(&signedOut)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrTokenRevoked)
+	suite.Nil(claims)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_TokenWithoutRevocationClaims() {
+	// Arrange - tokens issued before revocation support carry neither jti nor token_version
+	token, err := suite.keys.Sign(jwt.MapClaims{
+		"email":   suite.testUser.Email,
+		"user_id": suite.testUser.ID.String(),
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	suite.Require().NoError(err)
+	suite.mockUserRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.Email, claims["email"])
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_RevocationCheckError() {
+	// Arrange
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	suite.mockRevokedTokens.On("IsTokenRevoked", mock.AnythingOfType("uuid.UUID")).Return(false, errors.New("database down"))
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.NotErrorIs(err, services.ErrInvalidToken)
+	suite.Nil(claims)
+	suite.Contains(err.Error(), "database down")
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_DeletedOwner() {
+	// Arrange
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	suite.mockRevokedTokens.On("IsTokenRevoked", mock.AnythingOfType("uuid.UUID")).Return(false, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.testUser.ID).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidToken)
+	suite.Nil(claims)
+}
+
+// ===== SESSION TESTS =====
+
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_SessionID() {
+	// Arrange
+	sessionID := uuid.New()
+
+	// Act
+	withSession, err := suite.authService.GenerateJWTToken(suite.testUser, sessionID)
+	suite.Require().NoError(err)
+	withoutSession, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+
+	// Assert
+	withClaims, withoutClaims := jwt.MapClaims{}, jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(withSession, withClaims)
+	suite.Require().NoError(err)
+	_, _, err = jwt.NewParser().ParseUnverified(withoutSession, withoutClaims)
+	suite.Require().NoError(err)
+	suite.Equal(sessionID.String(), withClaims["sid"])
+	suite.NotContains(withoutClaims, "sid")
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_ActiveSession() {
+	// Arrange
+	sessions := serviceMocks.NewISessionService(suite.T())
+	suite.authService.Sessions = sessions
+	sessionID := uuid.New()
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, sessionID)
+	suite.Require().NoError(err)
+	suite.mockTokenOwner(suite.testUser)
+	sessions.On("CheckSession", suite.ctx, sessionID).Return(nil)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(sessionID.String(), claims["sid"])
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_RevokedSession() {
+	// Arrange
+	sessions := serviceMocks.NewISessionService(suite.T())
+	suite.authService.Sessions = sessions
+	sessionID := uuid.New()
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, sessionID)
+	suite.Require().NoError(err)
+	suite.mockTokenOwner(suite.testUser)
+	sessions.On("CheckSession", suite.ctx, sessionID).Return(services.ErrSessionRevoked)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrSessionRevoked)
+	suite.Nil(claims)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_TokenWithoutSession() {
+	// Arrange
+	sessions := serviceMocks.NewISessionService(suite.T())
+	suite.authService.Sessions = sessions
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	suite.mockTokenOwner(suite.testUser)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotNil(claims)
+	sessions.AssertNotCalled(suite.T(), "CheckSession", mock.Anything, mock.Anything)
+}
+
+// ===== REVOCATION TESTS =====
+
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_UniqueJTI() {
+	// Act
+	first, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	second, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+
+	// Assert
+	firstClaims, secondClaims := jwt.MapClaims{}, jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(first, firstClaims)
+	suite.Require().NoError(err)
+	_, _, err = jwt.NewParser().ParseUnverified(second, secondClaims)
+	suite.Require().NoError(err)
+	suite.NotEmpty(firstClaims["jti"])
+	suite.NotEqual(firstClaims["jti"], secondClaims["jti"])
+	suite.InDelta(0, firstClaims["token_version"], 0)
+}
+
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_IssuedAt() {
+	// Arrange
+	before := time.Now().Unix()
+
+	// Act
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+
+	// Assert - core-service compares iat with revocation times in local verification
+	suite.Require().NoError(err)
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+	suite.Require().NoError(err)
+	issuedAt, err := claims.GetIssuedAt()
+	suite.Require().NoError(err)
+	suite.Require().NotNil(issuedAt)
+	suite.GreaterOrEqual(issuedAt.Unix(), before)
+	suite.LessOrEqual(issuedAt.Unix(), time.Now().Unix())
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeToken_Success() {
+	// Arrange
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	suite.mockTokenOwner(suite.testUser)
+	suite.mockRevokedTokens.On("RevokeToken", mock.MatchedBy(func(revoked *models.RevokedToken) bool {
+		return revoked.UserID == suite.testUser.ID && revoked.JTI != uuid.Nil && revoked.ExpiresAt.After(time.Now())
+	})).Return(nil)
+	suite.mockRevokedTokens.On("DeleteExpiredRevokedTokens", mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockMessageBroker.On("PublishTokensRevoked", suite.testUser.ID, utils.HashToken(token), mock.MatchedBy(func(jti string) bool {
+		return uuid.Validate(jti) == nil
+	})).Return(nil)
+
+	// Act
+	err = suite.authService.RevokeToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeToken_AlreadyRevoked() {
+	// Arrange
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	suite.mockRevokedTokens.On("IsTokenRevoked", mock.AnythingOfType("uuid.UUID")).Return(true, nil)
+
+	// Act
+	err = suite.authService.RevokeToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidToken)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeToken_InvalidToken() {
+	// Act
+	err := suite.authService.RevokeToken(suite.ctx, "not-a-jwt")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidToken)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeToken_StoreError() {
+	// Arrange
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	suite.mockTokenOwner(suite.testUser)
+	suite.mockRevokedTokens.On("RevokeToken", mock.AnythingOfType("*models.RevokedToken")).Return(errors.New("database down"))
+
+	// Act
+	err = suite.authService.RevokeToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.NotErrorIs(err, services.ErrInvalidToken)
+	suite.Contains(err.Error(), "failed to revoke token")
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllTokens_Success() {
+	// Arrange
+	suite.mockUserRepo.On("IncrementTokenVersion", suite.testUser.ID).Return(nil)
+	suite.mockMessageBroker.On("PublishUserTokensRevoked", suite.testUser.ID, mock.AnythingOfType("time.Time")).Return(errors.New("broker down"))
+
+	// Act
+	err := suite.authService.RevokeAllTokens(suite.ctx, suite.testUser.ID)
+
+	// Assert - a broker failure only delays cache invalidation
+	suite.Require().NoError(err)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllTokens_RepositoryError() {
+	// Arrange
+	suite.mockUserRepo.On("IncrementTokenVersion", suite.testUser.ID).Return(errors.New("database down"))
+
+	// Act
+	err := suite.authService.RevokeAllTokens(suite.ctx, suite.testUser.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to revoke tokens")
+}
+
+// ===== ASYMMETRIC KEY TESTS =====
+
+// useKeys switches the service under test to a ring built from signing and verification
+func (suite *AuthServiceTestSuite) useKeys(signing *jwtkeys.Key, verification ...*jwtkeys.Key) {
+	keys, err := jwtkeys.NewKeyRing(signing, verification...)
+	suite.Require().NoError(err)
+	suite.authService = services.NewAuthService(suite.mockUserRepo, suite.mockRevokedTokens, suite.mockMessageBroker, keys)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_RS256() {
+	// Arrange
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	suite.useKeys(jwtkeys.NewRSAKey("rsa-1", privateKey))
+	suite.mockTokenOwner(suite.testUser)
+
+	// Act
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.ID.String(), claims["user_id"])
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	suite.Require().NoError(err)
+	suite.Equal("RS256", parsed.Header["alg"])
+	suite.Equal("rsa-1", parsed.Header["kid"])
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_EdDSA() {
+	// Arrange
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+	suite.useKeys(jwtkeys.NewEd25519Key("", privateKey))
+	suite.mockTokenOwner(suite.testUser)
+
+	// Act
+	token, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.Email, claims["email"])
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_LegacyHS256AfterSwitch() {
+	// Arrange
+	legacyToken, err := suite.authService.GenerateJWTToken(suite.testUser, uuid.Nil)
+	suite.Require().NoError(err)
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+	suite.useKeys(jwtkeys.NewEd25519Key("", privateKey), jwtkeys.NewHMACKey("", suite.secret))
+	suite.mockTokenOwner(suite.testUser)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, legacyToken)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.ID.String(), claims["user_id"])
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_UnknownKeyID() {
+	// Arrange
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	suite.useKeys(jwtkeys.NewEd25519Key("", otherKey))
+	foreign, err := jwtkeys.NewKeyRing(jwtkeys.NewEd25519Key("", privateKey))
+	suite.Require().NoError(err)
+	token, err := foreign.Sign(jwt.MapClaims{"user_id": suite.testUser.ID.String(), "exp": time.Now().Add(time.Hour).Unix()})
+	suite.Require().NoError(err)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(claims)
+	suite.Contains(err.Error(), "unknown signing key")
+}
+
+func (suite *AuthServiceTestSuite) TestPublicKeys() {
+	// Arrange
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	suite.useKeys(jwtkeys.NewEd25519Key("ed-1", privateKey), jwtkeys.NewHMACKey("", suite.secret))
+
+	// Act
+	jwks := suite.authService.PublicKeys()
+
+	// Assert
+	suite.Require().Len(jwks.Keys, 1)
+	suite.Equal("ed-1", jwks.Keys[0].Kid)
+	suite.Equal("EdDSA", jwks.Keys[0].Alg)
+}
+
 // Run tests
 func TestAuthServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthServiceTestSuite))
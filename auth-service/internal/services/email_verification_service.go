@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// EmailVerificationTokenTTL is how long an emailed verification link can be used
+	EmailVerificationTokenTTL = 24 * time.Hour
+	// MaxVerificationEmailsPerHour limits how many verification emails an account receives
+	MaxVerificationEmailsPerHour = 3
+)
+
+var (
+	// ErrInvalidVerificationToken is returned for unknown, expired and already used email verification tokens
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	// ErrTooManyVerificationEmails is returned when an email change is requested too often
+	ErrTooManyVerificationEmails = errors.New("too many verification emails, try again later")
+)
+
+// EmailVerificationService confirms that users own the email address they registered with
+type EmailVerificationService struct {
+	tokenRepo     repositories.ISingleUseTokenRepository
+	userRepo      repositories.IUserRepository
+	messageBroker messaging.IMessageBroker
+	// Emails finds accounts by the canonical form of their email
+	Emails *emails.Normalizer
+	now    func() time.Time
+}
+
+// NewEmailVerificationService creates a new EmailVerificationService instance
+func NewEmailVerificationService(
+	tokenRepo repositories.ISingleUseTokenRepository,
+	userRepo repositories.IUserRepository,
+	messageBroker messaging.IMessageBroker,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		tokenRepo:     tokenRepo,
+		userRepo:      userRepo,
+		messageBroker: messageBroker,
+		Emails:        emails.DefaultNormalizer(),
+		now:           time.Now,
+	}
+}
+
+// SendVerificationEmail emails a verification link to a newly registered user
+func (s *EmailVerificationService) SendVerificationEmail(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return errors.New("user cannot be nil")
+	}
+	if s.messageBroker == nil {
+		return errors.New("message broker is not initialized")
+	}
+	return s.send(user, user.Email, s.now().UTC())
+}
+
+// ResendVerificationEmail emails a new verification link to the account registered with email.
+// Unknown emails, verified accounts and throttled requests succeed silently, so that
+// the result does not reveal whether an email is registered.
+func (s *EmailVerificationService) ResendVerificationEmail(ctx context.Context, email string) error {
+	// Without a broker no email can be sent for any address
+	if s.messageBroker == nil {
+		return errors.New("message broker is not initialized")
+	}
+
+	user, err := s.userRepo.GetUserByEmail(s.Emails.Canonical(email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	now := s.now().UTC()
+	sent, err := s.tokenRepo.CountSingleUseTokensSince(models.TokenPurposeEmailVerification, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to count email verification tokens: %w", err)
+	}
+	if sent >= MaxVerificationEmailsPerHour {
+		log.Printf("Too many verification emails for user %s, not sending another one", user.ID)
+		return nil
+	}
+
+	return s.send(user, user.Email, now)
+}
+
+// RequestEmailChange emails a verification link to newEmail. The user's email is replaced
+// only once the link is used; pending links of the user are invalidated.
+func (s *EmailVerificationService) RequestEmailChange(ctx context.Context, user *models.User, newEmail string) error {
+	if user == nil {
+		return errors.New("user cannot be nil")
+	}
+	if s.messageBroker == nil {
+		return errors.New("message broker is not initialized")
+	}
+
+	now := s.now().UTC()
+	sent, err := s.tokenRepo.CountSingleUseTokensSince(models.TokenPurposeEmailVerification, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to count email verification tokens: %w", err)
+	}
+	if sent >= MaxVerificationEmailsPerHour {
+		return ErrTooManyVerificationEmails
+	}
+
+	if err := s.tokenRepo.InvalidateUserSingleUseTokens(models.TokenPurposeEmailVerification, user.ID, now); err != nil {
+		return fmt.Errorf("failed to invalidate email verification tokens: %w", err)
+	}
+	return s.send(user, newEmail, now)
+}
+
+// VerifyEmail confirms the email address with a token from a verification email.
+// A token sent for an email change replaces the user's email with the confirmed address.
+// The token works once; the user's other verification tokens are invalidated.
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, plaintext string) error {
+	if !strings.HasPrefix(plaintext, models.EmailVerificationTokenPrefix) {
+		return ErrInvalidVerificationToken
+	}
+
+	token, err := s.tokenRepo.GetSingleUseTokenByHash(models.TokenPurposeEmailVerification, utils.HashToken(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get email verification token: %w", err)
+	}
+
+	now := s.now().UTC()
+	if !token.IsActive(now) {
+		return ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.GetUserByID(token.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	marked, err := s.tokenRepo.MarkSingleUseTokenUsed(token.ID, now)
+	if err != nil {
+		return fmt.Errorf("failed to use email verification token: %w", err)
+	}
+	if !marked {
+		// A concurrent request used the same token first
+		return ErrInvalidVerificationToken
+	}
+
+	if token.Email != "" && token.Email != user.Email {
+		if err := s.changeEmail(user, token.Email, now); err != nil {
+			return err
+		}
+	} else if err := s.userRepo.MarkEmailVerified(token.UserID, now); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	if err := s.tokenRepo.InvalidateUserSingleUseTokens(models.TokenPurposeEmailVerification, token.UserID, now); err != nil {
+		log.Printf("Failed to invalidate email verification tokens of user %s: %v", token.UserID, err)
+	}
+	return nil
+}
+
+// changeEmail replaces the user's email with the confirmed address and announces the change
+func (s *EmailVerificationService) changeEmail(user *models.User, newEmail string, now time.Time) error {
+	if err := s.userRepo.UpdateEmail(user.ID, newEmail, s.Emails.Canonical(newEmail), now); err != nil {
+		return fmt.Errorf("failed to change email: %w", err)
+	}
+
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishUserEmailChanged(user.ID, user.Email, newEmail); err != nil {
+			log.Printf("Failed to publish email change of user %s: %v", user.ID, err)
+		}
+	}
+	return nil
+}
+
+// send creates a verification token for the email address of the user and publishes it for notification-service
+func (s *EmailVerificationService) send(user *models.User, email string, now time.Time) error {
+	plaintext, err := utils.GenerateOpaqueToken(models.EmailVerificationTokenPrefix)
+	if err != nil {
+		return err
+	}
+
+	token := &models.SingleUseToken{
+		Purpose:   models.TokenPurposeEmailVerification,
+		UserID:    user.ID,
+		Email:     email,
+		TokenHash: utils.HashToken(plaintext),
+		CreatedAt: now,
+		ExpiresAt: now.Add(EmailVerificationTokenTTL),
+	}
+	if err := s.tokenRepo.CreateSingleUseToken(token); err != nil {
+		return fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	recipient := *user
+	recipient.Email = email
+	if err := s.messageBroker.PublishEmailVerificationRequested(&recipient, plaintext, token.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to publish email verification requested event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type EmailVerificationServiceTestSuite struct {
+	suite.Suite
+	mockTokenRepo *repositoryMocks.ISingleUseTokenRepository
+	mockUserRepo  *repositoryMocks.IUserRepository
+	mockBroker    *messagingMocks.IMessageBroker
+	service       *services.EmailVerificationService
+	ctx           context.Context
+	user          *models.User
+	plaintext     string
+}
+
+func (suite *EmailVerificationServiceTestSuite) SetupTest() {
+	suite.mockTokenRepo = repositoryMocks.NewISingleUseTokenRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.service = services.NewEmailVerificationService(suite.mockTokenRepo, suite.mockUserRepo, suite.mockBroker)
+	suite.ctx = context.Background()
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleUser}
+	suite.plaintext = models.EmailVerificationTokenPrefix + "current"
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// storedToken returns the stored state of suite.plaintext
+func (suite *EmailVerificationServiceTestSuite) storedToken() *models.SingleUseToken {
+	return &models.SingleUseToken{
+		ID:        uuid.New(),
+		UserID:    suite.user.ID,
+		TokenHash: utils.HashToken(suite.plaintext),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// mockSend mocks storing and publishing a new verification token, returning the published token
+func (suite *EmailVerificationServiceTestSuite) mockSend(stored **models.SingleUseToken, sent *string) {
+	suite.mockTokenRepo.On("CreateSingleUseToken", mock.AnythingOfType("*models.SingleUseToken")).Run(func(args mock.Arguments) {
+		*stored = args.Get(0).(*models.SingleUseToken)
+	}).Return(nil)
+	suite.mockBroker.On("PublishEmailVerificationRequested", suite.user, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		*sent = args.String(1)
+	}).Return(nil)
+}
+
+// ===== SEND VERIFICATION EMAIL TESTS =====
+
+func (suite *EmailVerificationServiceTestSuite) TestSendVerificationEmail_Success() {
+	// Arrange
+	var stored *models.SingleUseToken
+	var sent string
+	suite.mockSend(&stored, &sent)
+
+	// Act
+	err := suite.service.SendVerificationEmail(suite.ctx, suite.user)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(sent, models.EmailVerificationTokenPrefix))
+	suite.Equal(utils.HashToken(sent), stored.TokenHash)
+	suite.Equal(suite.user.ID, stored.UserID)
+	suite.Equal(models.TokenPurposeEmailVerification, stored.Purpose)
+	suite.WithinDuration(time.Now().Add(services.EmailVerificationTokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestSendVerificationEmail_NoBroker() {
+	// Arrange
+	service := services.NewEmailVerificationService(suite.mockTokenRepo, suite.mockUserRepo, nil)
+
+	// Act
+	err := service.SendVerificationEmail(suite.ctx, suite.user)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "message broker is not initialized")
+}
+
+// ===== RESEND VERIFICATION EMAIL TESTS =====
+
+func (suite *EmailVerificationServiceTestSuite) TestResendVerificationEmail_Success() {
+	// Arrange
+	var stored *models.SingleUseToken
+	var sent string
+	suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposeEmailVerification, suite.user.ID, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	suite.mockSend(&stored, &sent)
+
+	// Act
+	err := suite.service.ResendVerificationEmail(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(utils.HashToken(sent), stored.TokenHash)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestResendVerificationEmail_SilentlySkipped() {
+	verifiedAt := time.Now()
+	verified := &models.User{ID: uuid.New(), Email: "verified@example.com", EmailVerifiedAt: &verifiedAt}
+
+	testCases := []struct {
+		name  string
+		email string
+		setup func()
+	}{
+		{
+			name:  "unknown email",
+			email: "unknown@example.com",
+			setup: func() {
+				suite.mockUserRepo.On("GetUserByEmail", "unknown@example.com").Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:  "already verified",
+			email: verified.Email,
+			setup: func() {
+				suite.mockUserRepo.On("GetUserByEmail", verified.Email).Return(verified, nil)
+			},
+		},
+		{
+			name:  "throttled",
+			email: suite.user.Email,
+			setup: func() {
+				suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+				suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposeEmailVerification, suite.user.ID, mock.AnythingOfType("time.Time")).
+					Return(int64(services.MaxVerificationEmailsPerHour), nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			suite.SetupTest()
+			tc.setup()
+
+			// Act
+			err := suite.service.ResendVerificationEmail(suite.ctx, tc.email)
+
+			// Assert
+			suite.Require().NoError(err)
+		})
+	}
+}
+
+// ===== VERIFY EMAIL TESTS =====
+
+func (suite *EmailVerificationServiceTestSuite) TestVerifyEmail_Success() {
+	// Arrange
+	token := suite.storedToken()
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeEmailVerification, utils.HashToken(suite.plaintext)).Return(token, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockUserRepo.On("MarkEmailVerified", suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockTokenRepo.On("InvalidateUserSingleUseTokens", models.TokenPurposeEmailVerification, suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := suite.service.VerifyEmail(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestVerifyEmail_InvalidTokens() {
+	testCases := []struct {
+		name  string
+		token *models.SingleUseToken
+		err   error
+	}{
+		{name: "unknown", err: gorm.ErrRecordNotFound},
+		{name: "expired", token: &models.SingleUseToken{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}},
+		{name: "used", token: &models.SingleUseToken{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), UsedAt: new(time.Time)}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			suite.SetupTest()
+			suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeEmailVerification, utils.HashToken(suite.plaintext)).Return(tc.token, tc.err)
+
+			// Act
+			err := suite.service.VerifyEmail(suite.ctx, suite.plaintext)
+
+			// Assert
+			suite.Require().ErrorIs(err, services.ErrInvalidVerificationToken)
+		})
+	}
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestVerifyEmail_ConcurrentUse() {
+	// Arrange
+	token := suite.storedToken()
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeEmailVerification, utils.HashToken(suite.plaintext)).Return(token, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Act
+	err := suite.service.VerifyEmail(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidVerificationToken)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestVerifyEmail_WrongPrefix() {
+	// Act
+	err := suite.service.VerifyEmail(suite.ctx, models.PasswordResetTokenPrefix+"current")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidVerificationToken)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestVerifyEmail_UpdateError() {
+	// Arrange
+	token := suite.storedToken()
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeEmailVerification, utils.HashToken(suite.plaintext)).Return(token, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockUserRepo.On("MarkEmailVerified", suite.user.ID, mock.AnythingOfType("time.Time")).Return(errors.New("db error"))
+
+	// Act
+	err := suite.service.VerifyEmail(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "db error")
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestVerifyEmail_ChangesEmail() {
+	// Arrange
+	token := suite.storedToken()
+	token.Email = "new@example.com"
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeEmailVerification, utils.HashToken(suite.plaintext)).Return(token, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockUserRepo.On("UpdateEmail", suite.user.ID, "new@example.com", "new@example.com", mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockBroker.On("PublishUserEmailChanged", suite.user.ID, "test@example.com", "new@example.com").Return(nil)
+	suite.mockTokenRepo.On("InvalidateUserSingleUseTokens", models.TokenPurposeEmailVerification, suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := suite.service.VerifyEmail(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestVerifyEmail_ChangeEmailError() {
+	// Arrange
+	token := suite.storedToken()
+	token.Email = "new@example.com"
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeEmailVerification, utils.HashToken(suite.plaintext)).Return(token, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockUserRepo.On("UpdateEmail", suite.user.ID, "new@example.com", "new@example.com", mock.AnythingOfType("time.Time")).Return(errors.New("duplicate key"))
+
+	// Act
+	err := suite.service.VerifyEmail(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to change email")
+}
+
+// ===== REQUEST EMAIL CHANGE TESTS =====
+
+func (suite *EmailVerificationServiceTestSuite) TestRequestEmailChange_Success() {
+	// Arrange
+	var stored *models.SingleUseToken
+	var sent string
+	recipient := *suite.user
+	recipient.Email = "new@example.com"
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposeEmailVerification, suite.user.ID, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	suite.mockTokenRepo.On("InvalidateUserSingleUseTokens", models.TokenPurposeEmailVerification, suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockTokenRepo.On("CreateSingleUseToken", mock.AnythingOfType("*models.SingleUseToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.SingleUseToken)
+	}).Return(nil)
+	suite.mockBroker.On("PublishEmailVerificationRequested", &recipient, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		sent = args.String(1)
+	}).Return(nil)
+
+	// Act
+	err := suite.service.RequestEmailChange(suite.ctx, suite.user, "new@example.com")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("new@example.com", stored.Email)
+	suite.Equal(utils.HashToken(sent), stored.TokenHash)
+	suite.Equal("test@example.com", suite.user.Email)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestRequestEmailChange_Throttled() {
+	// Arrange
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposeEmailVerification, suite.user.ID, mock.AnythingOfType("time.Time")).
+		Return(int64(services.MaxVerificationEmailsPerHour), nil)
+
+	// Act
+	err := suite.service.RequestEmailChange(suite.ctx, suite.user, "new@example.com")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrTooManyVerificationEmails)
+}
+
+func (suite *EmailVerificationServiceTestSuite) TestRequestEmailChange_NoBroker() {
+	// Arrange
+	service := services.NewEmailVerificationService(suite.mockTokenRepo, suite.mockUserRepo, nil)
+
+	// Act
+	err := service.RequestEmailChange(suite.ctx, suite.user, "new@example.com")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "message broker is not initialized")
+}
+
+// Run tests
+func TestEmailVerificationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationServiceTestSuite))
+}
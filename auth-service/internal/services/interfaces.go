@@ -2,18 +2,127 @@ package services
 
 import (
 	"context"
+	"time"
 
+	"github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 //go:generate mockery --name=IAuthService --output=./mocks --outpkg=mocks --filename=IAuthService.go
 type IAuthService interface {
 	Register(ctx context.Context, email, password string) (*models.User, error)
-	Login(ctx context.Context, email, password string) (string, *models.User, error)
+	Login(ctx context.Context, email, password, clientIP string) (*models.User, error)
 	ValidateToken(ctx context.Context, tokenString string) (jwt.MapClaims, error)
-	GenerateJWTToken(user *models.User) (string, error)
+	RevokeToken(ctx context.Context, tokenString string) error
+	RevokeAllTokens(ctx context.Context, userID uuid.UUID) error
+	GenerateJWTToken(user *models.User, sessionID uuid.UUID) (string, error)
+	IsReadOnly(user *models.User) bool
+	PublicKeys() jwtkeys.JWKS
+}
+
+//go:generate mockery --name=ILoginLockoutService --output=./mocks --outpkg=mocks --filename=ILoginLockoutService.go
+type ILoginLockoutService interface {
+	CheckLogin(ctx context.Context, email, clientIP string) error
+	RecordFailedLogin(ctx context.Context, email, clientIP string, user *models.User)
+	RecordSuccessfulLogin(ctx context.Context, email, clientIP string)
+	ReleaseLogin(ctx context.Context, email, clientIP string)
+}
+
+//go:generate mockery --name=IAccessTokenService --output=./mocks --outpkg=mocks --filename=IAccessTokenService.go
+type IAccessTokenService interface {
+	CreateToken(ctx context.Context, userID uuid.UUID, name string, scopes []string, ttl time.Duration) (string, *models.AccessToken, error)
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]models.AccessToken, error)
+	RevokeToken(ctx context.Context, userID, tokenID uuid.UUID) error
+	ValidateToken(ctx context.Context, token string) (*models.AccessToken, *models.User, error)
+}
+
+//go:generate mockery --name=IRefreshTokenService --output=./mocks --outpkg=mocks --filename=IRefreshTokenService.go
+type IRefreshTokenService interface {
+	IssueTokens(ctx context.Context, user *models.User) (*TokenPair, error)
+	Refresh(ctx context.Context, token string) (*TokenPair, *models.User, error)
+	RevokeRefreshToken(ctx context.Context, token string) error
+	RevokeAllRefreshTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+//go:generate mockery --name=ISessionService --output=./mocks --outpkg=mocks --filename=ISessionService.go
+type ISessionService interface {
+	StartSession(ctx context.Context, user *models.User) (*models.Session, error)
+	CheckSession(ctx context.Context, sessionID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	EndSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	EndAllSessions(ctx context.Context, userID uuid.UUID) error
+}
+
+//go:generate mockery --name=IPasswordResetService --output=./mocks --outpkg=mocks --filename=IPasswordResetService.go
+type IPasswordResetService interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+//go:generate mockery --name=IMagicLinkService --output=./mocks --outpkg=mocks --filename=IMagicLinkService.go
+type IMagicLinkService interface {
+	RequestMagicLink(ctx context.Context, email string) error
+	ConsumeMagicLink(ctx context.Context, token string) (*TokenPair, *models.User, error)
+}
+
+//go:generate mockery --name=IOIDCLoginService --output=./mocks --outpkg=mocks --filename=IOIDCLoginService.go
+type IOIDCLoginService interface {
+	LoginWithOIDC(ctx context.Context, identity OIDCIdentity) (*TokenPair, *models.User, error)
+}
+
+//go:generate mockery --name=IEmailVerificationService --output=./mocks --outpkg=mocks --filename=IEmailVerificationService.go
+type IEmailVerificationService interface {
+	SendVerificationEmail(ctx context.Context, user *models.User) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestEmailChange(ctx context.Context, user *models.User, newEmail string) error
+}
+
+//go:generate mockery --name=IAccountService --output=./mocks --outpkg=mocks --filename=IAccountService.go
+type IAccountService interface {
+	ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) (*TokenPair, error)
+	ChangeEmail(ctx context.Context, userID uuid.UUID, currentPassword, newEmail string) error
+}
+
+//go:generate mockery --name=IAccountDeletionService --output=./mocks --outpkg=mocks --filename=IAccountDeletionService.go
+type IAccountDeletionService interface {
+	DeleteAccount(ctx context.Context, userID uuid.UUID, currentPassword string) error
+	AcknowledgeDeletion(ctx context.Context, userID uuid.UUID, service string) error
+	RetryPendingDeletions(ctx context.Context) error
+}
+
+//go:generate mockery --name=ITwoFactorService --output=./mocks --outpkg=mocks --filename=ITwoFactorService.go
+type ITwoFactorService interface {
+	EnrollTOTP(ctx context.Context, userID uuid.UUID) (*TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID uuid.UUID, currentPassword, code string) error
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	CreateChallenge(ctx context.Context, user *models.User) (string, time.Time, error)
+	VerifySecondFactor(ctx context.Context, challenge, code, clientIP string) (*TokenPair, *models.User, error)
+}
+
+//go:generate mockery --name=IPasskeyService --output=./mocks --outpkg=mocks --filename=IPasskeyService.go
+type IPasskeyService interface {
+	BeginRegistration(ctx context.Context, userID uuid.UUID) (string, []byte, error)
+	FinishRegistration(ctx context.Context, userID uuid.UUID, ceremony string, response []byte) error
+	BeginLogin(ctx context.Context) (string, []byte, error)
+	FinishLogin(ctx context.Context, ceremony string, response []byte) (*TokenPair, *models.User, error)
 }
 
 // Interface compliance checks - will fail at compile time if interfaces are not implemented
 var _ IAuthService = (*AuthService)(nil)
+var _ IAccessTokenService = (*AccessTokenService)(nil)
+var _ IRefreshTokenService = (*RefreshTokenService)(nil)
+var _ ISessionService = (*SessionService)(nil)
+var _ IPasswordResetService = (*PasswordResetService)(nil)
+var _ IMagicLinkService = (*MagicLinkService)(nil)
+var _ IOIDCLoginService = (*OIDCLoginService)(nil)
+var _ IEmailVerificationService = (*EmailVerificationService)(nil)
+var _ IAccountService = (*AccountService)(nil)
+var _ IAccountDeletionService = (*AccountDeletionService)(nil)
+var _ ILoginLockoutService = (*LoginLockoutService)(nil)
+var _ ITwoFactorService = (*TwoFactorService)(nil)
+var _ IPasskeyService = (*PasskeyService)(nil)
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	// LoginFreeAttempts is how many consecutive failed logins are allowed without a delay
+	LoginFreeAttempts = 3
+	// LoginBaseDelay is the delay after the first failure beyond LoginFreeAttempts; it doubles with every further failure
+	LoginBaseDelay = time.Second
+	// LoginFailureWindow is how long after the last failure the failed logins are forgotten
+	LoginFailureWindow = time.Hour
+	// loginReserveRetries is how often an attempt is checked again when concurrent attempts keep winning
+	loginReserveRetries = 5
+)
+
+var (
+	// ErrAccountLocked is returned by Login while the account is locked after too many failed attempts
+	ErrAccountLocked = errors.New("account is temporarily locked after too many failed login attempts")
+	// ErrLoginThrottled is returned by Login while further attempts are delayed
+	ErrLoginThrottled = errors.New("too many failed login attempts, try again later")
+)
+
+// LoginBlockedError refuses a login attempt until RetryAt. It wraps ErrAccountLocked or ErrLoginThrottled.
+type LoginBlockedError struct {
+	Err     error
+	RetryAt time.Time
+}
+
+func (e *LoginBlockedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+	return e.Err
+}
+
+// LoginLockoutService protects Login against password guessing. Failed logins are counted per email
+// and per client IP; after LoginFreeAttempts each further attempt is delayed exponentially, and
+// reaching a threshold locks the email or the IP for LockoutDuration.
+type LoginLockoutService struct {
+	failureRepo repositories.ILoginFailureRepository
+	// AccountThreshold is how many failed logins lock an email, 0 disables the protection per email
+	AccountThreshold int
+	// IPThreshold is how many failed logins lock a client IP, 0 disables the protection per IP
+	IPThreshold     int
+	LockoutDuration time.Duration
+	messageBroker   messaging.IMessageBroker
+	now             func() time.Time
+}
+
+// NewLoginLockoutService creates a new LoginLockoutService instance
+func NewLoginLockoutService(
+	failureRepo repositories.ILoginFailureRepository,
+	accountThreshold, ipThreshold int,
+	lockoutDuration time.Duration,
+	messageBroker messaging.IMessageBroker,
+) *LoginLockoutService {
+	return &LoginLockoutService{
+		failureRepo:      failureRepo,
+		AccountThreshold: accountThreshold,
+		IPThreshold:      ipThreshold,
+		LockoutDuration:  lockoutDuration,
+		messageBroker:    messageBroker,
+		now:              time.Now,
+	}
+}
+
+// CheckLogin returns a *LoginBlockedError when a login for email from clientIP must be refused
+// before the password is checked. An empty clientIP is not tracked.
+// An admitted attempt is counted as failed right away, so that concurrent attempts wait for each
+// other's delay; the caller ends it with RecordFailedLogin, RecordSuccessfulLogin or ReleaseLogin.
+func (s *LoginLockoutService) CheckLogin(ctx context.Context, email, clientIP string) error {
+	now := s.now().UTC()
+	subjects := s.subjects(email, clientIP)
+	for i, subject := range subjects {
+		if err := s.reserve(subject.scope, subject.value, now); err != nil {
+			s.release(subjects[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordFailedLogin locks the email or the IP once its threshold is reached by a failed login admitted
+// by CheckLogin. user is nil when email is not registered; the owner of a registered account is told
+// about the lockout.
+func (s *LoginLockoutService) RecordFailedLogin(ctx context.Context, email, clientIP string, user *models.User) {
+	now := s.now().UTC()
+	for _, subject := range s.subjects(email, clientIP) {
+		failure, err := s.failureRepo.GetLoginFailure(subject.scope, subject.value)
+		if err != nil {
+			log.Printf("Failed to record failed login for %s %s: %v", subject.scope, subject.value, err)
+			continue
+		}
+		if failure.Failures < subject.threshold || failure.IsLocked(now) {
+			continue
+		}
+
+		lockedUntil := now.Add(s.LockoutDuration)
+		if err := s.failureRepo.LockLogin(subject.scope, subject.value, lockedUntil); err != nil {
+			log.Printf("Failed to lock login for %s %s: %v", subject.scope, subject.value, err)
+			continue
+		}
+		log.Printf("Login locked for %s %s until %s after %d failed attempts",
+			subject.scope, subject.value, lockedUntil.Format(time.RFC3339), failure.Failures)
+
+		if subject.scope == models.LoginScopeAccount && user != nil {
+			s.publishLocked(user, lockedUntil)
+		}
+	}
+}
+
+// RecordSuccessfulLogin forgets the failed logins for email. Earlier failures of the client IP are kept,
+// otherwise signing in to one's own account would reset the limit for guessing others.
+func (s *LoginLockoutService) RecordSuccessfulLogin(ctx context.Context, email, clientIP string) {
+	if s.AccountThreshold > 0 {
+		if err := s.failureRepo.DeleteLoginFailure(models.LoginScopeAccount, email); err != nil {
+			log.Printf("Failed to reset failed logins for %s: %v", email, err)
+		}
+	}
+	if s.IPThreshold > 0 && clientIP != "" {
+		s.release([]loginSubject{{models.LoginScopeIP, clientIP, s.IPThreshold}})
+	}
+
+	// Records are only needed until the failures are forgotten
+	now := s.now().UTC()
+	if err := s.failureRepo.DeleteStaleLoginFailures(now.Add(-LoginFailureWindow), now); err != nil {
+		log.Printf("Failed to purge stale login failures: %v", err)
+	}
+}
+
+// ReleaseLogin takes back an attempt admitted by CheckLogin that neither failed nor succeeded,
+// e.g. because of an internal error or because a second factor is required
+func (s *LoginLockoutService) ReleaseLogin(ctx context.Context, email, clientIP string) {
+	s.release(s.subjects(email, clientIP))
+}
+
+// loginSubject is an email or a client IP whose failed logins are counted
+type loginSubject struct {
+	scope     string
+	value     string
+	threshold int
+}
+
+// subjects lists the tracked subjects of a login attempt
+func (s *LoginLockoutService) subjects(email, clientIP string) []loginSubject {
+	var subjects []loginSubject
+	if s.AccountThreshold > 0 {
+		subjects = append(subjects, loginSubject{models.LoginScopeAccount, email, s.AccountThreshold})
+	}
+	if s.IPThreshold > 0 && clientIP != "" {
+		subjects = append(subjects, loginSubject{models.LoginScopeIP, clientIP, s.IPThreshold})
+	}
+	return subjects
+}
+
+// reserve counts an attempt of the subject unless check refuses it. When a concurrent attempt
+// changes the record first, the attempt is checked again against the new record.
+func (s *LoginLockoutService) reserve(scope, subject string, now time.Time) error {
+	for range loginReserveRetries {
+		failure, err := s.check(scope, subject, now)
+		if err != nil {
+			return err
+		}
+
+		reserved, err := s.failureRepo.ReserveLoginAttempt(scope, subject, failure, now)
+		if err != nil {
+			return fmt.Errorf("failed to check failed logins: %w", err)
+		}
+		if reserved {
+			return nil
+		}
+	}
+	return &LoginBlockedError{Err: ErrLoginThrottled, RetryAt: now.Add(LoginBaseDelay)}
+}
+
+// release takes back the attempts reserved for subjects. Failures are logged, the attempts are
+// forgotten with the failures at the latest.
+func (s *LoginLockoutService) release(subjects []loginSubject) {
+	for _, subject := range subjects {
+		if err := s.failureRepo.ReleaseLoginAttempt(subject.scope, subject.value); err != nil {
+			log.Printf("Failed to release login attempt for %s %s: %v", subject.scope, subject.value, err)
+		}
+	}
+}
+
+// check refuses the attempt while the subject is locked or its delay has not passed, otherwise it
+// returns the current record, nil when the subject has none.
+// Failures are forgotten when a lockout ended or LoginFailureWindow passed since the last one.
+func (s *LoginLockoutService) check(scope, subject string, now time.Time) (*models.LoginFailure, error) {
+	failure, err := s.failureRepo.GetLoginFailure(scope, subject)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check failed logins: %w", err)
+	}
+
+	if failure.IsLocked(now) {
+		blocked := ErrLoginThrottled
+		if scope == models.LoginScopeAccount {
+			blocked = ErrAccountLocked
+		}
+		return nil, &LoginBlockedError{Err: blocked, RetryAt: *failure.LockedUntil}
+	}
+
+	if failure.LockedUntil != nil || now.Sub(failure.LastFailedAt) >= LoginFailureWindow {
+		if err := s.failureRepo.DeleteLoginFailure(scope, subject); err != nil {
+			log.Printf("Failed to reset failed logins for %s %s: %v", scope, subject, err)
+			return failure, nil
+		}
+		return nil, nil
+	}
+
+	retryAt := failure.LastFailedAt.Add(s.loginDelay(failure.Failures))
+	if now.Before(retryAt) {
+		return nil, &LoginBlockedError{Err: ErrLoginThrottled, RetryAt: retryAt}
+	}
+	return failure, nil
+}
+
+// loginDelay is the time to wait after the last of failures consecutive failed logins,
+// doubling with every failure beyond LoginFreeAttempts up to LockoutDuration
+func (s *LoginLockoutService) loginDelay(failures int) time.Duration {
+	if failures <= LoginFreeAttempts {
+		return 0
+	}
+
+	delay := LoginBaseDelay
+	for i := LoginFreeAttempts + 1; i < failures && delay < s.LockoutDuration; i++ {
+		delay *= 2
+	}
+	return min(delay, s.LockoutDuration)
+}
+
+// publishLocked tells the owner about the lockout. Failures are logged, the lockout applies anyway.
+func (s *LoginLockoutService) publishLocked(user *models.User, lockedUntil time.Time) {
+	if s.messageBroker == nil {
+		return
+	}
+
+	if err := s.messageBroker.PublishUserLocked(user, lockedUntil); err != nil {
+		log.Printf("Failed to publish user locked event: %v", err)
+	}
+}
+
+// loginAttempt is a login admitted by beginLogin. It ends with failed, succeeded or, for attempts
+// without a verdict, the deferred release; only the first of them counts.
+type loginAttempt struct {
+	ctx      context.Context
+	lockout  ILoginLockoutService
+	email    string
+	clientIP string
+	ended    bool
+}
+
+// beginLogin admits a login for email from clientIP, lockout is nil when logins are not limited
+func beginLogin(ctx context.Context, lockout ILoginLockoutService, email, clientIP string) (*loginAttempt, error) {
+	if lockout != nil {
+		if err := lockout.CheckLogin(ctx, email, clientIP); err != nil {
+			return nil, err
+		}
+	}
+	return &loginAttempt{ctx: ctx, lockout: lockout, email: email, clientIP: clientIP}, nil
+}
+
+// failed counts the attempt as a failed login, user is nil when the email is not registered
+func (a *loginAttempt) failed(user *models.User) {
+	if a.end() {
+		a.lockout.RecordFailedLogin(a.ctx, a.email, a.clientIP, user)
+	}
+}
+
+// succeeded forgets the failed logins of the email
+func (a *loginAttempt) succeeded() {
+	if a.end() {
+		a.lockout.RecordSuccessfulLogin(a.ctx, a.email, a.clientIP)
+	}
+}
+
+// release takes the attempt back unless it failed or succeeded
+func (a *loginAttempt) release() {
+	if a.end() {
+		a.lockout.ReleaseLogin(a.ctx, a.email, a.clientIP)
+	}
+}
+
+// end reports whether the attempt has to be ended with the lockout
+func (a *loginAttempt) end() bool {
+	if a.ended || a.lockout == nil {
+		return false
+	}
+	a.ended = true
+	return true
+}
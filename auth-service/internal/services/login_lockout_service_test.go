@@ -0,0 +1,349 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type LoginLockoutServiceTestSuite struct {
+	suite.Suite
+	mockFailureRepo *repositoryMocks.ILoginFailureRepository
+	mockBroker      *messagingMocks.IMessageBroker
+	service         *services.LoginLockoutService
+	ctx             context.Context
+	email           string
+	clientIP        string
+	user            *models.User
+}
+
+func (suite *LoginLockoutServiceTestSuite) SetupTest() {
+	suite.mockFailureRepo = repositoryMocks.NewILoginFailureRepository(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.service = services.NewLoginLockoutService(suite.mockFailureRepo, 10, 100, 15*time.Minute, suite.mockBroker)
+	suite.ctx = context.Background()
+	suite.email = "test@example.com"
+	suite.clientIP = "192.0.2.1"
+	suite.user = &models.User{ID: uuid.New(), Email: suite.email}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// mockFailure mocks the stored failures of a subject, nil meaning none
+func (suite *LoginLockoutServiceTestSuite) mockFailure(scope, subject string, failure *models.LoginFailure) {
+	if failure == nil {
+		suite.mockFailureRepo.On("GetLoginFailure", scope, subject).Return(nil, gorm.ErrRecordNotFound)
+		return
+	}
+	suite.mockFailureRepo.On("GetLoginFailure", scope, subject).Return(failure, nil)
+}
+
+// mockFailures mocks a subject with failures counted, including the attempt being recorded
+func (suite *LoginLockoutServiceTestSuite) mockFailures(scope, subject string, failures int) {
+	suite.mockFailure(scope, subject, &models.LoginFailure{Scope: scope, Subject: subject, Failures: failures, LastFailedAt: time.Now()})
+}
+
+// mockReserve mocks counting an attempt of a subject before its outcome is known
+func (suite *LoginLockoutServiceTestSuite) mockReserve(scope, subject string) {
+	suite.mockFailureRepo.On("ReserveLoginAttempt", scope, subject, mock.Anything, mock.AnythingOfType("time.Time")).Return(true, nil)
+}
+
+// ===== CHECK LOGIN TESTS =====
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_NoFailures() {
+	// Arrange
+	suite.mockFailure(models.LoginScopeAccount, suite.email, nil)
+	suite.mockFailure(models.LoginScopeIP, suite.clientIP, nil)
+	suite.mockFailureRepo.On("ReserveLoginAttempt", models.LoginScopeAccount, suite.email, (*models.LoginFailure)(nil), mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockFailureRepo.On("ReserveLoginAttempt", models.LoginScopeIP, suite.clientIP, (*models.LoginFailure)(nil), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	// Act
+	err := suite.service.CheckLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_AccountLocked() {
+	// Arrange
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	suite.mockFailure(models.LoginScopeAccount, suite.email, &models.LoginFailure{Failures: 10, LastFailedAt: time.Now(), LockedUntil: &lockedUntil})
+
+	// Act
+	err := suite.service.CheckLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrAccountLocked)
+	var blocked *services.LoginBlockedError
+	suite.Require().ErrorAs(err, &blocked)
+	suite.Equal(lockedUntil, blocked.RetryAt)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_IPLocked() {
+	// Arrange
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	suite.mockFailure(models.LoginScopeAccount, suite.email, nil)
+	suite.mockReserve(models.LoginScopeAccount, suite.email)
+	suite.mockFailure(models.LoginScopeIP, suite.clientIP, &models.LoginFailure{Failures: 100, LastFailedAt: time.Now(), LockedUntil: &lockedUntil})
+	suite.mockFailureRepo.On("ReleaseLoginAttempt", models.LoginScopeAccount, suite.email).Return(nil)
+
+	// Act
+	err := suite.service.CheckLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrLoginThrottled)
+	suite.NotErrorIs(err, services.ErrAccountLocked)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_Delay() {
+	testCases := []struct {
+		name        string
+		failures    int
+		failedAgo   time.Duration
+		wantBlocked bool
+		wantDelay   time.Duration
+	}{
+		{name: "free attempts", failures: services.LoginFreeAttempts, failedAgo: 0},
+		{name: "first delay pending", failures: services.LoginFreeAttempts + 1, failedAgo: 0, wantBlocked: true, wantDelay: time.Second},
+		{name: "delay doubles", failures: services.LoginFreeAttempts + 3, failedAgo: time.Second, wantBlocked: true, wantDelay: 4 * time.Second},
+		{name: "delay passed", failures: services.LoginFreeAttempts + 3, failedAgo: 5 * time.Second},
+		{name: "delay capped at lockout duration", failures: 40, failedAgo: 14 * time.Minute, wantBlocked: true, wantDelay: 15 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			suite.SetupTest()
+			lastFailedAt := time.Now().Add(-tc.failedAgo)
+			suite.mockFailure(models.LoginScopeAccount, suite.email, &models.LoginFailure{Failures: tc.failures, LastFailedAt: lastFailedAt})
+			if !tc.wantBlocked {
+				suite.mockReserve(models.LoginScopeAccount, suite.email)
+				suite.mockFailure(models.LoginScopeIP, suite.clientIP, nil)
+				suite.mockReserve(models.LoginScopeIP, suite.clientIP)
+			}
+
+			// Act
+			err := suite.service.CheckLogin(suite.ctx, suite.email, suite.clientIP)
+
+			// Assert
+			if !tc.wantBlocked {
+				suite.Require().NoError(err)
+				return
+			}
+			suite.Require().ErrorIs(err, services.ErrLoginThrottled)
+			var blocked *services.LoginBlockedError
+			suite.Require().ErrorAs(err, &blocked)
+			suite.Equal(lastFailedAt.Add(tc.wantDelay), blocked.RetryAt)
+		})
+	}
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_ForgetsAfterLockout() {
+	// Arrange
+	lockedUntil := time.Now().Add(-time.Second)
+	suite.mockFailure(models.LoginScopeAccount, suite.email, &models.LoginFailure{Failures: 10, LastFailedAt: time.Now().Add(-15 * time.Minute), LockedUntil: &lockedUntil})
+	suite.mockFailureRepo.On("DeleteLoginFailure", models.LoginScopeAccount, suite.email).Return(nil)
+	suite.mockFailureRepo.On("ReserveLoginAttempt", models.LoginScopeAccount, suite.email, (*models.LoginFailure)(nil), mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockFailure(models.LoginScopeIP, suite.clientIP, nil)
+	suite.mockReserve(models.LoginScopeIP, suite.clientIP)
+
+	// Act
+	err := suite.service.CheckLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_ForgetsOldFailures() {
+	// Arrange
+	suite.mockFailure(models.LoginScopeAccount, suite.email, &models.LoginFailure{Failures: 9, LastFailedAt: time.Now().Add(-services.LoginFailureWindow)})
+	suite.mockFailureRepo.On("DeleteLoginFailure", models.LoginScopeAccount, suite.email).Return(nil)
+	suite.mockFailureRepo.On("ReserveLoginAttempt", models.LoginScopeAccount, suite.email, (*models.LoginFailure)(nil), mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockFailure(models.LoginScopeIP, suite.clientIP, nil)
+	suite.mockReserve(models.LoginScopeIP, suite.clientIP)
+
+	// Act
+	err := suite.service.CheckLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_WithoutClientIP() {
+	// Arrange
+	suite.mockFailure(models.LoginScopeAccount, suite.email, nil)
+	suite.mockReserve(models.LoginScopeAccount, suite.email)
+
+	// Act
+	err := suite.service.CheckLogin(suite.ctx, suite.email, "")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_ChecksAgainWhenConcurrentAttemptWins() {
+	// Arrange
+	free := &models.LoginFailure{Failures: services.LoginFreeAttempts, LastFailedAt: time.Now().Add(-time.Minute)}
+	raced := &models.LoginFailure{Failures: services.LoginFreeAttempts + 1, LastFailedAt: time.Now()}
+	suite.mockFailureRepo.On("GetLoginFailure", models.LoginScopeAccount, suite.email).Return(free, nil).Once()
+	suite.mockFailureRepo.On("ReserveLoginAttempt", models.LoginScopeAccount, suite.email, free, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+	suite.mockFailureRepo.On("GetLoginFailure", models.LoginScopeAccount, suite.email).Return(raced, nil).Once()
+
+	// Act
+	err := suite.service.CheckLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrLoginThrottled)
+	var blocked *services.LoginBlockedError
+	suite.Require().ErrorAs(err, &blocked)
+	suite.Equal(raced.LastFailedAt.Add(services.LoginBaseDelay), blocked.RetryAt)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestCheckLogin_ConcurrentAttempts() {
+	// Arrange
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.LoginFailure{}))
+	// Every connection would open another in-memory database
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+	repo := repositories.NewLoginFailureRepository(repositories.NewGormAdapterFromDB(db))
+	suite.Require().NoError(db.Create(&models.LoginFailure{
+		Scope:        models.LoginScopeAccount,
+		Subject:      suite.email,
+		Failures:     services.LoginFreeAttempts,
+		LastFailedAt: time.Now().UTC().Add(-time.Minute),
+	}).Error)
+	service := services.NewLoginLockoutService(repo, 10, 100, 15*time.Minute, nil)
+
+	// Act
+	const attempts = 20
+	errs := make([]error, attempts)
+	var wg sync.WaitGroup
+	for i := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = service.CheckLogin(suite.ctx, suite.email, "")
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	admitted := 0
+	for _, err := range errs {
+		if err == nil {
+			admitted++
+			continue
+		}
+		suite.Require().ErrorIs(err, services.ErrLoginThrottled)
+	}
+	suite.Equal(1, admitted)
+	failure, err := repo.GetLoginFailure(models.LoginScopeAccount, suite.email)
+	suite.Require().NoError(err)
+	suite.Equal(services.LoginFreeAttempts+1, failure.Failures)
+}
+
+// ===== RECORD FAILED LOGIN TESTS =====
+
+func (suite *LoginLockoutServiceTestSuite) TestRecordFailedLogin_BelowThreshold() {
+	// Arrange
+	suite.mockFailures(models.LoginScopeAccount, suite.email, 9)
+	suite.mockFailures(models.LoginScopeIP, suite.clientIP, 9)
+
+	// Act
+	suite.service.RecordFailedLogin(suite.ctx, suite.email, suite.clientIP, suite.user)
+
+	// Assert
+	suite.mockFailureRepo.AssertNotCalled(suite.T(), "LockLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestRecordFailedLogin_LocksAccountAndAlertsOwner() {
+	// Arrange
+	var lockedUntil time.Time
+	suite.mockFailures(models.LoginScopeAccount, suite.email, 10)
+	suite.mockFailures(models.LoginScopeIP, suite.clientIP, 10)
+	suite.mockFailureRepo.On("LockLogin", models.LoginScopeAccount, suite.email, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		lockedUntil = args.Get(2).(time.Time)
+	}).Return(nil)
+	suite.mockBroker.On("PublishUserLocked", suite.user, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	suite.service.RecordFailedLogin(suite.ctx, suite.email, suite.clientIP, suite.user)
+
+	// Assert
+	suite.WithinDuration(time.Now().Add(15*time.Minute), lockedUntil, time.Second)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestRecordFailedLogin_UnknownEmailLockedSilently() {
+	// Arrange
+	suite.mockFailures(models.LoginScopeAccount, suite.email, 10)
+	suite.mockFailures(models.LoginScopeIP, suite.clientIP, 10)
+	suite.mockFailureRepo.On("LockLogin", models.LoginScopeAccount, suite.email, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	suite.service.RecordFailedLogin(suite.ctx, suite.email, suite.clientIP, nil)
+
+	// Assert
+	suite.mockBroker.AssertNotCalled(suite.T(), "PublishUserLocked", mock.Anything, mock.Anything)
+}
+
+func (suite *LoginLockoutServiceTestSuite) TestRecordFailedLogin_LocksIP() {
+	// Arrange
+	suite.mockFailures(models.LoginScopeAccount, suite.email, 1)
+	suite.mockFailures(models.LoginScopeIP, suite.clientIP, 100)
+	suite.mockFailureRepo.On("LockLogin", models.LoginScopeIP, suite.clientIP, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	suite.service.RecordFailedLogin(suite.ctx, suite.email, suite.clientIP, suite.user)
+
+	// Assert
+	suite.mockBroker.AssertNotCalled(suite.T(), "PublishUserLocked", mock.Anything, mock.Anything)
+}
+
+// ===== RECORD SUCCESSFUL LOGIN TESTS =====
+
+func (suite *LoginLockoutServiceTestSuite) TestRecordSuccessfulLogin_ResetsAccountOnly() {
+	// Arrange
+	suite.mockFailureRepo.On("DeleteLoginFailure", models.LoginScopeAccount, suite.email).Return(nil)
+	suite.mockFailureRepo.On("ReleaseLoginAttempt", models.LoginScopeIP, suite.clientIP).Return(nil)
+	suite.mockFailureRepo.On("DeleteStaleLoginFailures", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	suite.service.RecordSuccessfulLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.mockFailureRepo.AssertNotCalled(suite.T(), "DeleteLoginFailure", models.LoginScopeIP, mock.Anything)
+}
+
+// ===== RELEASE LOGIN TESTS =====
+
+func (suite *LoginLockoutServiceTestSuite) TestReleaseLogin() {
+	// Arrange
+	suite.mockFailureRepo.On("ReleaseLoginAttempt", models.LoginScopeAccount, suite.email).Return(nil)
+	suite.mockFailureRepo.On("ReleaseLoginAttempt", models.LoginScopeIP, suite.clientIP).Return(nil)
+
+	// Act
+	suite.service.ReleaseLogin(suite.ctx, suite.email, suite.clientIP)
+
+	// Assert
+	suite.mockFailureRepo.AssertNotCalled(suite.T(), "DeleteLoginFailure", mock.Anything, mock.Anything)
+}
+
+// Run tests
+func TestLoginLockoutServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginLockoutServiceTestSuite))
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// MagicLinkTokenTTL is how long an emailed sign-in link can be used
+	MagicLinkTokenTTL = 15 * time.Minute
+	// MaxMagicLinkRequests limits how many sign-in emails an account receives per MagicLinkTokenTTL
+	MaxMagicLinkRequests = 3
+)
+
+// ErrInvalidMagicLink is returned for unknown, expired and already used magic link tokens
+var ErrInvalidMagicLink = errors.New("invalid or expired magic link")
+
+// MagicLinkService lets users sign in without a password through a link sent to their email
+type MagicLinkService struct {
+	tokenRepo     repositories.ISingleUseTokenRepository
+	userRepo      repositories.IUserRepository
+	refreshTokens IRefreshTokenService
+	messageBroker messaging.IMessageBroker
+	// TwoFactor asks for a second factor when the user enabled it, the link alone signs in when it is nil
+	TwoFactor ITwoFactorService
+	// Emails finds accounts by the canonical form of their email
+	Emails *emails.Normalizer
+	now    func() time.Time
+}
+
+// NewMagicLinkService creates a new MagicLinkService instance
+func NewMagicLinkService(
+	tokenRepo repositories.ISingleUseTokenRepository,
+	userRepo repositories.IUserRepository,
+	refreshTokens IRefreshTokenService,
+	messageBroker messaging.IMessageBroker,
+) *MagicLinkService {
+	return &MagicLinkService{
+		tokenRepo:     tokenRepo,
+		userRepo:      userRepo,
+		refreshTokens: refreshTokens,
+		messageBroker: messageBroker,
+		Emails:        emails.DefaultNormalizer(),
+		now:           time.Now,
+	}
+}
+
+// RequestMagicLink emails a sign-in link to the account registered with email.
+// Unknown emails and throttled requests succeed silently, so that the result does not
+// reveal whether an email is registered.
+func (s *MagicLinkService) RequestMagicLink(ctx context.Context, email string) error {
+	// Without a broker no email can be sent for any address
+	if s.messageBroker == nil {
+		return errors.New("message broker is not initialized")
+	}
+
+	user, err := s.userRepo.GetUserByEmail(s.Emails.Canonical(email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	now := s.now().UTC()
+	issued, err := s.tokenRepo.CountSingleUseTokensSince(models.TokenPurposeMagicLink, user.ID, now.Add(-MagicLinkTokenTTL))
+	if err != nil {
+		return fmt.Errorf("failed to count magic link tokens: %w", err)
+	}
+	if issued >= MaxMagicLinkRequests {
+		log.Printf("Too many magic link requests for user %s, not sending another email", user.ID)
+		return nil
+	}
+
+	plaintext, err := utils.GenerateOpaqueToken(models.MagicLinkTokenPrefix)
+	if err != nil {
+		return err
+	}
+
+	token := &models.SingleUseToken{
+		Purpose:   models.TokenPurposeMagicLink,
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(plaintext),
+		CreatedAt: now,
+		ExpiresAt: now.Add(MagicLinkTokenTTL),
+	}
+	if err := s.tokenRepo.CreateSingleUseToken(token); err != nil {
+		return fmt.Errorf("failed to create magic link token: %w", err)
+	}
+
+	if err := s.messageBroker.PublishMagicLinkRequested(user, plaintext, token.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to publish magic link requested event: %w", err)
+	}
+	return nil
+}
+
+// ConsumeMagicLink signs the user in with a token from a magic link email and issues the
+// tokens of the new session. The token works once, and the other outstanding links of the
+// user are void afterwards. Following the link proves access to the mailbox, so an unverified
+// email becomes verified. The link replaces the password only: accounts with two-factor
+// authentication get a *SecondFactorRequiredError instead of tokens.
+func (s *MagicLinkService) ConsumeMagicLink(ctx context.Context, plaintext string) (*TokenPair, *models.User, error) {
+	if !strings.HasPrefix(plaintext, models.MagicLinkTokenPrefix) {
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	token, err := s.tokenRepo.GetSingleUseTokenByHash(models.TokenPurposeMagicLink, utils.HashToken(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidMagicLink
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get magic link token: %w", err)
+	}
+
+	now := s.now().UTC()
+	if !token.IsActive(now) {
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	user, err := s.userRepo.GetUserByID(token.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The account was deleted after the link was sent
+		return nil, nil, ErrInvalidMagicLink
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	marked, err := s.tokenRepo.MarkSingleUseTokenUsed(token.ID, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to use magic link token: %w", err)
+	}
+	if !marked {
+		// A concurrent request used the same token first
+		return nil, nil, ErrInvalidMagicLink
+	}
+	if err := s.tokenRepo.InvalidateUserSingleUseTokens(models.TokenPurposeMagicLink, user.ID, now); err != nil {
+		log.Printf("Failed to invalidate magic link tokens of user %s: %v", user.ID, err)
+	}
+
+	if !user.IsEmailVerified() {
+		if err := s.userRepo.MarkEmailVerified(user.ID, now); err != nil {
+			return nil, nil, fmt.Errorf("failed to mark email as verified: %w", err)
+		}
+		user.EmailVerifiedAt = &now
+	}
+
+	if s.TwoFactor != nil {
+		enabled, err := s.TwoFactor.IsEnabled(ctx, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if enabled {
+			challenge, expiresAt, err := s.TwoFactor.CreateChallenge(ctx, user)
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, user, &SecondFactorRequiredError{Challenge: challenge, ExpiresAt: expiresAt}
+		}
+	}
+
+	pair, err := s.refreshTokens.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
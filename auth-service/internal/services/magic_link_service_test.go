@@ -0,0 +1,279 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MagicLinkServiceTestSuite struct {
+	suite.Suite
+	mockTokenRepo     *repositoryMocks.ISingleUseTokenRepository
+	mockUserRepo      *repositoryMocks.IUserRepository
+	mockRefreshTokens *serviceMocks.IRefreshTokenService
+	mockBroker        *messagingMocks.IMessageBroker
+	mockTwoFactor     *serviceMocks.ITwoFactorService
+	service           *services.MagicLinkService
+	ctx               context.Context
+	user              *models.User
+	plaintext         string
+}
+
+func (suite *MagicLinkServiceTestSuite) SetupTest() {
+	suite.mockTokenRepo = repositoryMocks.NewISingleUseTokenRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockRefreshTokens = serviceMocks.NewIRefreshTokenService(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.mockTwoFactor = serviceMocks.NewITwoFactorService(suite.T())
+	suite.service = services.NewMagicLinkService(suite.mockTokenRepo, suite.mockUserRepo, suite.mockRefreshTokens, suite.mockBroker)
+	suite.service.TwoFactor = suite.mockTwoFactor
+	suite.ctx = context.Background()
+	verifiedAt := time.Now().Add(-24 * time.Hour)
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleUser, EmailVerifiedAt: &verifiedAt}
+	suite.plaintext = models.MagicLinkTokenPrefix + "current"
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// storedToken returns the stored state of suite.plaintext
+func (suite *MagicLinkServiceTestSuite) storedToken() *models.SingleUseToken {
+	return &models.SingleUseToken{
+		ID:        uuid.New(),
+		UserID:    suite.user.ID,
+		TokenHash: utils.HashToken(suite.plaintext),
+		ExpiresAt: time.Now().Add(services.MagicLinkTokenTTL),
+	}
+}
+
+// mockUsableToken mocks the lookup and the single use of a valid suite.plaintext
+func (suite *MagicLinkServiceTestSuite) mockUsableToken() *models.SingleUseToken {
+	token := suite.storedToken()
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeMagicLink, utils.HashToken(suite.plaintext)).Return(token, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockTokenRepo.On("InvalidateUserSingleUseTokens", models.TokenPurposeMagicLink, suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	return token
+}
+
+// mockIssueTokens mocks the tokens of the new session
+func (suite *MagicLinkServiceTestSuite) mockIssueTokens() time.Time {
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL)
+	suite.mockRefreshTokens.On("IssueTokens", suite.ctx, suite.user).
+		Return(&services.TokenPair{AccessToken: "jwt-token", RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}, nil)
+	return refreshExpiresAt
+}
+
+// ===== REQUEST MAGIC LINK TESTS =====
+
+func (suite *MagicLinkServiceTestSuite) TestRequestMagicLink_SendsToken() {
+	// Arrange
+	var stored *models.SingleUseToken
+	var sent string
+	suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposeMagicLink, suite.user.ID, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	suite.mockTokenRepo.On("CreateSingleUseToken", mock.AnythingOfType("*models.SingleUseToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.SingleUseToken)
+	}).Return(nil)
+	suite.mockBroker.On("PublishMagicLinkRequested", suite.user, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		sent = args.String(1)
+	}).Return(nil)
+
+	// Act
+	err := suite.service.RequestMagicLink(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(sent, models.MagicLinkTokenPrefix))
+	suite.Equal(utils.HashToken(sent), stored.TokenHash)
+	suite.Equal(suite.user.ID, stored.UserID)
+	suite.Equal(models.TokenPurposeMagicLink, stored.Purpose)
+	suite.WithinDuration(time.Now().Add(services.MagicLinkTokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestRequestMagicLink_UnknownEmail() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByEmail", "unknown@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	err := suite.service.RequestMagicLink(suite.ctx, "unknown@example.com")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestRequestMagicLink_ThrottledPerEmail() {
+	// Arrange
+	var since time.Time
+	suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposeMagicLink, suite.user.ID, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		since = args.Get(2).(time.Time)
+	}).Return(int64(services.MaxMagicLinkRequests), nil)
+
+	// Act
+	err := suite.service.RequestMagicLink(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.WithinDuration(time.Now().Add(-services.MagicLinkTokenTTL), since, time.Minute)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestRequestMagicLink_PublishError() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposeMagicLink, suite.user.ID, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	suite.mockTokenRepo.On("CreateSingleUseToken", mock.AnythingOfType("*models.SingleUseToken")).Return(nil)
+	suite.mockBroker.On("PublishMagicLinkRequested", suite.user, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Return(errors.New("broker down"))
+
+	// Act
+	err := suite.service.RequestMagicLink(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to publish magic link requested event")
+}
+
+func (suite *MagicLinkServiceTestSuite) TestRequestMagicLink_NoBroker() {
+	// Arrange
+	service := services.NewMagicLinkService(suite.mockTokenRepo, suite.mockUserRepo, suite.mockRefreshTokens, nil)
+
+	// Act
+	err := service.RequestMagicLink(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "message broker is not initialized")
+}
+
+// ===== CONSUME MAGIC LINK TESTS =====
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_IssuesTokens() {
+	// Arrange
+	suite.mockUsableToken()
+	suite.mockTwoFactor.On("IsEnabled", suite.ctx, suite.user.ID).Return(false, nil)
+	refreshExpiresAt := suite.mockIssueTokens()
+
+	// Act
+	pair, user, err := suite.service.ConsumeMagicLink(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user, user)
+	suite.Equal("jwt-token", pair.AccessToken)
+	suite.Equal("rt_refresh", pair.RefreshToken)
+	suite.Equal(refreshExpiresAt, pair.RefreshExpiresAt)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_VerifiesEmail() {
+	// Arrange
+	suite.user.EmailVerifiedAt = nil
+	suite.mockUsableToken()
+	suite.mockUserRepo.On("MarkEmailVerified", suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockTwoFactor.On("IsEnabled", suite.ctx, suite.user.ID).Return(false, nil)
+	suite.mockIssueTokens()
+
+	// Act
+	_, user, err := suite.service.ConsumeMagicLink(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(user.IsEmailVerified())
+}
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_SecondFactorRequired() {
+	// Arrange
+	expiresAt := time.Now().Add(services.TwoFactorChallengeTTL)
+	suite.mockUsableToken()
+	suite.mockTwoFactor.On("IsEnabled", suite.ctx, suite.user.ID).Return(true, nil)
+	suite.mockTwoFactor.On("CreateChallenge", suite.ctx, suite.user).Return("tfc_challenge", expiresAt, nil)
+
+	// Act
+	pair, user, err := suite.service.ConsumeMagicLink(suite.ctx, suite.plaintext)
+
+	// Assert
+	var required *services.SecondFactorRequiredError
+	suite.Require().ErrorAs(err, &required)
+	suite.Equal("tfc_challenge", required.Challenge)
+	suite.Equal(expiresAt, required.ExpiresAt)
+	suite.Nil(pair)
+	suite.Equal(suite.user, user)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_Expired() {
+	// Arrange
+	token := suite.storedToken()
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeMagicLink, utils.HashToken(suite.plaintext)).Return(token, nil)
+
+	// Act
+	pair, _, err := suite.service.ConsumeMagicLink(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidMagicLink)
+	suite.Nil(pair)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_AlreadyUsed() {
+	// Arrange
+	token := suite.storedToken()
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeMagicLink, utils.HashToken(suite.plaintext)).Return(token, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	// A concurrent request used the token between the lookup and the update
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Act
+	pair, _, err := suite.service.ConsumeMagicLink(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidMagicLink)
+	suite.Nil(pair)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_UnknownToken() {
+	// Arrange
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeMagicLink, utils.HashToken(suite.plaintext)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	_, _, err := suite.service.ConsumeMagicLink(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_DeletedUser() {
+	// Arrange
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposeMagicLink, utils.HashToken(suite.plaintext)).Return(suite.storedToken(), nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	_, _, err := suite.service.ConsumeMagicLink(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+func (suite *MagicLinkServiceTestSuite) TestConsumeMagicLink_WrongPrefix() {
+	// Act
+	_, _, err := suite.service.ConsumeMagicLink(suite.ctx, models.PasswordResetTokenPrefix+"current")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+// Run tests
+func TestMagicLinkServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(MagicLinkServiceTestSuite))
+}
@@ -5,10 +5,14 @@ package mocks
 import (
 	context "context"
 
+	jwtkeys "github.com/Koshsky/subs-service/auth-service/internal/jwtkeys"
 	jwt "github.com/golang-jwt/jwt/v5"
+
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/Koshsky/subs-service/auth-service/internal/models"
+
+	uuid "github.com/google/uuid"
 )
 
 // IAuthService is an autogenerated mock type for the IAuthService type
@@ -16,9 +20,9 @@ type IAuthService struct {
 	mock.Mock
 }
 
-// GenerateJWTToken provides a mock function with given fields: user
-func (_m *IAuthService) GenerateJWTToken(user *models.User) (string, error) {
-	ret := _m.Called(user)
+// GenerateJWTToken provides a mock function with given fields: user, sessionID
+func (_m *IAuthService) GenerateJWTToken(user *models.User, sessionID uuid.UUID) (string, error) {
+	ret := _m.Called(user, sessionID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateJWTToken")
@@ -26,17 +30,17 @@ func (_m *IAuthService) GenerateJWTToken(user *models.User) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*models.User) (string, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(*models.User, uuid.UUID) (string, error)); ok {
+		return rf(user, sessionID)
 	}
-	if rf, ok := ret.Get(0).(func(*models.User) string); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(*models.User, uuid.UUID) string); ok {
+		r0 = rf(user, sessionID)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*models.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(*models.User, uuid.UUID) error); ok {
+		r1 = rf(user, sessionID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -44,41 +48,70 @@ func (_m *IAuthService) GenerateJWTToken(user *models.User) (string, error) {
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, email, password
-func (_m *IAuthService) Login(ctx context.Context, email string, password string) (string, *models.User, error) {
-	ret := _m.Called(ctx, email, password)
+// IsReadOnly provides a mock function with given fields: user
+func (_m *IAuthService) IsReadOnly(user *models.User) bool {
+	ret := _m.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsReadOnly")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*models.User) bool); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Login provides a mock function with given fields: ctx, email, password, clientIP
+func (_m *IAuthService) Login(ctx context.Context, email string, password string, clientIP string) (*models.User, error) {
+	ret := _m.Called(ctx, email, password, clientIP)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
-	var r1 *models.User
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, *models.User, error)); ok {
-		return rf(ctx, email, password)
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*models.User, error)); ok {
+		return rf(ctx, email, password, clientIP)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *models.User); ok {
+		r0 = rf(ctx, email, password, clientIP)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) *models.User); ok {
-		r1 = rf(ctx, email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, email, password, clientIP)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*models.User)
-		}
+		r1 = ret.Error(1)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
-		r2 = rf(ctx, email, password)
+	return r0, r1
+}
+
+// PublicKeys provides a mock function with no fields
+func (_m *IAuthService) PublicKeys() jwtkeys.JWKS {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 jwtkeys.JWKS
+	if rf, ok := ret.Get(0).(func() jwtkeys.JWKS); ok {
+		r0 = rf()
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Get(0).(jwtkeys.JWKS)
 	}
 
-	return r0, r1, r2
+	return r0
 }
 
 // Register provides a mock function with given fields: ctx, email, password
@@ -111,6 +144,42 @@ func (_m *IAuthService) Register(ctx context.Context, email string, password str
 	return r0, r1
 }
 
+// RevokeAllTokens provides a mock function with given fields: ctx, userID
+func (_m *IAuthService) RevokeAllTokens(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: ctx, tokenString
+func (_m *IAuthService) RevokeToken(ctx context.Context, tokenString string) error {
+	ret := _m.Called(ctx, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, tokenString)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateToken provides a mock function with given fields: ctx, tokenString
 func (_m *IAuthService) ValidateToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
 	ret := _m.Called(ctx, tokenString)
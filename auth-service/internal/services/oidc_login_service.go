@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidOIDCIdentity is returned when the identity lacks the issuer or subject claim
+	ErrInvalidOIDCIdentity = errors.New("issuer and subject are required")
+	// ErrOIDCEmailNotVerified is returned when a new identity cannot be linked because the
+	// identity provider did not verify its email
+	ErrOIDCEmailNotVerified = errors.New("identity provider did not verify the email address")
+	// ErrOIDCAccountNotVerified is returned when an account with the email exists but its owner
+	// never verified it, so it may have been registered by someone else
+	ErrOIDCAccountNotVerified = errors.New("an account with this email exists but its email is not verified")
+)
+
+// OIDCIdentity is the account at an OpenID Connect identity provider as described by the
+// claims of a validated ID token
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// OIDCLoginService signs users in with accounts at external OpenID Connect identity providers.
+// It trusts the caller to have validated the ID token the identity comes from.
+type OIDCLoginService struct {
+	identityRepo  repositories.IExternalIdentityRepository
+	userRepo      repositories.IUserRepository
+	refreshTokens IRefreshTokenService
+	messageBroker messaging.IMessageBroker
+	// TwoFactor asks for a second factor when the user enabled it, the identity provider alone signs in when it is nil
+	TwoFactor ITwoFactorService
+	// Emails normalizes the emails of identities
+	Emails *emails.Normalizer
+	now    func() time.Time
+}
+
+// NewOIDCLoginService creates a new OIDCLoginService instance
+func NewOIDCLoginService(
+	identityRepo repositories.IExternalIdentityRepository,
+	userRepo repositories.IUserRepository,
+	refreshTokens IRefreshTokenService,
+	messageBroker messaging.IMessageBroker,
+) *OIDCLoginService {
+	return &OIDCLoginService{
+		identityRepo:  identityRepo,
+		userRepo:      userRepo,
+		refreshTokens: refreshTokens,
+		messageBroker: messageBroker,
+		Emails:        emails.DefaultNormalizer(),
+		now:           time.Now,
+	}
+}
+
+// LoginWithOIDC signs in the user linked to the identity and issues the tokens of the new
+// session. An identity seen for the first time is linked to the account with the same email,
+// or to a new account without a password, provided the identity provider verified the email.
+// Like the password login, accounts with two-factor authentication get a
+// *SecondFactorRequiredError instead of tokens.
+func (s *OIDCLoginService) LoginWithOIDC(ctx context.Context, identity OIDCIdentity) (*TokenPair, *models.User, error) {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return nil, nil, ErrInvalidOIDCIdentity
+	}
+
+	now := s.now().UTC()
+	user, err := s.linkedUser(identity, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		user, err = s.linkIdentity(identity, now)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if s.TwoFactor != nil {
+		enabled, err := s.TwoFactor.IsEnabled(ctx, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if enabled {
+			challenge, expiresAt, err := s.TwoFactor.CreateChallenge(ctx, user)
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, user, &SecondFactorRequiredError{Challenge: challenge, ExpiresAt: expiresAt}
+		}
+	}
+
+	pair, err := s.refreshTokens.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// linkedUser returns the user the identity is linked to, or nil if it is not linked yet
+func (s *OIDCLoginService) linkedUser(identity OIDCIdentity, now time.Time) (*models.User, error) {
+	linked, err := s.identityRepo.GetExternalIdentity(identity.Issuer, identity.Subject)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external identity: %w", err)
+	}
+
+	user, err := s.userRepo.GetUserByID(linked.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The account was deleted, the identity is linked again like a new one
+		if err := s.identityRepo.DeleteExternalIdentity(linked.ID); err != nil {
+			return nil, fmt.Errorf("failed to unlink external identity: %w", err)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := s.identityRepo.RecordExternalLogin(linked.ID, identity.Email, now); err != nil {
+		log.Printf("Failed to record login with external identity %s: %v", linked.ID, err)
+	}
+	return user, nil
+}
+
+// linkIdentity links a new identity to the account with its email, creating the account if
+// there is none. The email is the only connection between both, so it must be verified at the
+// identity provider and, for existing accounts, here as well.
+func (s *OIDCLoginService) linkIdentity(identity OIDCIdentity, now time.Time) (*models.User, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepo.GetUserByEmail(s.Emails.Canonical(identity.Email))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user, err = s.createUser(identity.Email, now)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	case !user.IsEmailVerified():
+		return nil, ErrOIDCAccountNotVerified
+	}
+
+	link := &models.ExternalIdentity{
+		UserID:      user.ID,
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		CreatedAt:   now,
+		LastLoginAt: &now,
+	}
+	if err := s.identityRepo.CreateExternalIdentity(link); err != nil {
+		return nil, fmt.Errorf("failed to link external identity: %w", err)
+	}
+	return user, nil
+}
+
+// createUser creates the account of a new identity. It has no password until the user resets
+// it, and its email counts as verified since the identity provider verified it.
+func (s *OIDCLoginService) createUser(email string, now time.Time) (*models.User, error) {
+	address, err := s.Emails.Normalize(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Emails.CheckDomain(address); err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:           address,
+		EmailNormalized: s.Emails.Canonical(address),
+		Role:            models.RoleUser,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.userRepo.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishUserCreated(user); err != nil {
+			// Log error but don't fail the login
+			log.Printf("Failed to publish user created event: %v", err)
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,322 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type OIDCLoginServiceTestSuite struct {
+	suite.Suite
+	mockIdentityRepo  *repositoryMocks.IExternalIdentityRepository
+	mockUserRepo      *repositoryMocks.IUserRepository
+	mockRefreshTokens *serviceMocks.IRefreshTokenService
+	mockBroker        *messagingMocks.IMessageBroker
+	mockTwoFactor     *serviceMocks.ITwoFactorService
+	service           *services.OIDCLoginService
+	ctx               context.Context
+	user              *models.User
+	identity          services.OIDCIdentity
+}
+
+func (suite *OIDCLoginServiceTestSuite) SetupTest() {
+	suite.mockIdentityRepo = repositoryMocks.NewIExternalIdentityRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockRefreshTokens = serviceMocks.NewIRefreshTokenService(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.mockTwoFactor = serviceMocks.NewITwoFactorService(suite.T())
+	suite.service = services.NewOIDCLoginService(suite.mockIdentityRepo, suite.mockUserRepo, suite.mockRefreshTokens, suite.mockBroker)
+	suite.service.TwoFactor = suite.mockTwoFactor
+	suite.ctx = context.Background()
+	verifiedAt := time.Now().Add(-24 * time.Hour)
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleUser, EmailVerifiedAt: &verifiedAt}
+	suite.identity = services.OIDCIdentity{
+		Issuer:        "https://idp.example.com",
+		Subject:       "subject-1",
+		Email:         suite.user.Email,
+		EmailVerified: true,
+	}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// mockLinkedIdentity mocks an identity linked to suite.user
+func (suite *OIDCLoginServiceTestSuite) mockLinkedIdentity() *models.ExternalIdentity {
+	linked := &models.ExternalIdentity{
+		ID:      uuid.New(),
+		UserID:  suite.user.ID,
+		Issuer:  suite.identity.Issuer,
+		Subject: suite.identity.Subject,
+	}
+	suite.mockIdentityRepo.On("GetExternalIdentity", suite.identity.Issuer, suite.identity.Subject).Return(linked, nil)
+	return linked
+}
+
+// mockUnlinkedIdentity mocks an identity seen for the first time
+func (suite *OIDCLoginServiceTestSuite) mockUnlinkedIdentity() {
+	suite.mockIdentityRepo.On("GetExternalIdentity", suite.identity.Issuer, suite.identity.Subject).Return(nil, gorm.ErrRecordNotFound)
+}
+
+// mockIssueTokens mocks the tokens of the new session
+func (suite *OIDCLoginServiceTestSuite) mockIssueTokens(user interface{}) time.Time {
+	refreshExpiresAt := time.Now().Add(services.RefreshTokenTTL)
+	suite.mockTwoFactor.On("IsEnabled", suite.ctx, mock.AnythingOfType("uuid.UUID")).Return(false, nil)
+	suite.mockRefreshTokens.On("IssueTokens", suite.ctx, user).
+		Return(&services.TokenPair{AccessToken: "jwt-token", RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}, nil)
+	return refreshExpiresAt
+}
+
+// ===== LINKED IDENTITY TESTS =====
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_LinkedIdentity() {
+	// Arrange
+	linked := suite.mockLinkedIdentity()
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockIdentityRepo.On("RecordExternalLogin", linked.ID, suite.identity.Email, mock.AnythingOfType("time.Time")).Return(nil)
+	refreshExpiresAt := suite.mockIssueTokens(suite.user)
+
+	// Act
+	pair, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user, user)
+	suite.Equal("jwt-token", pair.AccessToken)
+	suite.Equal("rt_refresh", pair.RefreshToken)
+	suite.Equal(refreshExpiresAt, pair.RefreshExpiresAt)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_LinkedIdentityIgnoresEmail() {
+	// Arrange
+	suite.identity.Email = "renamed@example.com"
+	suite.identity.EmailVerified = false
+	linked := suite.mockLinkedIdentity()
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockIdentityRepo.On("RecordExternalLogin", linked.ID, "renamed@example.com", mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockIssueTokens(suite.user)
+
+	// Act
+	_, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user.Email, user.Email)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_SecondFactorRequired() {
+	// Arrange
+	linked := suite.mockLinkedIdentity()
+	expiresAt := time.Now().Add(services.TwoFactorChallengeTTL)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockIdentityRepo.On("RecordExternalLogin", linked.ID, suite.identity.Email, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockTwoFactor.On("IsEnabled", suite.ctx, suite.user.ID).Return(true, nil)
+	suite.mockTwoFactor.On("CreateChallenge", suite.ctx, suite.user).Return("tfc_challenge", expiresAt, nil)
+
+	// Act
+	pair, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	var required *services.SecondFactorRequiredError
+	suite.Require().ErrorAs(err, &required)
+	suite.Equal("tfc_challenge", required.Challenge)
+	suite.Equal(expiresAt, required.ExpiresAt)
+	suite.Equal(suite.user, user)
+	suite.Nil(pair)
+	suite.mockRefreshTokens.AssertNotCalled(suite.T(), "IssueTokens", mock.Anything, mock.Anything)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_DeletedAccountIsRelinked() {
+	// Arrange
+	linked := suite.mockLinkedIdentity()
+	var created *models.User
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockIdentityRepo.On("DeleteExternalIdentity", linked.ID).Return(nil)
+	suite.mockUserRepo.On("GetUserByEmail", suite.identity.Email).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockUserRepo.On("CreateUser", mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*models.User)
+		created.ID = uuid.New()
+	}).Return(nil)
+	suite.mockBroker.On("PublishUserCreated", mock.AnythingOfType("*models.User")).Return(nil)
+	suite.mockIdentityRepo.On("CreateExternalIdentity", mock.AnythingOfType("*models.ExternalIdentity")).Return(nil)
+	suite.mockIssueTokens(mock.AnythingOfType("*models.User"))
+
+	// Act
+	_, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(created, user)
+	suite.NotEqual(suite.user.ID, user.ID)
+}
+
+// ===== NEW IDENTITY TESTS =====
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_CreatesAccount() {
+	// Arrange
+	var created *models.User
+	var link *models.ExternalIdentity
+	suite.mockUnlinkedIdentity()
+	suite.mockUserRepo.On("GetUserByEmail", suite.identity.Email).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockUserRepo.On("CreateUser", mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*models.User)
+		created.ID = uuid.New()
+	}).Return(nil)
+	suite.mockBroker.On("PublishUserCreated", mock.AnythingOfType("*models.User")).Return(nil)
+	suite.mockIdentityRepo.On("CreateExternalIdentity", mock.AnythingOfType("*models.ExternalIdentity")).Run(func(args mock.Arguments) {
+		link = args.Get(0).(*models.ExternalIdentity)
+	}).Return(nil)
+	suite.mockIssueTokens(mock.AnythingOfType("*models.User"))
+
+	// Act
+	pair, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("jwt-token", pair.AccessToken)
+	suite.Equal(created, user)
+	suite.Equal(suite.identity.Email, created.Email)
+	suite.Equal(models.RoleUser, created.Role)
+	suite.Empty(created.Password)
+	suite.True(created.IsEmailVerified())
+	suite.Equal(created.ID, link.UserID)
+	suite.Equal(suite.identity.Issuer, link.Issuer)
+	suite.Equal(suite.identity.Subject, link.Subject)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_DisposableEmail() {
+	// Arrange
+	disposable, err := emails.LoadDomainList(strings.NewReader("mailinator.com\n"))
+	suite.Require().NoError(err)
+	suite.service.Emails.Disposable = disposable
+	suite.identity.Email = "test@mailinator.com"
+	suite.mockUnlinkedIdentity()
+	suite.mockUserRepo.On("GetUserByEmail", suite.identity.Email).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	pair, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().ErrorIs(err, emails.ErrDisposableEmail)
+	suite.Nil(pair)
+	suite.Nil(user)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "CreateUser", mock.Anything)
+	suite.mockIdentityRepo.AssertNotCalled(suite.T(), "CreateExternalIdentity", mock.Anything)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_LinksExistingAccount() {
+	// Arrange
+	var link *models.ExternalIdentity
+	suite.mockUnlinkedIdentity()
+	suite.mockUserRepo.On("GetUserByEmail", suite.identity.Email).Return(suite.user, nil)
+	suite.mockIdentityRepo.On("CreateExternalIdentity", mock.AnythingOfType("*models.ExternalIdentity")).Run(func(args mock.Arguments) {
+		link = args.Get(0).(*models.ExternalIdentity)
+	}).Return(nil)
+	suite.mockIssueTokens(suite.user)
+
+	// Act
+	_, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user, user)
+	suite.Equal(suite.user.ID, link.UserID)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "CreateUser", mock.Anything)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_UnverifiedAccountIsNotLinked() {
+	// Arrange
+	suite.user.EmailVerifiedAt = nil
+	suite.mockUnlinkedIdentity()
+	suite.mockUserRepo.On("GetUserByEmail", suite.identity.Email).Return(suite.user, nil)
+
+	// Act
+	pair, user, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrOIDCAccountNotVerified)
+	suite.Nil(pair)
+	suite.Nil(user)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_UnverifiedEmail() {
+	// Arrange
+	suite.identity.EmailVerified = false
+	suite.mockUnlinkedIdentity()
+
+	// Act
+	_, _, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrOIDCEmailNotVerified)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "GetUserByEmail", mock.Anything)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_MissingEmail() {
+	// Arrange
+	suite.identity.Email = ""
+	suite.mockUnlinkedIdentity()
+
+	// Act
+	_, _, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrOIDCEmailNotVerified)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_LinkError() {
+	// Arrange
+	suite.mockUnlinkedIdentity()
+	suite.mockUserRepo.On("GetUserByEmail", suite.identity.Email).Return(suite.user, nil)
+	suite.mockIdentityRepo.On("CreateExternalIdentity", mock.AnythingOfType("*models.ExternalIdentity")).Return(errors.New("duplicate key"))
+
+	// Act
+	pair, _, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to link external identity")
+	suite.Nil(pair)
+}
+
+// ===== VALIDATION TESTS =====
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_MissingSubject() {
+	// Arrange
+	suite.identity.Subject = ""
+
+	// Act
+	_, _, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidOIDCIdentity)
+}
+
+func (suite *OIDCLoginServiceTestSuite) TestLoginWithOIDC_LookupError() {
+	// Arrange
+	suite.mockIdentityRepo.On("GetExternalIdentity", suite.identity.Issuer, suite.identity.Subject).Return(nil, errors.New("connection refused"))
+
+	// Act
+	_, _, err := suite.service.LoginWithOIDC(suite.ctx, suite.identity)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to get external identity")
+}
+
+// Run tests
+func TestOIDCLoginServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCLoginServiceTestSuite))
+}
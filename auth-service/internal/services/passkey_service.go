@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PasskeyCeremonyTTL is how long a passkey registration or login may take between its begin and finish steps
+const PasskeyCeremonyTTL = 5 * time.Minute
+
+var (
+	// ErrInvalidPasskeyCeremony is returned for unknown, expired and finished ceremonies
+	ErrInvalidPasskeyCeremony = errors.New("invalid or expired passkey ceremony")
+	// ErrInvalidPasskey is returned when the authenticator response does not verify
+	ErrInvalidPasskey = errors.New("passkey verification failed")
+)
+
+// PasskeyService registers passkeys (WebAuthn credentials) and logs users in with them.
+// Passkeys always verify the user (PIN or biometrics) on the authenticator, so a passkey
+// login needs neither the password nor a second factor.
+type PasskeyService struct {
+	repo          repositories.IPasskeyRepository
+	userRepo      repositories.IUserRepository
+	refreshTokens IRefreshTokenService
+	webAuthn      *webauthn.WebAuthn
+	// EmailVerificationPolicy refuses passkey logins of unverified accounts under EmailVerificationLogin,
+	// as for passwords
+	EmailVerificationPolicy string
+	now                     func() time.Time
+}
+
+// NewPasskeyService creates a new PasskeyService instance
+func NewPasskeyService(
+	repo repositories.IPasskeyRepository,
+	userRepo repositories.IUserRepository,
+	refreshTokens IRefreshTokenService,
+	webAuthn *webauthn.WebAuthn,
+) *PasskeyService {
+	return &PasskeyService{
+		repo:          repo,
+		userRepo:      userRepo,
+		refreshTokens: refreshTokens,
+		webAuthn:      webAuthn,
+		now:           time.Now,
+	}
+}
+
+// BeginRegistration starts the registration of a passkey for the user. It returns the ceremony
+// token for FinishRegistration and the options for navigator.credentials.create() as JSON.
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID uuid.UUID) (string, []byte, error) {
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	creation, session, err := s.webAuthn.BeginRegistration(user,
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		}),
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()),
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+
+	return s.startCeremony(models.PasskeyCeremonyRegistration, &userID, session, creation)
+}
+
+// FinishRegistration verifies the response of the authenticator to the registration options
+// and stores the new passkey of the user
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID uuid.UUID, ceremony string, response []byte) error {
+	session, err := s.finishCeremony(models.PasskeyCeremonyRegistration, &userID, ceremony)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPasskey, describeWebAuthnError(err))
+	}
+
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return err
+	}
+
+	credential, err := s.webAuthn.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPasskey, describeWebAuthnError(err))
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	err = s.repo.CreateCredential(&models.PasskeyCredential{
+		UserID:          userID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		Transports:      strings.Join(transports, ","),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		CreatedAt:       s.now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save passkey: %w", err)
+	}
+
+	log.Printf("Passkey registered for user %s", userID)
+	return nil
+}
+
+// BeginLogin starts a passkey login. The user is not known yet: the authenticator offers
+// the passkeys it holds for this service and the chosen one names the user. It returns the
+// ceremony token for FinishLogin and the options for navigator.credentials.get() as JSON.
+func (s *PasskeyService) BeginLogin(ctx context.Context) (string, []byte, error) {
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+
+	return s.startCeremony(models.PasskeyCeremonyLogin, nil, session, assertion)
+}
+
+// FinishLogin verifies the assertion of the authenticator and issues the tokens of the new session.
+// Authenticators whose signature counter did not increase are refused as clones.
+func (s *PasskeyService) FinishLogin(ctx context.Context, ceremony string, response []byte) (*TokenPair, *models.User, error) {
+	session, err := s.finishCeremony(models.PasskeyCeremonyLogin, nil, ceremony)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidPasskey, describeWebAuthnError(err))
+	}
+
+	var (
+		owner  *passkeyUser
+		stored *models.PasskeyCredential
+	)
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		credential, err := s.repo.GetCredentialByCredentialID(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("unknown passkey: %w", err)
+		}
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil || userID != credential.UserID {
+			return nil, errors.New("passkey belongs to another user")
+		}
+		user, err := s.loadUser(userID)
+		if err != nil {
+			return nil, err
+		}
+		stored, owner = credential, user
+		return user, nil
+	}
+
+	_, credential, err := s.webAuthn.ValidatePasskeyLogin(handler, *session, parsed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidPasskey, describeWebAuthnError(err))
+	}
+
+	if credential.Authenticator.CloneWarning {
+		log.Printf("Refused passkey login of user %s: signature counter of passkey %s did not increase, the authenticator may be cloned",
+			owner.user.ID, stored.ID)
+		return nil, nil, ErrInvalidPasskey
+	}
+
+	if s.EmailVerificationPolicy == EmailVerificationLogin && !owner.user.IsEmailVerified() {
+		return nil, nil, ErrEmailNotVerified
+	}
+
+	err = s.repo.UpdateCredentialUsage(stored.ID, int64(credential.Authenticator.SignCount),
+		credential.Flags.BackupState, s.now().UTC())
+	if err != nil {
+		log.Printf("Failed to record usage of passkey %s: %v", stored.ID, err)
+	}
+
+	pair, err := s.refreshTokens.IssueTokens(ctx, owner.user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, owner.user, nil
+}
+
+// startCeremony stores the WebAuthn session under a new ceremony token and encodes the options
+func (s *PasskeyService) startCeremony(kind string, userID *uuid.UUID, session *webauthn.SessionData, options any) (string, []byte, error) {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode passkey session: %w", err)
+	}
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode passkey options: %w", err)
+	}
+
+	plaintext, err := utils.GenerateOpaqueToken(models.PasskeyCeremonyPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := s.now().UTC()
+	err = s.repo.CreateCeremony(&models.PasskeyCeremony{
+		TokenHash:   utils.HashToken(plaintext),
+		Kind:        kind,
+		UserID:      userID,
+		SessionData: string(sessionData),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(PasskeyCeremonyTTL),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create passkey ceremony: %w", err)
+	}
+
+	// Ceremonies are only needed until they expire
+	if err := s.repo.DeleteExpiredCeremonies(now); err != nil {
+		log.Printf("Failed to purge expired passkey ceremonies: %v", err)
+	}
+	return plaintext, encodedOptions, nil
+}
+
+// finishCeremony consumes an active ceremony of the given kind and returns its WebAuthn session.
+// A ceremony is consumed even when the response turns out to be invalid, so each challenge is signed once.
+func (s *PasskeyService) finishCeremony(kind string, userID *uuid.UUID, token string) (*webauthn.SessionData, error) {
+	if !strings.HasPrefix(token, models.PasskeyCeremonyPrefix) {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+
+	tokenHash := utils.HashToken(token)
+	ceremony, err := s.repo.GetCeremony(tokenHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get passkey ceremony: %w", err)
+	}
+
+	if ceremony.Kind != kind || !ceremony.IsActive(s.now().UTC()) || !sameUser(ceremony.UserID, userID) {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+
+	deleted, err := s.repo.DeleteCeremony(tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use passkey ceremony: %w", err)
+	}
+	if !deleted {
+		// A concurrent request finished the ceremony first
+		return nil, ErrInvalidPasskeyCeremony
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(ceremony.SessionData), &session); err != nil {
+		return nil, fmt.Errorf("failed to decode passkey session: %w", err)
+	}
+	return &session, nil
+}
+
+// loadUser returns the user with the passkeys registered so far
+func (s *PasskeyService) loadUser(userID uuid.UUID) (*passkeyUser, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	stored, err := s.repo.GetCredentialsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get passkeys: %w", err)
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(stored))
+	for _, c := range stored {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(c.TransportList()))
+		for _, transport := range c.TransportList() {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: c.BackupEligible,
+				BackupState:    c.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    c.AAGUID,
+				SignCount: uint32(c.SignCount),
+			},
+		})
+	}
+	return &passkeyUser{user: user, credentials: credentials}, nil
+}
+
+// passkeyUser adapts a user and the stored passkeys to webauthn.User.
+// The user handle is the user ID, which carries no personal data.
+type passkeyUser struct {
+	user        *models.User
+	credentials []webauthn.Credential
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	id := u.user.ID
+	return id[:]
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.user.Email
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// sameUser compares the optional user IDs of a ceremony and a request
+func sameUser(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// describeWebAuthnError includes the details of protocol errors, whose message alone is generic
+func describeWebAuthnError(err error) string {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.Details != "" {
+		return protocolErr.Details
+	}
+	return err.Error()
+}
@@ -0,0 +1,352 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/softauthn"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const passkeyOrigin = "http://localhost:8080"
+
+type PasskeyServiceTestSuite struct {
+	suite.Suite
+	mockRepo          *repositoryMocks.IPasskeyRepository
+	mockUserRepo      *repositoryMocks.IUserRepository
+	mockRefreshTokens *serviceMocks.IRefreshTokenService
+	service           *services.PasskeyService
+	authenticator     *softauthn.Authenticator
+	ctx               context.Context
+	user              *models.User
+}
+
+func (suite *PasskeyServiceTestSuite) SetupTest() {
+	suite.mockRepo = repositoryMocks.NewIPasskeyRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockRefreshTokens = serviceMocks.NewIRefreshTokenService(suite.T())
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          "localhost",
+		RPDisplayName: "subs-service",
+		RPOrigins:     []string{passkeyOrigin},
+	})
+	suite.Require().NoError(err)
+
+	suite.service = services.NewPasskeyService(suite.mockRepo, suite.mockUserRepo, suite.mockRefreshTokens, webAuthn)
+	suite.authenticator = softauthn.New(passkeyOrigin)
+	suite.ctx = context.Background()
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleUser}
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil).Maybe()
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// begin runs a begin step and mocks the storage of its ceremony.
+// It returns the ceremony token, the options for the authenticator and the stored ceremony.
+func (suite *PasskeyServiceTestSuite) begin(begin func() (string, []byte, error)) (string, []byte, *models.PasskeyCeremony) {
+	var stored *models.PasskeyCeremony
+	suite.mockRepo.On("CreateCeremony", mock.AnythingOfType("*models.PasskeyCeremony")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*models.PasskeyCeremony)
+		}).
+		Return(nil).Once()
+	suite.mockRepo.On("DeleteExpiredCeremonies", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	ceremony, options, err := begin()
+	suite.Require().NoError(err)
+	suite.Require().NotNil(stored)
+	suite.Equal(utils.HashToken(ceremony), stored.TokenHash)
+	return ceremony, options, stored
+}
+
+// startCeremony runs a begin step and mocks the storage of its ceremony until it is finished.
+// It returns the ceremony token and the options for the authenticator.
+func (suite *PasskeyServiceTestSuite) startCeremony(begin func() (string, []byte, error)) (string, []byte) {
+	ceremony, options, stored := suite.begin(begin)
+	suite.mockRepo.On("GetCeremony", stored.TokenHash).Return(stored, nil).Once()
+	suite.mockRepo.On("DeleteCeremony", stored.TokenHash).Return(true, nil).Once()
+	return ceremony, options
+}
+
+// registerPasskey registers a passkey of the suite user with the software authenticator
+// and returns it as stored
+func (suite *PasskeyServiceTestSuite) registerPasskey() *models.PasskeyCredential {
+	suite.mockRepo.On("GetCredentialsByUserID", suite.user.ID).Return([]models.PasskeyCredential{}, nil).Twice()
+	ceremony, options := suite.startCeremony(func() (string, []byte, error) {
+		return suite.service.BeginRegistration(suite.ctx, suite.user.ID)
+	})
+
+	response, err := suite.authenticator.Register(options)
+	suite.Require().NoError(err)
+
+	var stored *models.PasskeyCredential
+	suite.mockRepo.On("CreateCredential", mock.AnythingOfType("*models.PasskeyCredential")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*models.PasskeyCredential)
+			stored.ID = uuid.New()
+		}).
+		Return(nil).Once()
+
+	suite.Require().NoError(suite.service.FinishRegistration(suite.ctx, suite.user.ID, ceremony, response))
+	suite.Require().NotNil(stored)
+	return stored
+}
+
+// beginLogin starts a passkey login and returns the ceremony and the assertion of the software authenticator
+func (suite *PasskeyServiceTestSuite) beginLogin() (string, []byte) {
+	ceremony, options := suite.startCeremony(func() (string, []byte, error) {
+		return suite.service.BeginLogin(suite.ctx)
+	})
+
+	response, err := suite.authenticator.Login(options)
+	suite.Require().NoError(err)
+	return ceremony, response
+}
+
+// mockStoredPasskey mocks the lookup of a registered passkey during a login
+func (suite *PasskeyServiceTestSuite) mockStoredPasskey(credential *models.PasskeyCredential) {
+	suite.mockRepo.On("GetCredentialByCredentialID", credential.CredentialID).Return(credential, nil).Once()
+	suite.mockRepo.On("GetCredentialsByUserID", suite.user.ID).Return([]models.PasskeyCredential{*credential}, nil).Once()
+}
+
+// ===== REGISTRATION TESTS =====
+
+func (suite *PasskeyServiceTestSuite) TestRegistration_StoresPasskey() {
+	// Act
+	credential := suite.registerPasskey()
+
+	// Assert
+	suite.Equal(suite.user.ID, credential.UserID)
+	suite.Len(credential.CredentialID, 32)
+	suite.NotEmpty(credential.PublicKey)
+	suite.Equal("none", credential.AttestationType)
+	suite.Equal("internal,hybrid", credential.Transports)
+	suite.True(credential.BackupEligible)
+	suite.True(credential.BackupState)
+	suite.Zero(credential.SignCount)
+}
+
+func (suite *PasskeyServiceTestSuite) TestBeginRegistration_Options() {
+	// Arrange
+	registered := &models.PasskeyCredential{UserID: suite.user.ID, CredentialID: []byte("registered-passkey")}
+	suite.mockRepo.On("GetCredentialsByUserID", suite.user.ID).Return([]models.PasskeyCredential{*registered}, nil).Once()
+	suite.mockRepo.On("CreateCeremony", mock.MatchedBy(func(c *models.PasskeyCeremony) bool {
+		return c.Kind == models.PasskeyCeremonyRegistration && c.UserID != nil && *c.UserID == suite.user.ID &&
+			c.ExpiresAt.Sub(c.CreatedAt) == services.PasskeyCeremonyTTL
+	})).Return(nil).Once()
+	suite.mockRepo.On("DeleteExpiredCeremonies", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	// Act
+	ceremony, options, err := suite.service.BeginRegistration(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Contains(ceremony, models.PasskeyCeremonyPrefix)
+
+	var parsed struct {
+		PublicKey struct {
+			RP struct {
+				ID string `json:"id"`
+			} `json:"rp"`
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+			ExcludeCredentials []struct {
+				ID string `json:"id"`
+			} `json:"excludeCredentials"`
+			AuthenticatorSelection struct {
+				ResidentKey      string `json:"residentKey"`
+				UserVerification string `json:"userVerification"`
+			} `json:"authenticatorSelection"`
+		} `json:"publicKey"`
+	}
+	suite.Require().NoError(json.Unmarshal(options, &parsed))
+	suite.Equal("localhost", parsed.PublicKey.RP.ID)
+	suite.Equal(suite.user.Email, parsed.PublicKey.User.Name)
+	suite.Require().Len(parsed.PublicKey.ExcludeCredentials, 1)
+	suite.Equal("cmVnaXN0ZXJlZC1wYXNza2V5", parsed.PublicKey.ExcludeCredentials[0].ID)
+	suite.Equal("required", parsed.PublicKey.AuthenticatorSelection.ResidentKey)
+	suite.Equal("required", parsed.PublicKey.AuthenticatorSelection.UserVerification)
+}
+
+func (suite *PasskeyServiceTestSuite) TestFinishRegistration_CeremonyOfAnotherUser() {
+	// Arrange
+	otherUser := uuid.New()
+	ceremony := models.PasskeyCeremonyPrefix + "ceremony"
+	suite.mockRepo.On("GetCeremony", utils.HashToken(ceremony)).Return(&models.PasskeyCeremony{
+		Kind:      models.PasskeyCeremonyRegistration,
+		UserID:    &otherUser,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil).Once()
+
+	// Act
+	err := suite.service.FinishRegistration(suite.ctx, suite.user.ID, ceremony, []byte(`{}`))
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskeyCeremony)
+}
+
+func (suite *PasskeyServiceTestSuite) TestFinishRegistration_ExpiredCeremony() {
+	// Arrange
+	ceremony := models.PasskeyCeremonyPrefix + "ceremony"
+	suite.mockRepo.On("GetCeremony", utils.HashToken(ceremony)).Return(&models.PasskeyCeremony{
+		Kind:      models.PasskeyCeremonyRegistration,
+		UserID:    &suite.user.ID,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil).Once()
+
+	// Act
+	err := suite.service.FinishRegistration(suite.ctx, suite.user.ID, ceremony, []byte(`{}`))
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskeyCeremony)
+}
+
+func (suite *PasskeyServiceTestSuite) TestFinishRegistration_LoginCeremony() {
+	// Arrange
+	ceremony, _, stored := suite.begin(func() (string, []byte, error) {
+		return suite.service.BeginLogin(suite.ctx)
+	})
+	suite.mockRepo.On("GetCeremony", stored.TokenHash).Return(stored, nil).Once()
+
+	// Act
+	err := suite.service.FinishRegistration(suite.ctx, suite.user.ID, ceremony, []byte(`{}`))
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskeyCeremony)
+	// The ceremony stays usable for the login it was started for
+	suite.mockRepo.AssertNotCalled(suite.T(), "DeleteCeremony", mock.Anything)
+}
+
+// ===== LOGIN TESTS =====
+
+func (suite *PasskeyServiceTestSuite) TestLogin_IssuesTokens() {
+	// Arrange
+	credential := suite.registerPasskey()
+	ceremony, response := suite.beginLogin()
+	suite.mockStoredPasskey(credential)
+	suite.mockRepo.On("UpdateCredentialUsage", credential.ID, int64(1), true, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	refreshExpiresAt := time.Now().Add(30 * 24 * time.Hour)
+	suite.mockRefreshTokens.On("IssueTokens", suite.ctx, suite.user).
+		Return(&services.TokenPair{AccessToken: "jwt-token", RefreshToken: "rt_token", RefreshExpiresAt: refreshExpiresAt}, nil).Once()
+
+	// Act
+	tokens, user, err := suite.service.FinishLogin(suite.ctx, ceremony, response)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user, user)
+	suite.Equal("jwt-token", tokens.AccessToken)
+	suite.Equal("rt_token", tokens.RefreshToken)
+	suite.Equal(refreshExpiresAt, tokens.RefreshExpiresAt)
+}
+
+func (suite *PasskeyServiceTestSuite) TestLogin_ClonedAuthenticator() {
+	// Arrange
+	credential := suite.registerPasskey()
+	// The stored counter is ahead of the authenticator, as after logins with a copy of its key
+	credential.SignCount = 5
+	ceremony, response := suite.beginLogin()
+	suite.mockStoredPasskey(credential)
+
+	// Act
+	tokens, _, err := suite.service.FinishLogin(suite.ctx, ceremony, response)
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskey)
+	suite.Nil(tokens)
+}
+
+func (suite *PasskeyServiceTestSuite) TestLogin_AssertionOfAnotherCeremony() {
+	// Arrange
+	credential := suite.registerPasskey()
+	_, signedOptions, _ := suite.begin(func() (string, []byte, error) {
+		return suite.service.BeginLogin(suite.ctx)
+	})
+	response, err := suite.authenticator.Login(signedOptions)
+	suite.Require().NoError(err)
+	ceremony, _ := suite.startCeremony(func() (string, []byte, error) {
+		return suite.service.BeginLogin(suite.ctx)
+	})
+	suite.mockStoredPasskey(credential)
+
+	// Act
+	tokens, _, err := suite.service.FinishLogin(suite.ctx, ceremony, response)
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskey)
+	suite.Nil(tokens)
+}
+
+func (suite *PasskeyServiceTestSuite) TestLogin_UnknownPasskey() {
+	// Arrange
+	credential := suite.registerPasskey()
+	ceremony, response := suite.beginLogin()
+	suite.mockRepo.On("GetCredentialByCredentialID", credential.CredentialID).Return(nil, gorm.ErrRecordNotFound).Once()
+
+	// Act
+	tokens, _, err := suite.service.FinishLogin(suite.ctx, ceremony, response)
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskey)
+	suite.Nil(tokens)
+}
+
+func (suite *PasskeyServiceTestSuite) TestLogin_UnverifiedEmail() {
+	// Arrange
+	suite.service.EmailVerificationPolicy = services.EmailVerificationLogin
+	credential := suite.registerPasskey()
+	ceremony, response := suite.beginLogin()
+	suite.mockStoredPasskey(credential)
+
+	// Act
+	tokens, _, err := suite.service.FinishLogin(suite.ctx, ceremony, response)
+
+	// Assert
+	suite.ErrorIs(err, services.ErrEmailNotVerified)
+	suite.Nil(tokens)
+}
+
+func (suite *PasskeyServiceTestSuite) TestFinishLogin_UsedCeremony() {
+	// Arrange
+	ceremony := models.PasskeyCeremonyPrefix + "ceremony"
+	tokenHash := utils.HashToken(ceremony)
+	suite.mockRepo.On("GetCeremony", tokenHash).Return(&models.PasskeyCeremony{
+		Kind:      models.PasskeyCeremonyLogin,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil).Once()
+	suite.mockRepo.On("DeleteCeremony", tokenHash).Return(false, nil).Once()
+
+	// Act
+	tokens, _, err := suite.service.FinishLogin(suite.ctx, ceremony, []byte(`{}`))
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskeyCeremony)
+	suite.Nil(tokens)
+}
+
+func (suite *PasskeyServiceTestSuite) TestFinishLogin_NotACeremony() {
+	// Act
+	tokens, _, err := suite.service.FinishLogin(suite.ctx, "rt_refresh-token", []byte(`{}`))
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidPasskeyCeremony)
+	suite.Nil(tokens)
+}
+
+// Run tests
+func TestPasskeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PasskeyServiceTestSuite))
+}
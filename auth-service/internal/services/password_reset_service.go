@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/emails"
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// PasswordResetTokenTTL is how long an emailed password reset link can be used
+	PasswordResetTokenTTL = time.Hour
+	// MaxPasswordResetRequests limits how many reset emails an account receives per PasswordResetTokenTTL
+	MaxPasswordResetRequests = 3
+)
+
+// ErrInvalidResetToken is returned for unknown, expired and already used password reset tokens
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// PasswordResetService lets users who forgot their password set a new one through an emailed link
+type PasswordResetService struct {
+	tokenRepo     repositories.ISingleUseTokenRepository
+	userRepo      repositories.IUserRepository
+	authService   IAuthService
+	refreshTokens IRefreshTokenService
+	messageBroker messaging.IMessageBroker
+	// Passwords hashes new passwords
+	Passwords *passwords.Hasher
+	// PasswordPolicy rejects weak new passwords, any password is accepted when it is nil
+	PasswordPolicy *passwords.Policy
+	// Emails finds accounts by the canonical form of their email
+	Emails *emails.Normalizer
+	now    func() time.Time
+}
+
+// NewPasswordResetService creates a new PasswordResetService instance
+func NewPasswordResetService(
+	tokenRepo repositories.ISingleUseTokenRepository,
+	userRepo repositories.IUserRepository,
+	authService IAuthService,
+	refreshTokens IRefreshTokenService,
+	messageBroker messaging.IMessageBroker,
+) *PasswordResetService {
+	return &PasswordResetService{
+		tokenRepo:     tokenRepo,
+		userRepo:      userRepo,
+		authService:   authService,
+		refreshTokens: refreshTokens,
+		messageBroker: messageBroker,
+		Passwords:     passwords.DefaultHasher(),
+		Emails:        emails.DefaultNormalizer(),
+		now:           time.Now,
+	}
+}
+
+// RequestPasswordReset emails a password reset link to the account registered with email.
+// Unknown emails and throttled requests succeed silently, so that the result does not
+// reveal whether an email is registered.
+func (s *PasswordResetService) RequestPasswordReset(ctx context.Context, email string) error {
+	// Without a broker no email can be sent for any address
+	if s.messageBroker == nil {
+		return errors.New("message broker is not initialized")
+	}
+
+	user, err := s.userRepo.GetUserByEmail(s.Emails.Canonical(email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	now := s.now().UTC()
+	issued, err := s.tokenRepo.CountSingleUseTokensSince(models.TokenPurposePasswordReset, user.ID, now.Add(-PasswordResetTokenTTL))
+	if err != nil {
+		return fmt.Errorf("failed to count password reset tokens: %w", err)
+	}
+	if issued >= MaxPasswordResetRequests {
+		log.Printf("Too many password reset requests for user %s, not sending another email", user.ID)
+		return nil
+	}
+
+	plaintext, err := utils.GenerateOpaqueToken(models.PasswordResetTokenPrefix)
+	if err != nil {
+		return err
+	}
+
+	token := &models.SingleUseToken{
+		Purpose:   models.TokenPurposePasswordReset,
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(plaintext),
+		CreatedAt: now,
+		ExpiresAt: now.Add(PasswordResetTokenTTL),
+	}
+	if err := s.tokenRepo.CreateSingleUseToken(token); err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	if err := s.messageBroker.PublishPasswordResetRequested(user, plaintext, token.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to publish password reset requested event: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a token from a password reset email.
+// The token works once; afterwards all other reset tokens and all sessions of the user are revoked.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, plaintext, newPassword string) error {
+	if newPassword == "" {
+		return ErrEmptyPassword
+	}
+	if !strings.HasPrefix(plaintext, models.PasswordResetTokenPrefix) {
+		return ErrInvalidResetToken
+	}
+
+	token, err := s.tokenRepo.GetSingleUseTokenByHash(models.TokenPurposePasswordReset, utils.HashToken(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	now := s.now().UTC()
+	if !token.IsActive(now) {
+		return ErrInvalidResetToken
+	}
+
+	// Checked before the token is used, so the user can retry with another password
+	if s.PasswordPolicy != nil {
+		if err := s.PasswordPolicy.Check(newPassword); err != nil {
+			return err
+		}
+	}
+
+	hashedPassword, err := s.Passwords.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	marked, err := s.tokenRepo.MarkSingleUseTokenUsed(token.ID, now)
+	if err != nil {
+		return fmt.Errorf("failed to use password reset token: %w", err)
+	}
+	if !marked {
+		// A concurrent request used the same token first
+		return ErrInvalidResetToken
+	}
+
+	if err := s.userRepo.UpdatePassword(token.UserID, hashedPassword); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	s.endSessions(ctx, token.UserID, now)
+	return nil
+}
+
+// endSessions revokes the remaining reset tokens and every session of the user after a password reset.
+// The password is already changed at this point, so failures are logged instead of returned.
+func (s *PasswordResetService) endSessions(ctx context.Context, userID uuid.UUID, now time.Time) {
+	if err := s.tokenRepo.InvalidateUserSingleUseTokens(models.TokenPurposePasswordReset, userID, now); err != nil {
+		log.Printf("Failed to invalidate password reset tokens of user %s: %v", userID, err)
+	}
+	if err := s.refreshTokens.RevokeAllRefreshTokens(ctx, userID); err != nil {
+		log.Printf("Failed to revoke refresh tokens of user %s after password reset: %v", userID, err)
+	}
+	if err := s.authService.RevokeAllTokens(ctx, userID); err != nil {
+		log.Printf("Failed to revoke tokens of user %s after password reset: %v", userID, err)
+	}
+}
@@ -0,0 +1,259 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type PasswordResetServiceTestSuite struct {
+	suite.Suite
+	mockTokenRepo     *repositoryMocks.ISingleUseTokenRepository
+	mockUserRepo      *repositoryMocks.IUserRepository
+	mockAuthService   *serviceMocks.IAuthService
+	mockRefreshTokens *serviceMocks.IRefreshTokenService
+	mockBroker        *messagingMocks.IMessageBroker
+	service           *services.PasswordResetService
+	ctx               context.Context
+	user              *models.User
+	plaintext         string
+}
+
+func (suite *PasswordResetServiceTestSuite) SetupTest() {
+	suite.mockTokenRepo = repositoryMocks.NewISingleUseTokenRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockAuthService = serviceMocks.NewIAuthService(suite.T())
+	suite.mockRefreshTokens = serviceMocks.NewIRefreshTokenService(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.service = services.NewPasswordResetService(suite.mockTokenRepo, suite.mockUserRepo, suite.mockAuthService, suite.mockRefreshTokens, suite.mockBroker)
+	suite.ctx = context.Background()
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleUser}
+	suite.plaintext = models.PasswordResetTokenPrefix + "current"
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// storedToken returns the stored state of suite.plaintext
+func (suite *PasswordResetServiceTestSuite) storedToken() *models.SingleUseToken {
+	return &models.SingleUseToken{
+		ID:        uuid.New(),
+		UserID:    suite.user.ID,
+		TokenHash: utils.HashToken(suite.plaintext),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// mockGetPasswordResetTokenByHash mocks tokenRepo.GetSingleUseTokenByHash for the password reset token suite.plaintext
+func (suite *PasswordResetServiceTestSuite) mockGetPasswordResetTokenByHash(token *models.SingleUseToken, err error) {
+	suite.mockTokenRepo.On("GetSingleUseTokenByHash", models.TokenPurposePasswordReset, utils.HashToken(suite.plaintext)).Return(token, err)
+}
+
+// ===== REQUEST PASSWORD RESET TESTS =====
+
+func (suite *PasswordResetServiceTestSuite) TestRequestPasswordReset_SendsToken() {
+	// Arrange
+	var stored *models.SingleUseToken
+	var sent string
+	suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposePasswordReset, suite.user.ID, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	suite.mockTokenRepo.On("CreateSingleUseToken", mock.AnythingOfType("*models.SingleUseToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.SingleUseToken)
+	}).Return(nil)
+	suite.mockBroker.On("PublishPasswordResetRequested", suite.user, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		sent = args.String(1)
+	}).Return(nil)
+
+	// Act
+	err := suite.service.RequestPasswordReset(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(sent, models.PasswordResetTokenPrefix))
+	suite.Equal(utils.HashToken(sent), stored.TokenHash)
+	suite.Equal(suite.user.ID, stored.UserID)
+	suite.Equal(models.TokenPurposePasswordReset, stored.Purpose)
+	suite.WithinDuration(time.Now().Add(services.PasswordResetTokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func (suite *PasswordResetServiceTestSuite) TestRequestPasswordReset_UnknownEmail() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByEmail", "unknown@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	err := suite.service.RequestPasswordReset(suite.ctx, "unknown@example.com")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *PasswordResetServiceTestSuite) TestRequestPasswordReset_Throttled() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposePasswordReset, suite.user.ID, mock.AnythingOfType("time.Time")).
+		Return(int64(services.MaxPasswordResetRequests), nil)
+
+	// Act
+	err := suite.service.RequestPasswordReset(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *PasswordResetServiceTestSuite) TestRequestPasswordReset_PublishError() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockTokenRepo.On("CountSingleUseTokensSince", models.TokenPurposePasswordReset, suite.user.ID, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	suite.mockTokenRepo.On("CreateSingleUseToken", mock.AnythingOfType("*models.SingleUseToken")).Return(nil)
+	suite.mockBroker.On("PublishPasswordResetRequested", suite.user, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Return(errors.New("broker down"))
+
+	// Act
+	err := suite.service.RequestPasswordReset(suite.ctx, suite.user.Email)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "broker down")
+}
+
+func (suite *PasswordResetServiceTestSuite) TestRequestPasswordReset_NoBroker() {
+	// Arrange
+	service := services.NewPasswordResetService(suite.mockTokenRepo, suite.mockUserRepo, suite.mockAuthService, suite.mockRefreshTokens, nil)
+
+	// Act
+	err := service.RequestPasswordReset(suite.ctx, "unknown@example.com")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "message broker is not initialized")
+}
+
+// ===== RESET PASSWORD TESTS =====
+
+func (suite *PasswordResetServiceTestSuite) TestResetPassword_Success() {
+	// Arrange
+	token := suite.storedToken()
+	var passwordHash string
+	suite.mockGetPasswordResetTokenByHash(token, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockUserRepo.On("UpdatePassword", suite.user.ID, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		passwordHash = args.String(1)
+	}).Return(nil)
+	suite.mockTokenRepo.On("InvalidateUserSingleUseTokens", models.TokenPurposePasswordReset, suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockRefreshTokens.On("RevokeAllRefreshTokens", suite.ctx, suite.user.ID).Return(nil)
+	suite.mockAuthService.On("RevokeAllTokens", suite.ctx, suite.user.ID).Return(nil)
+
+	// Act
+	err := suite.service.ResetPassword(suite.ctx, suite.plaintext, "NewPassword123!")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(passwordHash, "$argon2id$"))
+	suite.NoError(passwords.Verify(passwordHash, "NewPassword123!"))
+}
+
+func (suite *PasswordResetServiceTestSuite) TestResetPassword_SessionRevocationErrorsAreIgnored() {
+	// Arrange
+	token := suite.storedToken()
+	suite.mockGetPasswordResetTokenByHash(token, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockUserRepo.On("UpdatePassword", suite.user.ID, mock.AnythingOfType("string")).Return(nil)
+	suite.mockTokenRepo.On("InvalidateUserSingleUseTokens", models.TokenPurposePasswordReset, suite.user.ID, mock.AnythingOfType("time.Time")).Return(errors.New("db error"))
+	suite.mockRefreshTokens.On("RevokeAllRefreshTokens", suite.ctx, suite.user.ID).Return(errors.New("db error"))
+	suite.mockAuthService.On("RevokeAllTokens", suite.ctx, suite.user.ID).Return(errors.New("db error"))
+
+	// Act
+	err := suite.service.ResetPassword(suite.ctx, suite.plaintext, "NewPassword123!")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *PasswordResetServiceTestSuite) TestResetPassword_InvalidTokens() {
+	testCases := []struct {
+		name  string
+		token *models.SingleUseToken
+		err   error
+	}{
+		{name: "unknown", err: gorm.ErrRecordNotFound},
+		{name: "expired", token: &models.SingleUseToken{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}},
+		{name: "used", token: &models.SingleUseToken{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), UsedAt: new(time.Time)}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			suite.SetupTest()
+			suite.mockGetPasswordResetTokenByHash(tc.token, tc.err)
+
+			// Act
+			err := suite.service.ResetPassword(suite.ctx, suite.plaintext, "NewPassword123!")
+
+			// Assert
+			suite.Require().ErrorIs(err, services.ErrInvalidResetToken)
+		})
+	}
+}
+
+func (suite *PasswordResetServiceTestSuite) TestResetPassword_WrongPrefix() {
+	// Act
+	err := suite.service.ResetPassword(suite.ctx, models.RefreshTokenPrefix+"current", "NewPassword123!")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidResetToken)
+}
+
+func (suite *PasswordResetServiceTestSuite) TestResetPassword_EmptyPassword() {
+	// Act
+	err := suite.service.ResetPassword(suite.ctx, suite.plaintext, "")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "password cannot be empty")
+}
+
+func (suite *PasswordResetServiceTestSuite) TestResetPassword_PasswordPolicyViolation() {
+	// Arrange - the token is not used, so the user can retry with another password
+	policy := passwords.DefaultPolicy()
+	suite.service.PasswordPolicy = &policy
+	suite.mockGetPasswordResetTokenByHash(suite.storedToken(), nil)
+
+	// Act
+	err := suite.service.ResetPassword(suite.ctx, suite.plaintext, "newpassword")
+
+	// Assert
+	var policyErr *passwords.PolicyError
+	suite.Require().ErrorAs(err, &policyErr)
+	suite.Len(policyErr.Violations, 3)
+}
+
+func (suite *PasswordResetServiceTestSuite) TestResetPassword_ConcurrentUse() {
+	// Arrange
+	token := suite.storedToken()
+	suite.mockGetPasswordResetTokenByHash(token, nil)
+	suite.mockTokenRepo.On("MarkSingleUseTokenUsed", token.ID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Act
+	err := suite.service.ResetPassword(suite.ctx, suite.plaintext, "NewPassword123!")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidResetToken)
+}
+
+// Run tests
+func TestPasswordResetServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetServiceTestSuite))
+}
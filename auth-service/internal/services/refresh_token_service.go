@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenTTL is how long a refresh token can be exchanged; every refresh starts a new period
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+// ErrInvalidRefreshToken is returned for unknown, expired, used and revoked refresh tokens
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// TokenPair is a new access token together with the refresh token that renews it
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// RefreshTokenService issues and rotates refresh tokens.
+// Every login is a session, its refresh tokens form a token family with the session's ID.
+type RefreshTokenService struct {
+	tokenRepo   repositories.IRefreshTokenRepository
+	userRepo    repositories.IUserRepository
+	authService IAuthService
+	sessions    ISessionService
+	now         func() time.Time
+}
+
+// NewRefreshTokenService creates a new RefreshTokenService instance
+func NewRefreshTokenService(tokenRepo repositories.IRefreshTokenRepository, userRepo repositories.IUserRepository, authService IAuthService, sessions ISessionService) *RefreshTokenService {
+	return &RefreshTokenService{
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		authService: authService,
+		sessions:    sessions,
+		now:         time.Now,
+	}
+}
+
+// IssueTokens starts a new session for a user who has just logged in and returns its
+// first access token and the refresh token that renews it
+func (s *RefreshTokenService) IssueTokens(ctx context.Context, user *models.User) (*TokenPair, error) {
+	if user == nil {
+		return nil, errors.New("user cannot be nil")
+	}
+
+	session, err := s.sessions.StartSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.authService.GenerateJWTToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, stored, err := s.issue(user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token of the same family.
+// Presenting a token that was already exchanged revokes the whole family, because
+// either the legitimate client or an attacker holds a stolen copy.
+func (s *RefreshTokenService) Refresh(ctx context.Context, plaintext string) (*TokenPair, *models.User, error) {
+	if !strings.HasPrefix(plaintext, models.RefreshTokenPrefix) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	token, err := s.tokenRepo.GetRefreshTokenByHash(utils.HashToken(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	now := s.now().UTC()
+	if token.IsReused() {
+		s.revokeFamily(ctx, token)
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if !token.IsActive(now) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	// Also records the activity of sessions whose access tokens are verified without auth-service
+	err = s.sessions.CheckSession(ctx, token.FamilyID)
+	if errors.Is(err, ErrSessionRevoked) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	marked, err := s.tokenRepo.MarkRefreshTokenUsed(token.ID, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+	if !marked {
+		// A concurrent request exchanged the same token first
+		s.revokeFamily(ctx, token)
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetUserByID(token.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	accessToken, err := s.authService.GenerateJWTToken(user, token.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, stored, err := s.issue(user.ID, token.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+	}, user, nil
+}
+
+// RevokeRefreshToken revokes the token's whole family, ending the session it was issued for
+func (s *RefreshTokenService) RevokeRefreshToken(ctx context.Context, plaintext string) error {
+	if !strings.HasPrefix(plaintext, models.RefreshTokenPrefix) {
+		return ErrInvalidRefreshToken
+	}
+
+	token, err := s.tokenRepo.GetRefreshTokenByHash(utils.HashToken(plaintext))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if err := s.tokenRepo.RevokeRefreshTokenFamily(token.FamilyID, s.now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return s.sessions.EndSession(ctx, token.UserID, token.FamilyID)
+}
+
+// RevokeAllRefreshTokens revokes the refresh tokens of every session of the user
+func (s *RefreshTokenService) RevokeAllRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	if err := s.tokenRepo.RevokeUserRefreshTokens(userID, s.now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return s.sessions.EndAllSessions(ctx, userID)
+}
+
+// issue creates and stores a refresh token of the family
+func (s *RefreshTokenService) issue(userID, familyID uuid.UUID) (string, *models.RefreshToken, error) {
+	plaintext, err := utils.GenerateOpaqueToken(models.RefreshTokenPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := s.now().UTC()
+	token := &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(plaintext),
+		CreatedAt: now,
+		ExpiresAt: now.Add(RefreshTokenTTL),
+	}
+	if err := s.tokenRepo.CreateRefreshToken(token); err != nil {
+		return "", nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return plaintext, token, nil
+}
+
+// revokeFamily revokes every token of a family and ends its session after reuse was detected
+func (s *RefreshTokenService) revokeFamily(ctx context.Context, token *models.RefreshToken) {
+	log.Printf("Refresh token reuse detected for user %s, revoking token family %s", token.UserID, token.FamilyID)
+	if err := s.tokenRepo.RevokeRefreshTokenFamily(token.FamilyID, s.now().UTC()); err != nil {
+		log.Printf("Failed to revoke refresh token family %s: %v", token.FamilyID, err)
+		return
+	}
+	if err := s.sessions.EndSession(ctx, token.UserID, token.FamilyID); err != nil {
+		log.Printf("Failed to end session %s: %v", token.FamilyID, err)
+	}
+}
@@ -0,0 +1,301 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenServiceTestSuite struct {
+	suite.Suite
+	mockTokenRepo   *repositoryMocks.IRefreshTokenRepository
+	mockUserRepo    *repositoryMocks.IUserRepository
+	mockAuthService *serviceMocks.IAuthService
+	mockSessions    *serviceMocks.ISessionService
+	service         *services.RefreshTokenService
+	ctx             context.Context
+	user            *models.User
+	plaintext       string
+}
+
+func (suite *RefreshTokenServiceTestSuite) SetupTest() {
+	suite.mockTokenRepo = repositoryMocks.NewIRefreshTokenRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockAuthService = serviceMocks.NewIAuthService(suite.T())
+	suite.mockSessions = serviceMocks.NewISessionService(suite.T())
+	suite.service = services.NewRefreshTokenService(suite.mockTokenRepo, suite.mockUserRepo, suite.mockAuthService, suite.mockSessions)
+	suite.ctx = context.Background()
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleUser}
+	suite.plaintext = models.RefreshTokenPrefix + "current"
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// storedToken returns the stored state of suite.plaintext
+func (suite *RefreshTokenServiceTestSuite) storedToken() *models.RefreshToken {
+	return &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    suite.user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: utils.HashToken(suite.plaintext),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// mockGetRefreshTokenByHash mock tokenRepo.GetRefreshTokenByHash for suite.plaintext
+func (suite *RefreshTokenServiceTestSuite) mockGetRefreshTokenByHash(token *models.RefreshToken, err error) {
+	suite.mockTokenRepo.On("GetRefreshTokenByHash", utils.HashToken(suite.plaintext)).Return(token, err)
+}
+
+// ===== ISSUE TESTS =====
+
+func (suite *RefreshTokenServiceTestSuite) TestIssueTokens_StartsSession() {
+	// Arrange
+	session := &models.Session{ID: uuid.New(), UserID: suite.user.ID}
+	suite.mockSessions.On("StartSession", suite.ctx, suite.user).Return(session, nil)
+	suite.mockAuthService.On("GenerateJWTToken", suite.user, session.ID).Return("jwt-token", nil)
+	var stored *models.RefreshToken
+	suite.mockTokenRepo.On("CreateRefreshToken", mock.AnythingOfType("*models.RefreshToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.RefreshToken)
+	}).Return(nil)
+
+	// Act
+	pair, err := suite.service.IssueTokens(suite.ctx, suite.user)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("jwt-token", pair.AccessToken)
+	suite.True(strings.HasPrefix(pair.RefreshToken, models.RefreshTokenPrefix))
+	suite.Equal(utils.HashToken(pair.RefreshToken), stored.TokenHash)
+	suite.Equal(suite.user.ID, stored.UserID)
+	suite.Equal(session.ID, stored.FamilyID)
+	suite.Equal(stored.ExpiresAt, pair.RefreshExpiresAt)
+	suite.WithinDuration(time.Now().Add(services.RefreshTokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestIssueTokens_SessionError() {
+	// Arrange
+	suite.mockSessions.On("StartSession", suite.ctx, suite.user).Return(nil, errors.New("database down"))
+
+	// Act
+	pair, err := suite.service.IssueTokens(suite.ctx, suite.user)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(pair)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "CreateRefreshToken", mock.Anything)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestIssueTokens_NilUser() {
+	// Act
+	_, err := suite.service.IssueTokens(suite.ctx, nil)
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+// ===== REFRESH TESTS =====
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_RotatesWithinFamily() {
+	// Arrange
+	current := suite.storedToken()
+	suite.mockGetRefreshTokenByHash(current, nil)
+	suite.mockSessions.On("CheckSession", suite.ctx, current.FamilyID).Return(nil)
+	suite.mockTokenRepo.On("MarkRefreshTokenUsed", current.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockAuthService.On("GenerateJWTToken", suite.user, current.FamilyID).Return("new-jwt", nil)
+	var next *models.RefreshToken
+	suite.mockTokenRepo.On("CreateRefreshToken", mock.AnythingOfType("*models.RefreshToken")).Run(func(args mock.Arguments) {
+		next = args.Get(0).(*models.RefreshToken)
+	}).Return(nil)
+
+	// Act
+	pair, user, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user, user)
+	suite.Equal("new-jwt", pair.AccessToken)
+	suite.NotEqual(suite.plaintext, pair.RefreshToken)
+	suite.Equal(utils.HashToken(pair.RefreshToken), next.TokenHash)
+	suite.Equal(current.FamilyID, next.FamilyID)
+	suite.Equal(next.ExpiresAt, pair.RefreshExpiresAt)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_ReuseRevokesFamily() {
+	// Arrange
+	usedAt := time.Now().Add(-time.Minute)
+	used := suite.storedToken()
+	used.UsedAt = &usedAt
+	suite.mockGetRefreshTokenByHash(used, nil)
+	suite.mockTokenRepo.On("RevokeRefreshTokenFamily", used.FamilyID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockSessions.On("EndSession", suite.ctx, suite.user.ID, used.FamilyID).Return(nil)
+
+	// Act
+	pair, user, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+	suite.Nil(pair)
+	suite.Nil(user)
+	suite.mockTokenRepo.AssertCalled(suite.T(), "RevokeRefreshTokenFamily", used.FamilyID, mock.AnythingOfType("time.Time"))
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_ConcurrentUseRevokesFamily() {
+	// Arrange - the token looked active, but another request exchanged it first
+	current := suite.storedToken()
+	suite.mockGetRefreshTokenByHash(current, nil)
+	suite.mockSessions.On("CheckSession", suite.ctx, current.FamilyID).Return(nil)
+	suite.mockTokenRepo.On("MarkRefreshTokenUsed", current.ID, mock.AnythingOfType("time.Time")).Return(false, nil)
+	suite.mockTokenRepo.On("RevokeRefreshTokenFamily", current.FamilyID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockSessions.On("EndSession", suite.ctx, suite.user.ID, current.FamilyID).Return(nil)
+
+	// Act
+	_, _, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_RevokedSession() {
+	// Arrange
+	current := suite.storedToken()
+	suite.mockGetRefreshTokenByHash(current, nil)
+	suite.mockSessions.On("CheckSession", suite.ctx, current.FamilyID).Return(services.ErrSessionRevoked)
+
+	// Act
+	_, _, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "MarkRefreshTokenUsed", mock.Anything, mock.Anything)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_RevokedFamily() {
+	// Arrange - tokens of a revoked family are rejected without revoking again
+	revokedAt := time.Now().Add(-time.Minute)
+	revoked := suite.storedToken()
+	revoked.UsedAt = &revokedAt
+	revoked.RevokedAt = &revokedAt
+	suite.mockGetRefreshTokenByHash(revoked, nil)
+
+	// Act
+	_, _, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_Expired() {
+	// Arrange
+	expired := suite.storedToken()
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	suite.mockGetRefreshTokenByHash(expired, nil)
+
+	// Act
+	_, _, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_UnknownToken() {
+	// Arrange
+	suite.mockGetRefreshTokenByHash(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	_, _, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_DatabaseError() {
+	// Arrange
+	suite.mockGetRefreshTokenByHash(nil, errors.New("connection refused"))
+
+	// Act
+	_, _, err := suite.service.Refresh(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.NotErrorIs(err, services.ErrInvalidRefreshToken)
+	suite.Contains(err.Error(), "failed to get refresh token")
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRefresh_WrongPrefix() {
+	// Act
+	_, _, err := suite.service.Refresh(suite.ctx, "pat_something")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+}
+
+// ===== REVOKE TESTS =====
+
+func (suite *RefreshTokenServiceTestSuite) TestRevokeRefreshToken_RevokesFamily() {
+	// Arrange
+	stored := suite.storedToken()
+	suite.mockGetRefreshTokenByHash(stored, nil)
+	suite.mockTokenRepo.On("RevokeRefreshTokenFamily", stored.FamilyID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockSessions.On("EndSession", suite.ctx, suite.user.ID, stored.FamilyID).Return(nil)
+
+	// Act
+	err := suite.service.RevokeRefreshToken(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRevokeRefreshToken_UnknownToken() {
+	// Arrange
+	suite.mockGetRefreshTokenByHash(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	err := suite.service.RevokeRefreshToken(suite.ctx, suite.plaintext)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidRefreshToken)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRevokeAllRefreshTokens() {
+	// Arrange
+	suite.mockTokenRepo.On("RevokeUserRefreshTokens", suite.user.ID, mock.AnythingOfType("time.Time")).Return(errors.New("database down"))
+
+	// Act
+	err := suite.service.RevokeAllRefreshTokens(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to revoke refresh tokens")
+	suite.mockSessions.AssertNotCalled(suite.T(), "EndAllSessions", mock.Anything, mock.Anything)
+}
+
+func (suite *RefreshTokenServiceTestSuite) TestRevokeAllRefreshTokens_EndsSessions() {
+	// Arrange
+	suite.mockTokenRepo.On("RevokeUserRefreshTokens", suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockSessions.On("EndAllSessions", suite.ctx, suite.user.ID).Return(nil)
+
+	// Act
+	err := suite.service.RevokeAllRefreshTokens(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+// Run tests
+func TestRefreshTokenServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenServiceTestSuite))
+}
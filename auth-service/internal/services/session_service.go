@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+	"gorm.io/gorm"
+)
+
+// gRPC metadata keys core-service describes the end user's client with
+const (
+	UserAgentMetadataKey = "x-client-user-agent"
+	ClientIPMetadataKey  = "x-client-ip"
+)
+
+const (
+	maxUserAgentLength = 512
+	maxClientIPLength  = 45
+)
+
+var (
+	// ErrSessionRevoked is returned for tokens of a session the user signed out remotely or by logout
+	ErrSessionRevoked = errors.New("session has been revoked")
+	// ErrSessionNotFound is returned when revoking a session the user does not have
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// SessionService records logins as sessions so that users can see and sign out their devices
+type SessionService struct {
+	sessionRepo      repositories.ISessionRepository
+	refreshTokenRepo repositories.IRefreshTokenRepository
+	messageBroker    messaging.IMessageBroker
+	now              func() time.Time
+}
+
+// NewSessionService creates a new SessionService instance
+func NewSessionService(sessionRepo repositories.ISessionRepository, refreshTokenRepo repositories.IRefreshTokenRepository, messageBroker messaging.IMessageBroker) *SessionService {
+	return &SessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		messageBroker:    messageBroker,
+		now:              time.Now,
+	}
+}
+
+// StartSession records a login of the user from the client described by the gRPC metadata of ctx
+func (s *SessionService) StartSession(ctx context.Context, user *models.User) (*models.Session, error) {
+	if user == nil {
+		return nil, errors.New("user cannot be nil")
+	}
+
+	now := s.now().UTC()
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  truncate(clientMetadata(ctx, UserAgentMetadataKey), maxUserAgentLength),
+		IP:         truncate(ClientIP(ctx), maxClientIPLength),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.sessionRepo.CreateSession(session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return session, nil
+}
+
+// CheckSession returns ErrSessionRevoked for revoked sessions and records that the session was seen.
+// Unknown sessions are not rejected, refresh tokens issued before sessions were recorded have none.
+func (s *SessionService) CheckSession(ctx context.Context, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetSession(sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	now := s.now().UTC()
+	if now.Sub(session.LastSeenAt) >= lastUsedGranularity {
+		if err := s.sessionRepo.UpdateSessionLastSeen(session.ID, now); err != nil {
+			// Activity tracking must not break authentication
+			log.Printf("Failed to update last activity of session %s: %v", session.ID, err)
+		}
+	}
+	return nil
+}
+
+// ListSessions returns the user's sessions that were not revoked. Sessions idle for longer than
+// RefreshTokenTTL are left out, their refresh tokens have expired.
+func (s *SessionService) ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	sessions, err := s.sessionRepo.ListUserSessions(userID, s.now().UTC().Add(-RefreshTokenTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's devices out. The refresh tokens of the session are
+// revoked and its access tokens are rejected from now on.
+func (s *SessionService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	now := s.now().UTC()
+	revoked, err := s.sessionRepo.RevokeSession(userID, sessionID, now)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(sessionID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens of session: %w", err)
+	}
+
+	// Drop cached validations of the session's tokens in other services
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishSessionRevoked(userID, sessionID); err != nil {
+			log.Printf("Failed to publish session revoked event: %v", err)
+		}
+	}
+	return nil
+}
+
+// EndSession marks a session of the user as revoked after its refresh tokens were revoked
+func (s *SessionService) EndSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if _, err := s.sessionRepo.RevokeSession(userID, sessionID, s.now().UTC()); err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+	return nil
+}
+
+// EndAllSessions marks every session of the user as revoked after all refresh tokens were revoked
+func (s *SessionService) EndAllSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := s.sessionRepo.RevokeUserSessions(userID, s.now().UTC()); err != nil {
+		return fmt.Errorf("failed to end sessions: %w", err)
+	}
+	return nil
+}
+
+// ClientIP returns the address of the end user that core-service sent as gRPC metadata.
+// Sessions record it and failed logins are limited by it.
+func ClientIP(ctx context.Context) string {
+	return clientMetadata(ctx, ClientIPMetadataKey)
+}
+
+// clientMetadata returns the first value of an incoming gRPC metadata key or "" if it is missing
+func clientMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// truncate shortens s to at most limit bytes, dropping a UTF-8 sequence split at the end
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return strings.ToValidUTF8(s[:limit], "")
+}
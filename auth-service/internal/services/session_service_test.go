@@ -0,0 +1,341 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
+	"gorm.io/gorm"
+)
+
+type SessionServiceTestSuite struct {
+	suite.Suite
+	mockSessionRepo *repositoryMocks.ISessionRepository
+	mockTokenRepo   *repositoryMocks.IRefreshTokenRepository
+	mockBroker      *messagingMocks.IMessageBroker
+	service         *services.SessionService
+	ctx             context.Context
+	user            *models.User
+}
+
+func (suite *SessionServiceTestSuite) SetupTest() {
+	suite.mockSessionRepo = repositoryMocks.NewISessionRepository(suite.T())
+	suite.mockTokenRepo = repositoryMocks.NewIRefreshTokenRepository(suite.T())
+	suite.mockBroker = messagingMocks.NewIMessageBroker(suite.T())
+	suite.service = services.NewSessionService(suite.mockSessionRepo, suite.mockTokenRepo, suite.mockBroker)
+	suite.ctx = context.Background()
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleUser}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// storedSession returns an active session of suite.user last seen at lastSeenAt
+func (suite *SessionServiceTestSuite) storedSession(lastSeenAt time.Time) *models.Session {
+	return &models.Session{
+		ID:         uuid.New(),
+		UserID:     suite.user.ID,
+		UserAgent:  "Mozilla/5.0",
+		IP:         "203.0.113.7",
+		CreatedAt:  lastSeenAt,
+		LastSeenAt: lastSeenAt,
+	}
+}
+
+// ===== START TESTS =====
+
+func (suite *SessionServiceTestSuite) TestStartSession_RecordsClient() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(suite.ctx, metadata.Pairs(
+		services.UserAgentMetadataKey, "Mozilla/5.0",
+		services.ClientIPMetadataKey, "203.0.113.7",
+	))
+	var stored *models.Session
+	suite.mockSessionRepo.On("CreateSession", mock.AnythingOfType("*models.Session")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.Session)
+	}).Return(nil)
+
+	// Act
+	session, err := suite.service.StartSession(ctx, suite.user)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(stored, session)
+	suite.NotEqual(uuid.Nil, session.ID)
+	suite.Equal(suite.user.ID, session.UserID)
+	suite.Equal("Mozilla/5.0", session.UserAgent)
+	suite.Equal("203.0.113.7", session.IP)
+	suite.Equal(session.CreatedAt, session.LastSeenAt)
+	suite.Nil(session.RevokedAt)
+}
+
+func (suite *SessionServiceTestSuite) TestStartSession_WithoutMetadata() {
+	// Arrange
+	suite.mockSessionRepo.On("CreateSession", mock.AnythingOfType("*models.Session")).Return(nil)
+
+	// Act
+	session, err := suite.service.StartSession(suite.ctx, suite.user)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Empty(session.UserAgent)
+	suite.Empty(session.IP)
+}
+
+func (suite *SessionServiceTestSuite) TestStartSession_TruncatesUserAgent() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(suite.ctx, metadata.Pairs(
+		services.UserAgentMetadataKey, strings.Repeat("a", 511)+"é",
+	))
+	suite.mockSessionRepo.On("CreateSession", mock.AnythingOfType("*models.Session")).Return(nil)
+
+	// Act
+	session, err := suite.service.StartSession(ctx, suite.user)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(strings.Repeat("a", 511), session.UserAgent)
+}
+
+func (suite *SessionServiceTestSuite) TestStartSession_RepositoryError() {
+	// Arrange
+	suite.mockSessionRepo.On("CreateSession", mock.AnythingOfType("*models.Session")).Return(errors.New("database down"))
+
+	// Act
+	session, err := suite.service.StartSession(suite.ctx, suite.user)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(session)
+	suite.Contains(err.Error(), "failed to create session")
+}
+
+func (suite *SessionServiceTestSuite) TestStartSession_NilUser() {
+	// Act
+	session, err := suite.service.StartSession(suite.ctx, nil)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(session)
+}
+
+// ===== CHECK TESTS =====
+
+func (suite *SessionServiceTestSuite) TestCheckSession_UnknownSession() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("GetSession", sessionID).Return(nil, gorm.ErrRecordNotFound)
+
+	// Act
+	err := suite.service.CheckSession(suite.ctx, sessionID)
+
+	// Assert
+	suite.NoError(err)
+}
+
+func (suite *SessionServiceTestSuite) TestCheckSession_Revoked() {
+	// Arrange
+	session := suite.storedSession(time.Now().Add(-time.Hour))
+	revokedAt := time.Now().Add(-time.Minute)
+	session.RevokedAt = &revokedAt
+	suite.mockSessionRepo.On("GetSession", session.ID).Return(session, nil)
+
+	// Act
+	err := suite.service.CheckSession(suite.ctx, session.ID)
+
+	// Assert
+	suite.ErrorIs(err, services.ErrSessionRevoked)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "UpdateSessionLastSeen", mock.Anything, mock.Anything)
+}
+
+func (suite *SessionServiceTestSuite) TestCheckSession_UpdatesStaleLastSeen() {
+	// Arrange
+	session := suite.storedSession(time.Now().Add(-time.Hour))
+	suite.mockSessionRepo.On("GetSession", session.ID).Return(session, nil)
+	suite.mockSessionRepo.On("UpdateSessionLastSeen", session.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := suite.service.CheckSession(suite.ctx, session.ID)
+
+	// Assert
+	suite.NoError(err)
+}
+
+func (suite *SessionServiceTestSuite) TestCheckSession_RecentlySeen() {
+	// Arrange
+	session := suite.storedSession(time.Now().UTC())
+	suite.mockSessionRepo.On("GetSession", session.ID).Return(session, nil)
+
+	// Act
+	err := suite.service.CheckSession(suite.ctx, session.ID)
+
+	// Assert
+	suite.NoError(err)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "UpdateSessionLastSeen", mock.Anything, mock.Anything)
+}
+
+func (suite *SessionServiceTestSuite) TestCheckSession_UpdateErrorIgnored() {
+	// Arrange
+	session := suite.storedSession(time.Now().Add(-time.Hour))
+	suite.mockSessionRepo.On("GetSession", session.ID).Return(session, nil)
+	suite.mockSessionRepo.On("UpdateSessionLastSeen", session.ID, mock.AnythingOfType("time.Time")).Return(errors.New("database down"))
+
+	// Act
+	err := suite.service.CheckSession(suite.ctx, session.ID)
+
+	// Assert
+	suite.NoError(err)
+}
+
+func (suite *SessionServiceTestSuite) TestCheckSession_RepositoryError() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("GetSession", sessionID).Return(nil, errors.New("database down"))
+
+	// Act
+	err := suite.service.CheckSession(suite.ctx, sessionID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.NotErrorIs(err, services.ErrSessionRevoked)
+}
+
+// ===== LIST TESTS =====
+
+func (suite *SessionServiceTestSuite) TestListSessions_SkipsExpired() {
+	// Arrange
+	sessions := []models.Session{*suite.storedSession(time.Now().Add(-time.Hour))}
+	var seenAfter time.Time
+	suite.mockSessionRepo.On("ListUserSessions", suite.user.ID, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		seenAfter = args.Get(1).(time.Time)
+	}).Return(sessions, nil)
+
+	// Act
+	result, err := suite.service.ListSessions(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(sessions, result)
+	suite.WithinDuration(time.Now().Add(-services.RefreshTokenTTL), seenAfter, time.Minute)
+}
+
+func (suite *SessionServiceTestSuite) TestListSessions_RepositoryError() {
+	// Arrange
+	suite.mockSessionRepo.On("ListUserSessions", suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil, errors.New("database down"))
+
+	// Act
+	result, err := suite.service.ListSessions(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(result)
+}
+
+// ===== REVOKE TESTS =====
+
+func (suite *SessionServiceTestSuite) TestRevokeSession_Success() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("RevokeSession", suite.user.ID, sessionID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockTokenRepo.On("RevokeRefreshTokenFamily", sessionID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockBroker.On("PublishSessionRevoked", suite.user.ID, sessionID).Return(nil)
+
+	// Act
+	err := suite.service.RevokeSession(suite.ctx, suite.user.ID, sessionID)
+
+	// Assert
+	suite.NoError(err)
+}
+
+func (suite *SessionServiceTestSuite) TestRevokeSession_NotFound() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("RevokeSession", suite.user.ID, sessionID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Act
+	err := suite.service.RevokeSession(suite.ctx, suite.user.ID, sessionID)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrSessionNotFound)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "RevokeRefreshTokenFamily", mock.Anything, mock.Anything)
+}
+
+func (suite *SessionServiceTestSuite) TestRevokeSession_RefreshTokensError() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("RevokeSession", suite.user.ID, sessionID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockTokenRepo.On("RevokeRefreshTokenFamily", sessionID, mock.AnythingOfType("time.Time")).Return(errors.New("database down"))
+
+	// Act
+	err := suite.service.RevokeSession(suite.ctx, suite.user.ID, sessionID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to revoke refresh tokens of session")
+	suite.mockBroker.AssertNotCalled(suite.T(), "PublishSessionRevoked", mock.Anything, mock.Anything)
+}
+
+func (suite *SessionServiceTestSuite) TestRevokeSession_PublishErrorIgnored() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("RevokeSession", suite.user.ID, sessionID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockTokenRepo.On("RevokeRefreshTokenFamily", sessionID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockBroker.On("PublishSessionRevoked", suite.user.ID, sessionID).Return(errors.New("broker down"))
+
+	// Act
+	err := suite.service.RevokeSession(suite.ctx, suite.user.ID, sessionID)
+
+	// Assert
+	suite.NoError(err)
+}
+
+// ===== END TESTS =====
+
+func (suite *SessionServiceTestSuite) TestEndSession_AlreadyRevoked() {
+	// Arrange
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("RevokeSession", suite.user.ID, sessionID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Act
+	err := suite.service.EndSession(suite.ctx, suite.user.ID, sessionID)
+
+	// Assert
+	suite.NoError(err)
+}
+
+func (suite *SessionServiceTestSuite) TestEndAllSessions_Success() {
+	// Arrange
+	suite.mockSessionRepo.On("RevokeUserSessions", suite.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := suite.service.EndAllSessions(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.NoError(err)
+}
+
+func (suite *SessionServiceTestSuite) TestEndAllSessions_RepositoryError() {
+	// Arrange
+	suite.mockSessionRepo.On("RevokeUserSessions", suite.user.ID, mock.AnythingOfType("time.Time")).Return(errors.New("database down"))
+
+	// Act
+	err := suite.service.EndAllSessions(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to end sessions")
+}
+
+// Run tests
+func TestSessionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionServiceTestSuite))
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/totp"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// TwoFactorChallengeTTL is how long the second step of a login may take
+	TwoFactorChallengeTTL = 5 * time.Minute
+	// MaxTwoFactorAttempts is how many wrong codes a login challenge accepts before the password is needed again
+	MaxTwoFactorAttempts = 5
+	// RecoveryCodeCount is how many recovery codes are issued when two-factor authentication is enabled
+	RecoveryCodeCount = 10
+	// recoveryCodeLength is the number of base32 characters of a recovery code (50 bits)
+	recoveryCodeLength = 10
+)
+
+var (
+	// ErrSecondFactorRequired is wrapped by SecondFactorRequiredError
+	ErrSecondFactorRequired = errors.New("second factor required")
+	// ErrInvalidChallenge is returned for unknown, expired and used login challenges
+	ErrInvalidChallenge = errors.New("invalid or expired login challenge")
+	// ErrInvalidSecondFactor is returned for wrong, expired and already used codes
+	ErrInvalidSecondFactor = errors.New("invalid authentication code")
+	// ErrTwoFactorEnabled is returned when enrolling while two-factor authentication is already enabled
+	ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnabled is returned when disabling two-factor authentication that is not enabled
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTwoFactorNotEnrolled is returned when confirming without a started enrollment
+	ErrTwoFactorNotEnrolled = errors.New("two-factor enrollment has not been started")
+)
+
+// SecondFactorRequiredError is returned by Login after a correct password when the account
+// has two-factor authentication enabled. The login is completed by VerifySecondFactor with
+// Challenge and a code. It wraps ErrSecondFactorRequired.
+type SecondFactorRequiredError struct {
+	Challenge string
+	ExpiresAt time.Time
+}
+
+func (e *SecondFactorRequiredError) Error() string {
+	return ErrSecondFactorRequired.Error()
+}
+
+func (e *SecondFactorRequiredError) Unwrap() error {
+	return ErrSecondFactorRequired
+}
+
+// TOTPEnrollment is a new authenticator app secret waiting for confirmation
+type TOTPEnrollment struct {
+	Secret string
+	// URI is the otpauth:// URI of the secret, usually shown as a QR code
+	URI string
+}
+
+// TwoFactorService manages two-factor authentication with authenticator apps (TOTP)
+// and completes logins that need a second factor
+type TwoFactorService struct {
+	repo          repositories.ITwoFactorRepository
+	userRepo      repositories.IUserRepository
+	refreshTokens IRefreshTokenService
+	// Issuer names the service in authenticator apps
+	Issuer string
+	// Lockout counts wrong codes as failed logins, codes are not limited beyond
+	// MaxTwoFactorAttempts per challenge when it is nil
+	Lockout ILoginLockoutService
+	now     func() time.Time
+}
+
+// NewTwoFactorService creates a new TwoFactorService instance
+func NewTwoFactorService(
+	repo repositories.ITwoFactorRepository,
+	userRepo repositories.IUserRepository,
+	refreshTokens IRefreshTokenService,
+	issuer string,
+) *TwoFactorService {
+	return &TwoFactorService{
+		repo:          repo,
+		userRepo:      userRepo,
+		refreshTokens: refreshTokens,
+		Issuer:        issuer,
+		now:           time.Now,
+	}
+}
+
+// EnrollTOTP starts the enrollment of an authenticator app with a new secret.
+// Logins do not need a code until the enrollment is confirmed with ConfirmTOTP;
+// an unconfirmed enrollment is replaced.
+func (s *TwoFactorService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (*TOTPEnrollment, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	credential, err := s.repo.GetTOTPCredential(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get TOTP credential: %w", err)
+	}
+	if err == nil && credential.IsConfirmed() {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.SaveTOTPCredential(&models.TOTPCredential{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: s.now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save TOTP credential: %w", err)
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once code shows that the authenticator app
+// was set up. It returns the recovery codes, which are not shown again.
+func (s *TwoFactorService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	credential, err := s.repo.GetTOTPCredential(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TOTP credential: %w", err)
+	}
+	if credential.IsConfirmed() {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	now := s.now().UTC()
+	step, ok := totp.Validate(credential.Secret, normalizeCode(code), now)
+	if !ok {
+		return nil, ErrInvalidSecondFactor
+	}
+
+	confirmed, err := s.repo.ConfirmTOTPCredential(userID, now, step)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm TOTP credential: %w", err)
+	}
+	if !confirmed {
+		// A concurrent request confirmed the enrollment first
+		return nil, ErrTwoFactorEnabled
+	}
+
+	codes, err := s.issueRecoveryCodes(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Two-factor authentication enabled for user %s", userID)
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off after checking the current password
+// and an authenticator or recovery code. The recovery codes are deleted.
+func (s *TwoFactorService) DisableTOTP(ctx context.Context, userID uuid.UUID, currentPassword, code string) error {
+	if _, err := checkCurrentPassword(s.userRepo, userID, currentPassword); err != nil {
+		return err
+	}
+
+	credential, err := s.repo.GetTOTPCredential(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get TOTP credential: %w", err)
+	}
+	if !credential.IsConfirmed() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err := s.checkCode(credential, code, s.now().UTC()); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTOTPCredential(userID); err != nil {
+		return fmt.Errorf("failed to delete TOTP credential: %w", err)
+	}
+	log.Printf("Two-factor authentication disabled for user %s", userID)
+	return nil
+}
+
+// IsEnabled reports whether logins of the user need a second factor
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	credential, err := s.repo.GetTOTPCredential(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get TOTP credential: %w", err)
+	}
+	return credential.IsConfirmed(), nil
+}
+
+// CreateChallenge issues the challenge of a login whose password was correct.
+// It returns the challenge and when it expires.
+func (s *TwoFactorService) CreateChallenge(ctx context.Context, user *models.User) (string, time.Time, error) {
+	plaintext, err := utils.GenerateOpaqueToken(models.TwoFactorChallengePrefix)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := s.now().UTC()
+	challenge := &models.TwoFactorChallenge{
+		TokenHash: utils.HashToken(plaintext),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(TwoFactorChallengeTTL),
+	}
+	if err := s.repo.CreateTwoFactorChallenge(challenge); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create two-factor challenge: %w", err)
+	}
+
+	// Challenges are only needed until they expire
+	if err := s.repo.DeleteExpiredTwoFactorChallenges(now); err != nil {
+		log.Printf("Failed to purge expired two-factor challenges: %v", err)
+	}
+	return plaintext, challenge.ExpiresAt, nil
+}
+
+// VerifySecondFactor completes a login with the challenge returned by Login and an
+// authenticator or recovery code, and issues the tokens of the new session.
+// Wrong codes count as failed logins; after MaxTwoFactorAttempts the challenge is void.
+func (s *TwoFactorService) VerifySecondFactor(ctx context.Context, challengeToken, code, clientIP string) (*TokenPair, *models.User, error) {
+	if !strings.HasPrefix(challengeToken, models.TwoFactorChallengePrefix) {
+		return nil, nil, ErrInvalidChallenge
+	}
+
+	tokenHash := utils.HashToken(challengeToken)
+	challenge, err := s.repo.GetTwoFactorChallenge(tokenHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidChallenge
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get two-factor challenge: %w", err)
+	}
+
+	now := s.now().UTC()
+	if !challenge.IsActive(now) || challenge.Attempts >= MaxTwoFactorAttempts {
+		return nil, nil, ErrInvalidChallenge
+	}
+
+	user, err := s.userRepo.GetUserByID(challenge.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	attempt, err := beginLogin(ctx, s.Lockout, user.EmailNormalized, clientIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer attempt.release()
+
+	credential, err := s.repo.GetTOTPCredential(user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Two-factor authentication was disabled since the login started
+		return nil, nil, ErrInvalidChallenge
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get TOTP credential: %w", err)
+	}
+
+	if err := s.checkCode(credential, code, now); err != nil {
+		if errors.Is(err, ErrInvalidSecondFactor) {
+			s.recordFailedAttempt(tokenHash, user, attempt)
+		}
+		return nil, nil, err
+	}
+
+	deleted, err := s.repo.DeleteTwoFactorChallenge(tokenHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to use two-factor challenge: %w", err)
+	}
+	if !deleted {
+		// A concurrent request completed the login first
+		return nil, nil, ErrInvalidChallenge
+	}
+
+	attempt.succeeded()
+
+	pair, err := s.refreshTokens.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// checkCode accepts an authenticator code that was not used before or an unused recovery code
+func (s *TwoFactorService) checkCode(credential *models.TOTPCredential, code string, now time.Time) error {
+	code = normalizeCode(code)
+
+	if step, ok := totp.Validate(credential.Secret, code, now); ok {
+		used, err := s.repo.UseTOTPStep(credential.UserID, step)
+		if err != nil {
+			return fmt.Errorf("failed to use authentication code: %w", err)
+		}
+		if !used {
+			return ErrInvalidSecondFactor
+		}
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(credential.UserID, utils.HashToken(code), now)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if !used {
+		return ErrInvalidSecondFactor
+	}
+	log.Printf("User %s used a recovery code", credential.UserID)
+	return nil
+}
+
+// recordFailedAttempt counts a wrong code against the challenge and as a failed login
+func (s *TwoFactorService) recordFailedAttempt(tokenHash string, user *models.User, attempt *loginAttempt) {
+	if err := s.repo.RecordTwoFactorChallengeAttempt(tokenHash); err != nil {
+		log.Printf("Failed to record wrong code for two-factor challenge of user %s: %v", user.ID, err)
+	}
+	attempt.failed(user)
+}
+
+// issueRecoveryCodes replaces the recovery codes of the user and returns the new ones
+func (s *TwoFactorService) issueRecoveryCodes(userID uuid.UUID, now time.Time) ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	stored := make([]models.RecoveryCode, 0, RecoveryCodeCount)
+	for range RecoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		stored = append(stored, models.RecoveryCode{
+			UserID:    userID,
+			CodeHash:  utils.HashToken(normalizeCode(code)),
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(userID, stored); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode returns a random code formatted as xxxxx-xxxxx for readability
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:recoveryCodeLength]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeCode removes the separators users may type and ignores the case of recovery codes
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
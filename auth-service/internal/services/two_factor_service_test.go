@@ -0,0 +1,363 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	serviceMocks "github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/totp"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type TwoFactorServiceTestSuite struct {
+	suite.Suite
+	mockRepo          *repositoryMocks.ITwoFactorRepository
+	mockUserRepo      *repositoryMocks.IUserRepository
+	mockRefreshTokens *serviceMocks.IRefreshTokenService
+	mockLockout       *serviceMocks.ILoginLockoutService
+	service           *services.TwoFactorService
+	ctx               context.Context
+	user              *models.User
+	clientIP          string
+	secret            string
+	step              int64
+}
+
+func (suite *TwoFactorServiceTestSuite) SetupTest() {
+	suite.mockRepo = repositoryMocks.NewITwoFactorRepository(suite.T())
+	suite.mockUserRepo = repositoryMocks.NewIUserRepository(suite.T())
+	suite.mockRefreshTokens = serviceMocks.NewIRefreshTokenService(suite.T())
+	suite.mockLockout = serviceMocks.NewILoginLockoutService(suite.T())
+	suite.service = services.NewTwoFactorService(suite.mockRepo, suite.mockUserRepo, suite.mockRefreshTokens, "subs-service")
+	suite.service.Lockout = suite.mockLockout
+	suite.ctx = context.Background()
+	suite.clientIP = "192.0.2.1"
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("current-password"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.user = &models.User{ID: uuid.New(), Email: "test@example.com", EmailNormalized: "test@example.com", Password: string(hashedPassword), Role: models.RoleUser}
+	suite.secret, err = totp.GenerateSecret()
+	suite.Require().NoError(err)
+	suite.step = totp.Step(time.Now())
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// currentCode returns the authenticator code of the suite secret for the current time step
+func (suite *TwoFactorServiceTestSuite) currentCode() string {
+	code, err := totp.Code(suite.secret, suite.step)
+	suite.Require().NoError(err)
+	return code
+}
+
+// mockCredential mocks the stored credential of the suite user, confirmed or not
+func (suite *TwoFactorServiceTestSuite) mockCredential(confirmed bool) {
+	credential := &models.TOTPCredential{UserID: suite.user.ID, Secret: suite.secret}
+	if confirmed {
+		confirmedAt := time.Now()
+		credential.ConfirmedAt = &confirmedAt
+	}
+	suite.mockRepo.On("GetTOTPCredential", suite.user.ID).Return(credential, nil)
+}
+
+// mockChallenge mocks a stored login challenge of the suite user and returns its plaintext
+func (suite *TwoFactorServiceTestSuite) mockChallenge(attempts int, expiresAt time.Time) string {
+	plaintext := models.TwoFactorChallengePrefix + "challenge"
+	suite.mockRepo.On("GetTwoFactorChallenge", utils.HashToken(plaintext)).Return(&models.TwoFactorChallenge{
+		TokenHash: utils.HashToken(plaintext),
+		UserID:    suite.user.ID,
+		Attempts:  attempts,
+		ExpiresAt: expiresAt,
+	}, nil)
+	return plaintext
+}
+
+// ===== ENROLL TESTS =====
+
+func (suite *TwoFactorServiceTestSuite) TestEnrollTOTP_Success() {
+	// Arrange
+	var saved *models.TOTPCredential
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockRepo.On("GetTOTPCredential", suite.user.ID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockRepo.On("SaveTOTPCredential", mock.AnythingOfType("*models.TOTPCredential")).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(*models.TOTPCredential)
+	}).Return(nil)
+
+	// Act
+	enrollment, err := suite.service.EnrollTOTP(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(saved.Secret, enrollment.Secret)
+	suite.False(saved.IsConfirmed())
+	suite.True(strings.HasPrefix(enrollment.URI, "otpauth://totp/subs-service:test@example.com?"))
+	suite.Contains(enrollment.URI, "secret="+enrollment.Secret)
+}
+
+func (suite *TwoFactorServiceTestSuite) TestEnrollTOTP_AlreadyEnabled() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockCredential(true)
+
+	// Act
+	enrollment, err := suite.service.EnrollTOTP(suite.ctx, suite.user.ID)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrTwoFactorEnabled)
+	suite.Nil(enrollment)
+}
+
+// ===== CONFIRM TESTS =====
+
+func (suite *TwoFactorServiceTestSuite) TestConfirmTOTP_IssuesRecoveryCodes() {
+	// Arrange
+	var stored []models.RecoveryCode
+	suite.mockCredential(false)
+	suite.mockRepo.On("ConfirmTOTPCredential", suite.user.ID, mock.AnythingOfType("time.Time"), suite.step).Return(true, nil)
+	suite.mockRepo.On("ReplaceRecoveryCodes", suite.user.ID, mock.AnythingOfType("[]models.RecoveryCode")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]models.RecoveryCode)
+	}).Return(nil)
+
+	// Act
+	codes, err := suite.service.ConfirmTOTP(suite.ctx, suite.user.ID, suite.currentCode())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Len(codes, services.RecoveryCodeCount)
+	suite.Require().Len(stored, services.RecoveryCodeCount)
+	suite.Regexp(`^[a-z2-7]{5}-[a-z2-7]{5}$`, codes[0])
+	suite.Equal(utils.HashToken(strings.ReplaceAll(codes[0], "-", "")), stored[0].CodeHash)
+	suite.NotEqual(codes[0], codes[1])
+}
+
+func (suite *TwoFactorServiceTestSuite) TestConfirmTOTP_Rejected() {
+	testCases := []struct {
+		name    string
+		setup   func()
+		code    string
+		wantErr error
+	}{
+		{name: "not enrolled", code: "123456", wantErr: services.ErrTwoFactorNotEnrolled, setup: func() {
+			suite.mockRepo.On("GetTOTPCredential", suite.user.ID).Return(nil, gorm.ErrRecordNotFound)
+		}},
+		{name: "already confirmed", code: "123456", wantErr: services.ErrTwoFactorEnabled, setup: func() {
+			suite.mockCredential(true)
+		}},
+		{name: "wrong code", code: "not-a-code", wantErr: services.ErrInvalidSecondFactor, setup: func() {
+			suite.mockCredential(false)
+		}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			suite.SetupTest()
+			tc.setup()
+
+			// Act
+			codes, err := suite.service.ConfirmTOTP(suite.ctx, suite.user.ID, tc.code)
+
+			// Assert
+			suite.Require().ErrorIs(err, tc.wantErr)
+			suite.Nil(codes)
+		})
+	}
+}
+
+// ===== DISABLE TESTS =====
+
+func (suite *TwoFactorServiceTestSuite) TestDisableTOTP_WithRecoveryCode() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockCredential(true)
+	suite.mockRepo.On("UseRecoveryCode", suite.user.ID, utils.HashToken("abcdefghij"), mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockRepo.On("DeleteTOTPCredential", suite.user.ID).Return(nil)
+
+	// Act
+	err := suite.service.DisableTOTP(suite.ctx, suite.user.ID, "current-password", "ABCDE-FGHIJ")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *TwoFactorServiceTestSuite) TestDisableTOTP_IncorrectPassword() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+
+	// Act
+	err := suite.service.DisableTOTP(suite.ctx, suite.user.ID, "wrong-password", suite.currentCode())
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrIncorrectPassword)
+}
+
+func (suite *TwoFactorServiceTestSuite) TestDisableTOTP_NotEnabled() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockCredential(false)
+
+	// Act
+	err := suite.service.DisableTOTP(suite.ctx, suite.user.ID, "current-password", suite.currentCode())
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrTwoFactorNotEnabled)
+}
+
+// ===== CHALLENGE TESTS =====
+
+func (suite *TwoFactorServiceTestSuite) TestCreateChallenge_StoresHash() {
+	// Arrange
+	var stored *models.TwoFactorChallenge
+	suite.mockRepo.On("CreateTwoFactorChallenge", mock.AnythingOfType("*models.TwoFactorChallenge")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.TwoFactorChallenge)
+	}).Return(nil)
+	suite.mockRepo.On("DeleteExpiredTwoFactorChallenges", mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	challenge, expiresAt, err := suite.service.CreateChallenge(suite.ctx, suite.user)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(challenge, models.TwoFactorChallengePrefix))
+	suite.Equal(utils.HashToken(challenge), stored.TokenHash)
+	suite.Equal(suite.user.ID, stored.UserID)
+	suite.WithinDuration(time.Now().Add(services.TwoFactorChallengeTTL), expiresAt, time.Second)
+}
+
+// ===== VERIFY SECOND FACTOR TESTS =====
+
+func (suite *TwoFactorServiceTestSuite) TestVerifySecondFactor_Success() {
+	// Arrange
+	challenge := suite.mockChallenge(0, time.Now().Add(time.Minute))
+	refreshExpiresAt := time.Now().Add(time.Hour)
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockLockout.On("CheckLogin", suite.ctx, suite.user.EmailNormalized, suite.clientIP).Return(nil)
+	suite.mockCredential(true)
+	suite.mockRepo.On("UseTOTPStep", suite.user.ID, suite.step).Return(true, nil)
+	suite.mockRepo.On("DeleteTwoFactorChallenge", utils.HashToken(challenge)).Return(true, nil)
+	suite.mockLockout.On("RecordSuccessfulLogin", suite.ctx, suite.user.EmailNormalized, suite.clientIP).Return()
+	suite.mockRefreshTokens.On("IssueTokens", suite.ctx, suite.user).
+		Return(&services.TokenPair{AccessToken: "access-token", RefreshToken: "rt_refresh", RefreshExpiresAt: refreshExpiresAt}, nil)
+
+	// Act
+	pair, user, err := suite.service.VerifySecondFactor(suite.ctx, challenge, suite.currentCode(), suite.clientIP)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.user, user)
+	suite.Equal("access-token", pair.AccessToken)
+	suite.Equal("rt_refresh", pair.RefreshToken)
+	suite.Equal(refreshExpiresAt, pair.RefreshExpiresAt)
+}
+
+func (suite *TwoFactorServiceTestSuite) TestVerifySecondFactor_WrongCodeCountsAsFailedLogin() {
+	// Arrange
+	challenge := suite.mockChallenge(0, time.Now().Add(time.Minute))
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockLockout.On("CheckLogin", suite.ctx, suite.user.EmailNormalized, suite.clientIP).Return(nil)
+	suite.mockCredential(true)
+	suite.mockRepo.On("UseRecoveryCode", suite.user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(false, nil)
+	suite.mockRepo.On("RecordTwoFactorChallengeAttempt", utils.HashToken(challenge)).Return(nil)
+	suite.mockLockout.On("RecordFailedLogin", suite.ctx, suite.user.EmailNormalized, suite.clientIP, suite.user).Return()
+
+	// Act
+	pair, _, err := suite.service.VerifySecondFactor(suite.ctx, challenge, "wrong", suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidSecondFactor)
+	suite.Nil(pair)
+}
+
+func (suite *TwoFactorServiceTestSuite) TestVerifySecondFactor_ReplayedCode() {
+	// Arrange
+	challenge := suite.mockChallenge(0, time.Now().Add(time.Minute))
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockLockout.On("CheckLogin", suite.ctx, suite.user.EmailNormalized, suite.clientIP).Return(nil)
+	suite.mockCredential(true)
+	suite.mockRepo.On("UseTOTPStep", suite.user.ID, suite.step).Return(false, nil)
+	suite.mockRepo.On("RecordTwoFactorChallengeAttempt", utils.HashToken(challenge)).Return(nil)
+	suite.mockLockout.On("RecordFailedLogin", suite.ctx, suite.user.EmailNormalized, suite.clientIP, suite.user).Return()
+
+	// Act
+	_, _, err := suite.service.VerifySecondFactor(suite.ctx, challenge, suite.currentCode(), suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidSecondFactor)
+}
+
+func (suite *TwoFactorServiceTestSuite) TestVerifySecondFactor_Locked() {
+	// Arrange
+	challenge := suite.mockChallenge(0, time.Now().Add(time.Minute))
+	blocked := &services.LoginBlockedError{Err: services.ErrAccountLocked, RetryAt: time.Now().Add(time.Minute)}
+	suite.mockUserRepo.On("GetUserByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockLockout.On("CheckLogin", suite.ctx, suite.user.EmailNormalized, suite.clientIP).Return(blocked)
+
+	// Act
+	_, _, err := suite.service.VerifySecondFactor(suite.ctx, challenge, suite.currentCode(), suite.clientIP)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrAccountLocked)
+}
+
+func (suite *TwoFactorServiceTestSuite) TestVerifySecondFactor_InvalidChallenge() {
+	testCases := []struct {
+		name      string
+		challenge func() string
+	}{
+		{name: "not a challenge", challenge: func() string { return "prt_token" }},
+		{name: "unknown", challenge: func() string {
+			suite.mockRepo.On("GetTwoFactorChallenge", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+			return models.TwoFactorChallengePrefix + "unknown"
+		}},
+		{name: "expired", challenge: func() string {
+			return suite.mockChallenge(0, time.Now().Add(-time.Minute))
+		}},
+		{name: "too many wrong codes", challenge: func() string {
+			return suite.mockChallenge(services.MaxTwoFactorAttempts, time.Now().Add(time.Minute))
+		}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Arrange
+			suite.SetupTest()
+			challenge := tc.challenge()
+
+			// Act
+			pair, user, err := suite.service.VerifySecondFactor(suite.ctx, challenge, suite.currentCode(), suite.clientIP)
+
+			// Assert
+			suite.Require().ErrorIs(err, services.ErrInvalidChallenge)
+			suite.Nil(pair)
+			suite.Nil(user)
+		})
+	}
+}
+
+func (suite *TwoFactorServiceTestSuite) TestVerifySecondFactor_StoreError() {
+	// Arrange
+	suite.mockRepo.On("GetTwoFactorChallenge", mock.AnythingOfType("string")).Return(nil, errors.New("db error"))
+
+	// Act
+	_, _, err := suite.service.VerifySecondFactor(suite.ctx, models.TwoFactorChallengePrefix+"challenge", "123456", suite.clientIP)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "db error")
+}
+
+// Run tests
+func TestTwoFactorServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorServiceTestSuite))
+}
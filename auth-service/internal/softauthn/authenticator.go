@@ -0,0 +1,207 @@
+// Package softauthn is a software WebAuthn authenticator for tests. It answers the options of
+// registration and login ceremonies like a platform passkey provider (ES256 keys, "none"
+// attestation, user verification), so passkey flows can be tested without hardware.
+package softauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// Authenticator data flags, WebAuthn section 6.1
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagBackupEligible         = 0x08
+	flagBackupState            = 0x10
+	flagAttestedCredentialData = 0x40
+)
+
+var encoding = base64.RawURLEncoding
+
+// Authenticator holds discoverable credentials (passkeys) in memory
+type Authenticator struct {
+	// Origin is reported in the client data, as a browser would
+	Origin      string
+	credentials []*credential
+}
+
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// New creates an authenticator without credentials that acts for origin
+func New(origin string) *Authenticator {
+	return &Authenticator{Origin: origin}
+}
+
+// creationOptions is the part of the PublicKeyCredentialCreationOptions the authenticator needs
+type creationOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+// requestOptions is the part of the PublicKeyCredentialRequestOptions the authenticator needs
+type requestOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RPID      string `json:"rpId"`
+	} `json:"publicKey"`
+}
+
+// Register creates a credential for the creation options (the JSON passed to
+// navigator.credentials.create) and returns the registration response as JSON
+func (a *Authenticator) Register(options []byte) ([]byte, error) {
+	var parsed creationOptions
+	if err := json.Unmarshal(options, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid creation options: %w", err)
+	}
+	userHandle, err := encoding.DecodeString(parsed.PublicKey.User.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user handle: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	cred := &credential{id: id, rpID: parsed.PublicKey.RP.ID, userHandle: userHandle, key: key}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Attested credential data: AAGUID (zero), credential ID length, credential ID, public key
+	attested := make([]byte, 16, 16+2+len(id)+len(publicKey))
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(id)))
+	attested = append(attested, id...)
+	attested = append(attested, publicKey...)
+	authData := cred.authenticatorData(flagAttestedCredentialData, attested)
+
+	attestationObject, err := webauthncbor.Marshal(struct {
+		Format       string         `cbor:"fmt"`
+		AttStatement map[string]any `cbor:"attStmt"`
+		AuthData     []byte         `cbor:"authData"`
+	}{Format: "none", AttStatement: map[string]any{}, AuthData: authData})
+	if err != nil {
+		return nil, err
+	}
+
+	clientData, err := a.clientData("webauthn.create", parsed.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.credentials = append(a.credentials, cred)
+	return json.Marshal(map[string]any{
+		"id":                      encoding.EncodeToString(id),
+		"rawId":                   encoding.EncodeToString(id),
+		"type":                    "public-key",
+		"authenticatorAttachment": "platform",
+		"response": map[string]any{
+			"clientDataJSON":    encoding.EncodeToString(clientData),
+			"attestationObject": encoding.EncodeToString(attestationObject),
+			"transports":        []string{"internal", "hybrid"},
+		},
+	})
+}
+
+// Login signs the challenge of the request options (the JSON passed to navigator.credentials.get)
+// with the most recent credential of the relying party and returns the assertion response as JSON
+func (a *Authenticator) Login(options []byte) ([]byte, error) {
+	var parsed requestOptions
+	if err := json.Unmarshal(options, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid request options: %w", err)
+	}
+
+	var cred *credential
+	for _, c := range a.credentials {
+		if c.rpID == parsed.PublicKey.RPID {
+			cred = c
+		}
+	}
+	if cred == nil {
+		return nil, errors.New("no credential for the relying party")
+	}
+
+	cred.signCount++
+	authData := cred.authenticatorData(0, nil)
+	clientData, err := a.clientData("webauthn.get", parsed.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":                      encoding.EncodeToString(cred.id),
+		"rawId":                   encoding.EncodeToString(cred.id),
+		"type":                    "public-key",
+		"authenticatorAttachment": "platform",
+		"response": map[string]any{
+			"clientDataJSON":    encoding.EncodeToString(clientData),
+			"authenticatorData": encoding.EncodeToString(authData),
+			"signature":         encoding.EncodeToString(signature),
+			"userHandle":        encoding.EncodeToString(cred.userHandle),
+		},
+	})
+}
+
+// authenticatorData encodes the RP ID hash, the flags, the signature counter and extra data.
+// The user is always present and verified, and the credential is synced like a passkey.
+func (c *credential) authenticatorData(flags byte, extra []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(c.rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags|flagUserPresent|flagUserVerified|flagBackupEligible|flagBackupState)
+	data = binary.BigEndian.AppendUint32(data, c.signCount)
+	return append(data, extra...)
+}
+
+// clientData returns the CollectedClientData JSON a browser would produce
+func (a *Authenticator) clientData(ceremonyType, challenge string) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        ceremonyType,
+		"challenge":   challenge,
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+}
@@ -0,0 +1,107 @@
+package totp_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 secret of the RFC 6238 test vectors, "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestCode checks the RFC 6238 test vectors, truncated to 6 digits
+func TestCode(t *testing.T) {
+	testCases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			code, err := totp.Code(rfcSecret, totp.Step(time.Unix(tc.unix, 0)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, code)
+		})
+	}
+}
+
+func TestCode_InvalidSecret(t *testing.T) {
+	// Act
+	_, err := totp.Code("not base32!", 1)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totp.Step(now)
+	codeAt := func(step int64) string {
+		code, err := totp.Code(rfcSecret, step)
+		require.NoError(t, err)
+		return code
+	}
+
+	testCases := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current period", code: codeAt(current), wantStep: current, wantOK: true},
+		{name: "previous period", code: codeAt(current - 1), wantStep: current - 1, wantOK: true},
+		{name: "next period", code: codeAt(current + 1), wantStep: current + 1, wantOK: true},
+		{name: "too old", code: codeAt(current - 2)},
+		{name: "wrong code", code: "000000"},
+		{name: "wrong length", code: "12345"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := totp.Validate(rfcSecret, tc.code, now)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantStep, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	// Act
+	first, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	second, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	// Assert
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+	_, err = totp.Code(first, 1)
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	// Act
+	uri := totp.URI("Subs Service", "user@example.com", rfcSecret)
+
+	// Assert
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Subs Service:user@example.com", parsed.Path)
+	assert.Equal(t, rfcSecret, parsed.Query().Get("secret"))
+	assert.Equal(t, "Subs Service", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
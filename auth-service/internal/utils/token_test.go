@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	// Act
+	first, err := GenerateOpaqueToken("pat_")
+	require.NoError(t, err)
+	second, err := GenerateOpaqueToken("pat_")
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(first, "pat_"))
+	assert.Len(t, first, len("pat_")+43)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashToken(t *testing.T) {
+	// Act
+	hash := HashToken("pat_example")
+
+	// Assert
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashToken("pat_example"))
+	assert.NotEqual(t, hash, HashToken("pat_other"))
+}
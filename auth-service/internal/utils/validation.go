@@ -1,45 +1,14 @@
 package utils
 
 import (
-	"unicode"
-
+	"github.com/Koshsky/subs-service/auth-service/internal/passwords"
 	"github.com/go-playground/validator/v10"
 )
 
-// ValidatePassword validates password complexity requirements
+// ValidatePassword validates password complexity requirements of the default password policy
 func ValidatePassword(fl validator.FieldLevel) bool {
-	password := fl.Field().String()
-
-	// Check minimum length
-	if len(password) < 10 || len(password) > 72 {
-		return false
-	}
-
-	// Check for lowercase letters
-	hasLower := false
-	// Check for uppercase letters
-	hasUpper := false
-	// Check for special characters
-	hasSpecial := false
-	// check for numbers
-	hasNumber := false
-
-	for _, char := range password {
-		if unicode.IsLower(char) {
-			hasLower = true
-		}
-		if unicode.IsUpper(char) {
-			hasUpper = true
-		}
-		if !unicode.IsLetter(char) && !unicode.IsNumber(char) {
-			hasSpecial = true
-		}
-		if unicode.IsNumber(char) {
-			hasNumber = true
-		}
-	}
-
-	return hasLower && hasUpper && hasSpecial && hasNumber
+	policy := passwords.DefaultPolicy()
+	return policy.Check(fl.Field().String()) == nil
 }
 
 // RegisterCustomValidations registers custom validations
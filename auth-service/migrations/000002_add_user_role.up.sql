@@ -0,0 +1,4 @@
+-- Auth Service Database: user roles for authorization
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'support', 'admin'));
@@ -0,0 +1,17 @@
+-- Auth Service Database: refresh tokens (only SHA-256 hashes are stored)
+-- Every login starts a token family; each refresh rotates the token within its family
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for revoking a whole token family on reuse
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+-- Index for revoking all tokens of a user
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
@@ -0,0 +1,14 @@
+-- Auth Service Database: JWT revocation
+-- Bumping token_version invalidates every JWT issued to the user before
+ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
+
+-- JWTs revoked before their expiry, by jti; rows can be purged once expires_at has passed
+CREATE TABLE revoked_tokens (
+    jti UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for purging expired entries
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,12 @@
+-- Auth Service Database: password reset tokens (only SHA-256 hashes are stored)
+CREATE TABLE password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for throttling requests and invalidating outstanding tokens of a user
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id, created_at);
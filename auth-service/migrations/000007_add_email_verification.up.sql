@@ -0,0 +1,17 @@
+-- Auth Service Database: email address verification
+-- Accounts created before verification was introduced are treated as verified
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+UPDATE users SET email_verified_at = created_at;
+
+-- Email verification tokens (only SHA-256 hashes are stored)
+CREATE TABLE email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for throttling verification emails of a user
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens(user_id, created_at);
@@ -0,0 +1,3 @@
+-- Auth Service Database: email change
+-- Address confirmed by the token; differs from users.email for a pending email change
+ALTER TABLE email_verification_tokens ADD COLUMN email VARCHAR(255);
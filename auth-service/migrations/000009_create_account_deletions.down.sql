@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS account_deletion_acks;
+DROP TABLE IF EXISTS account_deletions;
+DROP INDEX IF EXISTS idx_users_email_active;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
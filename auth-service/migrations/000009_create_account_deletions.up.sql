@@ -0,0 +1,24 @@
+-- Auth Service Database: account deletion saga
+-- Deleted users are soft-deleted, so their email can be registered again
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+
+-- One row per deleted account, completed once every service acknowledged the deletion
+CREATE TABLE account_deletions (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_published_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Services that deleted the data of the account
+CREATE TABLE account_deletion_acks (
+    user_id UUID NOT NULL REFERENCES account_deletions(user_id) ON DELETE CASCADE,
+    service VARCHAR(50) NOT NULL,
+    acknowledged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, service)
+);
+
+-- Index for finding deletions to retry
+CREATE INDEX idx_account_deletions_pending ON account_deletions(last_published_at) WHERE completed_at IS NULL;
@@ -0,0 +1,14 @@
+-- Auth Service Database: brute-force protection of login
+-- Failed login attempts per email (scope 'account') and per client IP (scope 'ip').
+-- Emails are tracked whether or not they are registered, so lockouts do not reveal accounts.
+CREATE TABLE login_failures (
+    scope VARCHAR(10) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (scope, subject)
+);
+
+-- Index for purging records of old failures
+CREATE INDEX idx_login_failures_last_failed_at ON login_failures(last_failed_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_two_factor_challenges_expires_at;
+DROP TABLE IF EXISTS two_factor_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS totp_credentials;
@@ -0,0 +1,31 @@
+-- Auth Service Database: passkeys (WebAuthn credentials) for passwordless login
+CREATE TABLE passkey_credentials (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    attestation_type VARCHAR(32) NOT NULL DEFAULT '',
+    aaguid BYTEA,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports VARCHAR(255) NOT NULL DEFAULT '',
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for listing the passkeys of a user
+CREATE INDEX idx_passkey_credentials_user_id ON passkey_credentials(user_id);
+
+-- State of registrations and logins between their begin and finish steps (only SHA-256 hashes are stored)
+CREATE TABLE passkey_ceremonies (
+    token_hash CHAR(64) PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    session_data TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Index for purging expired ceremonies
+CREATE INDEX idx_passkey_ceremonies_expires_at ON passkey_ceremonies(expires_at);
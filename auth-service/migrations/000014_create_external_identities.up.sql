@@ -0,0 +1,16 @@
+-- Auth Service Database: accounts at external OpenID Connect identity providers linked to users
+CREATE TABLE external_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A provider account is linked to one user only
+CREATE UNIQUE INDEX idx_external_identities_issuer_subject ON external_identities(issuer, subject);
+
+-- Index for finding the identities of a user
+CREATE INDEX idx_external_identities_user_id ON external_identities(user_id);
@@ -0,0 +1,18 @@
+-- Auth Service Database: normalized emails
+-- Accounts are looked up and kept unique by email_normalized, the lowercase address with the
+-- provider-specific dot and +tag rules of auth-service applied. The rules are configured in
+-- auth-service, which applies them to existing accounts before serving and refuses to start
+-- when they give several accounts the same email; this migration only lowercases.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE deleted_at IS NULL GROUP BY lower(btrim(email)) HAVING count(*) > 1) THEN
+        RAISE EXCEPTION 'users have emails that differ only in case, merge these accounts before migrating';
+    END IF;
+END $$;
+
+ALTER TABLE users ADD COLUMN email_normalized VARCHAR(255);
+UPDATE users SET email_normalized = lower(btrim(email));
+ALTER TABLE users ALTER COLUMN email_normalized SET NOT NULL;
+
+-- Like idx_users_email_active, deleted accounts do not keep their email taken
+CREATE UNIQUE INDEX idx_users_email_normalized ON users(email_normalized) WHERE deleted_at IS NULL;
@@ -0,0 +1,28 @@
+CREATE TABLE password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id, created_at);
+
+CREATE TABLE email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    email VARCHAR(255)
+);
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens(user_id, created_at);
+
+INSERT INTO password_reset_tokens (id, user_id, token_hash, created_at, expires_at, used_at)
+SELECT id, user_id, token_hash, created_at, expires_at, used_at FROM single_use_tokens WHERE purpose = 'password_reset';
+
+INSERT INTO email_verification_tokens (id, user_id, email, token_hash, created_at, expires_at, used_at)
+SELECT id, user_id, email, token_hash, created_at, expires_at, used_at FROM single_use_tokens WHERE purpose = 'email_verification';
+
+DROP TABLE IF EXISTS single_use_tokens;
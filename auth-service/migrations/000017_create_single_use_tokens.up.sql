@@ -0,0 +1,25 @@
+-- Auth Service Database: single-use tokens sent by email (only SHA-256 hashes are stored)
+-- Password reset and email verification tokens share this table and are told apart by purpose.
+-- email is the address an email verification token confirms.
+CREATE TABLE single_use_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL,
+    email VARCHAR(255),
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Index for throttling requests and invalidating outstanding tokens of a user
+CREATE INDEX idx_single_use_tokens_user_id ON single_use_tokens(user_id, purpose, created_at);
+
+INSERT INTO single_use_tokens (id, user_id, purpose, token_hash, created_at, expires_at, used_at)
+SELECT id, user_id, 'password_reset', token_hash, created_at, expires_at, used_at FROM password_reset_tokens;
+
+INSERT INTO single_use_tokens (id, user_id, purpose, email, token_hash, created_at, expires_at, used_at)
+SELECT id, user_id, 'email_verification', email, token_hash, created_at, expires_at, used_at FROM email_verification_tokens;
+
+DROP TABLE password_reset_tokens;
+DROP TABLE email_verification_tokens;
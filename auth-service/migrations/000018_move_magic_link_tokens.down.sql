@@ -0,0 +1,14 @@
+CREATE TABLE magic_link_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_magic_link_tokens_user_id ON magic_link_tokens(user_id, created_at);
+
+INSERT INTO magic_link_tokens (id, user_id, token_hash, created_at, expires_at, used_at)
+SELECT id, user_id, token_hash, created_at, expires_at, used_at FROM single_use_tokens WHERE purpose = 'magic_link';
+
+DELETE FROM single_use_tokens WHERE purpose = 'magic_link';
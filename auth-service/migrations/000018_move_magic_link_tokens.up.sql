@@ -0,0 +1,5 @@
+-- Auth Service Database: magic link tokens move to single_use_tokens
+INSERT INTO single_use_tokens (id, user_id, purpose, token_hash, created_at, expires_at, used_at)
+SELECT id, user_id, 'magic_link', token_hash, created_at, expires_at, used_at FROM magic_link_tokens;
+
+DROP TABLE magic_link_tokens;
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations of auth-service
+// so the service binary can apply them without external tooling.
+package migrations
+
+import "embed"
+
+// FS holds the *.up.sql and *.down.sql files of this directory
+//
+//go:embed *.sql
+var FS embed.FS
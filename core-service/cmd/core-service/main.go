@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -9,29 +11,46 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Koshsky/subs-service/core-service/internal/cache"
 	"github.com/Koshsky/subs-service/core-service/internal/config"
+	"github.com/Koshsky/subs-service/core-service/internal/controllers"
+	"github.com/Koshsky/subs-service/core-service/internal/middleware"
 	"github.com/Koshsky/subs-service/core-service/internal/repositories"
 	"github.com/Koshsky/subs-service/core-service/internal/router"
 	"github.com/Koshsky/subs-service/core-service/internal/services"
+	"github.com/Koshsky/subs-service/core-service/migrations"
+	"github.com/Koshsky/subs-service/pkg/migrator"
+	"gorm.io/gorm"
 )
 
 func main() {
+	// migrate loads only the database settings, so it runs without the service configuration
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if config.LoadStorageBackend() != config.StorageBackendPostgres {
+			log.Fatalf("Migrations are only supported for the %s storage backend", config.StorageBackendPostgres)
+		}
+		if err := runMigrate(config.LoadDBConfig(), os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
 	cfg := config.LoadConfig()
 
-	database, err := cfg.ConnectDB()
-	if err != nil {
-		log.Fatalf("Failed to connect to core database: %v", err)
+	if cfg.CheckSchemaVersion && cfg.StorageBackend == config.StorageBackendPostgres {
+		if err := checkSchemaVersion(cfg); err != nil {
+			log.Fatalf("Refusing to start: %v", err)
+		}
 	}
 
-	sqlDB, err := database.DB()
+	subRepo, closeStore, err := setupStore(cfg)
 	if err != nil {
-		log.Fatalf("Failed to get core database handle: %v", err)
+		log.Fatalf("Failed to set up %s storage: %v", cfg.StorageBackend, err)
 	}
-	defer func() {
-		if cerr := sqlDB.Close(); cerr != nil {
-			log.Printf("Error closing core database: %v", cerr)
-		}
-	}()
+	defer closeStore()
+
+	stopDeletions := startDeletionConsumer(cfg, subRepo)
+	defer stopDeletions()
 
 	authClient, err := services.NewAuthClient(cfg.AuthServiceAddr, cfg.EnableTLS, cfg.TLSCertFile)
 	if err != nil {
@@ -39,10 +58,21 @@ func main() {
 	}
 	defer authClient.Close()
 
-	subRepo := repositories.NewSubscriptionRepository(database)
 	subService := services.NewSubscriptionService(subRepo)
 
-	r := router.SetupRouter(subService, authClient, authClient.ValidateToken)
+	validateToken, stopTokenValidation := setupTokenValidation(cfg, authClient)
+	defer stopTokenValidation()
+
+	// Single sign-on is only offered when an identity provider is configured
+	var oidcProvider controllers.OIDCProvider
+	if cfg.OIDC.Enabled() {
+		oidcProvider = services.NewOIDCProvider(cfg.OIDC)
+	}
+
+	r, err := router.SetupRouter(subService, authClient, authClient, authClient, authClient, oidcProvider, validateToken, cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Failed to set up router: %v", err)
+	}
 
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -72,3 +102,163 @@ func main() {
 	}
 	log.Println("Server stopped gracefully")
 }
+
+// setupStore creates the subscription store selected by configuration
+// and returns a function releasing its resources
+func setupStore(cfg *config.Config) (repositories.SubscriptionStore, func(), error) {
+	switch cfg.StorageBackend {
+	case config.StorageBackendMemory:
+		log.Printf("Using in-memory storage, data will be lost on restart")
+		return repositories.NewMemorySubscriptionRepository(), func() {}, nil
+	case config.StorageBackendSQLite:
+		repo, err := repositories.NewSQLiteSubscriptionRepository(cfg.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, closeDB(repo.DB), nil
+	default:
+		database, err := cfg.ConnectDB()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to core database: %w", err)
+		}
+		return repositories.NewSubscriptionRepository(database), closeDB(database), nil
+	}
+}
+
+// setupTokenValidation wraps token validation in a cache unless it is disabled and, in local
+// verification mode, in a verifier that checks asymmetric JWTs in-process.
+// Revocation events drop cached entries and fill the deny list of the verifier. Without RabbitMQ
+// revocations only take effect once cached entries expire, and local verification is disabled
+// because it could not see revocations at all.
+func setupTokenValidation(cfg *config.Config, authClient *services.AuthClient) (middleware.ValidateTokenFunc, func()) {
+	validateToken := middleware.ValidateTokenFunc(authClient.ValidateToken)
+
+	var tokenCache *cache.TokenCache
+	if cfg.TokenCache.TTL > 0 {
+		tokenCache = cache.NewTokenCache(authClient.ValidateToken, cfg.TokenCache.TTL, cfg.TokenCache.MaxEntries)
+		expvar.Publish("token_cache", expvar.Func(func() any { return tokenCache.Stats() }))
+		validateToken = tokenCache.ValidateToken
+	}
+
+	var denyList *cache.DenyList
+	if cfg.TokenVerification.Mode == config.TokenVerificationLocal {
+		denyList = cache.NewDenyList(cfg.TokenVerification.MaxTokenLifetime)
+	}
+
+	if tokenCache == nil && denyList == nil {
+		return validateToken, func() {}
+	}
+
+	consumer, err := services.NewRevocationConsumer(cfg.RabbitMQ, func(event services.TokenRevokedEvent) {
+		if tokenCache != nil {
+			tokenCache.Invalidate(event.UserID, event.SessionID, event.TokenHash)
+		}
+		if denyList != nil {
+			denyList.Revoke(event.UserID, event.SessionID, event.TokenHash, event.JTI, event.RevokedAt)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to subscribe to token revocations: %v", err)
+		if tokenCache != nil {
+			log.Printf("Cached tokens will stay valid for up to %s after revocation", cfg.TokenCache.TTL)
+		}
+		if denyList != nil {
+			log.Printf("Local token verification is disabled, every token is validated by the auth service")
+		}
+		return validateToken, func() {}
+	}
+
+	go func() {
+		if err := consumer.StartConsuming(); err != nil {
+			log.Printf("Token revocation consumer stopped: %v", err)
+		}
+	}()
+
+	if denyList == nil {
+		return validateToken, consumer.Close
+	}
+
+	validateToken, stopVerifier := setupLocalVerification(cfg, authClient, validateToken, denyList)
+	return validateToken, func() {
+		stopVerifier()
+		consumer.Close()
+	}
+}
+
+// startDeletionConsumer deletes the subscriptions of deleted accounts on user.deleted events.
+// Without RabbitMQ the subscriptions stay until auth-service republishes the deletion.
+func startDeletionConsumer(cfg *config.Config, subRepo repositories.SubscriptionStore) func() {
+	consumer, err := services.NewDeletionConsumer(cfg.RabbitMQ, subRepo.DeleteUserSubscriptions)
+	if err != nil {
+		log.Printf("Warning: Failed to subscribe to account deletions: %v", err)
+		return func() {}
+	}
+
+	go func() {
+		if err := consumer.StartConsuming(); err != nil {
+			log.Printf("Account deletion consumer stopped: %v", err)
+		}
+	}()
+
+	return consumer.Close
+}
+
+// setupLocalVerification verifies asymmetrically signed JWTs in-process with keys
+// fetched from auth-service and refreshed periodically, and refuses those in denyList.
+// Other tokens, and all tokens until denyList has been filled for the longest JWT lifetime,
+// still go to remoteValidate.
+func setupLocalVerification(cfg *config.Config, authClient *services.AuthClient, remoteValidate middleware.ValidateTokenFunc, denyList *cache.DenyList) (middleware.ValidateTokenFunc, func()) {
+	verifier := services.NewJWTVerifier(authClient.GetJWKS, services.TokenValidator(remoteValidate), denyList)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := verifier.Refresh(ctx); err != nil {
+		log.Printf("Warning: Failed to fetch JWKS from auth service: %v", err)
+		log.Printf("Keys will be fetched again when the first token arrives")
+	}
+	if cfg.TokenVerification.JWKSRefreshInterval > 0 {
+		go verifier.Run(ctx, cfg.TokenVerification.JWKSRefreshInterval)
+	}
+
+	return verifier.ValidateToken, cancel
+}
+
+// closeDB returns a function closing the connection pool behind database
+func closeDB(database *gorm.DB) func() {
+	return func() {
+		sqlDB, err := database.DB()
+		if err != nil {
+			log.Printf("Failed to get core database handle: %v", err)
+			return
+		}
+		if cerr := sqlDB.Close(); cerr != nil {
+			log.Printf("Error closing core database: %v", cerr)
+		}
+	}
+}
+
+// runMigrate executes the migrate subcommand against the core database
+func runMigrate(db config.DBConfig, args []string) error {
+	if _, _, err := migrator.ParseArgs(args); err != nil {
+		return err
+	}
+
+	m, err := migrator.New(db.ConnectionString(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Run(args, os.Stdout)
+}
+
+// checkSchemaVersion verifies that the core database is migrated to the
+// version embedded in this binary
+func checkSchemaVersion(cfg *config.Config) error {
+	m, err := migrator.New(cfg.Database.ConnectionString(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.CheckVersion()
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// DenyList remembers revocations for tokens that core-service verifies itself.
+//
+// A JWT verified locally is never shown to auth-service, so revocations reach core-service
+// only as events: single tokens are denied by jti, login sessions by sid and users by a
+// not-before issued-at. Entries are kept for retention, the longest lifetime of a JWT,
+// after which every token they deny has expired anyway.
+type DenyList struct {
+	retention time.Duration
+	now       func() time.Time
+	since     time.Time
+
+	mu       sync.Mutex
+	tokens   map[string]time.Time // jti -> entry expiry
+	sessions map[string]time.Time // sid -> entry expiry
+	users    map[string]userDenial
+}
+
+// userDenial refuses the tokens of a user issued before notBefore
+type userDenial struct {
+	notBefore time.Time
+	expiresAt time.Time
+}
+
+// NewDenyList creates an empty deny list keeping revocations for retention
+func NewDenyList(retention time.Duration) *DenyList {
+	return &DenyList{
+		retention: retention,
+		now:       time.Now,
+		since:     time.Now(),
+		tokens:    make(map[string]time.Time),
+		sessions:  make(map[string]time.Time),
+		users:     make(map[string]userDenial),
+	}
+}
+
+// Complete reports whether the list has been filled for a whole retention period.
+// Before that, tokens may still be valid whose revocation happened before the list existed.
+func (d *DenyList) Complete() bool {
+	return d.now().Sub(d.since) >= d.retention
+}
+
+// Revoke records a revocation: the token with jti or, when jti is empty, the tokens of the
+// session with sessionID or, when both are empty, the tokens of the user issued before revokedAt.
+// Tokens named only by tokenHash are personal access tokens, which are never verified locally.
+func (d *DenyList) Revoke(userID, sessionID, tokenHash, jti string, revokedAt time.Time) {
+	if jti == "" && tokenHash != "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	d.purge(now)
+	expiresAt := now.Add(d.retention)
+
+	switch {
+	case jti != "":
+		d.tokens[jti] = expiresAt
+	case sessionID != "":
+		d.sessions[sessionID] = expiresAt
+	case userID != "":
+		if denial, ok := d.users[userID]; ok && denial.notBefore.After(revokedAt) {
+			revokedAt = denial.notBefore
+		}
+		d.users[userID] = userDenial{notBefore: revokedAt, expiresAt: expiresAt}
+	}
+}
+
+// IsRevoked reports whether a token with the given claims was revoked.
+// Tokens without an issued-at are treated as issued before any revocation of their user.
+func (d *DenyList) IsRevoked(userID, sessionID, jti string, issuedAt time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if expiresAt, ok := d.tokens[jti]; ok && jti != "" && now.Before(expiresAt) {
+		return true
+	}
+	if expiresAt, ok := d.sessions[sessionID]; ok && sessionID != "" && now.Before(expiresAt) {
+		return true
+	}
+	// iat has a precision of seconds, tokens issued in the second of the revocation are accepted
+	// so that a login right after signing out everywhere is not refused
+	if denial, ok := d.users[userID]; ok && now.Before(denial.expiresAt) {
+		return issuedAt.Unix() < denial.notBefore.Unix()
+	}
+	return false
+}
+
+// purge drops entries past their retention, d.mu must be held
+func (d *DenyList) purge(now time.Time) {
+	for jti, expiresAt := range d.tokens {
+		if !now.Before(expiresAt) {
+			delete(d.tokens, jti)
+		}
+	}
+	for sid, expiresAt := range d.sessions {
+		if !now.Before(expiresAt) {
+			delete(d.sessions, sid)
+		}
+	}
+	for userID, denial := range d.users {
+		if !now.Before(denial.expiresAt) {
+			delete(d.users, userID)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DenyListTestSuite struct {
+	suite.Suite
+	now      time.Time
+	issuedAt time.Time
+	list     *DenyList
+}
+
+func (suite *DenyListTestSuite) SetupTest() {
+	suite.now = time.Unix(1767225600, 0)
+	suite.issuedAt = suite.now.Add(-time.Minute)
+	suite.list = NewDenyList(15 * time.Minute)
+	suite.list.now = func() time.Time { return suite.now }
+	suite.list.since = suite.now
+}
+
+func (suite *DenyListTestSuite) TestIsRevoked_EmptyList() {
+	suite.False(suite.list.IsRevoked("alice", "s1", "jti-1", suite.issuedAt))
+}
+
+func (suite *DenyListTestSuite) TestRevoke_SingleToken() {
+	// Act
+	suite.list.Revoke("alice", "", "hash-1", "jti-1", time.Time{})
+
+	// Assert
+	suite.True(suite.list.IsRevoked("alice", "s1", "jti-1", suite.issuedAt))
+	suite.False(suite.list.IsRevoked("alice", "s1", "jti-2", suite.issuedAt))
+}
+
+func (suite *DenyListTestSuite) TestRevoke_IgnoresPersonalAccessTokens() {
+	// Act
+	suite.list.Revoke("alice", "", "hash-1", "", time.Time{})
+
+	// Assert
+	suite.False(suite.list.IsRevoked("alice", "s1", "jti-1", suite.issuedAt))
+	suite.Empty(suite.list.users)
+}
+
+func (suite *DenyListTestSuite) TestRevoke_Session() {
+	// Act
+	suite.list.Revoke("alice", "s1", "", "", time.Time{})
+
+	// Assert
+	suite.True(suite.list.IsRevoked("alice", "s1", "jti-1", suite.issuedAt))
+	suite.False(suite.list.IsRevoked("alice", "s2", "jti-2", suite.issuedAt))
+	suite.False(suite.list.IsRevoked("alice", "", "jti-3", suite.issuedAt))
+}
+
+func (suite *DenyListTestSuite) TestRevoke_UserRefusesTokensIssuedBefore() {
+	// Act
+	suite.list.Revoke("alice", "", "", "", suite.now)
+
+	// Assert
+	suite.True(suite.list.IsRevoked("alice", "s1", "jti-1", suite.issuedAt))
+	suite.True(suite.list.IsRevoked("alice", "", "jti-2", time.Time{}), "tokens without iat predate the revocation")
+	suite.False(suite.list.IsRevoked("alice", "s2", "jti-3", suite.now), "login right after the revocation")
+	suite.False(suite.list.IsRevoked("alice", "s2", "jti-4", suite.now.Add(time.Second)))
+	suite.False(suite.list.IsRevoked("bob", "s3", "jti-5", suite.issuedAt))
+}
+
+func (suite *DenyListTestSuite) TestRevoke_UserKeepsLatestRevocation() {
+	// Arrange
+	suite.list.Revoke("alice", "", "", "", suite.now)
+
+	// Act - a delayed event of an earlier revocation
+	suite.list.Revoke("alice", "", "", "", suite.now.Add(-10*time.Minute))
+
+	// Assert
+	suite.True(suite.list.IsRevoked("alice", "s1", "jti-1", suite.issuedAt))
+}
+
+func (suite *DenyListTestSuite) TestRevoke_ExpiresAfterRetention() {
+	// Arrange
+	suite.list.Revoke("alice", "", "hash-1", "jti-1", time.Time{})
+	suite.list.Revoke("alice", "s1", "", "", time.Time{})
+	suite.list.Revoke("bob", "", "", "", suite.now)
+
+	// Act
+	suite.now = suite.now.Add(15 * time.Minute)
+	suite.list.Revoke("carol", "", "hash-9", "jti-9", time.Time{})
+
+	// Assert
+	suite.False(suite.list.IsRevoked("alice", "s1", "jti-1", suite.issuedAt))
+	suite.False(suite.list.IsRevoked("bob", "", "jti-2", suite.issuedAt))
+	suite.Empty(suite.list.sessions)
+	suite.Empty(suite.list.users)
+	suite.Len(suite.list.tokens, 1)
+}
+
+func (suite *DenyListTestSuite) TestComplete_AfterRetention() {
+	suite.False(suite.list.Complete())
+
+	suite.now = suite.now.Add(15 * time.Minute)
+
+	suite.True(suite.list.Complete())
+}
+
+func TestDenyListTestSuite(t *testing.T) {
+	suite.Run(t, new(DenyListTestSuite))
+}
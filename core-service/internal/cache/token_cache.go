@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+)
+
+// ValidateFunc validates a token against auth-service
+type ValidateFunc func(ctx context.Context, token string) (*corepbv2.ValidateTokenResponse, error)
+
+// Stats are the counters of a TokenCache
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+}
+
+// TokenCache caches successful token validations.
+//
+// Entries are keyed by the SHA-256 hash of the token, so raw tokens are never kept in memory,
+// and live for the configured TTL but never past the token's own expiry.
+// When the cache is full the least recently used entry is evicted.
+// Failed validations are not cached.
+type TokenCache struct {
+	validate   ValidateFunc
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	byUser  map[string]map[string]struct{}
+	// bySession indexes JWT entries by login session, personal access tokens have none
+	bySession map[string]map[string]struct{}
+	// generation counts Invalidate calls. A validation that was in flight during one may
+	// predate the revocation, so its result is not cached.
+	generation atomic.Uint64
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	evictions     atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+type cacheEntry struct {
+	key       string
+	resp      *corepbv2.ValidateTokenResponse
+	expiresAt time.Time
+}
+
+// NewTokenCache wraps validate with a cache of at most maxEntries entries
+func NewTokenCache(validate ValidateFunc, ttl time.Duration, maxEntries int) *TokenCache {
+	return &TokenCache{
+		validate:   validate,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		byUser:     make(map[string]map[string]struct{}),
+		bySession:  make(map[string]map[string]struct{}),
+	}
+}
+
+// HashToken returns the cache key of a token, the hex-encoded SHA-256 also used by auth-service
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateToken returns a cached validation result or asks auth-service.
+// The returned response is shared and must not be modified.
+func (c *TokenCache) ValidateToken(ctx context.Context, token string) (*corepbv2.ValidateTokenResponse, error) {
+	key := HashToken(token)
+	if resp, ok := c.get(key); ok {
+		c.hits.Add(1)
+		return resp, nil
+	}
+	c.misses.Add(1)
+
+	generation := c.generation.Load()
+	resp, err := c.validate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, resp, generation)
+	return resp, nil
+}
+
+// Invalidate drops the cached validation of the token with tokenHash or, when tokenHash
+// is empty, of all tokens of the session with sessionID or, when both are empty,
+// of all tokens of the user
+func (c *TokenCache) Invalidate(userID, sessionID, tokenHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation.Add(1)
+
+	if tokenHash != "" {
+		if elem, ok := c.entries[tokenHash]; ok {
+			c.remove(elem)
+			c.invalidations.Add(1)
+		}
+		return
+	}
+
+	if sessionID != "" {
+		for key := range c.bySession[sessionID] {
+			c.remove(c.entries[key])
+			c.invalidations.Add(1)
+		}
+		return
+	}
+
+	for key := range c.byUser[userID] {
+		c.remove(c.entries[key])
+		c.invalidations.Add(1)
+	}
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *TokenCache) Stats() Stats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Evictions:     c.evictions.Load(),
+		Invalidations: c.invalidations.Load(),
+		Entries:       entries,
+	}
+}
+
+func (c *TokenCache) get(key string) (*corepbv2.ValidateTokenResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.resp, true
+}
+
+// put caches resp unless Invalidate was called since generation was read
+func (c *TokenCache) put(key string, resp *corepbv2.ValidateTokenResponse, generation uint64) {
+	now := c.now()
+	expiresAt := now.Add(c.ttl)
+	if resp.ExpiresAt > 0 {
+		if tokenExpiry := time.Unix(resp.ExpiresAt, 0); tokenExpiry.Before(expiresAt) {
+			expiresAt = tokenExpiry
+		}
+	}
+	if !now.Before(expiresAt) || c.maxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation.Load() != generation {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	for c.lru.Len() >= c.maxEntries {
+		c.remove(c.lru.Back())
+		c.evictions.Add(1)
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, resp: resp, expiresAt: expiresAt})
+	if c.byUser[resp.UserId] == nil {
+		c.byUser[resp.UserId] = make(map[string]struct{})
+	}
+	c.byUser[resp.UserId][key] = struct{}{}
+	if resp.SessionId != "" {
+		if c.bySession[resp.SessionId] == nil {
+			c.bySession[resp.SessionId] = make(map[string]struct{})
+		}
+		c.bySession[resp.SessionId][key] = struct{}{}
+	}
+}
+
+// remove deletes an entry from all indexes, c.mu must be held
+func (c *TokenCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+
+	userKeys := c.byUser[entry.resp.UserId]
+	delete(userKeys, entry.key)
+	if len(userKeys) == 0 {
+		delete(c.byUser, entry.resp.UserId)
+	}
+
+	if entry.resp.SessionId != "" {
+		sessionKeys := c.bySession[entry.resp.SessionId]
+		delete(sessionKeys, entry.key)
+		if len(sessionKeys) == 0 {
+			delete(c.bySession, entry.resp.SessionId)
+		}
+	}
+}
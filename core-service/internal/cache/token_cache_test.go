@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type TokenCacheTestSuite struct {
+	suite.Suite
+	calls     map[string]int
+	responses map[string]*corepbv2.ValidateTokenResponse
+	now       time.Time
+	cache     *TokenCache
+	ctx       context.Context
+}
+
+func (suite *TokenCacheTestSuite) SetupTest() {
+	suite.now = time.Unix(1767225600, 0)
+	suite.calls = map[string]int{}
+	suite.responses = map[string]*corepbv2.ValidateTokenResponse{
+		"alice-1":  {UserId: "alice", SessionId: "s1", ExpiresAt: suite.now.Add(time.Hour).Unix()},
+		"alice-2":  {UserId: "alice", SessionId: "s2", ExpiresAt: suite.now.Add(time.Hour).Unix()},
+		"alice-3":  {UserId: "alice", SessionId: "s1", ExpiresAt: suite.now.Add(time.Hour).Unix()},
+		"bob":      {UserId: "bob", ExpiresAt: suite.now.Add(time.Hour).Unix()},
+		"expiring": {UserId: "bob", ExpiresAt: suite.now.Add(10 * time.Second).Unix()},
+	}
+	suite.cache = suite.newCache(time.Minute, 10)
+	suite.ctx = context.Background()
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// newCache creates a cache over the fake validator using the suite clock
+func (suite *TokenCacheTestSuite) newCache(ttl time.Duration, maxEntries int) *TokenCache {
+	c := NewTokenCache(suite.validate, ttl, maxEntries)
+	c.now = func() time.Time { return suite.now }
+	return c
+}
+
+// validate is a fake ValidateFunc counting calls per token
+func (suite *TokenCacheTestSuite) validate(_ context.Context, token string) (*corepbv2.ValidateTokenResponse, error) {
+	suite.calls[token]++
+	if token == "invalid" {
+		return nil, status.Error(codes.Unauthenticated, "token is expired")
+	}
+	resp, ok := suite.responses[token]
+	if !ok {
+		return nil, errors.New("auth service unavailable")
+	}
+	return resp, nil
+}
+
+// validateTwice validates token twice and returns the second response
+func (suite *TokenCacheTestSuite) validateTwice(c *TokenCache, token string) *corepbv2.ValidateTokenResponse {
+	_, err := c.ValidateToken(suite.ctx, token)
+	suite.Require().NoError(err)
+	resp, err := c.ValidateToken(suite.ctx, token)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// ===== TESTS =====
+
+func (suite *TokenCacheTestSuite) TestValidateToken_CachesValidTokens() {
+	// Act
+	resp := suite.validateTwice(suite.cache, "alice-1")
+
+	// Assert
+	suite.Equal("alice", resp.UserId)
+	suite.Equal(1, suite.calls["alice-1"])
+	suite.Equal(Stats{Hits: 1, Misses: 1, Entries: 1}, suite.cache.Stats())
+}
+
+func (suite *TokenCacheTestSuite) TestValidateToken_DoesNotCacheInvalidTokens() {
+	// Act
+	_, firstErr := suite.cache.ValidateToken(suite.ctx, "invalid")
+	_, secondErr := suite.cache.ValidateToken(suite.ctx, "invalid")
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(firstErr))
+	suite.Equal(codes.Unauthenticated, status.Code(secondErr))
+	suite.Equal(2, suite.calls["invalid"])
+}
+
+func (suite *TokenCacheTestSuite) TestValidateToken_DoesNotCacheErrors() {
+	// Act
+	_, err := suite.cache.ValidateToken(suite.ctx, "unknown")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Zero(suite.cache.Stats().Entries)
+}
+
+func (suite *TokenCacheTestSuite) TestValidateToken_ExpiresAfterTTL() {
+	// Arrange
+	suite.validateTwice(suite.cache, "alice-1")
+
+	// Act
+	suite.now = suite.now.Add(time.Minute)
+	_, err := suite.cache.ValidateToken(suite.ctx, "alice-1")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(2, suite.calls["alice-1"])
+}
+
+func (suite *TokenCacheTestSuite) TestValidateToken_NeverOutlivesTokenExpiry() {
+	// Arrange
+	suite.validateTwice(suite.cache, "expiring")
+
+	// Act
+	suite.now = suite.now.Add(10 * time.Second)
+	_, err := suite.cache.ValidateToken(suite.ctx, "expiring")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(2, suite.calls["expiring"])
+}
+
+func (suite *TokenCacheTestSuite) TestValidateToken_EvictsLeastRecentlyUsed() {
+	// Arrange
+	c := suite.newCache(time.Minute, 2)
+	suite.validateTwice(c, "alice-1")
+	suite.validateTwice(c, "alice-2")
+	suite.validateTwice(c, "alice-1")
+
+	// Act
+	suite.validateTwice(c, "bob")
+	_, err := c.ValidateToken(suite.ctx, "alice-2")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(1, suite.calls["alice-1"])
+	suite.Equal(2, suite.calls["alice-2"])
+	suite.Equal(2, c.Stats().Entries)
+	suite.Equal(uint64(2), c.Stats().Evictions)
+}
+
+func (suite *TokenCacheTestSuite) TestInvalidate_SingleToken() {
+	// Arrange
+	suite.validateTwice(suite.cache, "alice-1")
+	suite.validateTwice(suite.cache, "alice-2")
+
+	// Act
+	suite.cache.Invalidate("alice", "", HashToken("alice-1"))
+
+	// Assert
+	suite.validateTwice(suite.cache, "alice-1")
+	suite.validateTwice(suite.cache, "alice-2")
+	suite.Equal(2, suite.calls["alice-1"])
+	suite.Equal(1, suite.calls["alice-2"])
+	suite.Equal(uint64(1), suite.cache.Stats().Invalidations)
+}
+
+func (suite *TokenCacheTestSuite) TestInvalidate_AllUserTokens() {
+	// Arrange
+	suite.validateTwice(suite.cache, "alice-1")
+	suite.validateTwice(suite.cache, "alice-2")
+	suite.validateTwice(suite.cache, "bob")
+
+	// Act
+	suite.cache.Invalidate("alice", "", "")
+
+	// Assert
+	suite.Equal(1, suite.cache.Stats().Entries)
+	suite.Equal(uint64(2), suite.cache.Stats().Invalidations)
+	suite.validateTwice(suite.cache, "bob")
+	suite.Equal(1, suite.calls["bob"])
+}
+
+func (suite *TokenCacheTestSuite) TestInvalidate_SessionTokens() {
+	// Arrange
+	suite.validateTwice(suite.cache, "alice-1")
+	suite.validateTwice(suite.cache, "alice-2")
+	suite.validateTwice(suite.cache, "alice-3")
+
+	// Act
+	suite.cache.Invalidate("alice", "s1", "")
+
+	// Assert
+	suite.Equal(1, suite.cache.Stats().Entries)
+	suite.Equal(uint64(2), suite.cache.Stats().Invalidations)
+	suite.validateTwice(suite.cache, "alice-2")
+	suite.Equal(1, suite.calls["alice-2"])
+}
+
+func (suite *TokenCacheTestSuite) TestInvalidate_UnknownUser() {
+	// Act & Assert
+	suite.NotPanics(func() {
+		suite.cache.Invalidate("nobody", "", "")
+		suite.cache.Invalidate("nobody", "missing", "")
+		suite.cache.Invalidate("nobody", "", "missing")
+	})
+	suite.Zero(suite.cache.Stats().Invalidations)
+}
+
+func (suite *TokenCacheTestSuite) TestInvalidate_DuringValidation() {
+	// Arrange - the validation of alice-1 is blocked in auth-service
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	c := NewTokenCache(func(ctx context.Context, token string) (*corepbv2.ValidateTokenResponse, error) {
+		started <- struct{}{}
+		<-release
+		return suite.validate(ctx, token)
+	}, time.Minute, 10)
+	c.now = func() time.Time { return suite.now }
+	done := make(chan error)
+	go func() {
+		_, err := c.ValidateToken(suite.ctx, "alice-1")
+		done <- err
+	}()
+	<-started
+
+	// Act - the user is revoked before the stale result arrives
+	c.Invalidate("alice", "", "")
+	close(release)
+	suite.Require().NoError(<-done)
+
+	// Assert - the next request asks auth-service again
+	_, err := c.ValidateToken(suite.ctx, "alice-1")
+	suite.Require().NoError(err)
+	suite.Equal(2, suite.calls["alice-1"])
+	suite.Equal(Stats{Misses: 2, Entries: 1}, c.Stats())
+}
+
+func TestTokenCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenCacheTestSuite))
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessTokenClient defines the personal access token operations of the auth client
+type AccessTokenClient interface {
+	CreateAccessToken(ctx context.Context, userID, name string, scopes []string, expiresInDays int32) (*corepbv2.CreateAccessTokenResponse, error)
+	ListAccessTokens(ctx context.Context, userID string) (*corepbv2.ListAccessTokensResponse, error)
+	RevokeAccessToken(ctx context.Context, userID, tokenID string) (*corepbv2.RevokeAccessTokenResponse, error)
+}
+
+// AccessTokenController manages personal access tokens of the current user
+type AccessTokenController struct {
+	TokenClient AccessTokenClient
+}
+
+func NewAccessTokenController(tokenClient AccessTokenClient) *AccessTokenController {
+	return &AccessTokenController{TokenClient: tokenClient}
+}
+
+// accessTokenView is the JSON representation of token metadata
+type accessTokenView struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  string   `json:"created_at"`
+	ExpiresAt  string   `json:"expires_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+}
+
+func newAccessTokenView(token *corepbv2.AccessToken) accessTokenView {
+	view := accessTokenView{
+		ID:        token.Id,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		CreatedAt: formatUnix(token.CreatedAt),
+		ExpiresAt: formatUnix(token.ExpiresAt),
+	}
+	if view.Scopes == nil {
+		view.Scopes = []string{}
+	}
+	if token.LastUsedAt > 0 {
+		lastUsedAt := formatUnix(token.LastUsedAt)
+		view.LastUsedAt = &lastUsedAt
+	}
+	return view
+}
+
+// formatUnix formats unix seconds as an RFC 3339 UTC timestamp
+func formatUnix(seconds int64) string {
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
+
+// Create issues a new personal access token.
+// The token is only shown in this response.
+func (c *AccessTokenController) Create(ctx *gin.Context) {
+	var req struct {
+		Name          string   `json:"name" binding:"required"`
+		Scopes        []string `json:"scopes" binding:"required"`
+		ExpiresInDays int32    `json:"expires_in_days"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "invalid request body",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	resp, err := c.TokenClient.CreateAccessToken(ctx.Request.Context(), ctx.GetString("user_id"), req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		writeAuthError(ctx, err, "failed to create access token")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"token":        resp.Token,
+		"access_token": newAccessTokenView(resp.AccessToken),
+	})
+}
+
+// List lists personal access tokens of the current user
+func (c *AccessTokenController) List(ctx *gin.Context) {
+	resp, err := c.TokenClient.ListAccessTokens(ctx.Request.Context(), ctx.GetString("user_id"))
+	if err != nil {
+		writeAuthError(ctx, err, "failed to list access tokens")
+		return
+	}
+
+	tokens := make([]accessTokenView, 0, len(resp.Tokens))
+	for _, token := range resp.Tokens {
+		tokens = append(tokens, newAccessTokenView(token))
+	}
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Revoke revokes a personal access token of the current user
+func (c *AccessTokenController) Revoke(ctx *gin.Context) {
+	_, err := c.TokenClient.RevokeAccessToken(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"))
+	if err != nil {
+		writeAuthError(ctx, err, "failed to revoke access token")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Access token revoked"})
+}
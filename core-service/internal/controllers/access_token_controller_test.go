@@ -0,0 +1,171 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/core-service/internal/controllers"
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAccessTokenClient is a controllers.AccessTokenClient recording the user it acts for
+type fakeAccessTokenClient struct {
+	userID         string
+	createRequest  *corepbv2.CreateAccessTokenRequest
+	createResponse *corepbv2.CreateAccessTokenResponse
+	listResponse   *corepbv2.ListAccessTokensResponse
+	revokeResponse *corepbv2.RevokeAccessTokenResponse
+	err            error
+}
+
+func (f *fakeAccessTokenClient) CreateAccessToken(_ context.Context, userID, name string, scopes []string, expiresInDays int32) (*corepbv2.CreateAccessTokenResponse, error) {
+	f.userID = userID
+	f.createRequest = &corepbv2.CreateAccessTokenRequest{UserId: userID, Name: name, Scopes: scopes, ExpiresInDays: expiresInDays}
+	return f.createResponse, f.err
+}
+
+func (f *fakeAccessTokenClient) ListAccessTokens(_ context.Context, userID string) (*corepbv2.ListAccessTokensResponse, error) {
+	f.userID = userID
+	return f.listResponse, f.err
+}
+
+func (f *fakeAccessTokenClient) RevokeAccessToken(_ context.Context, userID, _ string) (*corepbv2.RevokeAccessTokenResponse, error) {
+	f.userID = userID
+	return f.revokeResponse, f.err
+}
+
+type AccessTokenControllerTestSuite struct {
+	suite.Suite
+	client *fakeAccessTokenClient
+	router *gin.Engine
+}
+
+func (suite *AccessTokenControllerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *AccessTokenControllerTestSuite) SetupTest() {
+	suite.client = &fakeAccessTokenClient{}
+	controller := controllers.NewAccessTokenController(suite.client)
+
+	suite.router = gin.New()
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", "user-id")
+	})
+	suite.router.POST("/api/tokens", controller.Create)
+	suite.router.GET("/api/tokens", controller.List)
+	suite.router.DELETE("/api/tokens/:id", controller.Revoke)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// request performs a request against the controller routes
+func (suite *AccessTokenControllerTestSuite) request(method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// ===== TESTS =====
+
+func (suite *AccessTokenControllerTestSuite) TestCreate_Success() {
+	// Arrange
+	suite.client.createResponse = &corepbv2.CreateAccessTokenResponse{
+		Token: "pat_secret",
+		AccessToken: &corepbv2.AccessToken{
+			Id: "token-id", Name: "ci", Scopes: []string{"subscriptions:read"},
+			CreatedAt: 1767225600, ExpiresAt: 1775001600,
+		},
+	}
+
+	// Act
+	w := suite.request(http.MethodPost, "/api/tokens", `{"name":"ci","scopes":["subscriptions:read"],"expires_in_days":90}`)
+
+	// Assert
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.Equal("user-id", suite.client.userID)
+	suite.Equal(int32(90), suite.client.createRequest.ExpiresInDays)
+	suite.JSONEq(`{
+		"token": "pat_secret",
+		"access_token": {
+			"id": "token-id",
+			"name": "ci",
+			"scopes": ["subscriptions:read"],
+			"created_at": "2026-01-01T00:00:00Z",
+			"expires_at": "2026-04-01T00:00:00Z",
+			"last_used_at": null
+		}
+	}`, w.Body.String())
+}
+
+func (suite *AccessTokenControllerTestSuite) TestCreate_Rejected() {
+	// Arrange
+	st, err := status.New(codes.InvalidArgument, `unknown scope "bogus"`).WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "scopes", Reason: "INVALID", Description: `unknown scope "bogus"`},
+		},
+	})
+	suite.Require().NoError(err)
+	suite.client.err = st.Err()
+
+	// Act
+	w := suite.request(http.MethodPost, "/api/tokens", `{"name":"ci","scopes":["bogus"]}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "unknown scope")
+}
+
+func (suite *AccessTokenControllerTestSuite) TestCreate_InvalidBody() {
+	// Act
+	w := suite.request(http.MethodPost, "/api/tokens", `{"scopes":["subscriptions:read"]}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.client.createRequest)
+}
+
+func (suite *AccessTokenControllerTestSuite) TestList_Success() {
+	// Arrange
+	suite.client.listResponse = &corepbv2.ListAccessTokensResponse{
+		Tokens: []*corepbv2.AccessToken{{Id: "token-id", Name: "ci", LastUsedAt: 1767225600}},
+	}
+
+	// Act
+	w := suite.request(http.MethodGet, "/api/tokens", "")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	var tokens []map[string]any
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &tokens))
+	suite.Require().Len(tokens, 1)
+	suite.Equal("2026-01-01T00:00:00Z", tokens[0]["last_used_at"])
+	suite.Equal([]any{}, tokens[0]["scopes"])
+}
+
+func (suite *AccessTokenControllerTestSuite) TestRevoke_NotFound() {
+	// Arrange
+	suite.client.err = status.Error(codes.NotFound, "access token not found")
+
+	// Act
+	w := suite.request(http.MethodDelete, "/api/tokens/token-id", "")
+
+	// Assert
+	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Equal("user-id", suite.client.userID)
+}
+
+func TestAccessTokenControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenControllerTestSuite))
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 100
+)
+
+// AdminController exposes subscriptions of all users to support staff
+type AdminController struct{ SubService SubscriptionService }
+
+func NewAdminController(service SubscriptionService) *AdminController {
+	return &AdminController{SubService: service}
+}
+
+// ListSubscriptions lists and searches subscriptions of all users.
+// Query parameters: user_id, service_name, limit, offset.
+func (c *AdminController) ListSubscriptions(ctx *gin.Context) {
+	filter := models.SubscriptionFilter{
+		Service: ctx.Query("service_name"),
+		Limit:   defaultSearchLimit,
+	}
+
+	if userIDStr := ctx.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"GetError": "invalid user_id format",
+				"details":  err.Error(),
+			})
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"GetError": "invalid limit",
+				"details":  "limit must be between 1 and " + strconv.Itoa(maxSearchLimit),
+			})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if offsetStr := ctx.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"GetError": "invalid offset",
+				"details":  "offset must be a non-negative integer",
+			})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	subs, err := c.SubService.Search(filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"GetError": "failed to search subscriptions",
+			"details":  err.Error(),
+		})
+		return
+	}
+	if subs == nil {
+		subs = []models.Subscription{}
+	}
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// GetSubscription gets any user's subscription by id
+func (c *AdminController) GetSubscription(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "invalid id format",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	sub, err := c.SubService.GetByID(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"GetError": "not found",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sub)
+}
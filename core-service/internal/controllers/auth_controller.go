@@ -2,19 +2,57 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"time"
 
-	"github.com/Koshsky/subs-service/core-service/internal/corepb"
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/Koshsky/subs-service/core-service/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 // AuthClient defines methods controller needs from auth client
 // Matching the concrete client signatures for simple wiring
 type AuthClient interface {
-	Register(ctx context.Context, email, password string) (*corepb.RegisterResponse, error)
-	Login(ctx context.Context, email, password string) (*corepb.LoginResponse, error)
+	Register(ctx context.Context, email, password string) (*corepbv2.RegisterResponse, error)
+	Login(ctx context.Context, email, password string) (*corepbv2.LoginResponse, error)
+	VerifySecondFactor(ctx context.Context, challenge, code string) (*corepbv2.VerifySecondFactorResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*corepbv2.RefreshResponse, error)
+	Logout(ctx context.Context, token, refreshToken string) (*corepbv2.LogoutResponse, error)
+	LogoutAll(ctx context.Context, userID string) (*corepbv2.LogoutAllResponse, error)
+	RequestPasswordReset(ctx context.Context, email string) (*corepbv2.RequestPasswordResetResponse, error)
+	ResetPassword(ctx context.Context, token, newPassword string) (*corepbv2.ResetPasswordResponse, error)
+	RequestMagicLink(ctx context.Context, email string) (*corepbv2.RequestMagicLinkResponse, error)
+	ConsumeMagicLink(ctx context.Context, token string) (*corepbv2.ConsumeMagicLinkResponse, error)
+	VerifyEmail(ctx context.Context, token string) (*corepbv2.VerifyEmailResponse, error)
+	ResendVerificationEmail(ctx context.Context, email string) (*corepbv2.ResendVerificationEmailResponse, error)
+	ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) (*corepbv2.ChangePasswordResponse, error)
+	ChangeEmail(ctx context.Context, userID, currentPassword, newEmail string) (*corepbv2.ChangeEmailResponse, error)
+	DeleteAccount(ctx context.Context, userID, currentPassword string) (*corepbv2.DeleteAccountResponse, error)
+	EnrollTOTP(ctx context.Context, userID string) (*corepbv2.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, userID, code string) (*corepbv2.ConfirmTOTPResponse, error)
+	DisableTOTP(ctx context.Context, userID, currentPassword, code string) (*corepbv2.DisableTOTPResponse, error)
+	BeginPasskeyRegistration(ctx context.Context, userID string) (*corepbv2.BeginPasskeyRegistrationResponse, error)
+	FinishPasskeyRegistration(ctx context.Context, userID, ceremony, credential string) (*corepbv2.FinishPasskeyRegistrationResponse, error)
+	BeginPasskeyLogin(ctx context.Context) (*corepbv2.BeginPasskeyLoginResponse, error)
+	FinishPasskeyLogin(ctx context.Context, ceremony, credential string) (*corepbv2.FinishPasskeyLoginResponse, error)
 }
 
+// Login response modes selected with the ?response= query parameter
+const (
+	loginResponseCookie = "cookie"
+	loginResponseToken  = "token"
+)
+
+// Cookies carrying the tokens in cookie mode
+const (
+	authCookieName    = "auth_token"
+	refreshCookieName = "refresh_token"
+	refreshCookiePath = "/auth"
+	// defaultAuthCookieMaxAge applies when auth-service does not report the token expiry
+	defaultAuthCookieMaxAge = 3600
+)
+
 type AuthController struct {
 	AuthClient AuthClient
 }
@@ -43,23 +81,12 @@ func (ac *AuthController) Register(c *gin.Context) {
 	resp, err := ac.AuthClient.Register(c.Request.Context(), credentials.Email, credentials.Password)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"GetError": "Failed to register user",
-			"details":  err.Error(),
-		})
-		return
-	}
-
-	if !resp.Success {
-		c.JSON(http.StatusConflict, gin.H{
-			"GetError": resp.Error,
-			"details":  resp.Error,
-		})
+		writeAuthError(c, err, "Failed to register user")
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": resp.Message,
+		"message": "User created successfully",
 		"user": gin.H{
 			"id":    resp.UserId,
 			"email": resp.Email,
@@ -67,7 +94,11 @@ func (ac *AuthController) Register(c *gin.Context) {
 	})
 }
 
-// Login handles user authentication via gRPC
+// Login handles user authentication via gRPC.
+// By default the tokens are set as the auth_token and refresh_token cookies;
+// with ?response=token they are returned in the JSON body with their expiry.
+// After too many failed attempts it answers 429 with Retry-After. Accounts with two-factor
+// authentication get a challenge instead of the tokens, to be completed with VerifySecondFactor.
 func (ac *AuthController) Login(c *gin.Context) {
 	var credentials struct {
 		Email    string `json:"email" binding:"required"`
@@ -82,25 +113,669 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
+	responseMode, ok := parseResponseMode(c)
+	if !ok {
+		return
+	}
+
 	resp, err := ac.AuthClient.Login(c.Request.Context(), credentials.Email, credentials.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"GetError": "Failed to authenticate",
+		writeAuthError(c, err, "Failed to authenticate")
+		return
+	}
+
+	if resp.SecondFactorRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"message":                "Second factor required",
+			"second_factor_required": true,
+			"challenge":              resp.Challenge,
+			"challenge_expires_at":   formatUnix(resp.ChallengeExpiresAt),
+		})
+		return
+	}
+
+	writeTokens(c, responseMode, "Successful login", issuedTokens{
+		token:            resp.Token,
+		expiresAt:        resp.ExpiresAt,
+		refreshToken:     resp.RefreshToken,
+		refreshExpiresAt: resp.RefreshExpiresAt,
+	})
+}
+
+// VerifySecondFactor completes a login that answered second_factor_required with the challenge
+// and a code from the authenticator app or a recovery code. The tokens are delivered the same
+// way as by Login, and wrong codes are limited like failed logins.
+func (ac *AuthController) VerifySecondFactor(c *gin.Context) {
+	var body struct {
+		Challenge string `json:"challenge" binding:"required"`
+		Code      string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
 			"details":  err.Error(),
 		})
 		return
 	}
 
-	if !resp.Success {
+	responseMode, ok := parseResponseMode(c)
+	if !ok {
+		return
+	}
+
+	resp, err := ac.AuthClient.VerifySecondFactor(c.Request.Context(), body.Challenge, body.Code)
+	if err != nil {
+		writeAuthError(c, err, "Failed to authenticate")
+		return
+	}
+
+	writeTokens(c, responseMode, "Successful login", issuedTokens{
+		token:            resp.Token,
+		expiresAt:        resp.ExpiresAt,
+		refreshToken:     resp.RefreshToken,
+		refreshExpiresAt: resp.RefreshExpiresAt,
+	})
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token.
+// The refresh token is read from the JSON body or the refresh_token cookie,
+// and the new tokens are delivered the same way as by Login.
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"GetError": "Invalid request payload",
+				"details":  err.Error(),
+			})
+			return
+		}
+	}
+
+	responseMode, ok := parseResponseMode(c)
+	if !ok {
+		return
+	}
+
+	refreshToken := body.RefreshToken
+	if refreshToken == "" {
+		refreshToken, _ = c.Cookie(refreshCookieName)
+	}
+	if refreshToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"GetError": "Refresh token required",
+			"details":  "no refresh_token in the request body or cookie",
+		})
+		return
+	}
+
+	resp, err := ac.AuthClient.Refresh(c.Request.Context(), refreshToken)
+	if err != nil {
+		writeAuthError(c, err, "Failed to refresh token")
+		return
+	}
+
+	writeTokens(c, responseMode, "Token refreshed", issuedTokens{
+		token:            resp.Token,
+		expiresAt:        resp.ExpiresAt,
+		refreshToken:     resp.RefreshToken,
+		refreshExpiresAt: resp.RefreshExpiresAt,
+	})
+}
+
+// Logout revokes the access token and the refresh token sent with the request
+// and clears the auth cookies. Tokens that are already invalid are ignored,
+// so that a client can always log out.
+func (ac *AuthController) Logout(c *gin.Context) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"GetError": "Invalid request payload",
+				"details":  err.Error(),
+			})
+			return
+		}
+	}
+
+	// A malformed Authorization header only means there is no access token to revoke
+	token, _ := middleware.ExtractToken(c)
+	refreshToken := body.RefreshToken
+	if refreshToken == "" {
+		refreshToken, _ = c.Cookie(refreshCookieName)
+	}
+	if token == "" && refreshToken == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{
-			"GetError": resp.Error,
-			"details":  resp.Error,
+			"GetError": "Authorization required",
+			"details":  "no access token or refresh token provided",
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.Logout(c.Request.Context(), token, refreshToken)
+	if err != nil {
+		writeAuthError(c, err, "Failed to log out")
+		return
+	}
+
+	clearAuthCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out",
+	})
+}
+
+// LogoutAll revokes every session of the authenticated user on all devices.
+// It must run after AuthMiddleware.
+func (ac *AuthController) LogoutAll(c *gin.Context) {
+	_, err := ac.AuthClient.LogoutAll(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		writeAuthError(c, err, "Failed to log out")
+		return
+	}
+
+	clearAuthCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out from all sessions",
+	})
+}
+
+// RequestPasswordReset emails a password reset link. It answers 202 whether or not
+// the email is registered, so that it cannot be used to discover accounts.
+func (ac *AuthController) RequestPasswordReset(c *gin.Context) {
+	var body struct {
+		Email string `json:"email" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.RequestPasswordReset(c.Request.Context(), body.Email)
+	if err != nil {
+		writeAuthError(c, err, "Failed to request password reset")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword sets a new password with the token from a password reset email.
+// All sessions of the user are signed out.
+func (ac *AuthController) ResetPassword(c *gin.Context) {
+	var body struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.ResetPassword(c.Request.Context(), body.Token, body.NewPassword)
+	if err != nil {
+		writeAuthError(c, err, "Failed to reset password")
+		return
+	}
+
+	clearAuthCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset",
+	})
+}
+
+// RequestMagicLink emails a sign-in link. It answers 202 whether or not
+// the email is registered, so that it cannot be used to discover accounts.
+func (ac *AuthController) RequestMagicLink(c *gin.Context) {
+	var body struct {
+		Email string `json:"email" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.RequestMagicLink(c.Request.Context(), body.Email)
+	if err != nil {
+		writeAuthError(c, err, "Failed to request magic link")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If the email is registered, a sign-in link has been sent",
+	})
+}
+
+// ConsumeMagicLink signs the user in with the token from a magic link email. The tokens are
+// delivered the same way as by Login, and accounts with two-factor authentication get a
+// challenge to be completed with VerifySecondFactor.
+func (ac *AuthController) ConsumeMagicLink(c *gin.Context) {
+	var body struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	responseMode, ok := parseResponseMode(c)
+	if !ok {
+		return
+	}
+
+	resp, err := ac.AuthClient.ConsumeMagicLink(c.Request.Context(), body.Token)
+	if err != nil {
+		writeAuthError(c, err, "Failed to authenticate")
+		return
+	}
+
+	if resp.SecondFactorRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"message":                "Second factor required",
+			"second_factor_required": true,
+			"challenge":              resp.Challenge,
+			"challenge_expires_at":   formatUnix(resp.ChallengeExpiresAt),
+		})
+		return
+	}
+
+	writeTokens(c, responseMode, "Successful login", issuedTokens{
+		token:            resp.Token,
+		expiresAt:        resp.ExpiresAt,
+		refreshToken:     resp.RefreshToken,
+		refreshExpiresAt: resp.RefreshExpiresAt,
+	})
+}
+
+// VerifyEmail confirms the email address with the token from a verification email.
+// Session tokens issued before the verification stay read-only until they are refreshed.
+func (ac *AuthController) VerifyEmail(c *gin.Context) {
+	var body struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.VerifyEmail(c.Request.Context(), body.Token)
+	if err != nil {
+		writeAuthError(c, err, "Failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified",
+	})
+}
+
+// ResendVerificationEmail emails a new verification link. It answers 202 whether or not
+// the email is registered, so that it cannot be used to discover accounts.
+func (ac *AuthController) ResendVerificationEmail(c *gin.Context) {
+	var body struct {
+		Email string `json:"email" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.ResendVerificationEmail(c.Request.Context(), body.Email)
+	if err != nil {
+		writeAuthError(c, err, "Failed to send verification email")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If the email is registered and not verified yet, a verification link has been sent",
+	})
+}
+
+// ChangePassword replaces the password of the authenticated user. Every other session
+// is signed out; the new tokens for this session are delivered the same way as by Login.
+// It must run after AuthMiddleware.
+func (ac *AuthController) ChangePassword(c *gin.Context) {
+	var body struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	responseMode, ok := parseResponseMode(c)
+	if !ok {
+		return
+	}
+
+	resp, err := ac.AuthClient.ChangePassword(c.Request.Context(), c.GetString("user_id"), body.CurrentPassword, body.NewPassword)
+	if err != nil {
+		writeAuthError(c, err, "Failed to change password")
+		return
+	}
+
+	writeTokens(c, responseMode, "Password changed", issuedTokens{
+		token:            resp.Token,
+		expiresAt:        resp.ExpiresAt,
+		refreshToken:     resp.RefreshToken,
+		refreshExpiresAt: resp.RefreshExpiresAt,
+	})
+}
+
+// ChangeEmail emails a verification link to the new address of the authenticated user.
+// The email is replaced once the link is used. It must run after AuthMiddleware.
+func (ac *AuthController) ChangeEmail(c *gin.Context) {
+	var body struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewEmail        string `json:"new_email" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.ChangeEmail(c.Request.Context(), c.GetString("user_id"), body.CurrentPassword, body.NewEmail)
+	if err != nil {
+		writeAuthError(c, err, "Failed to change email")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "A verification link has been sent to the new email address",
+	})
+}
+
+// DeleteAccount deletes the account of the authenticated user and signs it out.
+// The user's subscriptions are deleted asynchronously, hence 202. It must run after AuthMiddleware.
+func (ac *AuthController) DeleteAccount(c *gin.Context) {
+	var body struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
 		})
 		return
 	}
 
-	c.SetCookie("auth_token", resp.Token, 3600, "/", "localhost", false, true)
+	_, err := ac.AuthClient.DeleteAccount(c.Request.Context(), c.GetString("user_id"), body.CurrentPassword)
+	if err != nil {
+		writeAuthError(c, err, "Failed to delete account")
+		return
+	}
+
+	clearAuthCookies(c)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Account deleted",
+	})
+}
+
+// EnrollTOTP starts the enrollment of an authenticator app for the authenticated user and
+// returns the secret and its otpauth:// URI. It must run after AuthMiddleware.
+func (ac *AuthController) EnrollTOTP(c *gin.Context) {
+	resp, err := ac.AuthClient.EnrollTOTP(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		writeAuthError(c, err, "Failed to enroll authenticator app")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": resp.Message,
+		"secret":      resp.Secret,
+		"otpauth_uri": resp.OtpauthUri,
 	})
 }
+
+// ConfirmTOTP enables two-factor authentication with a first code from the authenticator app.
+// The response carries the recovery codes, which are not shown again. It must run after AuthMiddleware.
+func (ac *AuthController) ConfirmTOTP(c *gin.Context) {
+	var body struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	resp, err := ac.AuthClient.ConfirmTOTP(c.Request.Context(), c.GetString("user_id"), body.Code)
+	if err != nil {
+		writeAuthError(c, err, "Failed to enable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": resp.RecoveryCodes,
+	})
+}
+
+// DisableTOTP turns two-factor authentication off for the authenticated user after checking
+// the current password and a code. It must run after AuthMiddleware.
+func (ac *AuthController) DisableTOTP(c *gin.Context) {
+	var body struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		Code            string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.DisableTOTP(c.Request.Context(), c.GetString("user_id"), body.CurrentPassword, body.Code)
+	if err != nil {
+		writeAuthError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// BeginPasskeyRegistration returns the options to pass to navigator.credentials.create and the
+// ceremony token to send back with the new credential. It must run after AuthMiddleware.
+func (ac *AuthController) BeginPasskeyRegistration(c *gin.Context) {
+	resp, err := ac.AuthClient.BeginPasskeyRegistration(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		writeAuthError(c, err, "Failed to start passkey registration")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ceremony": resp.Ceremony,
+		"options":  json.RawMessage(resp.Options),
+	})
+}
+
+// FinishPasskeyRegistration stores the passkey created by the authenticator for the
+// authenticated user. It must run after AuthMiddleware.
+func (ac *AuthController) FinishPasskeyRegistration(c *gin.Context) {
+	var body struct {
+		Ceremony   string          `json:"ceremony" binding:"required"`
+		Credential json.RawMessage `json:"credential" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	_, err := ac.AuthClient.FinishPasskeyRegistration(c.Request.Context(), c.GetString("user_id"), body.Ceremony, string(body.Credential))
+	if err != nil {
+		writeAuthError(c, err, "Failed to register passkey")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Passkey registered",
+	})
+}
+
+// BeginPasskeyLogin returns the options to pass to navigator.credentials.get and the
+// ceremony token to send back with the assertion
+func (ac *AuthController) BeginPasskeyLogin(c *gin.Context) {
+	resp, err := ac.AuthClient.BeginPasskeyLogin(c.Request.Context())
+	if err != nil {
+		writeAuthError(c, err, "Failed to start passkey login")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ceremony": resp.Ceremony,
+		"options":  json.RawMessage(resp.Options),
+	})
+}
+
+// FinishPasskeyLogin signs the user in with the assertion of a passkey. The tokens are
+// delivered the same way as by Login; no password or second factor is needed.
+func (ac *AuthController) FinishPasskeyLogin(c *gin.Context) {
+	var body struct {
+		Ceremony   string          `json:"ceremony" binding:"required"`
+		Credential json.RawMessage `json:"credential" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	responseMode, ok := parseResponseMode(c)
+	if !ok {
+		return
+	}
+
+	resp, err := ac.AuthClient.FinishPasskeyLogin(c.Request.Context(), body.Ceremony, string(body.Credential))
+	if err != nil {
+		writeAuthError(c, err, "Failed to authenticate")
+		return
+	}
+
+	writeTokens(c, responseMode, "Successful login", issuedTokens{
+		token:            resp.Token,
+		expiresAt:        resp.ExpiresAt,
+		refreshToken:     resp.RefreshToken,
+		refreshExpiresAt: resp.RefreshExpiresAt,
+	})
+}
+
+// issuedTokens are the tokens returned by Login, Refresh and ChangePassword, expiries are unix seconds
+type issuedTokens struct {
+	token            string
+	expiresAt        int64
+	refreshToken     string
+	refreshExpiresAt int64
+}
+
+// parseResponseMode reads the ?response= query parameter and rejects unknown modes
+func parseResponseMode(c *gin.Context) (string, bool) {
+	responseMode := c.DefaultQuery("response", loginResponseCookie)
+	if responseMode != loginResponseCookie && responseMode != loginResponseToken {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid response mode",
+			"details":  "response must be 'cookie' or 'token'",
+		})
+		return "", false
+	}
+	return responseMode, true
+}
+
+// writeTokens delivers issued tokens as cookies or, in token mode, in the JSON body
+func writeTokens(c *gin.Context, responseMode, message string, tokens issuedTokens) {
+	// Token mode is meant for scripts and services: the tokens are returned
+	// in the body and no cookie is set
+	if responseMode == loginResponseToken {
+		body := gin.H{
+			"message":    message,
+			"token":      tokens.token,
+			"token_type": "Bearer",
+		}
+		if tokens.expiresAt > 0 {
+			expiresAt := time.Unix(tokens.expiresAt, 0).UTC()
+			body["expires_at"] = expiresAt.Format(time.RFC3339)
+			body["expires_in"] = int64(time.Until(expiresAt).Seconds())
+		}
+		if tokens.refreshToken != "" {
+			body["refresh_token"] = tokens.refreshToken
+			body["refresh_expires_at"] = time.Unix(tokens.refreshExpiresAt, 0).UTC().Format(time.RFC3339)
+		}
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
+	c.SetCookie(authCookieName, tokens.token, cookieMaxAge(tokens.expiresAt, defaultAuthCookieMaxAge), "/", "localhost", false, true)
+	if tokens.refreshToken != "" {
+		// The refresh token is only sent to the auth endpoints
+		c.SetCookie(refreshCookieName, tokens.refreshToken, cookieMaxAge(tokens.refreshExpiresAt, 0), refreshCookiePath, "localhost", false, true)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+	})
+}
+
+// clearAuthCookies removes the cookies set by writeTokens
+func clearAuthCookies(c *gin.Context) {
+	c.SetCookie(authCookieName, "", -1, "/", "localhost", false, true)
+	c.SetCookie(refreshCookieName, "", -1, refreshCookiePath, "localhost", false, true)
+}
+
+// cookieMaxAge returns the seconds until the unix expiry, or fallback when it is unknown
+func cookieMaxAge(expiresAt int64, fallback int) int {
+	if expiresAt <= 0 {
+		return fallback
+	}
+	return max(int(time.Until(time.Unix(expiresAt, 0)).Seconds()), 1)
+}
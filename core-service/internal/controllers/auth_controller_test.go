@@ -0,0 +1,1135 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/core-service/internal/controllers"
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// fakeAuthClient is a controllers.AuthClient returning canned responses, or err when it is set
+type fakeAuthClient struct {
+	err             error
+	loginResponse   *corepbv2.LoginResponse
+	loginCalls      int
+	refreshResponse *corepbv2.RefreshResponse
+	refreshedToken  string
+	logoutResponse  *corepbv2.LogoutResponse
+	loggedOut       []string // access and refresh token passed to Logout
+	loggedOutUser   string
+	resetRequests   []string // emails passed to RequestPasswordReset
+	resetResponse   *corepbv2.ResetPasswordResponse
+	magicLinkEmails []string // emails passed to RequestMagicLink
+	consumedLink    string
+	magicLogin      *corepbv2.ConsumeMagicLinkResponse
+	verifyResponse  *corepbv2.VerifyEmailResponse
+	resentTo        []string // emails passed to ResendVerificationEmail
+	changedPassword []string // user ID and passwords passed to ChangePassword
+	changePassword  *corepbv2.ChangePasswordResponse
+	changedEmail    []string // user ID, password and email passed to ChangeEmail
+	changeEmail     *corepbv2.ChangeEmailResponse
+	deletedAccount  []string // user ID and password passed to DeleteAccount
+	deleteAccount   *corepbv2.DeleteAccountResponse
+	verifiedFactor  []string // challenge and code passed to VerifySecondFactor
+	secondFactor    *corepbv2.VerifySecondFactorResponse
+	enrolledUser    string
+	confirmedTOTP   []string // user ID and code passed to ConfirmTOTP
+	confirmTOTP     *corepbv2.ConfirmTOTPResponse
+	disabledTOTP    []string // user ID, password and code passed to DisableTOTP
+	disableTOTP     *corepbv2.DisableTOTPResponse
+	passkeyUser     string
+	registeredKey   []string // user ID, ceremony and credential passed to FinishPasskeyRegistration
+	registerKey     *corepbv2.FinishPasskeyRegistrationResponse
+	passkeyLogin    []string // ceremony and credential passed to FinishPasskeyLogin
+	passkeyTokens   *corepbv2.FinishPasskeyLoginResponse
+}
+
+func (f *fakeAuthClient) Register(_ context.Context, _, _ string) (*corepbv2.RegisterResponse, error) {
+	return &corepbv2.RegisterResponse{}, f.err
+}
+
+func (f *fakeAuthClient) Login(_ context.Context, _, _ string) (*corepbv2.LoginResponse, error) {
+	f.loginCalls++
+	return f.loginResponse, f.err
+}
+
+func (f *fakeAuthClient) Refresh(_ context.Context, refreshToken string) (*corepbv2.RefreshResponse, error) {
+	f.refreshedToken = refreshToken
+	return f.refreshResponse, f.err
+}
+
+func (f *fakeAuthClient) Logout(_ context.Context, token, refreshToken string) (*corepbv2.LogoutResponse, error) {
+	f.loggedOut = []string{token, refreshToken}
+	return f.logoutResponse, f.err
+}
+
+func (f *fakeAuthClient) LogoutAll(_ context.Context, userID string) (*corepbv2.LogoutAllResponse, error) {
+	f.loggedOutUser = userID
+	return &corepbv2.LogoutAllResponse{}, f.err
+}
+
+func (f *fakeAuthClient) RequestPasswordReset(_ context.Context, email string) (*corepbv2.RequestPasswordResetResponse, error) {
+	f.resetRequests = append(f.resetRequests, email)
+	return &corepbv2.RequestPasswordResetResponse{}, f.err
+}
+
+func (f *fakeAuthClient) ResetPassword(_ context.Context, _, _ string) (*corepbv2.ResetPasswordResponse, error) {
+	return f.resetResponse, f.err
+}
+
+func (f *fakeAuthClient) RequestMagicLink(_ context.Context, email string) (*corepbv2.RequestMagicLinkResponse, error) {
+	f.magicLinkEmails = append(f.magicLinkEmails, email)
+	return &corepbv2.RequestMagicLinkResponse{}, f.err
+}
+
+func (f *fakeAuthClient) ConsumeMagicLink(_ context.Context, token string) (*corepbv2.ConsumeMagicLinkResponse, error) {
+	f.consumedLink = token
+	return f.magicLogin, f.err
+}
+
+func (f *fakeAuthClient) VerifyEmail(_ context.Context, _ string) (*corepbv2.VerifyEmailResponse, error) {
+	return f.verifyResponse, f.err
+}
+
+func (f *fakeAuthClient) ResendVerificationEmail(_ context.Context, email string) (*corepbv2.ResendVerificationEmailResponse, error) {
+	f.resentTo = append(f.resentTo, email)
+	return &corepbv2.ResendVerificationEmailResponse{}, f.err
+}
+
+func (f *fakeAuthClient) ChangePassword(_ context.Context, userID, currentPassword, newPassword string) (*corepbv2.ChangePasswordResponse, error) {
+	f.changedPassword = []string{userID, currentPassword, newPassword}
+	return f.changePassword, f.err
+}
+
+func (f *fakeAuthClient) ChangeEmail(_ context.Context, userID, currentPassword, newEmail string) (*corepbv2.ChangeEmailResponse, error) {
+	f.changedEmail = []string{userID, currentPassword, newEmail}
+	return f.changeEmail, f.err
+}
+
+func (f *fakeAuthClient) DeleteAccount(_ context.Context, userID, currentPassword string) (*corepbv2.DeleteAccountResponse, error) {
+	f.deletedAccount = []string{userID, currentPassword}
+	return f.deleteAccount, f.err
+}
+
+func (f *fakeAuthClient) VerifySecondFactor(_ context.Context, challenge, code string) (*corepbv2.VerifySecondFactorResponse, error) {
+	f.verifiedFactor = []string{challenge, code}
+	return f.secondFactor, f.err
+}
+
+func (f *fakeAuthClient) EnrollTOTP(_ context.Context, userID string) (*corepbv2.EnrollTOTPResponse, error) {
+	f.enrolledUser = userID
+	return &corepbv2.EnrollTOTPResponse{
+		Secret:     "JBSWY3DPEHPK3PXP",
+		OtpauthUri: "otpauth://totp/subs-service:test%40example.com?secret=JBSWY3DPEHPK3PXP",
+	}, f.err
+}
+
+func (f *fakeAuthClient) ConfirmTOTP(_ context.Context, userID, code string) (*corepbv2.ConfirmTOTPResponse, error) {
+	f.confirmedTOTP = []string{userID, code}
+	return f.confirmTOTP, f.err
+}
+
+func (f *fakeAuthClient) DisableTOTP(_ context.Context, userID, currentPassword, code string) (*corepbv2.DisableTOTPResponse, error) {
+	f.disabledTOTP = []string{userID, currentPassword, code}
+	return f.disableTOTP, f.err
+}
+
+func (f *fakeAuthClient) BeginPasskeyRegistration(_ context.Context, userID string) (*corepbv2.BeginPasskeyRegistrationResponse, error) {
+	f.passkeyUser = userID
+	return &corepbv2.BeginPasskeyRegistrationResponse{
+		Ceremony: "pkc_register",
+		Options:  `{"publicKey":{"challenge":"Y2hhbGxlbmdl","rp":{"id":"localhost"}}}`,
+	}, f.err
+}
+
+func (f *fakeAuthClient) FinishPasskeyRegistration(_ context.Context, userID, ceremony, credential string) (*corepbv2.FinishPasskeyRegistrationResponse, error) {
+	f.registeredKey = []string{userID, ceremony, credential}
+	return f.registerKey, f.err
+}
+
+func (f *fakeAuthClient) BeginPasskeyLogin(_ context.Context) (*corepbv2.BeginPasskeyLoginResponse, error) {
+	return &corepbv2.BeginPasskeyLoginResponse{
+		Ceremony: "pkc_login",
+		Options:  `{"publicKey":{"challenge":"Y2hhbGxlbmdl","rpId":"localhost"}}`,
+	}, f.err
+}
+
+func (f *fakeAuthClient) FinishPasskeyLogin(_ context.Context, ceremony, credential string) (*corepbv2.FinishPasskeyLoginResponse, error) {
+	f.passkeyLogin = []string{ceremony, credential}
+	return f.passkeyTokens, f.err
+}
+
+type AuthControllerTestSuite struct {
+	suite.Suite
+	client    *fakeAuthClient
+	router    *gin.Engine
+	expiresAt time.Time
+}
+
+func (suite *AuthControllerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *AuthControllerTestSuite) SetupTest() {
+	suite.expiresAt = time.Now().Add(time.Hour).Truncate(time.Second)
+	refreshExpiresAt := suite.expiresAt.Add(30 * 24 * time.Hour).Unix()
+	suite.client = &fakeAuthClient{
+		loginResponse: &corepbv2.LoginResponse{
+			Token:            "jwt-token",
+			ExpiresAt:        suite.expiresAt.Unix(),
+			RefreshToken:     "rt_first",
+			RefreshExpiresAt: refreshExpiresAt,
+		},
+		refreshResponse: &corepbv2.RefreshResponse{
+			Token:            "jwt-refreshed",
+			ExpiresAt:        suite.expiresAt.Unix(),
+			RefreshToken:     "rt_second",
+			RefreshExpiresAt: refreshExpiresAt,
+		},
+		logoutResponse: &corepbv2.LogoutResponse{},
+		resetResponse:  &corepbv2.ResetPasswordResponse{},
+		verifyResponse: &corepbv2.VerifyEmailResponse{},
+		magicLogin: &corepbv2.ConsumeMagicLinkResponse{
+			Token:            "jwt-magic",
+			ExpiresAt:        suite.expiresAt.Unix(),
+			RefreshToken:     "rt_magic",
+			RefreshExpiresAt: refreshExpiresAt,
+		},
+		changePassword: &corepbv2.ChangePasswordResponse{
+			Token:            "jwt-changed",
+			ExpiresAt:        suite.expiresAt.Unix(),
+			RefreshToken:     "rt_changed",
+			RefreshExpiresAt: refreshExpiresAt,
+		},
+		changeEmail:   &corepbv2.ChangeEmailResponse{},
+		deleteAccount: &corepbv2.DeleteAccountResponse{},
+		secondFactor: &corepbv2.VerifySecondFactorResponse{
+			Token:            "jwt-2fa",
+			ExpiresAt:        suite.expiresAt.Unix(),
+			RefreshToken:     "rt_2fa",
+			RefreshExpiresAt: refreshExpiresAt,
+		},
+		confirmTOTP: &corepbv2.ConfirmTOTPResponse{
+			RecoveryCodes: []string{"abcde-fghij", "klmno-pqrst"},
+		},
+		disableTOTP: &corepbv2.DisableTOTPResponse{},
+		registerKey: &corepbv2.FinishPasskeyRegistrationResponse{},
+		passkeyTokens: &corepbv2.FinishPasskeyLoginResponse{
+			Token:            "jwt-passkey",
+			ExpiresAt:        suite.expiresAt.Unix(),
+			RefreshToken:     "rt_passkey",
+			RefreshExpiresAt: refreshExpiresAt,
+		},
+	}
+
+	controller := controllers.NewAuthController(suite.client)
+	suite.router = gin.New()
+	suite.router.POST("/api/register", controller.Register)
+	suite.router.POST("/api/login", controller.Login)
+	suite.router.POST("/auth/refresh", controller.Refresh)
+	suite.router.POST("/auth/logout", controller.Logout)
+	suite.router.POST("/auth/logout-all", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+	}, controller.LogoutAll)
+	suite.router.POST("/auth/password-reset", controller.RequestPasswordReset)
+	suite.router.POST("/auth/password-reset/confirm", controller.ResetPassword)
+	suite.router.POST("/auth/magic-link", controller.RequestMagicLink)
+	suite.router.POST("/auth/magic-link/consume", controller.ConsumeMagicLink)
+	suite.router.POST("/auth/verify-email", controller.VerifyEmail)
+	suite.router.POST("/auth/verify-email/resend", controller.ResendVerificationEmail)
+	signedIn := func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+	}
+	suite.router.POST("/auth/change-password", signedIn, controller.ChangePassword)
+	suite.router.POST("/auth/change-email", signedIn, controller.ChangeEmail)
+	suite.router.POST("/auth/delete-account", signedIn, controller.DeleteAccount)
+	suite.router.POST("/auth/2fa/verify", controller.VerifySecondFactor)
+	suite.router.POST("/auth/2fa/enroll", signedIn, controller.EnrollTOTP)
+	suite.router.POST("/auth/2fa/confirm", signedIn, controller.ConfirmTOTP)
+	suite.router.POST("/auth/2fa/disable", signedIn, controller.DisableTOTP)
+	suite.router.POST("/auth/passkeys/register/begin", signedIn, controller.BeginPasskeyRegistration)
+	suite.router.POST("/auth/passkeys/register/finish", signedIn, controller.FinishPasskeyRegistration)
+	suite.router.POST("/auth/passkeys/login/begin", controller.BeginPasskeyLogin)
+	suite.router.POST("/auth/passkeys/login/finish", controller.FinishPasskeyLogin)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// login performs POST /api/login with the given query string
+func (suite *AuthControllerTestSuite) login(query string) *httptest.ResponseRecorder {
+	body := strings.NewReader(`{"email":"test@example.com","password":"password123"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/login"+query, body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// refresh performs POST /auth/refresh with an optional JSON body and refresh_token cookie
+func (suite *AuthControllerTestSuite) refresh(query, body, cookie string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh"+query, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: cookie})
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// postJSON performs a POST request with a JSON body
+func (suite *AuthControllerTestSuite) postJSON(path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// badRequest returns the INVALID_ARGUMENT error auth-service reports for the field violations
+func (suite *AuthControllerTestSuite) badRequest(message string, violations ...*errdetails.BadRequest_FieldViolation) error {
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	suite.Require().NoError(err)
+	return st.Err()
+}
+
+// loginBlocked returns the RESOURCE_EXHAUSTED error auth-service reports for a throttled or locked login
+func (suite *AuthControllerTestSuite) loginBlocked(message, reason string, retryDelay time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, message).WithDetails(
+		&errdetails.ErrorInfo{Reason: reason, Domain: "auth-service"},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)},
+	)
+	suite.Require().NoError(err)
+	return st.Err()
+}
+
+// cookie returns the cookie set by the response
+func (suite *AuthControllerTestSuite) cookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// ===== REGISTER TESTS =====
+
+func (suite *AuthControllerTestSuite) TestRegister() {
+	// Act
+	w := suite.postJSON("/api/register", `{"email":"test@example.com","password":"Tr0ub4dor&3x"}`)
+
+	// Assert
+	suite.Equal(http.StatusCreated, w.Code)
+}
+
+func (suite *AuthControllerTestSuite) TestRegister_UserExists() {
+	// Arrange
+	suite.client.err = status.Error(codes.AlreadyExists, "user already exists")
+
+	// Act
+	w := suite.postJSON("/api/register", `{"email":"test@example.com","password":"Tr0ub4dor&3x"}`)
+
+	// Assert
+	suite.Equal(http.StatusConflict, w.Code)
+	suite.NotContains(w.Body.String(), "violations")
+}
+
+func (suite *AuthControllerTestSuite) TestRegister_PasswordPolicyViolations() {
+	// Arrange
+	suite.client.err = suite.badRequest(
+		"password does not meet the policy: must contain a symbol; appears in a list of breached passwords, choose another one",
+		&errdetails.BadRequest_FieldViolation{Field: "password", Reason: "MISSING_SYMBOL", Description: "must contain a symbol"},
+		&errdetails.BadRequest_FieldViolation{Field: "password", Reason: "BREACHED", Description: "appears in a list of breached passwords, choose another one"},
+	)
+
+	// Act
+	w := suite.postJSON("/api/register", `{"email":"test@example.com","password":"Password123"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	var body struct {
+		Violations []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"violations"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Require().Len(body.Violations, 2)
+	suite.Equal("missing_symbol", body.Violations[0].Code)
+	suite.Equal("must contain a symbol", body.Violations[0].Message)
+	suite.Equal("breached", body.Violations[1].Code)
+}
+
+// ===== LOGIN TESTS =====
+
+func (suite *AuthControllerTestSuite) TestLogin_CookieModeByDefault() {
+	// Act
+	w := suite.login("")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.NotContains(w.Body.String(), "jwt-token")
+	auth := suite.cookie(w, "auth_token")
+	suite.Require().NotNil(auth)
+	suite.Equal("jwt-token", auth.Value)
+	suite.InDelta(time.Hour.Seconds(), float64(auth.MaxAge), 5)
+	refresh := suite.cookie(w, "refresh_token")
+	suite.Require().NotNil(refresh)
+	suite.Equal("rt_first", refresh.Value)
+	suite.Equal("/auth", refresh.Path)
+	suite.True(refresh.HttpOnly)
+}
+
+func (suite *AuthControllerTestSuite) TestLogin_TokenMode() {
+	// Act
+	w := suite.login("?response=token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Header().Get("Set-Cookie"))
+
+	var body struct {
+		Token        string `json:"token"`
+		TokenType    string `json:"token_type"`
+		ExpiresAt    string `json:"expires_at"`
+		ExpiresIn    int64  `json:"expires_in"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal("jwt-token", body.Token)
+	suite.Equal("rt_first", body.RefreshToken)
+	suite.Equal("Bearer", body.TokenType)
+	suite.Equal(suite.expiresAt.UTC().Format(time.RFC3339), body.ExpiresAt)
+	suite.InDelta(time.Hour.Seconds(), float64(body.ExpiresIn), 5)
+}
+
+func (suite *AuthControllerTestSuite) TestLogin_InvalidResponseMode() {
+	// Act
+	w := suite.login("?response=header")
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Zero(suite.client.loginCalls)
+}
+
+func (suite *AuthControllerTestSuite) TestLogin_Locked() {
+	// Arrange
+	suite.client.err = suite.loginBlocked("account is temporarily locked after too many failed login attempts", "ACCOUNT_LOCKED", 15*time.Minute)
+
+	// Act
+	w := suite.login("")
+
+	// Assert
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+	suite.Equal("900", w.Header().Get("Retry-After"))
+	suite.Contains(w.Body.String(), "Account temporarily locked")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestLogin_Throttled() {
+	// Arrange
+	suite.client.err = suite.loginBlocked("too many failed login attempts, try again later", "LOGIN_THROTTLED", 3500*time.Millisecond)
+
+	// Act
+	w := suite.login("")
+
+	// Assert
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+	suite.Equal("4", w.Header().Get("Retry-After"))
+	suite.Contains(w.Body.String(), "Too many failed login attempts")
+}
+
+// ===== REFRESH TESTS =====
+
+func (suite *AuthControllerTestSuite) TestRefresh_FromCookie() {
+	// Act
+	w := suite.refresh("", "", "rt_first")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("rt_first", suite.client.refreshedToken)
+	suite.Equal("jwt-refreshed", suite.cookie(w, "auth_token").Value)
+	suite.Equal("rt_second", suite.cookie(w, "refresh_token").Value)
+}
+
+func (suite *AuthControllerTestSuite) TestRefresh_FromBodyInTokenMode() {
+	// Act
+	w := suite.refresh("?response=token", `{"refresh_token":"rt_first"}`, "")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("rt_first", suite.client.refreshedToken)
+	suite.Empty(w.Header().Get("Set-Cookie"))
+	var body struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal("jwt-refreshed", body.Token)
+	suite.Equal("rt_second", body.RefreshToken)
+}
+
+func (suite *AuthControllerTestSuite) TestRefresh_MissingToken() {
+	// Act
+	w := suite.refresh("", "", "")
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Empty(suite.client.refreshedToken)
+}
+
+func (suite *AuthControllerTestSuite) TestRefresh_Rejected() {
+	// Arrange
+	suite.client.err = status.Error(codes.Unauthenticated, "invalid refresh token")
+
+	// Act
+	w := suite.refresh("", "", "rt_reused")
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Contains(w.Body.String(), "invalid refresh token")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+// ===== LOGOUT TESTS =====
+
+// logout performs POST path with the given Authorization header and refresh_token cookie
+func (suite *AuthControllerTestSuite) logout(path, authorization, refreshCookie string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	if refreshCookie != "" {
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: refreshCookie})
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *AuthControllerTestSuite) TestLogout_RevokesTokensAndClearsCookies() {
+	// Act
+	w := suite.logout("/auth/logout", "Bearer jwt-token", "rt_first")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"jwt-token", "rt_first"}, suite.client.loggedOut)
+	auth := suite.cookie(w, "auth_token")
+	suite.Require().NotNil(auth)
+	suite.Empty(auth.Value)
+	suite.Negative(auth.MaxAge)
+	refresh := suite.cookie(w, "refresh_token")
+	suite.Require().NotNil(refresh)
+	suite.Equal("/auth", refresh.Path)
+	suite.Negative(refresh.MaxAge)
+}
+
+func (suite *AuthControllerTestSuite) TestLogout_RefreshTokenOnly() {
+	// Act
+	w := suite.logout("/auth/logout", "", "rt_first")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"", "rt_first"}, suite.client.loggedOut)
+}
+
+func (suite *AuthControllerTestSuite) TestLogout_NoTokens() {
+	// Act
+	w := suite.logout("/auth/logout", "", "")
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Nil(suite.client.loggedOut)
+}
+
+func (suite *AuthControllerTestSuite) TestLogout_AuthServiceUnavailable() {
+	// Arrange
+	suite.client.err = status.Error(codes.Unavailable, "service temporarily unavailable")
+
+	// Act
+	w := suite.logout("/auth/logout", "Bearer jwt-token", "")
+
+	// Assert
+	suite.Equal(http.StatusServiceUnavailable, w.Code)
+	suite.Contains(w.Body.String(), "service temporarily unavailable")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestLogout_Failure() {
+	// Arrange
+	suite.client.err = status.Error(codes.Internal, "internal error")
+
+	// Act
+	w := suite.logout("/auth/logout", "Bearer jwt-token", "")
+
+	// Assert
+	suite.Equal(http.StatusInternalServerError, w.Code)
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestLogoutAll() {
+	// Act
+	w := suite.logout("/auth/logout-all", "Bearer jwt-token", "")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("user-1", suite.client.loggedOutUser)
+	suite.NotNil(suite.cookie(w, "auth_token"))
+}
+
+// ===== PASSWORD RESET TESTS =====
+
+func (suite *AuthControllerTestSuite) TestRequestPasswordReset() {
+	// Act
+	w := suite.postJSON("/auth/password-reset", `{"email":"test@example.com"}`)
+
+	// Assert
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Equal([]string{"test@example.com"}, suite.client.resetRequests)
+	suite.Contains(w.Body.String(), "If the email is registered")
+}
+
+func (suite *AuthControllerTestSuite) TestRequestPasswordReset_MissingEmail() {
+	// Act
+	w := suite.postJSON("/auth/password-reset", `{}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.client.resetRequests)
+}
+
+func (suite *AuthControllerTestSuite) TestResetPassword() {
+	// Act
+	w := suite.postJSON("/auth/password-reset/confirm", `{"token":"prt_token","new_password":"NewPassword123!"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.NotNil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestResetPassword_InvalidToken() {
+	// Arrange
+	suite.client.err = suite.badRequest("invalid or expired password reset token",
+		&errdetails.BadRequest_FieldViolation{Field: "token", Reason: "INVALID_TOKEN", Description: "invalid or expired password reset token"},
+	)
+
+	// Act
+	w := suite.postJSON("/auth/password-reset/confirm", `{"token":"prt_used","new_password":"NewPassword123!"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "invalid or expired password reset token")
+}
+
+func (suite *AuthControllerTestSuite) TestResetPassword_PasswordPolicyViolations() {
+	// Arrange
+	suite.client.err = suite.badRequest("password does not meet the policy: must contain a digit",
+		&errdetails.BadRequest_FieldViolation{Field: "new_password", Reason: "MISSING_DIGIT", Description: "must contain a digit"},
+	)
+
+	// Act
+	w := suite.postJSON("/auth/password-reset/confirm", `{"token":"prt_token","new_password":"NewPassword!"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), `"violations":[{"code":"missing_digit","message":"must contain a digit"}]`)
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+// ===== MAGIC LINK TESTS =====
+
+func (suite *AuthControllerTestSuite) TestRequestMagicLink() {
+	// Act
+	w := suite.postJSON("/auth/magic-link", `{"email":"test@example.com"}`)
+
+	// Assert
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Equal([]string{"test@example.com"}, suite.client.magicLinkEmails)
+	suite.Contains(w.Body.String(), "If the email is registered")
+}
+
+func (suite *AuthControllerTestSuite) TestRequestMagicLink_MissingEmail() {
+	// Act
+	w := suite.postJSON("/auth/magic-link", `{}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.client.magicLinkEmails)
+}
+
+func (suite *AuthControllerTestSuite) TestConsumeMagicLink_SetsCookies() {
+	// Act
+	w := suite.postJSON("/auth/magic-link/consume", `{"token":"mlt_token"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("mlt_token", suite.client.consumedLink)
+	suite.Equal("jwt-magic", suite.cookie(w, "auth_token").Value)
+	suite.Equal("rt_magic", suite.cookie(w, "refresh_token").Value)
+}
+
+func (suite *AuthControllerTestSuite) TestConsumeMagicLink_TokenMode() {
+	// Act
+	w := suite.postJSON("/auth/magic-link/consume?response=token", `{"token":"mlt_token"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Header().Get("Set-Cookie"))
+	suite.Contains(w.Body.String(), "jwt-magic")
+}
+
+func (suite *AuthControllerTestSuite) TestConsumeMagicLink_SecondFactorRequired() {
+	// Arrange
+	suite.client.magicLogin = &corepbv2.ConsumeMagicLinkResponse{
+		SecondFactorRequired: true,
+		Challenge:            "tfc_challenge",
+		ChallengeExpiresAt:   suite.expiresAt.Unix(),
+	}
+
+	// Act
+	w := suite.postJSON("/auth/magic-link/consume", `{"token":"mlt_token"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Nil(suite.cookie(w, "auth_token"))
+
+	var body struct {
+		SecondFactorRequired bool   `json:"second_factor_required"`
+		Challenge            string `json:"challenge"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.True(body.SecondFactorRequired)
+	suite.Equal("tfc_challenge", body.Challenge)
+}
+
+func (suite *AuthControllerTestSuite) TestConsumeMagicLink_InvalidToken() {
+	// Arrange
+	suite.client.err = status.Error(codes.Unauthenticated, "invalid or expired magic link")
+
+	// Act
+	w := suite.postJSON("/auth/magic-link/consume", `{"token":"mlt_used"}`)
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Contains(w.Body.String(), "invalid or expired magic link")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+// ===== EMAIL VERIFICATION TESTS =====
+
+func (suite *AuthControllerTestSuite) TestVerifyEmail() {
+	// Act
+	w := suite.postJSON("/auth/verify-email", `{"token":"evt_token"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "Email verified")
+}
+
+func (suite *AuthControllerTestSuite) TestVerifyEmail_InvalidToken() {
+	// Arrange
+	suite.client.err = suite.badRequest("invalid or expired email verification token",
+		&errdetails.BadRequest_FieldViolation{Field: "token", Reason: "INVALID_TOKEN", Description: "invalid or expired email verification token"},
+	)
+
+	// Act
+	w := suite.postJSON("/auth/verify-email", `{"token":"evt_used"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "invalid or expired email verification token")
+}
+
+func (suite *AuthControllerTestSuite) TestResendVerificationEmail() {
+	// Act
+	w := suite.postJSON("/auth/verify-email/resend", `{"email":"test@example.com"}`)
+
+	// Assert
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Equal([]string{"test@example.com"}, suite.client.resentTo)
+}
+
+// ===== CREDENTIAL CHANGE TESTS =====
+
+func (suite *AuthControllerTestSuite) TestChangePassword_SetsNewCookies() {
+	// Act
+	w := suite.postJSON("/auth/change-password", `{"current_password":"password123","new_password":"new-password"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"user-1", "password123", "new-password"}, suite.client.changedPassword)
+	suite.Require().NotNil(suite.cookie(w, "auth_token"))
+	suite.Equal("jwt-changed", suite.cookie(w, "auth_token").Value)
+	suite.Require().NotNil(suite.cookie(w, "refresh_token"))
+	suite.Equal("rt_changed", suite.cookie(w, "refresh_token").Value)
+}
+
+func (suite *AuthControllerTestSuite) TestChangePassword_TokenMode() {
+	// Act
+	w := suite.postJSON("/auth/change-password?response=token", `{"current_password":"password123","new_password":"new-password"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"token":"jwt-changed"`)
+	suite.Contains(w.Body.String(), `"refresh_token":"rt_changed"`)
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestChangePassword_IncorrectPassword() {
+	// Arrange
+	suite.client.err = status.Error(codes.PermissionDenied, "current password is incorrect")
+
+	// Act
+	w := suite.postJSON("/auth/change-password", `{"current_password":"wrong","new_password":"new-password"}`)
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Contains(w.Body.String(), "current password is incorrect")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestChangePassword_PasswordPolicyViolations() {
+	// Arrange
+	suite.client.err = suite.badRequest("password does not meet the policy: must be at least 10 characters long",
+		&errdetails.BadRequest_FieldViolation{Field: "new_password", Reason: "TOO_SHORT", Description: "must be at least 10 characters long"},
+	)
+
+	// Act
+	w := suite.postJSON("/auth/change-password", `{"current_password":"password123","new_password":"short"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), `"violations":[{"code":"too_short","message":"must be at least 10 characters long"}]`)
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestChangeEmail() {
+	// Act
+	w := suite.postJSON("/auth/change-email", `{"current_password":"password123","new_email":"new@example.com"}`)
+
+	// Assert
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Equal([]string{"user-1", "password123", "new@example.com"}, suite.client.changedEmail)
+}
+
+func (suite *AuthControllerTestSuite) TestChangeEmail_EmailTaken() {
+	// Arrange
+	suite.client.err = status.Error(codes.AlreadyExists, "email is already in use")
+
+	// Act
+	w := suite.postJSON("/auth/change-email", `{"current_password":"password123","new_email":"taken@example.com"}`)
+
+	// Assert
+	suite.Equal(http.StatusConflict, w.Code)
+	suite.Contains(w.Body.String(), "email is already in use")
+}
+
+// ===== DELETE ACCOUNT TESTS =====
+
+func (suite *AuthControllerTestSuite) TestDeleteAccount() {
+	// Act
+	w := suite.postJSON("/auth/delete-account", `{"current_password":"password123"}`)
+
+	// Assert
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Equal([]string{"user-1", "password123"}, suite.client.deletedAccount)
+	cleared := suite.cookie(w, "auth_token")
+	suite.Require().NotNil(cleared)
+	suite.Empty(cleared.Value)
+}
+
+func (suite *AuthControllerTestSuite) TestDeleteAccount_IncorrectPassword() {
+	// Arrange
+	suite.client.err = status.Error(codes.PermissionDenied, "current password is incorrect")
+
+	// Act
+	w := suite.postJSON("/auth/delete-account", `{"current_password":"wrong"}`)
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Contains(w.Body.String(), "current password is incorrect")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestDeleteAccount_MissingPassword() {
+	// Act
+	w := suite.postJSON("/auth/delete-account", `{}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.client.deletedAccount)
+}
+
+// ===== TWO-FACTOR TESTS =====
+
+func (suite *AuthControllerTestSuite) TestLogin_SecondFactorRequired() {
+	// Arrange
+	challengeExpiresAt := suite.expiresAt.Add(-55 * time.Minute)
+	suite.client.loginResponse = &corepbv2.LoginResponse{
+		UserId:               "user-1",
+		SecondFactorRequired: true,
+		Challenge:            "tfc_challenge",
+		ChallengeExpiresAt:   challengeExpiresAt.Unix(),
+	}
+
+	// Act
+	w := suite.login("")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Nil(suite.cookie(w, "auth_token"))
+	suite.Nil(suite.cookie(w, "refresh_token"))
+
+	var body struct {
+		SecondFactorRequired bool   `json:"second_factor_required"`
+		Challenge            string `json:"challenge"`
+		ChallengeExpiresAt   string `json:"challenge_expires_at"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.True(body.SecondFactorRequired)
+	suite.Equal("tfc_challenge", body.Challenge)
+	suite.Equal(challengeExpiresAt.UTC().Format(time.RFC3339), body.ChallengeExpiresAt)
+}
+
+func (suite *AuthControllerTestSuite) TestVerifySecondFactor_SetsCookies() {
+	// Act
+	w := suite.postJSON("/auth/2fa/verify", `{"challenge":"tfc_challenge","code":"123456"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"tfc_challenge", "123456"}, suite.client.verifiedFactor)
+	suite.Equal("jwt-2fa", suite.cookie(w, "auth_token").Value)
+	suite.Equal("rt_2fa", suite.cookie(w, "refresh_token").Value)
+}
+
+func (suite *AuthControllerTestSuite) TestVerifySecondFactor_TokenMode() {
+	// Act
+	w := suite.postJSON("/auth/2fa/verify?response=token", `{"challenge":"tfc_challenge","code":"123456"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Header().Get("Set-Cookie"))
+	suite.Contains(w.Body.String(), "jwt-2fa")
+}
+
+func (suite *AuthControllerTestSuite) TestVerifySecondFactor_InvalidCode() {
+	// Arrange
+	suite.client.err = status.Error(codes.Unauthenticated, "invalid two-factor code")
+
+	// Act
+	w := suite.postJSON("/auth/2fa/verify", `{"challenge":"tfc_challenge","code":"000000"}`)
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Contains(w.Body.String(), "invalid two-factor code")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *AuthControllerTestSuite) TestVerifySecondFactor_Locked() {
+	// Arrange
+	suite.client.err = suite.loginBlocked("account is temporarily locked after too many failed login attempts", "ACCOUNT_LOCKED", 15*time.Minute)
+
+	// Act
+	w := suite.postJSON("/auth/2fa/verify", `{"challenge":"tfc_challenge","code":"000000"}`)
+
+	// Assert
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+	suite.Equal("900", w.Header().Get("Retry-After"))
+	suite.Contains(w.Body.String(), "Account temporarily locked")
+}
+
+func (suite *AuthControllerTestSuite) TestVerifySecondFactor_MissingCode() {
+	// Act
+	w := suite.postJSON("/auth/2fa/verify", `{"challenge":"tfc_challenge"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.client.verifiedFactor)
+}
+
+func (suite *AuthControllerTestSuite) TestEnrollTOTP() {
+	// Act
+	w := suite.postJSON("/auth/2fa/enroll", ``)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("user-1", suite.client.enrolledUser)
+
+	var body struct {
+		Secret     string `json:"secret"`
+		OtpauthURI string `json:"otpauth_uri"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal("JBSWY3DPEHPK3PXP", body.Secret)
+	suite.True(strings.HasPrefix(body.OtpauthURI, "otpauth://totp/"))
+}
+
+func (suite *AuthControllerTestSuite) TestConfirmTOTP_ReturnsRecoveryCodes() {
+	// Act
+	w := suite.postJSON("/auth/2fa/confirm", `{"code":"123456"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"user-1", "123456"}, suite.client.confirmedTOTP)
+
+	var body struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal([]string{"abcde-fghij", "klmno-pqrst"}, body.RecoveryCodes)
+}
+
+func (suite *AuthControllerTestSuite) TestConfirmTOTP_InvalidCode() {
+	// Arrange
+	suite.client.err = suite.badRequest("invalid two-factor code",
+		&errdetails.BadRequest_FieldViolation{Field: "code", Reason: "INVALID_CODE", Description: "invalid two-factor code"},
+	)
+
+	// Act
+	w := suite.postJSON("/auth/2fa/confirm", `{"code":"000000"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "invalid two-factor code")
+}
+
+func (suite *AuthControllerTestSuite) TestDisableTOTP() {
+	// Act
+	w := suite.postJSON("/auth/2fa/disable", `{"current_password":"password123","code":"123456"}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"user-1", "password123", "123456"}, suite.client.disabledTOTP)
+	suite.Contains(w.Body.String(), "Two-factor authentication disabled")
+}
+
+func (suite *AuthControllerTestSuite) TestDisableTOTP_MissingCode() {
+	// Act
+	w := suite.postJSON("/auth/2fa/disable", `{"current_password":"password123"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.client.disabledTOTP)
+}
+
+// ===== PASSKEY TESTS =====
+
+func (suite *AuthControllerTestSuite) TestBeginPasskeyRegistration_ReturnsOptions() {
+	// Act
+	w := suite.postJSON("/auth/passkeys/register/begin", ``)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("user-1", suite.client.passkeyUser)
+
+	var body struct {
+		Ceremony string `json:"ceremony"`
+		Options  struct {
+			PublicKey struct {
+				Challenge string `json:"challenge"`
+			} `json:"publicKey"`
+		} `json:"options"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal("pkc_register", body.Ceremony)
+	suite.Equal("Y2hhbGxlbmdl", body.Options.PublicKey.Challenge)
+}
+
+func (suite *AuthControllerTestSuite) TestFinishPasskeyRegistration_PassesCredential() {
+	// Act
+	w := suite.postJSON("/auth/passkeys/register/finish", `{"ceremony":"pkc_register","credential":{"id":"cred"}}`)
+
+	// Assert
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.Require().Len(suite.client.registeredKey, 3)
+	suite.Equal("user-1", suite.client.registeredKey[0])
+	suite.Equal("pkc_register", suite.client.registeredKey[1])
+	suite.JSONEq(`{"id":"cred"}`, suite.client.registeredKey[2])
+	suite.Contains(w.Body.String(), "Passkey registered")
+}
+
+func (suite *AuthControllerTestSuite) TestFinishPasskeyRegistration_Rejected() {
+	// Arrange
+	suite.client.err = suite.badRequest("invalid or expired passkey ceremony",
+		&errdetails.BadRequest_FieldViolation{Field: "ceremony", Reason: "INVALID_CEREMONY", Description: "invalid or expired passkey ceremony"},
+	)
+
+	// Act
+	w := suite.postJSON("/auth/passkeys/register/finish", `{"ceremony":"pkc_register","credential":{"id":"cred"}}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "invalid or expired passkey ceremony")
+}
+
+func (suite *AuthControllerTestSuite) TestFinishPasskeyRegistration_MissingCredential() {
+	// Act
+	w := suite.postJSON("/auth/passkeys/register/finish", `{"ceremony":"pkc_register"}`)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.client.registeredKey)
+}
+
+func (suite *AuthControllerTestSuite) TestBeginPasskeyLogin_ReturnsOptions() {
+	// Act
+	w := suite.postJSON("/auth/passkeys/login/begin", ``)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"ceremony":"pkc_login"`)
+	suite.Contains(w.Body.String(), `"rpId":"localhost"`)
+}
+
+func (suite *AuthControllerTestSuite) TestFinishPasskeyLogin_SetsCookies() {
+	// Act
+	w := suite.postJSON("/auth/passkeys/login/finish", `{"ceremony":"pkc_login","credential":{"id":"cred"}}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("pkc_login", suite.client.passkeyLogin[0])
+	suite.Equal("jwt-passkey", suite.cookie(w, "auth_token").Value)
+	suite.Equal("rt_passkey", suite.cookie(w, "refresh_token").Value)
+}
+
+func (suite *AuthControllerTestSuite) TestFinishPasskeyLogin_TokenMode() {
+	// Act
+	w := suite.postJSON("/auth/passkeys/login/finish?response=token", `{"ceremony":"pkc_login","credential":{"id":"cred"}}`)
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Header().Get("Set-Cookie"))
+	suite.Contains(w.Body.String(), "jwt-passkey")
+}
+
+func (suite *AuthControllerTestSuite) TestFinishPasskeyLogin_InvalidPasskey() {
+	// Arrange
+	suite.client.err = status.Error(codes.Unauthenticated, "invalid passkey")
+
+	// Act
+	w := suite.postJSON("/auth/passkeys/login/finish", `{"ceremony":"pkc_login","credential":{"id":"cred"}}`)
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Contains(w.Body.String(), "invalid passkey")
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func TestAuthControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthControllerTestSuite))
+}
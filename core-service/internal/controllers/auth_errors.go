@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reasonAccountLocked is the ErrorInfo reason auth-service reports for a locked account
+const reasonAccountLocked = "ACCOUNT_LOCKED"
+
+// passwordFields are the request fields password policy violations are reported for
+var passwordFields = []string{"password", "new_password"}
+
+// authErrorStatus maps the gRPC codes of auth-service errors to HTTP statuses
+var authErrorStatus = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusServiceUnavailable,
+}
+
+// writeAuthError answers with the HTTP status matching the gRPC status of an auth-service error.
+// message describes the failed operation, the status message is passed on as details.
+func writeAuthError(c *gin.Context, err error, message string) {
+	st := status.Convert(err)
+
+	if st.Code() == codes.InvalidArgument {
+		if violations := passwordViolations(st); len(violations) > 0 {
+			writePasswordViolations(c, violations, st.Message())
+			return
+		}
+	}
+	if st.Code() == codes.ResourceExhausted {
+		if retryAfter, ok := retryAfterSeconds(st); ok {
+			writeLoginBlocked(c, hasReason(st, reasonAccountLocked), retryAfter, st.Message())
+			return
+		}
+	}
+
+	code, ok := authErrorStatus[st.Code()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{
+		"GetError": message,
+		"details":  st.Message(),
+	})
+}
+
+// writeLoginBlocked answers 429 with Retry-After while failed logins are throttled or the account is locked
+func writeLoginBlocked(c *gin.Context, locked bool, retryAfter int64, details string) {
+	message := "Too many failed login attempts"
+	if locked {
+		message = "Account temporarily locked"
+	}
+	c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"GetError": message,
+		"details":  details,
+	})
+}
+
+// writePasswordViolations answers 400 listing the password policy rules the new password does not meet
+func writePasswordViolations(c *gin.Context, violations []*errdetails.BadRequest_FieldViolation, details string) {
+	views := make([]gin.H, 0, len(violations))
+	for _, violation := range violations {
+		views = append(views, gin.H{
+			"code":    strings.ToLower(violation.Reason),
+			"message": violation.Description,
+		})
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"GetError":   "Password does not meet the password policy",
+		"details":    details,
+		"violations": views,
+	})
+}
+
+// passwordViolations returns the password policy violations among the field violations of st.
+// A missing password is not a policy violation.
+func passwordViolations(st *status.Status) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.FieldViolations {
+			if slices.Contains(passwordFields, violation.Field) && violation.Reason != "REQUIRED" {
+				violations = append(violations, violation)
+			}
+		}
+	}
+	return violations
+}
+
+// retryAfterSeconds reads the RetryInfo of st in whole seconds, at least 1
+func retryAfterSeconds(st *status.Status) (int64, bool) {
+	for _, detail := range st.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok && retryInfo.RetryDelay != nil {
+			seconds := int64(math.Ceil(retryInfo.RetryDelay.AsDuration().Seconds()))
+			return max(seconds, 1), true
+		}
+	}
+	return 0, false
+}
+
+// hasReason reports whether st carries an ErrorInfo with the reason
+func hasReason(st *status.Status, reason string) bool {
+	for _, detail := range st.Details() {
+		if errorInfo, ok := detail.(*errdetails.ErrorInfo); ok && errorInfo.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCProvider performs the relying party side of a login with an OpenID Connect identity provider
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (*models.OIDCIdentity, error)
+}
+
+// OIDCLoginClient signs in identities validated by the identity provider
+type OIDCLoginClient interface {
+	LoginWithOIDC(ctx context.Context, issuer, subject, email string, emailVerified bool) (*corepbv2.LoginWithOIDCResponse, error)
+}
+
+// The flow cookie keeps the secrets of a login between the redirect to the identity provider
+// and the callback, it is only sent to the OIDC endpoints
+const (
+	oidcFlowCookieName   = "oidc_flow"
+	oidcFlowCookiePath   = "/auth/oidc"
+	oidcFlowCookieMaxAge = 600
+)
+
+// oidcFlow is the state of a login started by Login
+type oidcFlow struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	Verifier     string `json:"verifier"`
+	ResponseMode string `json:"response_mode"`
+}
+
+// OIDCController signs users in with an external OpenID Connect identity provider
+type OIDCController struct {
+	Provider   OIDCProvider
+	AuthClient OIDCLoginClient
+}
+
+func NewOIDCController(provider OIDCProvider, authClient OIDCLoginClient) *OIDCController {
+	return &OIDCController{
+		Provider:   provider,
+		AuthClient: authClient,
+	}
+}
+
+// Login redirects the browser to the login page of the identity provider. The ?response=
+// mode is applied when the provider redirects back to Callback.
+func (oc *OIDCController) Login(c *gin.Context) {
+	responseMode, ok := parseResponseMode(c)
+	if !ok {
+		return
+	}
+
+	flow := oidcFlow{
+		State:        randomOIDCValue(),
+		Nonce:        randomOIDCValue(),
+		Verifier:     randomOIDCValue(),
+		ResponseMode: responseMode,
+	}
+
+	authURL, err := oc.Provider.AuthCodeURL(c.Request.Context(), flow.State, flow.Nonce, flow.Verifier)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"GetError": "Identity provider is unavailable",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	encoded, err := json.Marshal(flow)
+	if err != nil {
+		writeAuthError(c, err, "Failed to start login")
+		return
+	}
+	c.SetCookie(oidcFlowCookieName, base64.RawURLEncoding.EncodeToString(encoded), oidcFlowCookieMaxAge, oidcFlowCookiePath, "localhost", false, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback completes the login when the identity provider redirects back. The authorization
+// code is exchanged with the PKCE verifier, and the identity from the validated ID token is
+// signed in by auth-service. The tokens are delivered the same way as by Login, and accounts
+// with two-factor authentication get a challenge to be completed with VerifySecondFactor.
+func (oc *OIDCController) Callback(c *gin.Context) {
+	flow, ok := readOIDCFlow(c)
+	// The flow works once, whatever the outcome
+	c.SetCookie(oidcFlowCookieName, "", -1, oidcFlowCookiePath, "localhost", false, true)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Login session expired",
+			"details":  "start the login again",
+		})
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(c.Query("state")), []byte(flow.State)) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid state",
+			"details":  "the response does not belong to the login started by this browser",
+		})
+		return
+	}
+
+	if providerError := c.Query("error"); providerError != "" {
+		details := c.Query("error_description")
+		if details == "" {
+			details = providerError
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"GetError": "Identity provider login failed",
+			"details":  details,
+		})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"GetError": "Invalid request payload",
+			"details":  "code is required",
+		})
+		return
+	}
+
+	identity, err := oc.Provider.Exchange(c.Request.Context(), code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"GetError": "Identity provider login failed",
+			"details":  err.Error(),
+		})
+		return
+	}
+
+	resp, err := oc.AuthClient.LoginWithOIDC(c.Request.Context(), identity.Issuer, identity.Subject, identity.Email, identity.EmailVerified)
+	if err != nil {
+		writeAuthError(c, err, "Failed to authenticate")
+		return
+	}
+
+	if resp.SecondFactorRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"message":                "Second factor required",
+			"second_factor_required": true,
+			"challenge":              resp.Challenge,
+			"challenge_expires_at":   formatUnix(resp.ChallengeExpiresAt),
+		})
+		return
+	}
+
+	writeTokens(c, flow.ResponseMode, "Successful login", issuedTokens{
+		token:            resp.Token,
+		expiresAt:        resp.ExpiresAt,
+		refreshToken:     resp.RefreshToken,
+		refreshExpiresAt: resp.RefreshExpiresAt,
+	})
+}
+
+// readOIDCFlow decodes the flow cookie set by Login
+func readOIDCFlow(c *gin.Context) (oidcFlow, bool) {
+	var flow oidcFlow
+	value, err := c.Cookie(oidcFlowCookieName)
+	if err != nil || value == "" {
+		return flow, false
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return flow, false
+	}
+	if err := json.Unmarshal(decoded, &flow); err != nil || flow.State == "" {
+		return flow, false
+	}
+	return flow, true
+}
+
+// randomOIDCValue returns 256 random bits encoded as 43 URL-safe characters,
+// long enough for state, nonce and PKCE verifiers
+func randomOIDCValue() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
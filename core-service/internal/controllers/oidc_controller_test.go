@@ -0,0 +1,348 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/core-service/internal/config"
+	"github.com/Koshsky/subs-service/core-service/internal/controllers"
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/Koshsky/subs-service/core-service/internal/oidctest"
+	"github.com/Koshsky/subs-service/core-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeOIDCProvider is a controllers.OIDCProvider returning canned results
+type fakeOIDCProvider struct {
+	started     []string // state, nonce and verifier passed to AuthCodeURL
+	startErr    error
+	exchanged   []string // code, verifier and nonce passed to Exchange
+	identity    *models.OIDCIdentity
+	exchangeErr error
+}
+
+func (f *fakeOIDCProvider) AuthCodeURL(_ context.Context, state, nonce, verifier string) (string, error) {
+	f.started = []string{state, nonce, verifier}
+	if f.startErr != nil {
+		return "", f.startErr
+	}
+	return "https://idp.example.com/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (f *fakeOIDCProvider) Exchange(_ context.Context, code, verifier, nonce string) (*models.OIDCIdentity, error) {
+	f.exchanged = []string{code, verifier, nonce}
+	return f.identity, f.exchangeErr
+}
+
+// fakeOIDCLoginClient is a controllers.OIDCLoginClient returning a canned response, or err when it is set
+type fakeOIDCLoginClient struct {
+	identity *models.OIDCIdentity
+	response *corepbv2.LoginWithOIDCResponse
+	err      error
+}
+
+func (f *fakeOIDCLoginClient) LoginWithOIDC(_ context.Context, issuer, subject, email string, emailVerified bool) (*corepbv2.LoginWithOIDCResponse, error) {
+	f.identity = &models.OIDCIdentity{Issuer: issuer, Subject: subject, Email: email, EmailVerified: emailVerified}
+	return f.response, f.err
+}
+
+type OIDCControllerTestSuite struct {
+	suite.Suite
+	provider  *fakeOIDCProvider
+	client    *fakeOIDCLoginClient
+	router    *gin.Engine
+	expiresAt time.Time
+}
+
+func (suite *OIDCControllerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *OIDCControllerTestSuite) SetupTest() {
+	suite.expiresAt = time.Now().Add(time.Hour).Truncate(time.Second)
+	suite.provider = &fakeOIDCProvider{
+		identity: &models.OIDCIdentity{
+			Issuer:        "https://idp.example.com",
+			Subject:       "subject-1",
+			Email:         "sso@example.com",
+			EmailVerified: true,
+		},
+	}
+	suite.client = &fakeOIDCLoginClient{
+		response: &corepbv2.LoginWithOIDCResponse{
+			Token:            "jwt-sso",
+			ExpiresAt:        suite.expiresAt.Unix(),
+			RefreshToken:     "rt_sso",
+			RefreshExpiresAt: suite.expiresAt.Add(30 * 24 * time.Hour).Unix(),
+		},
+	}
+	suite.router = suite.newRouter(suite.provider)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// newRouter serves the OIDC endpoints with the provider and suite.client
+func (suite *OIDCControllerTestSuite) newRouter(provider controllers.OIDCProvider) *gin.Engine {
+	controller := controllers.NewOIDCController(provider, suite.client)
+	router := gin.New()
+	router.GET("/auth/oidc/login", controller.Login)
+	router.GET("/auth/oidc/callback", controller.Callback)
+	return router
+}
+
+// get performs a GET request with an optional cookie
+func (suite *OIDCControllerTestSuite) get(target string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// startLogin starts a login and returns the flow cookie
+func (suite *OIDCControllerTestSuite) startLogin(query string) *http.Cookie {
+	w := suite.get("/auth/oidc/login"+query, nil)
+	suite.Require().Equal(http.StatusFound, w.Code)
+	flow := suite.cookie(w, "oidc_flow")
+	suite.Require().NotNil(flow)
+	return flow
+}
+
+// cookie returns the cookie set by the response
+func (suite *OIDCControllerTestSuite) cookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// callback returns from the identity provider with the state of the started login
+func (suite *OIDCControllerTestSuite) callback(flow *http.Cookie, params url.Values) *httptest.ResponseRecorder {
+	if !params.Has("state") {
+		params.Set("state", suite.provider.started[0])
+	}
+	return suite.get("/auth/oidc/callback?"+params.Encode(), flow)
+}
+
+// ===== LOGIN TESTS =====
+
+func (suite *OIDCControllerTestSuite) TestLogin_RedirectsToProvider() {
+	// Act
+	w := suite.get("/auth/oidc/login", nil)
+
+	// Assert
+	suite.Equal(http.StatusFound, w.Code)
+	state, nonce, verifier := suite.provider.started[0], suite.provider.started[1], suite.provider.started[2]
+	suite.Equal("https://idp.example.com/authorize?state="+state, w.Header().Get("Location"))
+	suite.Len(state, 43)
+	suite.Len(verifier, 43)
+	suite.NotEqual(state, nonce)
+	suite.NotEqual(nonce, verifier)
+
+	flow := suite.cookie(w, "oidc_flow")
+	suite.Require().NotNil(flow)
+	suite.Equal("/auth/oidc", flow.Path)
+	suite.True(flow.HttpOnly)
+	suite.NotContains(flow.Value, verifier)
+}
+
+func (suite *OIDCControllerTestSuite) TestLogin_InvalidResponseMode() {
+	// Act
+	w := suite.get("/auth/oidc/login?response=header", nil)
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.provider.started)
+}
+
+func (suite *OIDCControllerTestSuite) TestLogin_ProviderUnavailable() {
+	// Arrange
+	suite.provider.startErr = errors.New("failed to discover identity provider: connection refused")
+
+	// Act
+	w := suite.get("/auth/oidc/login", nil)
+
+	// Assert
+	suite.Equal(http.StatusServiceUnavailable, w.Code)
+	suite.Nil(suite.cookie(w, "oidc_flow"))
+}
+
+// ===== CALLBACK TESTS =====
+
+func (suite *OIDCControllerTestSuite) TestCallback_SetsCookies() {
+	// Arrange
+	flow := suite.startLogin("")
+
+	// Act
+	w := suite.callback(flow, url.Values{"code": {"code-1"}})
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"code-1", suite.provider.started[2], suite.provider.started[1]}, suite.provider.exchanged)
+	suite.Equal(suite.provider.identity, suite.client.identity)
+	suite.Equal("jwt-sso", suite.cookie(w, "auth_token").Value)
+	suite.Equal("rt_sso", suite.cookie(w, "refresh_token").Value)
+	suite.Equal(-1, suite.cookie(w, "oidc_flow").MaxAge)
+}
+
+func (suite *OIDCControllerTestSuite) TestCallback_TokenMode() {
+	// Arrange
+	flow := suite.startLogin("?response=token")
+
+	// Act
+	w := suite.callback(flow, url.Values{"code": {"code-1"}})
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	var body map[string]any
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal("jwt-sso", body["token"])
+	suite.Equal("rt_sso", body["refresh_token"])
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *OIDCControllerTestSuite) TestCallback_StateMismatch() {
+	// Arrange
+	flow := suite.startLogin("")
+
+	// Act
+	w := suite.callback(flow, url.Values{"code": {"code-1"}, "state": {"forged"}})
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.provider.exchanged)
+	suite.Nil(suite.client.identity)
+}
+
+func (suite *OIDCControllerTestSuite) TestCallback_WithoutFlowCookie() {
+	// Arrange
+	suite.startLogin("")
+
+	// Act
+	w := suite.callback(nil, url.Values{"code": {"code-1"}})
+
+	// Assert
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Nil(suite.provider.exchanged)
+}
+
+func (suite *OIDCControllerTestSuite) TestCallback_ProviderError() {
+	// Arrange
+	flow := suite.startLogin("")
+
+	// Act
+	w := suite.callback(flow, url.Values{"error": {"access_denied"}, "error_description": {"User cancelled the login"}})
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Contains(w.Body.String(), "User cancelled the login")
+	suite.Nil(suite.provider.exchanged)
+}
+
+func (suite *OIDCControllerTestSuite) TestCallback_InvalidIDToken() {
+	// Arrange
+	suite.provider.exchangeErr = services.ErrInvalidIDToken
+	flow := suite.startLogin("")
+
+	// Act
+	w := suite.callback(flow, url.Values{"code": {"code-1"}})
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Nil(suite.client.identity)
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *OIDCControllerTestSuite) TestCallback_SecondFactorRequired() {
+	// Arrange
+	challengeExpiresAt := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	suite.client.response = &corepbv2.LoginWithOIDCResponse{
+		SecondFactorRequired: true,
+		Challenge:            "tfc_challenge",
+		ChallengeExpiresAt:   challengeExpiresAt.Unix(),
+	}
+	flow := suite.startLogin("")
+
+	// Act
+	w := suite.callback(flow, url.Values{"code": {"code-1"}})
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	var body map[string]any
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal(true, body["second_factor_required"])
+	suite.Equal("tfc_challenge", body["challenge"])
+	suite.Equal(challengeExpiresAt.UTC().Format(time.RFC3339), body["challenge_expires_at"])
+	suite.Nil(suite.cookie(w, "auth_token"))
+}
+
+func (suite *OIDCControllerTestSuite) TestCallback_LoginRejected() {
+	// Arrange
+	suite.client.err = status.Error(codes.PermissionDenied, "identity provider did not verify the email address")
+	flow := suite.startLogin("")
+
+	// Act
+	w := suite.callback(flow, url.Values{"code": {"code-1"}})
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Contains(w.Body.String(), "did not verify the email address")
+}
+
+// ===== MOCK PROVIDER TESTS =====
+
+func (suite *OIDCControllerTestSuite) TestLoginFlow_WithMockProvider() {
+	// Arrange
+	idp := oidctest.NewServer("core-service", "client-secret")
+	defer idp.Close()
+	suite.router = suite.newRouter(services.NewOIDCProvider(config.OIDCConfig{
+		IssuerURL:    idp.Issuer(),
+		ClientID:     "core-service",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/auth/oidc/callback",
+		Scopes:       []string{"openid", "email"},
+	}))
+
+	// Act
+	login := suite.get("/auth/oidc/login?response=token", nil)
+	suite.Require().Equal(http.StatusFound, login.Code)
+	noRedirects := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noRedirects.Get(login.Header().Get("Location"))
+	suite.Require().NoError(err)
+	resp.Body.Close()
+	suite.Require().Equal(http.StatusFound, resp.StatusCode)
+	callbackURL, err := url.Parse(resp.Header.Get("Location"))
+	suite.Require().NoError(err)
+	w := suite.get(callbackURL.RequestURI(), suite.cookie(login, "oidc_flow"))
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "jwt-sso")
+	suite.Equal(&models.OIDCIdentity{
+		Issuer:        idp.Issuer(),
+		Subject:       "oidctest-subject",
+		Email:         "sso@example.com",
+		EmailVerified: true,
+	}, suite.client.identity)
+}
+
+// Run tests
+func TestOIDCControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCControllerTestSuite))
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionClient defines the login session operations of the auth client
+type SessionClient interface {
+	ListSessions(ctx context.Context, userID string) (*corepbv2.ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) (*corepbv2.RevokeSessionResponse, error)
+}
+
+// SessionController lists the devices the current user is logged in on and signs them out
+type SessionController struct {
+	SessionClient SessionClient
+}
+
+func NewSessionController(sessionClient SessionClient) *SessionController {
+	return &SessionController{SessionClient: sessionClient}
+}
+
+// sessionView is the JSON representation of a login session
+type sessionView struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IP         string `json:"ip"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	Current    bool   `json:"current"`
+}
+
+func newSessionView(session *corepbv2.Session, currentID string) sessionView {
+	return sessionView{
+		ID:         session.Id,
+		UserAgent:  session.UserAgent,
+		IP:         session.Ip,
+		CreatedAt:  formatUnix(session.CreatedAt),
+		LastSeenAt: formatUnix(session.LastSeenAt),
+		Current:    session.Id != "" && session.Id == currentID,
+	}
+}
+
+// List lists the active login sessions of the current user.
+// The session of the request is marked as current.
+func (c *SessionController) List(ctx *gin.Context) {
+	resp, err := c.SessionClient.ListSessions(ctx.Request.Context(), ctx.GetString("user_id"))
+	if err != nil {
+		writeAuthError(ctx, err, "failed to list sessions")
+		return
+	}
+
+	currentID := ctx.GetString("session_id")
+	sessions := make([]sessionView, 0, len(resp.Sessions))
+	for _, session := range resp.Sessions {
+		sessions = append(sessions, newSessionView(session, currentID))
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// Revoke signs the device of a login session of the current user out
+func (c *SessionController) Revoke(ctx *gin.Context) {
+	_, err := c.SessionClient.RevokeSession(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"))
+	if err != nil {
+		writeAuthError(ctx, err, "failed to revoke session")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
@@ -0,0 +1,170 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Koshsky/subs-service/core-service/internal/controllers"
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSessionClient is a controllers.SessionClient recording the user and session it acts for
+type fakeSessionClient struct {
+	userID         string
+	sessionID      string
+	listResponse   *corepbv2.ListSessionsResponse
+	revokeResponse *corepbv2.RevokeSessionResponse
+	err            error
+}
+
+func (f *fakeSessionClient) ListSessions(_ context.Context, userID string) (*corepbv2.ListSessionsResponse, error) {
+	f.userID = userID
+	return f.listResponse, f.err
+}
+
+func (f *fakeSessionClient) RevokeSession(_ context.Context, userID, sessionID string) (*corepbv2.RevokeSessionResponse, error) {
+	f.userID = userID
+	f.sessionID = sessionID
+	return f.revokeResponse, f.err
+}
+
+type SessionControllerTestSuite struct {
+	suite.Suite
+	client *fakeSessionClient
+	router *gin.Engine
+}
+
+func (suite *SessionControllerTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *SessionControllerTestSuite) SetupTest() {
+	suite.client = &fakeSessionClient{}
+	controller := controllers.NewSessionController(suite.client)
+
+	suite.router = gin.New()
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", "user-id")
+		c.Set("session_id", "current-id")
+	})
+	suite.router.GET("/api/account/sessions", controller.List)
+	suite.router.DELETE("/api/account/sessions/:id", controller.Revoke)
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// request performs a request against the controller routes
+func (suite *SessionControllerTestSuite) request(method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// ===== TESTS =====
+
+func (suite *SessionControllerTestSuite) TestList_MarksCurrentSession() {
+	// Arrange
+	suite.client.listResponse = &corepbv2.ListSessionsResponse{
+		Sessions: []*corepbv2.Session{
+			{Id: "current-id", UserAgent: "Mozilla/5.0", Ip: "203.0.113.7", CreatedAt: 1767225600, LastSeenAt: 1775001600},
+			{Id: "other-id", UserAgent: "curl/8.5.0", Ip: "198.51.100.1", CreatedAt: 1767225600, LastSeenAt: 1767225600},
+		},
+	}
+
+	// Act
+	w := suite.request(http.MethodGet, "/api/account/sessions")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("user-id", suite.client.userID)
+	suite.JSONEq(`[
+		{
+			"id": "current-id",
+			"user_agent": "Mozilla/5.0",
+			"ip": "203.0.113.7",
+			"created_at": "2026-01-01T00:00:00Z",
+			"last_seen_at": "2026-04-01T00:00:00Z",
+			"current": true
+		},
+		{
+			"id": "other-id",
+			"user_agent": "curl/8.5.0",
+			"ip": "198.51.100.1",
+			"created_at": "2026-01-01T00:00:00Z",
+			"last_seen_at": "2026-01-01T00:00:00Z",
+			"current": false
+		}
+	]`, w.Body.String())
+}
+
+func (suite *SessionControllerTestSuite) TestList_Empty() {
+	// Arrange
+	suite.client.listResponse = &corepbv2.ListSessionsResponse{}
+
+	// Act
+	w := suite.request(http.MethodGet, "/api/account/sessions")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`[]`, w.Body.String())
+}
+
+func (suite *SessionControllerTestSuite) TestList_ClientError() {
+	// Arrange
+	suite.client.err = errors.New("connection refused")
+
+	// Act
+	w := suite.request(http.MethodGet, "/api/account/sessions")
+
+	// Assert
+	suite.Equal(http.StatusInternalServerError, w.Code)
+}
+
+func (suite *SessionControllerTestSuite) TestList_AuthServiceUnavailable() {
+	// Arrange
+	suite.client.err = status.Error(codes.Unavailable, "service temporarily unavailable")
+
+	// Act
+	w := suite.request(http.MethodGet, "/api/account/sessions")
+
+	// Assert
+	suite.Equal(http.StatusServiceUnavailable, w.Code)
+}
+
+func (suite *SessionControllerTestSuite) TestRevoke_Success() {
+	// Arrange
+	suite.client.revokeResponse = &corepbv2.RevokeSessionResponse{}
+
+	// Act
+	w := suite.request(http.MethodDelete, "/api/account/sessions/other-id")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("user-id", suite.client.userID)
+	suite.Equal("other-id", suite.client.sessionID)
+	suite.JSONEq(`{"message":"Session revoked"}`, w.Body.String())
+}
+
+func (suite *SessionControllerTestSuite) TestRevoke_NotFound() {
+	// Arrange
+	suite.client.err = status.Error(codes.NotFound, "session not found")
+
+	// Act
+	w := suite.request(http.MethodDelete, "/api/account/sessions/other-id")
+
+	// Assert
+	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Contains(w.Body.String(), "session not found")
+}
+
+func TestSessionControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionControllerTestSuite))
+}
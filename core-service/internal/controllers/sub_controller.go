@@ -25,6 +25,7 @@ type SubscriptionService interface {
 	GetUserSubscriptions(userID uuid.UUID) ([]models.Subscription, error)
 	UpdateByID(id int, update models.Subscription) (models.Subscription, error)
 	DeleteByID(id int) error
+	Search(filter models.SubscriptionFilter) ([]models.Subscription, error)
 }
 
 type SubscriptionController struct{ SubService SubscriptionService }
@@ -2,45 +2,154 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
 
-	"github.com/Koshsky/subs-service/core-service/internal/corepb"
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/Koshsky/subs-service/core-service/internal/models"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ValidateTokenFunc is a function that validates a token and returns user info
-type ValidateTokenFunc func(ctx context.Context, token string) (*corepb.UserResponse, error)
+type ValidateTokenFunc func(ctx context.Context, token string) (*corepbv2.ValidateTokenResponse, error)
+
+// ExtractToken returns the token sent with the request.
+//
+// Precedence rules:
+//   - if an Authorization header is present it always wins, and it must
+//     use the Bearer scheme; a malformed header is rejected rather than
+//     falling back to the cookie
+//   - otherwise the auth_token cookie is used
+func ExtractToken(c *gin.Context) (string, error) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		token = strings.TrimSpace(token)
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return "", errors.New("authorization header must be in the form 'Bearer <token>'")
+		}
+		return token, nil
+	}
+
+	token, err := c.Cookie("auth_token")
+	if err != nil || token == "" {
+		return "", errors.New("no bearer token or auth_token cookie provided")
+	}
+	return token, nil
+}
 
 // AuthMiddleware is a middleware that validates the token
 func AuthMiddleware(validateToken ValidateTokenFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString, err := c.Cookie("auth_token")
+		tokenString, err := ExtractToken(c)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"GetError": "Authorization required",
+				"details":  err.Error(),
 			})
 			return
 		}
 
 		resp, err := validateToken(c.Request.Context(), tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"GetError": "Invalid token",
-				"details":  err.Error(),
+			abortValidationError(c, err)
+			return
+		}
+
+		c.Set("email", resp.Email)
+		c.Set("user_id", resp.UserId)
+		c.Set("role", resp.Role)
+		c.Set("token_type", resp.TokenType)
+		c.Set("scopes", resp.Scopes)
+		c.Set("read_only", resp.ReadOnly)
+		c.Set("session_id", resp.SessionId)
+		c.Next()
+	}
+}
+
+// abortValidationError rejects a request whose token could not be validated. Only tokens
+// auth-service rejected are 401, so that clients do not sign out while it is unreachable.
+func abortValidationError(c *gin.Context, err error) {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Unauthenticated, codes.InvalidArgument:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"GetError": "Invalid token",
+			"details":  st.Message(),
+		})
+	case codes.Unavailable, codes.DeadlineExceeded:
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"GetError": "Authentication service unavailable",
+			"details":  st.Message(),
+		})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"GetError": "Failed to validate token",
+			"details":  st.Message(),
+		})
+	}
+}
+
+// RequireRole is a middleware that only lets through users with one of the given roles.
+// It must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if !slices.Contains(roles, role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"GetError": "forbidden",
+				"details":  "insufficient role",
 			})
 			return
 		}
+		c.Next()
+	}
+}
 
-		if !resp.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"GetError": "Invalid token",
-				"details":  resp.Error,
+// RequireScope is a middleware that only lets through personal access tokens granted the scope.
+// Session tokens are not scoped and always pass. It must run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("token_type") == models.TokenTypeAccessToken && !slices.Contains(c.GetStringSlice("scopes"), scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"GetError": "forbidden",
+				"details":  "token lacks scope " + scope,
 			})
 			return
 		}
+		c.Next()
+	}
+}
 
-		c.Set("email", resp.Email)
-		c.Set("user_id", resp.UserId)
+// RequireSession is a middleware that rejects requests authenticated with a personal access token,
+// so that such tokens cannot be used to manage other tokens. It must run after AuthMiddleware.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("token_type") == models.TokenTypeAccessToken {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"GetError": "forbidden",
+				"details":  "personal access tokens cannot be used here",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireWriteAccess is a middleware that rejects changes by users restricted to reading
+// until they verify their email address. It must run after AuthMiddleware.
+func RequireWriteAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("read_only") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"GetError": "forbidden",
+				"details":  "verify your email address to make changes",
+			})
+			return
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,373 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/Koshsky/subs-service/core-service/internal/middleware"
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuthMiddlewareTestSuite struct {
+	suite.Suite
+	validated []string
+	responses map[string]*corepbv2.ValidateTokenResponse
+	errors    map[string]error
+}
+
+func (suite *AuthMiddlewareTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *AuthMiddlewareTestSuite) SetupTest() {
+	suite.validated = nil
+	suite.errors = map[string]error{
+		"invalid-token": status.Error(codes.Unauthenticated, "token is expired"),
+		"outage-token":  status.Error(codes.Unavailable, "connection refused"),
+	}
+	suite.responses = map[string]*corepbv2.ValidateTokenResponse{
+		"user-token":    {UserId: "user-id", Email: "user@example.com", Role: "user", SessionId: "session-id"},
+		"support-token": {UserId: "support-id", Email: "support@example.com", Role: "support"},
+		"unverified-token": {
+			UserId: "unverified-id", Email: "unverified@example.com", Role: "user", ReadOnly: true,
+		},
+		"read-pat": {
+			UserId: "user-id", Email: "user@example.com", Role: "user",
+			TokenType: models.TokenTypeAccessToken, Scopes: []string{models.ScopeSubscriptionsRead},
+		},
+	}
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// validateToken is a fake ValidateTokenFunc recording the tokens it receives
+func (suite *AuthMiddlewareTestSuite) validateToken(_ context.Context, token string) (*corepbv2.ValidateTokenResponse, error) {
+	suite.validated = append(suite.validated, token)
+	if err, ok := suite.errors[token]; ok {
+		return nil, err
+	}
+	resp, ok := suite.responses[token]
+	if !ok {
+		return nil, errors.New("unknown token")
+	}
+	return resp, nil
+}
+
+// newRouter builds an engine with the middlewares under test and an echo handler
+func (suite *AuthMiddlewareTestSuite) newRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	handlers = append(handlers, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id": c.GetString("user_id"),
+			"email":   c.GetString("email"),
+			"role":    c.GetString("role"),
+		})
+	})
+	r.GET("/", handlers...)
+	return r
+}
+
+// request performs a GET / with the given auth_token cookie
+func (suite *AuthMiddlewareTestSuite) request(r *gin.Engine, cookie string) *httptest.ResponseRecorder {
+	return suite.requestWithHeader(r, "", cookie)
+}
+
+// requestWithHeader performs a GET / with the given Authorization header and auth_token cookie
+func (suite *AuthMiddlewareTestSuite) requestWithHeader(r *gin.Engine, authorization, cookie string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "auth_token", Value: cookie})
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// ===== AUTH MIDDLEWARE TESTS =====
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_SetsUserContext() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.request(r, "support-token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"user_id":"support-id","email":"support@example.com","role":"support"}`, w.Body.String())
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_SetsSessionID() {
+	// Arrange
+	var sessionID string
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken), func(c *gin.Context) {
+		sessionID = c.GetString("session_id")
+	})
+
+	// Act
+	w := suite.request(r, "user-token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("session-id", sessionID)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_MissingToken() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.request(r, "")
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Empty(suite.validated)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_InvalidToken() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.request(r, "invalid-token")
+
+	// Assert
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Contains(w.Body.String(), "token is expired")
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_ValidationError() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.request(r, "unknown")
+
+	// Assert
+	suite.Equal(http.StatusInternalServerError, w.Code)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_AuthServiceUnavailable() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.request(r, "outage-token")
+
+	// Assert
+	suite.Equal(http.StatusServiceUnavailable, w.Code)
+	suite.Contains(w.Body.String(), "Authentication service unavailable")
+}
+
+// ===== BEARER TOKEN TESTS =====
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_BearerHeader() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.requestWithHeader(r, "Bearer user-token", "")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"user_id":"user-id","email":"user@example.com","role":"user"}`, w.Body.String())
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_BearerSchemeCaseInsensitive() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.requestWithHeader(r, "bearer user-token", "")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"user-token"}, suite.validated)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_HeaderWinsOverCookie() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+	// Act
+	w := suite.requestWithHeader(r, "Bearer support-token", "user-token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal([]string{"support-token"}, suite.validated)
+	suite.Contains(w.Body.String(), `"role":"support"`)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthMiddleware_MalformedHeaderDoesNotFallBack() {
+	testCases := []string{
+		"user-token",
+		"Basic dXNlcjpwYXNz",
+		"Bearer",
+		"Bearer   ",
+	}
+
+	for _, header := range testCases {
+		suite.Run(header, func() {
+			// Arrange
+			suite.validated = nil
+			r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken))
+
+			// Act
+			w := suite.requestWithHeader(r, header, "user-token")
+
+			// Assert
+			suite.Equal(http.StatusUnauthorized, w.Code)
+			suite.Empty(suite.validated)
+		})
+	}
+}
+
+// ===== REQUIRE ROLE TESTS =====
+
+func (suite *AuthMiddlewareTestSuite) TestRequireRole_Allowed() {
+	// Arrange
+	r := suite.newRouter(
+		middleware.AuthMiddleware(suite.validateToken),
+		middleware.RequireRole("support", "admin"),
+	)
+
+	// Act
+	w := suite.request(r, "support-token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireRole_Forbidden() {
+	// Arrange
+	r := suite.newRouter(
+		middleware.AuthMiddleware(suite.validateToken),
+		middleware.RequireRole("support", "admin"),
+	)
+
+	// Act
+	w := suite.request(r, "user-token")
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireRole_WithoutAuthentication() {
+	// Arrange
+	r := suite.newRouter(middleware.RequireRole("admin"))
+
+	// Act
+	w := suite.request(r, "")
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+}
+
+// ===== REQUIRE SCOPE TESTS =====
+
+func (suite *AuthMiddlewareTestSuite) TestRequireScope_SessionTokenUnscoped() {
+	// Arrange
+	r := suite.newRouter(
+		middleware.AuthMiddleware(suite.validateToken),
+		middleware.RequireScope(models.ScopeSubscriptionsWrite),
+	)
+
+	// Act
+	w := suite.request(r, "user-token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireScope_AccessTokenWithScope() {
+	// Arrange
+	r := suite.newRouter(
+		middleware.AuthMiddleware(suite.validateToken),
+		middleware.RequireScope(models.ScopeSubscriptionsRead),
+	)
+
+	// Act
+	w := suite.requestWithHeader(r, "Bearer read-pat", "")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireScope_AccessTokenWithoutScope() {
+	// Arrange
+	r := suite.newRouter(
+		middleware.AuthMiddleware(suite.validateToken),
+		middleware.RequireScope(models.ScopeSubscriptionsWrite),
+	)
+
+	// Act
+	w := suite.requestWithHeader(r, "Bearer read-pat", "")
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Contains(w.Body.String(), models.ScopeSubscriptionsWrite)
+}
+
+// ===== REQUIRE SESSION TESTS =====
+
+func (suite *AuthMiddlewareTestSuite) TestRequireSession_AllowsSessionToken() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken), middleware.RequireSession())
+
+	// Act
+	w := suite.request(r, "user-token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireSession_RejectsAccessToken() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken), middleware.RequireSession())
+
+	// Act
+	w := suite.requestWithHeader(r, "Bearer read-pat", "")
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+}
+
+// ===== REQUIRE WRITE ACCESS TESTS =====
+
+func (suite *AuthMiddlewareTestSuite) TestRequireWriteAccess_AllowsVerifiedUser() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken), middleware.RequireWriteAccess())
+
+	// Act
+	w := suite.request(r, "user-token")
+
+	// Assert
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireWriteAccess_RejectsReadOnlyUser() {
+	// Arrange
+	r := suite.newRouter(middleware.AuthMiddleware(suite.validateToken), middleware.RequireWriteAccess())
+
+	// Act
+	w := suite.request(r, "unverified-token")
+
+	// Assert
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Contains(w.Body.String(), "verify your email address")
+}
+
+func TestAuthMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthMiddlewareTestSuite))
+}
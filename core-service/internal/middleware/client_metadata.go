@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+)
+
+// gRPC metadata keys auth-service records the client of a login session with
+const (
+	UserAgentMetadataKey = "x-client-user-agent"
+	ClientIPMetadataKey  = "x-client-ip"
+)
+
+// ClientMetadata is a middleware that forwards the user agent and IP of the end user
+// to auth-service as outgoing gRPC metadata of the request context
+func ClientMetadata() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := metadata.AppendToOutgoingContext(c.Request.Context(),
+			UserAgentMetadataKey, c.Request.UserAgent(),
+			ClientIPMetadataKey, c.ClientIP(),
+		)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
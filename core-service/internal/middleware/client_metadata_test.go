@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Koshsky/subs-service/core-service/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
+)
+
+type ClientMetadataTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ClientMetadataTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+// ===== CLIENT METADATA TESTS =====
+
+func (suite *ClientMetadataTestSuite) TestClientMetadata_ForwardsClient() {
+	// Arrange
+	var md metadata.MD
+	r := gin.New()
+	r.GET("/", middleware.ClientMetadata(), func(c *gin.Context) {
+		md, _ = metadata.FromOutgoingContext(c.Request.Context())
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	req.RemoteAddr = "203.0.113.7:51234"
+
+	// Act
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	suite.Equal([]string{"Mozilla/5.0"}, md.Get(middleware.UserAgentMetadataKey))
+	suite.Equal([]string{"203.0.113.7"}, md.Get(middleware.ClientIPMetadataKey))
+}
+
+// Run tests
+func TestClientMetadataTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientMetadataTestSuite))
+}
@@ -16,29 +16,59 @@ const (
 	IPExpiration      = 10 * time.Minute // When to remove inactive IPs
 )
 
+// Limits of the sensitive endpoints guarded by StrictRateLimiter:
+// a burst of StrictBurstLimit requests, then one request per StrictRequestInterval
+const (
+	StrictRequestInterval = 3 * time.Minute
+	StrictBurstLimit      = 5
+)
+
 type ipLimiter struct {
 	limiter  *rate.Limiter
 	lastSeen time.Time
 }
 
-var (
-	limiters = make(map[string]*ipLimiter)
-	mu       sync.RWMutex
+// ipRateLimiter keeps a token bucket per client IP
+type ipRateLimiter struct {
+	limit    rate.Limit
+	burst    int
+	limiters map[string]*ipLimiter
+	mu       sync.Mutex
 	once     sync.Once
-)
+}
+
+var defaultLimiter = newIPRateLimiter(RequestsPerSecond, BurstLimit)
 
 // RateLimiter is a middleware that limits the number of requests per second for each IP
 func RateLimiter() gin.HandlerFunc {
+	return defaultLimiter.middleware()
+}
+
+// StrictRateLimiter is a middleware for endpoints that send emails or accept secrets, such as
+// password reset. Each call creates an independent limiter, so that routes do not share a budget.
+func StrictRateLimiter() gin.HandlerFunc {
+	return newIPRateLimiter(rate.Every(StrictRequestInterval), StrictBurstLimit).middleware()
+}
+
+func newIPRateLimiter(limit rate.Limit, burst int) *ipRateLimiter {
+	return &ipRateLimiter{
+		limit:    limit,
+		burst:    burst,
+		limiters: make(map[string]*ipLimiter),
+	}
+}
+
+func (l *ipRateLimiter) middleware() gin.HandlerFunc {
 	// Start cleanup goroutine just once
-	once.Do(func() {
-		go cleanupExpiredIPs()
+	l.once.Do(func() {
+		go l.cleanupExpiredIPs()
 	})
 
 	return func(c *gin.Context) {
 		ip := c.ClientIP()
 
 		// Get or create the limiter for this IP
-		limiter := getLimiter(ip)
+		limiter := l.getLimiter(ip)
 
 		if !limiter.Allow() {
 			c.JSON(http.StatusTooManyRequests, gin.H{
@@ -52,35 +82,35 @@ func RateLimiter() gin.HandlerFunc {
 	}
 }
 
-func getLimiter(ip string) *rate.Limiter {
-	mu.Lock()
-	defer mu.Unlock()
+func (l *ipRateLimiter) getLimiter(ip string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
 	// Update last seen time if IP exists
-	if limiter, exists := limiters[ip]; exists {
+	if limiter, exists := l.limiters[ip]; exists {
 		limiter.lastSeen = time.Now()
 		return limiter.limiter
 	}
 
 	// Create new limiter for new IP
-	limiters[ip] = &ipLimiter{
-		limiter:  rate.NewLimiter(RequestsPerSecond, BurstLimit),
+	l.limiters[ip] = &ipLimiter{
+		limiter:  rate.NewLimiter(l.limit, l.burst),
 		lastSeen: time.Now(),
 	}
 
-	return limiters[ip].limiter
+	return l.limiters[ip].limiter
 }
 
-func cleanupExpiredIPs() {
+func (l *ipRateLimiter) cleanupExpiredIPs() {
 	for {
 		time.Sleep(CleanupInterval)
 
-		mu.Lock()
-		for ip, limiter := range limiters {
+		l.mu.Lock()
+		for ip, limiter := range l.limiters {
 			if time.Since(limiter.lastSeen) > IPExpiration {
-				delete(limiters, ip)
+				delete(l.limiters, ip)
 			}
 		}
-		mu.Unlock()
+		l.mu.Unlock()
 	}
 }
@@ -0,0 +1,35 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Koshsky/subs-service/core-service/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStrictRateLimiter tests that the strict limiter allows a burst per IP and per route
+func TestStrictRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.POST("/first", middleware.StrictRateLimiter(), ok)
+	r.POST("/second", middleware.StrictRateLimiter(), ok)
+
+	send := func(path, ip string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for i := 0; i < middleware.StrictBurstLimit; i++ {
+		assert.Equal(t, http.StatusOK, send("/first", "10.0.0.1"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("/first", "10.0.0.1"))
+	assert.Equal(t, http.StatusOK, send("/first", "10.0.0.2"))
+	assert.Equal(t, http.StatusOK, send("/second", "10.0.0.1"))
+}
@@ -0,0 +1,10 @@
+package models
+
+// OIDCIdentity is an account at an OpenID Connect identity provider as described by the
+// claims of a validated ID token. Issuer and Subject identify it, the email may change.
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
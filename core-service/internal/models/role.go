@@ -0,0 +1,8 @@
+package models
+
+// Roles assigned by auth-service and carried in validated tokens
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
@@ -0,0 +1,14 @@
+package models
+
+// Scopes of personal access tokens issued by auth-service
+const (
+	ScopeSubscriptionsRead  = "subscriptions:read"
+	ScopeSubscriptionsWrite = "subscriptions:write"
+	ScopeAdmin              = "admin"
+)
+
+// Token types auth-service reports for session tokens (JWT) and personal access tokens
+const (
+	TokenTypeJWT         = "jwt"
+	TokenTypeAccessToken = "pat"
+)
@@ -13,3 +13,12 @@ type Subscription struct {
 	StartDate MonthYear  `json:"start_date" gorm:"column:start_date" binding:"required"`
 	EndDate   *MonthYear `json:"end_date" gorm:"column:end_date"`
 }
+
+// SubscriptionFilter narrows down a subscription search across all users.
+// Zero values mean "no restriction".
+type SubscriptionFilter struct {
+	UserID  *uuid.UUID
+	Service string // case-insensitive substring of the service name
+	Limit   int
+	Offset  int
+}
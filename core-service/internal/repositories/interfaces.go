@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// SubscriptionStore is the storage contract for subscriptions.
+// Lookups of missing records return gorm.ErrRecordNotFound
+// regardless of the backend.
+type SubscriptionStore interface {
+	Create(sub models.Subscription) (models.Subscription, error)
+	GetByID(id uint) (models.Subscription, error)
+	GetUserSubscriptions(userID uuid.UUID) ([]models.Subscription, error)
+	UpdateByID(id uint, updatedSub models.Subscription) (models.Subscription, error)
+	DeleteByID(id uint) error
+	// DeleteUserSubscriptions deletes every subscription of the user and returns how many there were
+	DeleteUserSubscriptions(userID uuid.UUID) (int64, error)
+	// Search lists subscriptions of all users ordered by id
+	Search(filter models.SubscriptionFilter) ([]models.Subscription, error)
+}
+
+// Interface compliance checks - will fail at compile time if interfaces are not implemented
+var _ SubscriptionStore = (*SubscriptionRepository)(nil)
+var _ SubscriptionStore = (*MemorySubscriptionRepository)(nil)
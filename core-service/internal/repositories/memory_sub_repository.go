@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemorySubscriptionRepository keeps subscriptions in process memory.
+// It is meant for local development and tests; data is lost on restart.
+type MemorySubscriptionRepository struct {
+	mu     sync.RWMutex
+	subs   map[uint]models.Subscription
+	nextID uint
+}
+
+func NewMemorySubscriptionRepository() *MemorySubscriptionRepository {
+	return &MemorySubscriptionRepository{
+		subs:   make(map[uint]models.Subscription),
+		nextID: 1,
+	}
+}
+
+// GetUserSubscriptions gets user subscriptions ordered by id
+func (mr *MemorySubscriptionRepository) GetUserSubscriptions(userID uuid.UUID) ([]models.Subscription, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	var subs []models.Subscription
+	for _, sub := range mr.subs {
+		if sub.UserID == userID {
+			subs = append(subs, copySubscription(sub))
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	return subs, nil
+}
+
+// GetByID gets a subscription by id
+func (mr *MemorySubscriptionRepository) GetByID(id uint) (models.Subscription, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	sub, ok := mr.subs[id]
+	if !ok {
+		return models.Subscription{}, gorm.ErrRecordNotFound
+	}
+	return copySubscription(sub), nil
+}
+
+// Create creates a new subscription
+func (mr *MemorySubscriptionRepository) Create(sub models.Subscription) (models.Subscription, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now()
+	sub.ID = mr.nextID
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+	sub.DeletedAt = gorm.DeletedAt{}
+	mr.nextID++
+
+	mr.subs[sub.ID] = copySubscription(sub)
+	return sub, nil
+}
+
+// UpdateByID updates a subscription by id.
+// Like gorm's Updates with a struct, only non-zero fields are applied.
+func (mr *MemorySubscriptionRepository) UpdateByID(id uint, updatedSub models.Subscription) (models.Subscription, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	sub, ok := mr.subs[id]
+	if !ok {
+		return models.Subscription{}, gorm.ErrRecordNotFound
+	}
+
+	if updatedSub.Service != "" {
+		sub.Service = updatedSub.Service
+	}
+	if updatedSub.Price != 0 {
+		sub.Price = updatedSub.Price
+	}
+	if updatedSub.UserID != uuid.Nil {
+		sub.UserID = updatedSub.UserID
+	}
+	if !updatedSub.StartDate.Time().IsZero() {
+		sub.StartDate = updatedSub.StartDate
+	}
+	if updatedSub.EndDate != nil {
+		sub.EndDate = updatedSub.EndDate
+	}
+	sub.UpdatedAt = time.Now()
+
+	mr.subs[id] = copySubscription(sub)
+	return copySubscription(sub), nil
+}
+
+// DeleteByID deletes a subscription by id
+func (mr *MemorySubscriptionRepository) DeleteByID(id uint) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	delete(mr.subs, id)
+	return nil
+}
+
+// DeleteUserSubscriptions deletes every subscription of the user
+func (mr *MemorySubscriptionRepository) DeleteUserSubscriptions(userID uuid.UUID) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var deleted int64
+	for id, sub := range mr.subs {
+		if sub.UserID == userID {
+			delete(mr.subs, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Search lists subscriptions of all users matching the filter
+func (mr *MemorySubscriptionRepository) Search(filter models.SubscriptionFilter) ([]models.Subscription, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	service := strings.ToLower(filter.Service)
+	var subs []models.Subscription
+	for _, sub := range mr.subs {
+		if filter.UserID != nil && sub.UserID != *filter.UserID {
+			continue
+		}
+		if service != "" && !strings.Contains(strings.ToLower(sub.Service), service) {
+			continue
+		}
+		subs = append(subs, copySubscription(sub))
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(subs) {
+			return nil, nil
+		}
+		subs = subs[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(subs) {
+		subs = subs[:filter.Limit]
+	}
+	return subs, nil
+}
+
+// copySubscription detaches the EndDate pointer from the stored value
+func copySubscription(sub models.Subscription) models.Subscription {
+	if sub.EndDate != nil {
+		endDate := *sub.EndDate
+		sub.EndDate = &endDate
+	}
+	return sub
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// NewSQLiteSubscriptionRepository opens the SQLite database at path
+// (":memory:" for a throwaway one), creates the subscriptions table
+// if needed and returns a repository backed by it.
+// The SQL migrations target Postgres, so the schema is derived from
+// the model instead.
+func NewSQLiteSubscriptionRepository(path string) (*SubscriptionRepository, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite serializes writes anyway, and every connection to ":memory:"
+	// would otherwise see its own empty database
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sqlite database handle: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&models.Subscription{}); err != nil {
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+
+	return NewSubscriptionRepository(db), nil
+}
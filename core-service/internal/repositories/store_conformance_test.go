@@ -0,0 +1,361 @@
+package repositories_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/Koshsky/subs-service/core-service/internal/repositories"
+	"github.com/Koshsky/subs-service/core-service/migrations"
+	"github.com/Koshsky/subs-service/pkg/migrator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// StoreConformanceSuite runs the same behavioural checks against
+// every SubscriptionStore implementation
+type StoreConformanceSuite struct {
+	suite.Suite
+	newStore func() repositories.SubscriptionStore
+	store    repositories.SubscriptionStore
+	userID   uuid.UUID
+}
+
+func (s *StoreConformanceSuite) SetupTest() {
+	s.store = s.newStore()
+	s.userID = uuid.New()
+}
+
+// ===== HELPER FUNCTIONS =====
+
+func monthYear(value string) models.MonthYear {
+	t, err := time.Parse("01-2006", value)
+	if err != nil {
+		panic(err)
+	}
+	return models.MonthYear(t)
+}
+
+func (s *StoreConformanceSuite) newSubscription(service string, price int) models.Subscription {
+	return models.Subscription{
+		Service:   service,
+		Price:     price,
+		UserID:    s.userID,
+		StartDate: monthYear("07-2025"),
+	}
+}
+
+func (s *StoreConformanceSuite) assertMonthYear(expected string, actual models.MonthYear) {
+	s.Equal(expected, actual.Time().Format("01-2006"))
+}
+
+// ===== CREATE TESTS =====
+
+func (s *StoreConformanceSuite) TestCreate_AssignsIDAndTimestamps() {
+	// Arrange
+	sub := s.newSubscription("Yandex Plus", 450)
+	endDate := monthYear("12-2026")
+	sub.EndDate = &endDate
+
+	// Act
+	created, err := s.store.Create(sub)
+
+	// Assert
+	s.Require().NoError(err)
+	s.NotZero(created.ID)
+	s.False(created.CreatedAt.IsZero())
+	s.False(created.UpdatedAt.IsZero())
+
+	stored, err := s.store.GetByID(created.ID)
+	s.Require().NoError(err)
+	s.Equal("Yandex Plus", stored.Service)
+	s.Equal(450, stored.Price)
+	s.Equal(s.userID, stored.UserID)
+	s.assertMonthYear("07-2025", stored.StartDate)
+	s.Require().NotNil(stored.EndDate)
+	s.assertMonthYear("12-2026", *stored.EndDate)
+}
+
+func (s *StoreConformanceSuite) TestCreate_AssignsDistinctIDs() {
+	// Act
+	first, err := s.store.Create(s.newSubscription("Netflix", 999))
+	s.Require().NoError(err)
+	second, err := s.store.Create(s.newSubscription("Spotify", 299))
+	s.Require().NoError(err)
+
+	// Assert
+	s.NotEqual(first.ID, second.ID)
+}
+
+// ===== GET TESTS =====
+
+func (s *StoreConformanceSuite) TestGetByID_NotFound() {
+	// Act
+	_, err := s.store.GetByID(999999)
+
+	// Assert
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *StoreConformanceSuite) TestGetUserSubscriptions_OnlyOwnSubscriptions() {
+	// Arrange
+	own, err := s.store.Create(s.newSubscription("Netflix", 999))
+	s.Require().NoError(err)
+
+	other := s.newSubscription("Spotify", 299)
+	other.UserID = uuid.New()
+	_, err = s.store.Create(other)
+	s.Require().NoError(err)
+
+	// Act
+	subs, err := s.store.GetUserSubscriptions(s.userID)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(subs, 1)
+	s.Equal(own.ID, subs[0].ID)
+}
+
+func (s *StoreConformanceSuite) TestGetUserSubscriptions_UnknownUser() {
+	// Act
+	subs, err := s.store.GetUserSubscriptions(uuid.New())
+
+	// Assert
+	s.Require().NoError(err)
+	s.Empty(subs)
+}
+
+// ===== UPDATE TESTS =====
+
+func (s *StoreConformanceSuite) TestUpdateByID_AppliesNonZeroFields() {
+	// Arrange
+	created, err := s.store.Create(s.newSubscription("Yandex Plus", 450))
+	s.Require().NoError(err)
+	endDate := monthYear("12-2026")
+
+	// Act
+	updated, err := s.store.UpdateByID(created.ID, models.Subscription{
+		Price:   650,
+		EndDate: &endDate,
+	})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(created.ID, updated.ID)
+	s.Equal(650, updated.Price)
+
+	stored, err := s.store.GetByID(created.ID)
+	s.Require().NoError(err)
+	s.Equal("Yandex Plus", stored.Service)
+	s.Equal(650, stored.Price)
+	s.Equal(s.userID, stored.UserID)
+	s.assertMonthYear("07-2025", stored.StartDate)
+	s.Require().NotNil(stored.EndDate)
+	s.assertMonthYear("12-2026", *stored.EndDate)
+}
+
+func (s *StoreConformanceSuite) TestUpdateByID_NotFound() {
+	// Act
+	_, err := s.store.UpdateByID(999999, models.Subscription{Price: 1})
+
+	// Assert
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+// ===== DELETE TESTS =====
+
+func (s *StoreConformanceSuite) TestDeleteByID_RemovesSubscription() {
+	// Arrange
+	created, err := s.store.Create(s.newSubscription("Netflix", 999))
+	s.Require().NoError(err)
+
+	// Act
+	err = s.store.DeleteByID(created.ID)
+
+	// Assert
+	s.Require().NoError(err)
+	_, err = s.store.GetByID(created.ID)
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+
+	subs, err := s.store.GetUserSubscriptions(s.userID)
+	s.Require().NoError(err)
+	s.Empty(subs)
+}
+
+func (s *StoreConformanceSuite) TestDeleteByID_NotFound() {
+	// Act
+	err := s.store.DeleteByID(999999)
+
+	// Assert
+	s.Require().NoError(err)
+}
+
+func (s *StoreConformanceSuite) TestDeleteUserSubscriptions_OnlyOwnSubscriptions() {
+	// Arrange
+	_, err := s.store.Create(s.newSubscription("Netflix", 999))
+	s.Require().NoError(err)
+	_, err = s.store.Create(s.newSubscription("Spotify", 299))
+	s.Require().NoError(err)
+	other := s.newSubscription("Netflix", 999)
+	other.UserID = uuid.New()
+	other, err = s.store.Create(other)
+	s.Require().NoError(err)
+
+	// Act
+	deleted, err := s.store.DeleteUserSubscriptions(s.userID)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(int64(2), deleted)
+
+	subs, err := s.store.GetUserSubscriptions(s.userID)
+	s.Require().NoError(err)
+	s.Empty(subs)
+	_, err = s.store.GetByID(other.ID)
+	s.Require().NoError(err)
+}
+
+func (s *StoreConformanceSuite) TestDeleteUserSubscriptions_UnknownUser() {
+	// Act
+	deleted, err := s.store.DeleteUserSubscriptions(uuid.New())
+
+	// Assert
+	s.Require().NoError(err)
+	s.Zero(deleted)
+}
+
+// ===== SEARCH TESTS =====
+
+func (s *StoreConformanceSuite) TestSearch_AcrossUsers() {
+	// Arrange
+	first, err := s.store.Create(s.newSubscription("Netflix", 999))
+	s.Require().NoError(err)
+	other := s.newSubscription("Spotify", 299)
+	other.UserID = uuid.New()
+	second, err := s.store.Create(other)
+	s.Require().NoError(err)
+
+	// Act
+	subs, err := s.store.Search(models.SubscriptionFilter{})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(subs, 2)
+	s.Equal(first.ID, subs[0].ID)
+	s.Equal(second.ID, subs[1].ID)
+}
+
+func (s *StoreConformanceSuite) TestSearch_ByUserAndService() {
+	// Arrange
+	_, err := s.store.Create(s.newSubscription("Yandex Plus", 450))
+	s.Require().NoError(err)
+	match, err := s.store.Create(s.newSubscription("Netflix Premium", 999))
+	s.Require().NoError(err)
+	other := s.newSubscription("Netflix", 799)
+	other.UserID = uuid.New()
+	_, err = s.store.Create(other)
+	s.Require().NoError(err)
+
+	// Act
+	subs, err := s.store.Search(models.SubscriptionFilter{UserID: &s.userID, Service: "netFLIX"})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(subs, 1)
+	s.Equal(match.ID, subs[0].ID)
+}
+
+func (s *StoreConformanceSuite) TestSearch_WildcardsAreLiteral() {
+	// Arrange
+	_, err := s.store.Create(s.newSubscription("Netflix", 999))
+	s.Require().NoError(err)
+	match, err := s.store.Create(s.newSubscription("100% Music", 199))
+	s.Require().NoError(err)
+
+	// Act
+	subs, err := s.store.Search(models.SubscriptionFilter{Service: "%"})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(subs, 1)
+	s.Equal(match.ID, subs[0].ID)
+}
+
+func (s *StoreConformanceSuite) TestSearch_Pagination() {
+	// Arrange
+	var ids []uint
+	for _, name := range []string{"A1", "A2", "A3"} {
+		sub, err := s.store.Create(s.newSubscription(name, 100))
+		s.Require().NoError(err)
+		ids = append(ids, sub.ID)
+	}
+
+	// Act
+	page, err := s.store.Search(models.SubscriptionFilter{Limit: 1, Offset: 1})
+	s.Require().NoError(err)
+	beyond, err := s.store.Search(models.SubscriptionFilter{Limit: 10, Offset: 5})
+	s.Require().NoError(err)
+
+	// Assert
+	s.Require().Len(page, 1)
+	s.Equal(ids[1], page[0].ID)
+	s.Empty(beyond)
+}
+
+// ===== BACKENDS =====
+
+func TestMemoryStoreConformance(t *testing.T) {
+	suite.Run(t, &StoreConformanceSuite{
+		newStore: func() repositories.SubscriptionStore {
+			return repositories.NewMemorySubscriptionRepository()
+		},
+	})
+}
+
+func TestSQLiteStoreConformance(t *testing.T) {
+	suite.Run(t, &StoreConformanceSuite{
+		newStore: func() repositories.SubscriptionStore {
+			repo, err := repositories.NewSQLiteSubscriptionRepository(":memory:")
+			if err != nil {
+				t.Fatalf("failed to open sqlite store: %v", err)
+			}
+			return repo
+		},
+	})
+}
+
+// TestPostgresStoreConformance runs against the database in CORE_TEST_DB_DSN,
+// e.g. "host=localhost port=5434 user=core_user password=core_pass dbname=core_db sslmode=disable".
+// The embedded migrations are applied and the table is truncated between tests.
+func TestPostgresStoreConformance(t *testing.T) {
+	dsn := os.Getenv("CORE_TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("CORE_TEST_DB_DSN is not set")
+	}
+
+	m, err := migrator.New(dsn, migrations.FS)
+	if err != nil {
+		t.Fatalf("failed to init migrator: %v", err)
+	}
+	if err := m.Up(0); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	m.Close()
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	suite.Run(t, &StoreConformanceSuite{
+		newStore: func() repositories.SubscriptionStore {
+			if err := db.Exec("TRUNCATE subscriptions RESTART IDENTITY").Error; err != nil {
+				t.Fatalf("failed to truncate subscriptions: %v", err)
+			}
+			return repositories.NewSubscriptionRepository(db)
+		},
+	})
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"strings"
+
 	"github.com/Koshsky/subs-service/core-service/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -48,3 +50,34 @@ func (sr *SubscriptionRepository) DeleteByID(id uint) error {
 	result := sr.DB.Delete(&models.Subscription{}, id)
 	return result.Error
 }
+
+// DeleteUserSubscriptions deletes every subscription of the user
+func (sr *SubscriptionRepository) DeleteUserSubscriptions(userID uuid.UUID) (int64, error) {
+	result := sr.DB.Where("user_id = ?", userID).Delete(&models.Subscription{})
+	return result.RowsAffected, result.Error
+}
+
+// Search lists subscriptions of all users matching the filter
+func (sr *SubscriptionRepository) Search(filter models.SubscriptionFilter) ([]models.Subscription, error) {
+	query := sr.DB.Model(&models.Subscription{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Service != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(filter.Service)) + "%"
+		query = query.Where(`LOWER(service_name) LIKE ? ESCAPE '\'`, pattern)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var subs []models.Subscription
+	result := query.Order("id").Find(&subs)
+	return subs, result.Error
+}
+
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"expvar"
+	"fmt"
 	"net/http"
 	"net/http/pprof"
 	"time"
@@ -9,49 +11,180 @@ import (
 
 	"github.com/Koshsky/subs-service/core-service/internal/controllers"
 	"github.com/Koshsky/subs-service/core-service/internal/middleware"
+	"github.com/Koshsky/subs-service/core-service/internal/models"
 )
 
-// SetupRouter sets up the router
+// SetupRouter sets up the router. The client IP, which rate limits and login lockouts are keyed by,
+// is only taken from X-Forwarded-For and X-Real-IP of requests sent by one of trustedProxies.
 func SetupRouter(
 	subService controllers.SubscriptionService,
 	authClient controllers.AuthClient,
+	tokenClient controllers.AccessTokenClient,
+	sessionClient controllers.SessionClient,
+	oidcClient controllers.OIDCLoginClient,
+	oidcProvider controllers.OIDCProvider,
 	validateToken middleware.ValidateTokenFunc,
-) *gin.Engine {
+	trustedProxies []string,
+) (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
 
 	subController := controllers.NewSubscriptionController(subService)
 	authController := controllers.NewAuthController(authClient)
+	adminController := controllers.NewAdminController(subService)
+	tokenController := controllers.NewAccessTokenController(tokenClient)
+	sessionController := controllers.NewSessionController(sessionClient)
 
 	r := gin.Default()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 	r.Use(middleware.RateLimiter())
+	r.Use(middleware.ClientMetadata())
 	r.GET("/health", healthCheck)
 
-	// Auth routes (no auth required)
+	// Auth routes (no auth required, except for signing out everywhere and changing credentials)
 	authGroup := r.Group("/auth")
 	{
 		authGroup.POST("/register", authController.Register)
 		authGroup.POST("/login", authController.Login)
+		authGroup.POST("/refresh", authController.Refresh)
+		authGroup.POST("/logout", authController.Logout)
+		authGroup.POST("/logout-all",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			authController.LogoutAll,
+		)
+
+		// Password reset and magic links send emails and accept emailed tokens, so they are limited much more strictly
+		authGroup.POST("/password-reset", middleware.StrictRateLimiter(), authController.RequestPasswordReset)
+		authGroup.POST("/password-reset/confirm", middleware.StrictRateLimiter(), authController.ResetPassword)
+		authGroup.POST("/magic-link", middleware.StrictRateLimiter(), authController.RequestMagicLink)
+		authGroup.POST("/magic-link/consume", middleware.StrictRateLimiter(), authController.ConsumeMagicLink)
+		authGroup.POST("/verify-email", middleware.StrictRateLimiter(), authController.VerifyEmail)
+		authGroup.POST("/verify-email/resend", middleware.StrictRateLimiter(), authController.ResendVerificationEmail)
+
+		// Credential changes and account deletion need the current password, which is guarded like the login
+		authGroup.POST("/change-password",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			middleware.StrictRateLimiter(),
+			authController.ChangePassword,
+		)
+		authGroup.POST("/change-email",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			middleware.StrictRateLimiter(),
+			authController.ChangeEmail,
+		)
+		authGroup.POST("/delete-account",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			middleware.StrictRateLimiter(),
+			authController.DeleteAccount,
+		)
+
+		// Two-factor authentication: the second login step accepts codes, so it is limited strictly,
+		// and the authenticator app can only be managed from a login session
+		twoFactor := authGroup.Group("/2fa")
+		twoFactor.POST("/verify", middleware.StrictRateLimiter(), authController.VerifySecondFactor)
+		twoFactor.POST("/enroll",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			authController.EnrollTOTP,
+		)
+		twoFactor.POST("/confirm",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			middleware.StrictRateLimiter(),
+			authController.ConfirmTOTP,
+		)
+		twoFactor.POST("/disable",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			middleware.StrictRateLimiter(),
+			authController.DisableTOTP,
+		)
+
+		// Passkeys: registration needs a login session, the login finish step verifies
+		// signatures and is limited like the password login
+		passkeys := authGroup.Group("/passkeys")
+		passkeys.POST("/register/begin",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			authController.BeginPasskeyRegistration,
+		)
+		passkeys.POST("/register/finish",
+			middleware.AuthMiddleware(validateToken),
+			middleware.RequireSession(),
+			middleware.StrictRateLimiter(),
+			authController.FinishPasskeyRegistration,
+		)
+		passkeys.POST("/login/begin", authController.BeginPasskeyLogin)
+		passkeys.POST("/login/finish", middleware.StrictRateLimiter(), authController.FinishPasskeyLogin)
+
+		// Single sign-on with an OpenID Connect identity provider, when one is configured.
+		// The callback redeems authorization codes and is limited like the password login
+		if oidcProvider != nil {
+			oidcController := controllers.NewOIDCController(oidcProvider, oidcClient)
+			sso := authGroup.Group("/oidc")
+			sso.GET("/login", oidcController.Login)
+			sso.GET("/callback", middleware.StrictRateLimiter(), oidcController.Callback)
+		}
 	}
 
 	// Protected routes (require authentication)
+	// Personal access tokens additionally need the scope of each route,
+	// and users with an unverified email may be limited to reading
 	api := r.Group("/api")
 	api.Use(middleware.AuthMiddleware(validateToken))
 	{
+		canRead := middleware.RequireScope(models.ScopeSubscriptionsRead)
+		canWrite := middleware.RequireScope(models.ScopeSubscriptionsWrite)
+		writable := middleware.RequireWriteAccess()
+
 		subscriptions := api.Group("/subscriptions")
 		{
-			subscriptions.POST("", subController.Create)
-			subscriptions.GET("", subController.List)
-			subscriptions.GET("/:id", subController.Get)
-			subscriptions.PUT("/:id", subController.Update)
-			subscriptions.DELETE("/:id", subController.Delete)
+			subscriptions.POST("", writable, canWrite, subController.Create)
+			subscriptions.GET("", canRead, subController.List)
+			subscriptions.GET("/:id", canRead, subController.Get)
+			subscriptions.PUT("/:id", writable, canWrite, subController.Update)
+			subscriptions.DELETE("/:id", writable, canWrite, subController.Delete)
 		}
 
+		// Personal access tokens can only be managed from a login session
+		tokens := api.Group("/tokens")
+		tokens.Use(middleware.RequireSession(), writable)
+		{
+			tokens.POST("", tokenController.Create)
+			tokens.GET("", tokenController.List)
+			tokens.DELETE("/:id", tokenController.Revoke)
+		}
+
+		// Login sessions, one per device, can likewise only be managed from a login session
+		sessions := api.Group("/account/sessions")
+		sessions.Use(middleware.RequireSession())
+		{
+			sessions.GET("", sessionController.List)
+			sessions.DELETE("/:id", sessionController.Revoke)
+		}
+	}
+
+	// Admin routes (require support or admin role)
+	admin := r.Group("/admin")
+	admin.Use(
+		middleware.AuthMiddleware(validateToken),
+		middleware.RequireRole(models.RoleSupport, models.RoleAdmin),
+		middleware.RequireScope(models.ScopeAdmin),
+	)
+	{
+		admin.GET("/subscriptions", adminController.ListSubscriptions)
+		admin.GET("/subscriptions/:id", adminController.GetSubscription)
 	}
 
 	// for debugging
 	registerPprofHandlers(r)
 
-	return r
+	return r, nil
 }
 
 var pprofPath = "/internal/debug/pprof"
@@ -70,6 +203,8 @@ func registerPprofHandlers(r *gin.Engine) {
 		pprofGroup.GET("/threadcreate", pprofHandler(pprof.Handler("threadcreate").ServeHTTP))
 		pprofGroup.GET("/block", pprofHandler(pprof.Handler("block").ServeHTTP))
 		pprofGroup.GET("/mutex", pprofHandler(pprof.Handler("mutex").ServeHTTP))
+		// expvar counters, including token_cache hits and misses
+		pprofGroup.GET("/vars", pprofHandler(expvar.Handler().ServeHTTP))
 	}
 }
 
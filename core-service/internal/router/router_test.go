@@ -0,0 +1,55 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Koshsky/subs-service/core-service/internal/router"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientIP returns the client IP the router sees for a request from remoteAddr with X-Forwarded-For
+func clientIP(t *testing.T, trustedProxies []string, remoteAddr string) string {
+	r, err := router.SetupRouter(nil, nil, nil, nil, nil, nil, nil, trustedProxies)
+	require.NoError(t, err)
+	r.GET("/client-ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+	req := httptest.NewRequest(http.MethodGet, "/client-ip", nil)
+	req.RemoteAddr = remoteAddr + ":1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+func TestSetupRouter_TrustedProxies(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		want           string
+	}{
+		{name: "no trusted proxies", trustedProxies: nil, remoteAddr: "10.0.0.5", want: "10.0.0.5"},
+		{name: "trusted proxy", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.5", want: "203.0.113.7"},
+		{name: "untrusted proxy", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "192.168.1.5", want: "192.168.1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, clientIP(t, tt.trustedProxies, tt.remoteAddr))
+		})
+	}
+}
+
+func TestSetupRouter_InvalidTrustedProxies(t *testing.T) {
+	// Act
+	r, err := router.SetupRouter(nil, nil, nil, nil, nil, nil, nil, []string{"not-an-ip"})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, r)
+}
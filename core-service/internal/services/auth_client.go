@@ -5,7 +5,7 @@ import (
 	"log"
 	"time"
 
-	"github.com/Koshsky/subs-service/core-service/internal/corepb"
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -13,7 +13,7 @@ import (
 )
 
 type AuthClient struct {
-	client corepb.AuthServiceClient
+	client corepbv2.AuthServiceClient
 	conn   *grpc.ClientConn
 }
 
@@ -50,7 +50,7 @@ func NewAuthClient(authServiceAddr string, enableTLS bool, tlsCertFile string) (
 		return nil, err
 	}
 
-	client := corepb.NewAuthServiceClient(conn)
+	client := corepbv2.NewAuthServiceClient(conn)
 
 	ac := &AuthClient{
 		client: client,
@@ -72,7 +72,7 @@ func (ac *AuthClient) warmupConnection() {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	req := &corepb.TokenRequest{Token: ""}
+	req := &corepbv2.ValidateTokenRequest{Token: ""}
 	_, err := ac.client.ValidateToken(ctx, req)
 	if err != nil {
 		log.Printf("Warmup connection completed (expected error: %v)", err)
@@ -81,11 +81,11 @@ func (ac *AuthClient) warmupConnection() {
 	}
 }
 
-func (ac *AuthClient) ValidateToken(ctx context.Context, token string) (*corepb.UserResponse, error) {
+func (ac *AuthClient) ValidateToken(ctx context.Context, token string) (*corepbv2.ValidateTokenResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
-	req := &corepb.TokenRequest{Token: token}
+	req := &corepbv2.ValidateTokenRequest{Token: token}
 	resp, err := ac.client.ValidateToken(ctx, req)
 	if err != nil {
 		log.Printf("Failed to validate token: %v", err)
@@ -94,8 +94,8 @@ func (ac *AuthClient) ValidateToken(ctx context.Context, token string) (*corepb.
 	return resp, nil
 }
 
-func (ac *AuthClient) Register(ctx context.Context, email, password string) (*corepb.RegisterResponse, error) {
-	req := &corepb.RegisterRequest{Email: email, Password: password}
+func (ac *AuthClient) Register(ctx context.Context, email, password string) (*corepbv2.RegisterResponse, error) {
+	req := &corepbv2.RegisterRequest{Email: email, Password: password}
 	resp, err := ac.client.Register(ctx, req)
 
 	if err != nil {
@@ -105,11 +105,281 @@ func (ac *AuthClient) Register(ctx context.Context, email, password string) (*co
 	return resp, nil
 }
 
-func (ac *AuthClient) Login(ctx context.Context, email, password string) (*corepb.LoginResponse, error) {
-	req := &corepb.LoginRequest{Email: email, Password: password}
+func (ac *AuthClient) Login(ctx context.Context, email, password string) (*corepbv2.LoginResponse, error) {
+	req := &corepbv2.LoginRequest{Email: email, Password: password}
 	resp, err := ac.client.Login(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
+
+func (ac *AuthClient) VerifySecondFactor(ctx context.Context, challenge, code string) (*corepbv2.VerifySecondFactorResponse, error) {
+	req := &corepbv2.VerifySecondFactorRequest{Challenge: challenge, Code: code}
+	resp, err := ac.client.VerifySecondFactor(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) Refresh(ctx context.Context, refreshToken string) (*corepbv2.RefreshResponse, error) {
+	req := &corepbv2.RefreshRequest{RefreshToken: refreshToken}
+	resp, err := ac.client.Refresh(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) Logout(ctx context.Context, token, refreshToken string) (*corepbv2.LogoutResponse, error) {
+	req := &corepbv2.LogoutRequest{Token: token, RefreshToken: refreshToken}
+	resp, err := ac.client.Logout(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) LogoutAll(ctx context.Context, userID string) (*corepbv2.LogoutAllResponse, error) {
+	req := &corepbv2.LogoutAllRequest{UserId: userID}
+	resp, err := ac.client.LogoutAll(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) RequestPasswordReset(ctx context.Context, email string) (*corepbv2.RequestPasswordResetResponse, error) {
+	req := &corepbv2.RequestPasswordResetRequest{Email: email}
+	resp, err := ac.client.RequestPasswordReset(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ResetPassword(ctx context.Context, token, newPassword string) (*corepbv2.ResetPasswordResponse, error) {
+	req := &corepbv2.ResetPasswordRequest{Token: token, NewPassword: newPassword}
+	resp, err := ac.client.ResetPassword(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) RequestMagicLink(ctx context.Context, email string) (*corepbv2.RequestMagicLinkResponse, error) {
+	req := &corepbv2.RequestMagicLinkRequest{Email: email}
+	resp, err := ac.client.RequestMagicLink(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ConsumeMagicLink(ctx context.Context, token string) (*corepbv2.ConsumeMagicLinkResponse, error) {
+	req := &corepbv2.ConsumeMagicLinkRequest{Token: token}
+	resp, err := ac.client.ConsumeMagicLink(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) VerifyEmail(ctx context.Context, token string) (*corepbv2.VerifyEmailResponse, error) {
+	req := &corepbv2.VerifyEmailRequest{Token: token}
+	resp, err := ac.client.VerifyEmail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ResendVerificationEmail(ctx context.Context, email string) (*corepbv2.ResendVerificationEmailResponse, error) {
+	req := &corepbv2.ResendVerificationEmailRequest{Email: email}
+	resp, err := ac.client.ResendVerificationEmail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) (*corepbv2.ChangePasswordResponse, error) {
+	req := &corepbv2.ChangePasswordRequest{
+		UserId:          userID,
+		CurrentPassword: currentPassword,
+		NewPassword:     newPassword,
+	}
+	resp, err := ac.client.ChangePassword(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ChangeEmail(ctx context.Context, userID, currentPassword, newEmail string) (*corepbv2.ChangeEmailResponse, error) {
+	req := &corepbv2.ChangeEmailRequest{
+		UserId:          userID,
+		CurrentPassword: currentPassword,
+		NewEmail:        newEmail,
+	}
+	resp, err := ac.client.ChangeEmail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) DeleteAccount(ctx context.Context, userID, currentPassword string) (*corepbv2.DeleteAccountResponse, error) {
+	req := &corepbv2.DeleteAccountRequest{
+		UserId:          userID,
+		CurrentPassword: currentPassword,
+	}
+	resp, err := ac.client.DeleteAccount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) EnrollTOTP(ctx context.Context, userID string) (*corepbv2.EnrollTOTPResponse, error) {
+	req := &corepbv2.EnrollTOTPRequest{UserId: userID}
+	resp, err := ac.client.EnrollTOTP(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ConfirmTOTP(ctx context.Context, userID, code string) (*corepbv2.ConfirmTOTPResponse, error) {
+	req := &corepbv2.ConfirmTOTPRequest{UserId: userID, Code: code}
+	resp, err := ac.client.ConfirmTOTP(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) DisableTOTP(ctx context.Context, userID, currentPassword, code string) (*corepbv2.DisableTOTPResponse, error) {
+	req := &corepbv2.DisableTOTPRequest{
+		UserId:          userID,
+		CurrentPassword: currentPassword,
+		Code:            code,
+	}
+	resp, err := ac.client.DisableTOTP(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) BeginPasskeyRegistration(ctx context.Context, userID string) (*corepbv2.BeginPasskeyRegistrationResponse, error) {
+	req := &corepbv2.BeginPasskeyRegistrationRequest{UserId: userID}
+	resp, err := ac.client.BeginPasskeyRegistration(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) FinishPasskeyRegistration(ctx context.Context, userID, ceremony, credential string) (*corepbv2.FinishPasskeyRegistrationResponse, error) {
+	req := &corepbv2.FinishPasskeyRegistrationRequest{
+		UserId:     userID,
+		Ceremony:   ceremony,
+		Credential: credential,
+	}
+	resp, err := ac.client.FinishPasskeyRegistration(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) BeginPasskeyLogin(ctx context.Context) (*corepbv2.BeginPasskeyLoginResponse, error) {
+	resp, err := ac.client.BeginPasskeyLogin(ctx, &corepbv2.BeginPasskeyLoginRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) FinishPasskeyLogin(ctx context.Context, ceremony, credential string) (*corepbv2.FinishPasskeyLoginResponse, error) {
+	req := &corepbv2.FinishPasskeyLoginRequest{Ceremony: ceremony, Credential: credential}
+	resp, err := ac.client.FinishPasskeyLogin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) LoginWithOIDC(ctx context.Context, issuer, subject, email string, emailVerified bool) (*corepbv2.LoginWithOIDCResponse, error) {
+	req := &corepbv2.LoginWithOIDCRequest{
+		Issuer:        issuer,
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: emailVerified,
+	}
+	resp, err := ac.client.LoginWithOIDC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) CreateAccessToken(ctx context.Context, userID, name string, scopes []string, expiresInDays int32) (*corepbv2.CreateAccessTokenResponse, error) {
+	req := &corepbv2.CreateAccessTokenRequest{
+		UserId:        userID,
+		Name:          name,
+		Scopes:        scopes,
+		ExpiresInDays: expiresInDays,
+	}
+	resp, err := ac.client.CreateAccessToken(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ListAccessTokens(ctx context.Context, userID string) (*corepbv2.ListAccessTokensResponse, error) {
+	req := &corepbv2.ListAccessTokensRequest{UserId: userID}
+	resp, err := ac.client.ListAccessTokens(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) RevokeAccessToken(ctx context.Context, userID, tokenID string) (*corepbv2.RevokeAccessTokenResponse, error) {
+	req := &corepbv2.RevokeAccessTokenRequest{UserId: userID, TokenId: tokenID}
+	resp, err := ac.client.RevokeAccessToken(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) ListSessions(ctx context.Context, userID string) (*corepbv2.ListSessionsResponse, error) {
+	req := &corepbv2.ListSessionsRequest{UserId: userID}
+	resp, err := ac.client.ListSessions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (ac *AuthClient) RevokeSession(ctx context.Context, userID, sessionID string) (*corepbv2.RevokeSessionResponse, error) {
+	req := &corepbv2.RevokeSessionRequest{UserId: userID, SessionId: sessionID}
+	resp, err := ac.client.RevokeSession(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetJWKS fetches the public keys JWTs are signed with
+func (ac *AuthClient) GetJWKS(ctx context.Context) (*corepbv2.GetJWKSResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return ac.client.GetJWKS(ctx, &corepbv2.GetJWKSRequest{})
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Koshsky/subs-service/core-service/internal/config"
+	"github.com/google/uuid"
+	"github.com/wagslane/go-rabbitmq"
+)
+
+// DeletionServiceName identifies core-service in user.deletion_acknowledged events
+const DeletionServiceName = "core-service"
+
+// errMalformedEvent marks events that cannot be handled however often they are retried
+var errMalformedEvent = errors.New("malformed event")
+
+// UserDeletedEvent is published by auth-service when an account is deleted
+type UserDeletedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// UserDeletionAcknowledgedEvent tells auth-service that the data of a deleted user is gone
+type UserDeletionAcknowledgedEvent struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Service string    `json:"service"`
+}
+
+// DeleteSubscriptionsFunc deletes every subscription of the user
+type DeleteSubscriptionsFunc func(userID uuid.UUID) (int64, error)
+
+// DeletionConsumer deletes the subscriptions of deleted accounts and acknowledges it to auth-service
+type DeletionConsumer struct {
+	conn                *rabbitmq.Conn
+	consumer            *rabbitmq.Consumer
+	publisher           *rabbitmq.Publisher
+	exchange            string
+	deleteSubscriptions DeleteSubscriptionsFunc
+}
+
+// NewDeletionConsumer subscribes to user.deleted events.
+// The queue is durable and shared by all instances, so every deletion is handled by one
+// of them, including deletions published while core-service was down.
+func NewDeletionConsumer(cfg config.RabbitMQConfig, deleteSubscriptions DeleteSubscriptionsFunc) (*DeletionConsumer, error) {
+	conn, err := rabbitmq.NewConn(
+		cfg.URL,
+		rabbitmq.WithConnectionOptionsLogging,
+		rabbitmq.WithConnectionOptionsReconnectInterval(5), // 5 seconds between reconnection attempts
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+	}
+
+	publisher, err := rabbitmq.NewPublisher(
+		conn,
+		rabbitmq.WithPublisherOptionsLogging,
+		rabbitmq.WithPublisherOptionsExchangeName(cfg.Exchange),
+		rabbitmq.WithPublisherOptionsExchangeDeclare,
+		rabbitmq.WithPublisherOptionsExchangeKind("topic"),
+		rabbitmq.WithPublisherOptionsExchangeDurable,
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create publisher: %v", err)
+	}
+
+	consumer, err := rabbitmq.NewConsumer(
+		conn,
+		cfg.DeletionQueue,
+		rabbitmq.WithConsumerOptionsRoutingKey("user.deleted"),
+		rabbitmq.WithConsumerOptionsExchangeName(cfg.Exchange),
+		rabbitmq.WithConsumerOptionsExchangeDeclare,
+		rabbitmq.WithConsumerOptionsExchangeKind("topic"),
+		rabbitmq.WithConsumerOptionsExchangeDurable,
+		rabbitmq.WithConsumerOptionsQueueDurable,
+		rabbitmq.WithConsumerOptionsLogging,
+	)
+	if err != nil {
+		publisher.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to create consumer: %v", err)
+	}
+
+	return &DeletionConsumer{
+		conn:                conn,
+		consumer:            consumer,
+		publisher:           publisher,
+		exchange:            cfg.Exchange,
+		deleteSubscriptions: deleteSubscriptions,
+	}, nil
+}
+
+// StartConsuming handles events until the consumer is closed.
+// Events that fail to be handled are requeued; auth-service republishes
+// user.deleted anyway while the deletion is not acknowledged.
+func (d *DeletionConsumer) StartConsuming() error {
+	err := d.consumer.Run(func(delivery rabbitmq.Delivery) rabbitmq.Action {
+		ack, err := handleUserDeleted(delivery.Body, d.deleteSubscriptions)
+		if err != nil {
+			log.Printf("Error handling user deleted event: %v", err)
+			if errors.Is(err, errMalformedEvent) {
+				return rabbitmq.NackDiscard
+			}
+			return rabbitmq.NackRequeue
+		}
+
+		if err := d.publisher.Publish(
+			ack,
+			[]string{"user.deletion_acknowledged"},
+			rabbitmq.WithPublishOptionsContentType("application/json"),
+			rabbitmq.WithPublishOptionsExchange(d.exchange),
+		); err != nil {
+			log.Printf("Failed to publish user deletion acknowledgement: %v", err)
+			return rabbitmq.NackRequeue
+		}
+		return rabbitmq.Ack
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start consumer: %v", err)
+	}
+	return nil
+}
+
+func (d *DeletionConsumer) Close() {
+	if d.consumer != nil {
+		d.consumer.Close()
+	}
+	if d.publisher != nil {
+		d.publisher.Close()
+	}
+	if d.conn != nil {
+		d.conn.Close()
+	}
+}
+
+// handleUserDeleted decodes a user deleted event, deletes the user's subscriptions and
+// returns the acknowledgement to publish. Events that cannot be decoded fail with errMalformedEvent.
+func handleUserDeleted(data []byte, deleteSubscriptions DeleteSubscriptionsFunc) ([]byte, error) {
+	var event UserDeletedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal user deleted event: %v", errMalformedEvent, err)
+	}
+	if event.UserID == uuid.Nil {
+		return nil, fmt.Errorf("%w: user deleted event has no user_id", errMalformedEvent)
+	}
+
+	deleted, err := deleteSubscriptions(event.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete subscriptions of user %s: %v", event.UserID, err)
+	}
+	log.Printf("Deleted %d subscriptions of deleted user %s", deleted, event.UserID)
+
+	ack, err := json.Marshal(UserDeletionAcknowledgedEvent{UserID: event.UserID, Service: DeletionServiceName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user deletion acknowledgement: %v", err)
+	}
+	return ack, nil
+}
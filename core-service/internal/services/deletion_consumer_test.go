@@ -0,0 +1,80 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleUserDeleted(t *testing.T) {
+	userID := uuid.MustParse("8f3b2a44-53a5-4a8e-9c1b-0a6f1f3c2d11")
+
+	tests := []struct {
+		name          string
+		body          string
+		deleteErr     error
+		wantMalformed bool
+		wantErr       bool
+		wantDeleted   bool
+	}{
+		{
+			name:        "deletes subscriptions",
+			body:        `{"user_id":"8f3b2a44-53a5-4a8e-9c1b-0a6f1f3c2d11"}`,
+			wantDeleted: true,
+		},
+		{
+			name:        "store error",
+			body:        `{"user_id":"8f3b2a44-53a5-4a8e-9c1b-0a6f1f3c2d11"}`,
+			deleteErr:   errors.New("db error"),
+			wantErr:     true,
+			wantDeleted: true,
+		},
+		{
+			name:          "missing user id",
+			body:          `{}`,
+			wantMalformed: true,
+		},
+		{
+			name:          "malformed json",
+			body:          `{"user_id":`,
+			wantMalformed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var deletedFor uuid.UUID
+			deleteSubscriptions := func(id uuid.UUID) (int64, error) {
+				deletedFor = id
+				return 2, tt.deleteErr
+			}
+
+			// Act
+			ack, err := handleUserDeleted([]byte(tt.body), deleteSubscriptions)
+
+			// Assert
+			if tt.wantDeleted {
+				assert.Equal(t, userID, deletedFor)
+			} else {
+				assert.Equal(t, uuid.Nil, deletedFor)
+			}
+			if tt.wantMalformed || tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantMalformed, errors.Is(err, errMalformedEvent))
+				assert.Nil(t, ack)
+				return
+			}
+			require.NoError(t, err)
+
+			var event UserDeletionAcknowledgedEvent
+			require.NoError(t, json.Unmarshal(ack, &event))
+			assert.Equal(t, userID, event.UserID)
+			assert.Equal(t, DeletionServiceName, event.Service)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/Koshsky/subs-service/core-service/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Asymmetric algorithms that can be verified without asking auth-service
+var localAlgorithms = []string{"RS256", "EdDSA"}
+
+// minJWKSRefreshInterval limits refreshes triggered by tokens with an unknown kid
+const minJWKSRefreshInterval = 30 * time.Second
+
+// accessTokenPrefix marks personal access tokens, they are opaque and always validated remotely
+const accessTokenPrefix = "pat_"
+
+// JWKSFetcher returns the current JWT verification keys of auth-service
+type JWKSFetcher func(ctx context.Context) (*corepbv2.GetJWKSResponse, error)
+
+// TokenValidator validates a token and returns user info
+type TokenValidator func(ctx context.Context, token string) (*corepbv2.ValidateTokenResponse, error)
+
+// RevocationList reports revocations of locally verified tokens, it is filled from revocation events
+type RevocationList interface {
+	// Complete reports whether the list has seen every revocation of tokens that are still valid
+	Complete() bool
+	IsRevoked(userID, sessionID, jti string, issuedAt time.Time) bool
+}
+
+// JWTVerifier verifies asymmetrically signed JWTs with public keys published by auth-service
+// and refuses those the revocation list denies.
+// Personal access tokens and HS256 tokens cannot be verified locally and go to the fallback validator,
+// as do all tokens while the revocation list is not complete yet.
+type JWTVerifier struct {
+	fetch       JWKSFetcher
+	fallback    TokenValidator
+	revocations RevocationList
+
+	mu          sync.RWMutex
+	keys        map[string]any // kid -> *rsa.PublicKey or ed25519.PublicKey
+	lastRefresh time.Time
+
+	refreshMu sync.Mutex
+	now       func() time.Time
+}
+
+// NewJWTVerifier creates a verifier without keys, call Refresh to load them
+func NewJWTVerifier(fetch JWKSFetcher, fallback TokenValidator, revocations RevocationList) *JWTVerifier {
+	return &JWTVerifier{
+		fetch:       fetch,
+		fallback:    fallback,
+		revocations: revocations,
+		keys:        make(map[string]any),
+		now:         time.Now,
+	}
+}
+
+// Refresh replaces the known keys with the key set published by auth-service
+func (v *JWTVerifier) Refresh(ctx context.Context) error {
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+	return v.refresh(ctx)
+}
+
+// Run refreshes the keys every interval until ctx is done
+func (v *JWTVerifier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := v.Refresh(ctx); err != nil {
+				log.Printf("Failed to refresh JWKS, keeping the previous keys: %v", err)
+			}
+		}
+	}
+}
+
+// ValidateToken verifies the token locally when possible and defers to the fallback otherwise
+func (v *JWTVerifier) ValidateToken(ctx context.Context, token string) (*corepbv2.ValidateTokenResponse, error) {
+	if strings.HasPrefix(token, accessTokenPrefix) || !v.revocations.Complete() {
+		return v.fallback(ctx, token)
+	}
+
+	unverified, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return nil, invalidToken(err)
+	}
+	if !slices.Contains(localAlgorithms, unverified.Method.Alg()) {
+		return v.fallback(ctx, token)
+	}
+
+	kid, _ := unverified.Header["kid"].(string)
+	if _, ok := v.key(kid); !ok {
+		v.refreshUnknownKey(ctx, kid)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, v.keyfunc,
+		jwt.WithValidMethods(localAlgorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		return nil, invalidToken(err)
+	}
+	if v.isRevoked(claims) {
+		return nil, status.Error(codes.Unauthenticated, "token has been revoked")
+	}
+	return userFromClaims(claims)
+}
+
+// isRevoked checks the claims of a verified token against the revocation list
+func (v *JWTVerifier) isRevoked(claims jwt.MapClaims) bool {
+	userID, _ := claims["user_id"].(string)
+	sessionID, _ := claims["sid"].(string)
+	jti, _ := claims["jti"].(string)
+
+	var issuedAt time.Time
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		issuedAt = iat.Time
+	}
+	return v.revocations.IsRevoked(userID, sessionID, jti, issuedAt)
+}
+
+// keyfunc selects the public key by the kid header for jwt.Parse
+func (v *JWTVerifier) keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := v.key(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (v *JWTVerifier) key(kid string) (any, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// refreshUnknownKey fetches the key set for a kid that is not known yet,
+// e.g. right after auth-service rotated its signing key.
+// Refreshes are rate limited so that forged kids cannot flood auth-service.
+func (v *JWTVerifier) refreshUnknownKey(ctx context.Context, kid string) {
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+
+	if _, ok := v.key(kid); ok {
+		return
+	}
+	v.mu.RLock()
+	recent := !v.lastRefresh.IsZero() && v.now().Sub(v.lastRefresh) < minJWKSRefreshInterval
+	v.mu.RUnlock()
+	if recent {
+		return
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		log.Printf("Failed to refresh JWKS for key %q: %v", kid, err)
+	}
+}
+
+// refresh must be called with refreshMu held
+func (v *JWTVerifier) refresh(ctx context.Context) error {
+	resp, err := v.fetch(ctx)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lastRefresh = v.now()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]any, len(resp.Keys))
+	for _, jwk := range resp.Keys {
+		key, err := parseJWK(jwk)
+		if err != nil {
+			log.Printf("Skipping JWK %q: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	v.keys = keys
+	return nil
+}
+
+// parseJWK converts a published JWK into a public key
+func parseJWK(jwk *corepbv2.JSONWebKey) (any, error) {
+	switch {
+	case jwk.Kty == "RSA" && jwk.Alg == "RS256":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent is too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519" && jwk.Alg == "EdDSA":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s/%s", jwk.Kty, jwk.Alg)
+	}
+}
+
+// userFromClaims builds the response auth-service would return for the claims
+func userFromClaims(claims jwt.MapClaims) (*corepbv2.ValidateTokenResponse, error) {
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid user ID in token")
+	}
+	email, ok := claims["email"].(string)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid email in token")
+	}
+	role, _ := claims["role"].(string)
+	if role == "" {
+		role = models.RoleUser
+	}
+	readOnly, _ := claims["read_only"].(bool)
+	sessionID, _ := claims["sid"].(string)
+
+	resp := &corepbv2.ValidateTokenResponse{
+		UserId:    userID,
+		Email:     email,
+		Role:      role,
+		TokenType: models.TokenTypeJWT,
+		ReadOnly:  readOnly,
+		SessionId: sessionID,
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		resp.ExpiresAt = exp.Unix()
+	}
+	return resp, nil
+}
+
+// invalidToken reports a token that failed local verification like auth-service does
+func invalidToken(err error) error {
+	return status.Error(codes.Unauthenticated, err.Error())
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	corepbv2 "github.com/Koshsky/subs-service/core-service/internal/corepb/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type JWTVerifierTestSuite struct {
+	suite.Suite
+	edKey      ed25519.PrivateKey
+	rsaKey     *rsa.PrivateKey
+	published  []*corepbv2.JSONWebKey
+	fetchErr   error
+	fetches    int
+	fallbacks  int
+	revoked    *fakeRevocationList
+	now        time.Time
+	verifier   *JWTVerifier
+	ctx        context.Context
+	userClaims jwt.MapClaims
+}
+
+func (suite *JWTVerifierTestSuite) SetupSuite() {
+	var err error
+	_, suite.edKey, err = ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+	suite.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+}
+
+func (suite *JWTVerifierTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.published = []*corepbv2.JSONWebKey{
+		edJWK("ed-1", suite.edKey),
+		rsaJWK("rsa-1", suite.rsaKey),
+	}
+	suite.fetchErr = nil
+	suite.fetches = 0
+	suite.fallbacks = 0
+	suite.revoked = &fakeRevocationList{complete: true, jtis: map[string]bool{}}
+	suite.now = time.Now()
+	suite.userClaims = jwt.MapClaims{
+		"user_id": "11111111-1111-1111-1111-111111111111",
+		"email":   "test@example.com",
+		"role":    "admin",
+		"exp":     suite.now.Add(time.Hour).Unix(),
+	}
+
+	suite.verifier = NewJWTVerifier(
+		func(context.Context) (*corepbv2.GetJWKSResponse, error) {
+			suite.fetches++
+			if suite.fetchErr != nil {
+				return nil, suite.fetchErr
+			}
+			return &corepbv2.GetJWKSResponse{Keys: suite.published}, nil
+		},
+		func(_ context.Context, token string) (*corepbv2.ValidateTokenResponse, error) {
+			suite.fallbacks++
+			return &corepbv2.ValidateTokenResponse{UserId: "remote", TokenType: "pat"}, nil
+		},
+		suite.revoked,
+	)
+	suite.verifier.now = func() time.Time { return suite.now }
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// fakeRevocationList denies tokens by jti and records the claims it was asked about
+type fakeRevocationList struct {
+	complete bool
+	jtis     map[string]bool
+	userID   string
+	issuedAt time.Time
+}
+
+func (f *fakeRevocationList) Complete() bool {
+	return f.complete
+}
+
+func (f *fakeRevocationList) IsRevoked(userID, _, jti string, issuedAt time.Time) bool {
+	f.userID, f.issuedAt = userID, issuedAt
+	return f.jtis[jti]
+}
+
+func edJWK(kid string, key ed25519.PrivateKey) *corepbv2.JSONWebKey {
+	return &corepbv2.JSONWebKey{
+		Kty: "OKP", Kid: kid, Alg: "EdDSA", Use: "sig", Crv: "Ed25519",
+		X: base64.RawURLEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) *corepbv2.JSONWebKey {
+	return &corepbv2.JSONWebKey{
+		Kty: "RSA", Kid: kid, Alg: "RS256", Use: "sig",
+		N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// sign issues a token the way auth-service does
+func (suite *JWTVerifierTestSuite) sign(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	suite.Require().NoError(err)
+	return signed
+}
+
+// ===== LOCAL VERIFICATION TESTS =====
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_EdDSA() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(resp.UserId)
+	suite.Equal("11111111-1111-1111-1111-111111111111", resp.UserId)
+	suite.Equal("test@example.com", resp.Email)
+	suite.Equal("admin", resp.Role)
+	suite.Equal("jwt", resp.TokenType)
+	suite.Equal(suite.userClaims["exp"], resp.ExpiresAt)
+	suite.False(resp.ReadOnly)
+	suite.Zero(suite.fallbacks)
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_ReadOnly() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	suite.userClaims["read_only"] = true
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(resp.UserId)
+	suite.True(resp.ReadOnly)
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_SessionID() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	suite.userClaims["sid"] = "22222222-2222-2222-2222-222222222222"
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(resp.UserId)
+	suite.Equal("22222222-2222-2222-2222-222222222222", resp.SessionId)
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_RS256DefaultsRole() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	delete(suite.userClaims, "role")
+	token := suite.sign(jwt.SigningMethodRS256, "rsa-1", suite.rsaKey, suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(resp.UserId)
+	suite.Equal("user", resp.Role)
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_Expired() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	suite.userClaims["exp"] = suite.now.Add(-time.Minute).Unix()
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	_, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Contains(err.Error(), "token is expired")
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_WrongKey() {
+	// Arrange - a token claiming a published kid but signed with another key
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", otherKey, suite.userClaims)
+
+	// Act
+	_, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Contains(err.Error(), "signature is invalid")
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_MissingUserID() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	delete(suite.userClaims, "user_id")
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	_, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Equal("Invalid user ID in token", status.Convert(err).Message())
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_Malformed() {
+	// Act
+	_, err := suite.verifier.ValidateToken(suite.ctx, "not-a-jwt")
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Zero(suite.fallbacks)
+}
+
+// ===== FALLBACK TESTS =====
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_AccessTokenGoesRemote() {
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, "pat_abcdef")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("remote", resp.UserId)
+	suite.Equal(1, suite.fallbacks)
+	suite.Zero(suite.fetches)
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_HS256GoesRemote() {
+	// Arrange
+	token := suite.sign(jwt.SigningMethodHS256, "", []byte("secret"), suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("remote", resp.UserId)
+	suite.Equal(1, suite.fallbacks)
+}
+
+// ===== KEY REFRESH TESTS =====
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_UnknownKidRefreshes() {
+	// Arrange - the key is rotated after the verifier loaded the key set
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	_, rotated, _ := ed25519.GenerateKey(rand.Reader)
+	suite.published = append(suite.published, edJWK("ed-2", rotated))
+	suite.now = suite.now.Add(minJWKSRefreshInterval)
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-2", rotated, suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(resp.UserId)
+	suite.Equal(2, suite.fetches)
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_UnknownKidRateLimited() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	_, forged, _ := ed25519.GenerateKey(rand.Reader)
+
+	// Act
+	for i := 0; i < 3; i++ {
+		token := suite.sign(jwt.SigningMethodEdDSA, "forged", forged, suite.userClaims)
+		_, err := suite.verifier.ValidateToken(suite.ctx, token)
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+		suite.Contains(err.Error(), "unknown signing key")
+	}
+
+	// Assert
+	suite.Equal(1, suite.fetches)
+}
+
+func (suite *JWTVerifierTestSuite) TestRefresh_ErrorKeepsKeys() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	suite.fetchErr = errors.New("auth-service unavailable")
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	err := suite.verifier.Refresh(suite.ctx)
+	resp, verr := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Require().NoError(verr)
+	suite.NotEmpty(resp.UserId)
+}
+
+func (suite *JWTVerifierTestSuite) TestRefresh_SkipsUnsupportedKeys() {
+	// Arrange
+	suite.published = append(suite.published, &corepbv2.JSONWebKey{Kty: "EC", Kid: "ec-1", Alg: "ES256", Crv: "P-256"})
+
+	// Act
+	err := suite.verifier.Refresh(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, ok := suite.verifier.key("ec-1")
+	suite.False(ok)
+	_, ok = suite.verifier.key("ed-1")
+	suite.True(ok)
+}
+
+// ===== REVOCATION TESTS =====
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_Revoked() {
+	// Arrange
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	suite.userClaims["jti"] = "revoked-jti"
+	suite.userClaims["iat"] = suite.now.Add(-time.Minute).Unix()
+	suite.revoked.jtis["revoked-jti"] = true
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Nil(resp)
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Equal(suite.userClaims["user_id"], suite.revoked.userID)
+	suite.Equal(suite.userClaims["iat"], suite.revoked.issuedAt.Unix())
+	suite.Zero(suite.fallbacks)
+}
+
+func (suite *JWTVerifierTestSuite) TestValidateToken_IncompleteRevocationListGoesRemote() {
+	// Arrange - revocations made before core-service started are unknown
+	suite.Require().NoError(suite.verifier.Refresh(suite.ctx))
+	suite.revoked.complete = false
+	token := suite.sign(jwt.SigningMethodEdDSA, "ed-1", suite.edKey, suite.userClaims)
+
+	// Act
+	resp, err := suite.verifier.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("remote", resp.UserId)
+	suite.Equal(1, suite.fallbacks)
+}
+
+func TestJWTVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(JWTVerifierTestSuite))
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/core-service/internal/config"
+	"github.com/Koshsky/subs-service/core-service/internal/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type OIDCProviderTestSuite struct {
+	suite.Suite
+	idp      *oidctest.Server
+	provider *OIDCProvider
+	ctx      context.Context
+	verifier string
+}
+
+func (suite *OIDCProviderTestSuite) SetupTest() {
+	suite.idp = oidctest.NewServer("core-service", "client-secret")
+	suite.provider = NewOIDCProvider(config.OIDCConfig{
+		IssuerURL:    suite.idp.Issuer(),
+		ClientID:     "core-service",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/auth/oidc/callback",
+		Scopes:       []string{"openid", "email"},
+	})
+	suite.ctx = context.Background()
+	suite.verifier = "verifier-0123456789-0123456789-0123456789-0123456789"
+}
+
+func (suite *OIDCProviderTestSuite) TearDownTest() {
+	suite.idp.Close()
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// authorize opens the login page of the provider and returns the authorization code it
+// redirects back with
+func (suite *OIDCProviderTestSuite) authorize(nonce string) string {
+	authURL, err := suite.provider.AuthCodeURL(suite.ctx, "state-1", nonce, suite.verifier)
+	suite.Require().NoError(err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	suite.Require().NoError(err)
+	suite.Require().Equal("state-1", location.Query().Get("state"))
+	return location.Query().Get("code")
+}
+
+// ===== AUTHORIZATION URL TESTS =====
+
+func (suite *OIDCProviderTestSuite) TestAuthCodeURL_UsesPKCE() {
+	// Act
+	authURL, err := suite.provider.AuthCodeURL(suite.ctx, "state-1", "nonce-1", suite.verifier)
+
+	// Assert
+	suite.Require().NoError(err)
+	parsed, err := url.Parse(authURL)
+	suite.Require().NoError(err)
+	query := parsed.Query()
+	challenge := sha256.Sum256([]byte(suite.verifier))
+	suite.Equal(suite.idp.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	suite.Equal("code", query.Get("response_type"))
+	suite.Equal("core-service", query.Get("client_id"))
+	suite.Equal("http://localhost:8080/auth/oidc/callback", query.Get("redirect_uri"))
+	suite.Equal("openid email", query.Get("scope"))
+	suite.Equal("state-1", query.Get("state"))
+	suite.Equal("nonce-1", query.Get("nonce"))
+	suite.Equal("S256", query.Get("code_challenge_method"))
+	suite.Equal(base64.RawURLEncoding.EncodeToString(challenge[:]), query.Get("code_challenge"))
+}
+
+func (suite *OIDCProviderTestSuite) TestAuthCodeURL_ProviderUnavailable() {
+	// Arrange
+	suite.idp.Close()
+
+	// Act
+	_, err := suite.provider.AuthCodeURL(suite.ctx, "state-1", "nonce-1", suite.verifier)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to discover identity provider")
+}
+
+func (suite *OIDCProviderTestSuite) TestAuthCodeURL_IssuerMismatch() {
+	// Arrange
+	suite.provider.cfg.IssuerURL = suite.idp.URL + "/"
+
+	// Act
+	_, err := suite.provider.AuthCodeURL(suite.ctx, "state-1", "nonce-1", suite.verifier)
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+// ===== EXCHANGE TESTS =====
+
+func (suite *OIDCProviderTestSuite) TestExchange_ReturnsIdentity() {
+	// Arrange
+	code := suite.authorize("nonce-1")
+
+	// Act
+	identity, err := suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-1")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.idp.Issuer(), identity.Issuer)
+	suite.Equal("oidctest-subject", identity.Subject)
+	suite.Equal("sso@example.com", identity.Email)
+	suite.True(identity.EmailVerified)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange_WrongVerifier() {
+	// Arrange
+	code := suite.authorize("nonce-1")
+
+	// Act
+	identity, err := suite.provider.Exchange(suite.ctx, code, "another-verifier-0123456789-0123456789-0123", "nonce-1")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to exchange authorization code")
+	suite.Nil(identity)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange_CodeWorksOnce() {
+	// Arrange
+	code := suite.authorize("nonce-1")
+	_, err := suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-1")
+	suite.Require().NoError(err)
+
+	// Act
+	_, err = suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-1")
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange_NonceMismatch() {
+	// Arrange
+	code := suite.authorize("nonce-1")
+
+	// Act
+	_, err := suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-2")
+
+	// Assert
+	suite.Require().ErrorIs(err, ErrInvalidIDToken)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange_OtherAudience() {
+	// Arrange
+	suite.idp.Claims = jwt.MapClaims{"aud": "another-client"}
+	code := suite.authorize("nonce-1")
+
+	// Act
+	_, err := suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-1")
+
+	// Assert
+	suite.Require().ErrorIs(err, ErrInvalidIDToken)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange_OtherIssuer() {
+	// Arrange
+	suite.idp.Claims = jwt.MapClaims{"iss": "https://evil.example.com"}
+	code := suite.authorize("nonce-1")
+
+	// Act
+	_, err := suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-1")
+
+	// Assert
+	suite.Require().ErrorIs(err, ErrInvalidIDToken)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange_ExpiredIDToken() {
+	// Arrange
+	suite.idp.Claims = jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}
+	code := suite.authorize("nonce-1")
+
+	// Act
+	_, err := suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-1")
+
+	// Assert
+	suite.Require().ErrorIs(err, ErrInvalidIDToken)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange_UnverifiedEmail() {
+	// Arrange
+	suite.idp.EmailVerified = false
+	code := suite.authorize("nonce-1")
+
+	// Act
+	identity, err := suite.provider.Exchange(suite.ctx, code, suite.verifier, "nonce-1")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(identity.EmailVerified)
+}
+
+// Run tests
+func TestOIDCProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCProviderTestSuite))
+}
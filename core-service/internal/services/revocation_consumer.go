@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Koshsky/subs-service/core-service/internal/config"
+	"github.com/wagslane/go-rabbitmq"
+)
+
+// TokenRevokedEvent is published by auth-service when tokens are revoked.
+// It names a single token by TokenHash (and JTI for JWTs) or the tokens of a login session
+// by SessionID; without either it means all tokens of the user issued before RevokedAt.
+// user.tokens_revoked events, published when a user signs out everywhere, and user.deleted
+// events only carry the user ID and, for the former, RevokedAt.
+type TokenRevokedEvent struct {
+	UserID    string    `json:"user_id"`
+	SessionID string    `json:"session_id,omitempty"`
+	TokenHash string    `json:"token_hash,omitempty"`
+	JTI       string    `json:"jti,omitempty"`
+	RevokedAt time.Time `json:"revoked_at,omitempty"`
+}
+
+// RevocationHandler applies a revocation to the token validation cache and the deny list
+type RevocationHandler func(event TokenRevokedEvent)
+
+// RevocationConsumer applies token revocation events to the token validation cache
+// and the deny list of locally verified tokens
+type RevocationConsumer struct {
+	conn     *rabbitmq.Conn
+	consumer *rabbitmq.Consumer
+	handle   RevocationHandler
+}
+
+// NewRevocationConsumer subscribes to token.revoked, user.tokens_revoked and user.deleted events.
+// The queue is exclusive to this instance and deleted when it disconnects,
+// so that every instance invalidates its own cache.
+func NewRevocationConsumer(cfg config.RabbitMQConfig, handle RevocationHandler) (*RevocationConsumer, error) {
+	conn, err := rabbitmq.NewConn(
+		cfg.URL,
+		rabbitmq.WithConnectionOptionsLogging,
+		rabbitmq.WithConnectionOptionsReconnectInterval(5), // 5 seconds between reconnection attempts
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+	}
+
+	consumer, err := rabbitmq.NewConsumer(
+		conn,
+		cfg.Queue,
+		rabbitmq.WithConsumerOptionsRoutingKey("token.revoked"),
+		rabbitmq.WithConsumerOptionsRoutingKey("user.tokens_revoked"),
+		rabbitmq.WithConsumerOptionsRoutingKey("user.deleted"),
+		rabbitmq.WithConsumerOptionsExchangeName(cfg.Exchange),
+		rabbitmq.WithConsumerOptionsExchangeDeclare,
+		rabbitmq.WithConsumerOptionsExchangeKind("topic"),
+		rabbitmq.WithConsumerOptionsExchangeDurable,
+		rabbitmq.WithConsumerOptionsQueueExclusive,
+		rabbitmq.WithConsumerOptionsQueueAutoDelete,
+		rabbitmq.WithConsumerOptionsLogging,
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create consumer: %v", err)
+	}
+
+	return &RevocationConsumer{
+		conn:     conn,
+		consumer: consumer,
+		handle:   handle,
+	}, nil
+}
+
+// StartConsuming handles events until the consumer is closed
+func (r *RevocationConsumer) StartConsuming() error {
+	err := r.consumer.Run(func(d rabbitmq.Delivery) rabbitmq.Action {
+		if err := handleTokenRevoked(d.Body, time.Now().UTC(), r.handle); err != nil {
+			log.Printf("Error handling token revoked event: %v", err)
+			return rabbitmq.NackDiscard
+		}
+		return rabbitmq.Ack
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start consumer: %v", err)
+	}
+	return nil
+}
+
+func (r *RevocationConsumer) Close() {
+	if r.consumer != nil {
+		r.consumer.Close()
+	}
+	if r.conn != nil {
+		r.conn.Close()
+	}
+}
+
+// handleTokenRevoked decodes a token revoked event and applies it.
+// Events without a revocation time, user.deleted among them, take effect as of receivedAt.
+func handleTokenRevoked(data []byte, receivedAt time.Time, handle RevocationHandler) error {
+	var event TokenRevokedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal token revoked event: %v", err)
+	}
+	if event.UserID == "" && event.SessionID == "" && event.TokenHash == "" && event.JTI == "" {
+		return fmt.Errorf("token revoked event has neither user_id, session_id, token_hash nor jti")
+	}
+	if event.RevokedAt.IsZero() {
+		event.RevokedAt = receivedAt
+	}
+
+	handle(event)
+	return nil
+}
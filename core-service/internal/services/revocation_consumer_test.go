@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleTokenRevoked(t *testing.T) {
+	receivedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	revokedAt := time.Date(2026, 1, 1, 11, 59, 58, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		body      string
+		wantErr   bool
+		wantEvent TokenRevokedEvent
+	}{
+		{
+			name:      "single token",
+			body:      `{"user_id":"alice","token_hash":"abc","jti":"def"}`,
+			wantEvent: TokenRevokedEvent{UserID: "alice", TokenHash: "abc", JTI: "def", RevokedAt: receivedAt},
+		},
+		{
+			name:      "session tokens",
+			body:      `{"user_id":"alice","session_id":"s1"}`,
+			wantEvent: TokenRevokedEvent{UserID: "alice", SessionID: "s1", RevokedAt: receivedAt},
+		},
+		{
+			name:      "all user tokens",
+			body:      `{"user_id":"alice","revoked_at":"2026-01-01T11:59:58Z"}`,
+			wantEvent: TokenRevokedEvent{UserID: "alice", RevokedAt: revokedAt},
+		},
+		{
+			name:      "user deleted",
+			body:      `{"user_id":"alice"}`,
+			wantEvent: TokenRevokedEvent{UserID: "alice", RevokedAt: receivedAt},
+		},
+		{
+			name:    "empty event",
+			body:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed json",
+			body:    `{"user_id":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var called bool
+			var event TokenRevokedEvent
+			handle := func(e TokenRevokedEvent) {
+				called = true
+				event = e
+			}
+
+			// Act
+			err := handleTokenRevoked([]byte(tt.body), receivedAt, handle)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, event.RevokedAt.Equal(tt.wantEvent.RevokedAt))
+			event.RevokedAt = tt.wantEvent.RevokedAt
+			assert.Equal(t, tt.wantEvent, event)
+		})
+	}
+}
@@ -7,10 +7,10 @@ import (
 )
 
 type SubscriptionService struct {
-	SubRepo *repositories.SubscriptionRepository
+	SubRepo repositories.SubscriptionStore
 }
 
-func NewSubscriptionService(repo *repositories.SubscriptionRepository) *SubscriptionService {
+func NewSubscriptionService(repo repositories.SubscriptionStore) *SubscriptionService {
 	return &SubscriptionService{SubRepo: repo}
 }
 
@@ -38,3 +38,8 @@ func (s *SubscriptionService) UpdateByID(id int, update models.Subscription) (mo
 func (s *SubscriptionService) DeleteByID(id int) error {
 	return s.SubRepo.DeleteByID(uint(id))
 }
+
+// Search searches subscriptions of all users
+func (s *SubscriptionService) Search(filter models.SubscriptionFilter) ([]models.Subscription, error) {
+	return s.SubRepo.Search(filter)
+}
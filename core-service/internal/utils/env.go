@@ -3,7 +3,10 @@ package utils
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // GetEnv gets an environment variable with default value
@@ -33,6 +36,15 @@ func GetEnvRequiredWithValidation(key string, validator func(string) error) stri
 	return value
 }
 
+// GetEnvWithValidation gets an environment variable with default value and validation
+func GetEnvWithValidation(key, defaultValue string, validator func(string) error) string {
+	value := GetEnv(key, defaultValue)
+	if err := validator(value); err != nil {
+		panic(fmt.Sprintf("CRITICAL ERROR: Environment variable %s validation failed: %v", key, err))
+	}
+	return value
+}
+
 // GetEnvBool gets an environment variable as a boolean
 func GetEnvBool(key string, defaultValue bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
@@ -69,6 +81,18 @@ func GetEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// GetEnvDuration gets an environment variable as a time.Duration (e.g. "30s")
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return defaultValue
+		}
+		return duration
+	}
+	return defaultValue
+}
+
 // GetEnvIntRequired gets a critical integer environment variable
 func GetEnvIntRequired(key string) int {
 	if value, exists := os.LookupEnv(key); exists {
@@ -116,3 +140,13 @@ func ValidateMinLength(minLength int) func(string) error {
 		return nil
 	}
 }
+
+// ValidateOneOf validates that a string is one of the allowed values
+func ValidateOneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		if !slices.Contains(allowed, value) {
+			return fmt.Errorf("value must be one of: %s", strings.Join(allowed, ", "))
+		}
+		return nil
+	}
+}
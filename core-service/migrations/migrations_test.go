@@ -0,0 +1,19 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/Koshsky/subs-service/core-service/migrations"
+	"github.com/Koshsky/subs-service/pkg/migrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion_EmbeddedMigrations(t *testing.T) {
+	// Act
+	version, err := migrator.LatestVersion(migrations.FS)
+
+	// Assert
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, version, uint(1))
+}
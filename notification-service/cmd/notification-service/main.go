@@ -12,12 +12,29 @@ import (
 
 	"github.com/Koshsky/subs-service/notification-service/internal/config"
 	"github.com/Koshsky/subs-service/notification-service/internal/services"
+	"github.com/Koshsky/subs-service/notification-service/migrations"
+	"github.com/Koshsky/subs-service/pkg/migrator"
 )
 
 func main() {
+	// Run migrate subcommand instead of the service if requested, it only needs the database settings
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(config.LoadDBConfig(), os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// Refuse to serve against a schema this binary was not built for
+	if cfg.CheckSchemaVersion {
+		if err := checkSchemaVersion(cfg); err != nil {
+			log.Fatalf("Refusing to start: %v", err)
+		}
+	}
+
 	// Connect to notification database
 	database, err := cfg.ConnectDB()
 	if err != nil {
@@ -80,3 +97,30 @@ func main() {
 
 	log.Println("Notification service stopped gracefully")
 }
+
+// runMigrate executes the migrate subcommand against the notification database
+func runMigrate(db config.DBConfig, args []string) error {
+	if _, _, err := migrator.ParseArgs(args); err != nil {
+		return err
+	}
+
+	m, err := migrator.New(db.ConnectionString(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Run(args, os.Stdout)
+}
+
+// checkSchemaVersion verifies that the notification database is migrated
+// to the version embedded in this binary
+func checkSchemaVersion(cfg *config.Config) error {
+	m, err := migrator.New(cfg.Database.ConnectionString(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.CheckVersion()
+}
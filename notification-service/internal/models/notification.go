@@ -19,8 +19,68 @@ type Notification struct {
 	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
+// Contact is the address notifications of a user are sent to
+type Contact struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"primaryKey;type:uuid"`
+	Email     string    `json:"email" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // UserCreatedEvent represents the user created event from RabbitMQ
 type UserCreatedEvent struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
 }
+
+// PasswordResetRequestedEvent represents the password reset requested event from RabbitMQ.
+// Token is the plaintext reset token and must only be sent to Email.
+type PasswordResetRequestedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MagicLinkRequestedEvent represents the magic link requested event from RabbitMQ.
+// Token is the plaintext sign-in token and must only be sent to Email.
+type MagicLinkRequestedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EmailVerificationRequestedEvent represents the email verification requested event from RabbitMQ.
+// Token is the plaintext verification token and must only be sent to Email.
+type EmailVerificationRequestedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserLockedEvent represents the user locked event from RabbitMQ, published when logins
+// to the account are refused until LockedUntil after too many failed attempts
+type UserLockedEvent struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Email       string    `json:"email"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// UserDeletedEvent represents the user deleted event from RabbitMQ
+type UserDeletedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// UserDeletionAcknowledgedEvent tells auth-service that the data of a deleted user is gone
+type UserDeletionAcknowledgedEvent struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Service string    `json:"service"`
+}
+
+// UserEmailChangedEvent represents the user email changed event from RabbitMQ
+type UserEmailChangedEvent struct {
+	UserID   uuid.UUID `json:"user_id"`
+	OldEmail string    `json:"old_email"`
+	NewEmail string    `json:"new_email"`
+}
@@ -3,22 +3,45 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
 
 	"github.com/Koshsky/subs-service/notification-service/internal/config"
 	"github.com/Koshsky/subs-service/notification-service/internal/models"
+	"github.com/google/uuid"
 	"github.com/wagslane/go-rabbitmq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Routing keys of the events handled by the service
+const (
+	routingKeyUserCreated            = "user.created"
+	routingKeyPasswordResetRequested = "user.password_reset_requested"
+	routingKeyMagicLinkRequested     = "user.magic_link_requested"
+	routingKeyVerificationRequested  = "user.email_verification_requested"
+	routingKeyEmailChanged           = "user.email_changed"
+	routingKeyUserLocked             = "user.locked"
+	routingKeyUserDeleted            = "user.deleted"
+	routingKeyDeletionAcknowledged   = "user.deletion_acknowledged"
+)
+
+// deletionServiceName identifies the service in user.deletion_acknowledged events
+const deletionServiceName = "notification-service"
+
+// errMalformedEvent marks events that cannot be handled however often they are retried
+var errMalformedEvent = errors.New("malformed event")
+
 type RabbitMQService struct {
-	conn     *rabbitmq.Conn
-	consumer *rabbitmq.Consumer
-	config   *config.Config
-	db       *gorm.DB
-	ctx      context.Context
-	cancel   context.CancelFunc
+	conn      *rabbitmq.Conn
+	consumer  *rabbitmq.Consumer
+	publisher *rabbitmq.Publisher
+	config    *config.Config
+	db        *gorm.DB
+	ctx       context.Context
+	cancel    context.CancelFunc
 }
 
 func NewRabbitMQService(cfg *config.Config, db *gorm.DB) (*RabbitMQService, error) {
@@ -35,11 +58,32 @@ func NewRabbitMQService(cfg *config.Config, db *gorm.DB) (*RabbitMQService, erro
 		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
 	}
 
+	// The publisher acknowledges account deletions to auth-service
+	publisher, err := rabbitmq.NewPublisher(
+		conn,
+		rabbitmq.WithPublisherOptionsLogging,
+		rabbitmq.WithPublisherOptionsExchangeName(cfg.RabbitMQ.Exchange),
+		rabbitmq.WithPublisherOptionsExchangeDeclare,
+		rabbitmq.WithPublisherOptionsExchangeKind("topic"),
+		rabbitmq.WithPublisherOptionsExchangeDurable,
+	)
+	if err != nil {
+		conn.Close()
+		cancel()
+		return nil, fmt.Errorf("failed to create publisher: %v", err)
+	}
+
 	// Create consumer with automatic reconnection
 	consumer, err := rabbitmq.NewConsumer(
 		conn,
 		cfg.RabbitMQ.Queue,
-		rabbitmq.WithConsumerOptionsRoutingKey("user.created"),
+		rabbitmq.WithConsumerOptionsRoutingKey(routingKeyUserCreated),
+		rabbitmq.WithConsumerOptionsRoutingKey(routingKeyPasswordResetRequested),
+		rabbitmq.WithConsumerOptionsRoutingKey(routingKeyMagicLinkRequested),
+		rabbitmq.WithConsumerOptionsRoutingKey(routingKeyVerificationRequested),
+		rabbitmq.WithConsumerOptionsRoutingKey(routingKeyEmailChanged),
+		rabbitmq.WithConsumerOptionsRoutingKey(routingKeyUserLocked),
+		rabbitmq.WithConsumerOptionsRoutingKey(routingKeyUserDeleted),
 		rabbitmq.WithConsumerOptionsExchangeName(cfg.RabbitMQ.Exchange),
 		rabbitmq.WithConsumerOptionsExchangeDeclare,
 		rabbitmq.WithConsumerOptionsExchangeKind("topic"),
@@ -48,18 +92,20 @@ func NewRabbitMQService(cfg *config.Config, db *gorm.DB) (*RabbitMQService, erro
 		rabbitmq.WithConsumerOptionsLogging,
 	)
 	if err != nil {
+		publisher.Close()
 		conn.Close()
 		cancel()
 		return nil, fmt.Errorf("failed to create consumer: %v", err)
 	}
 
 	service := &RabbitMQService{
-		conn:     conn,
-		consumer: consumer,
-		config:   cfg,
-		db:       db,
-		ctx:      ctx,
-		cancel:   cancel,
+		conn:      conn,
+		consumer:  consumer,
+		publisher: publisher,
+		config:    cfg,
+		db:        db,
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 
 	return service, nil
@@ -74,12 +120,7 @@ func (r *RabbitMQService) StartConsuming() error {
 		default:
 		}
 
-		if err := r.handleUserCreated(d.Body); err != nil {
-			log.Printf("Error handling message: %v", err)
-			return rabbitmq.NackRequeue
-		}
-
-		return rabbitmq.Ack
+		return r.handleMessage(d.RoutingKey, d.Body)
 	})
 
 	if err != nil {
@@ -89,10 +130,50 @@ func (r *RabbitMQService) StartConsuming() error {
 	return nil
 }
 
+// handleMessage dispatches a message by its routing key. Messages failing with
+// errMalformedEvent would fail again on every redelivery and are discarded,
+// other failures are requeued.
+func (r *RabbitMQService) handleMessage(routingKey string, body []byte) rabbitmq.Action {
+	var err error
+	switch routingKey {
+	case routingKeyUserCreated:
+		err = r.handleUserCreated(body)
+	case routingKeyPasswordResetRequested:
+		err = r.handlePasswordResetRequested(body)
+	case routingKeyMagicLinkRequested:
+		err = r.handleMagicLinkRequested(body)
+	case routingKeyVerificationRequested:
+		err = r.handleEmailVerificationRequested(body)
+	case routingKeyEmailChanged:
+		err = r.handleUserEmailChanged(body)
+	case routingKeyUserLocked:
+		err = r.handleUserLocked(body)
+	case routingKeyUserDeleted:
+		err = r.handleUserDeleted(body)
+	default:
+		log.Printf("Discarding message with unexpected routing key: %s", routingKey)
+		return rabbitmq.NackDiscard
+	}
+
+	if err != nil {
+		log.Printf("Error handling message: %v", err)
+		if errors.Is(err, errMalformedEvent) {
+			return rabbitmq.NackDiscard
+		}
+		return rabbitmq.NackRequeue
+	}
+
+	return rabbitmq.Ack
+}
+
 func (r *RabbitMQService) handleUserCreated(data []byte) error {
 	var event models.UserCreatedEvent
 	if err := json.Unmarshal(data, &event); err != nil {
-		return fmt.Errorf("failed to unmarshal user created event: %v", err)
+		return fmt.Errorf("%w: failed to unmarshal user created event: %v", errMalformedEvent, err)
+	}
+
+	if err := r.saveContact(event.UserID, event.Email); err != nil {
+		return err
 	}
 
 	// Create notification in database
@@ -113,6 +194,232 @@ func (r *RabbitMQService) handleUserCreated(data []byte) error {
 	return nil
 }
 
+func (r *RabbitMQService) handlePasswordResetRequested(data []byte) error {
+	var event models.PasswordResetRequestedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal password reset requested event: %v", errMalformedEvent, err)
+	}
+
+	link, err := tokenLink(r.config.PasswordResetURL, event.Token)
+	if err != nil {
+		return err
+	}
+
+	// The stored message must not contain the link: anyone reading it could reset the password
+	notification := &models.Notification{
+		UserID:  event.UserID,
+		Type:    routingKeyPasswordResetRequested,
+		Message: fmt.Sprintf("Password reset link sent to %s, valid until %s", event.Email, event.ExpiresAt.UTC().Format("2006-01-02 15:04 MST")),
+		Status:  "pending",
+	}
+
+	if err := r.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create notification record: %v", err)
+	}
+
+	r.sendPasswordResetEmail(event.Email, link)
+
+	return nil
+}
+
+// sendPasswordResetEmail delivers the reset link. The link is a credential and is never logged.
+func (r *RabbitMQService) sendPasswordResetEmail(email, link string) {
+	// TODO: Add email sending logic here, with link in the email body
+	log.Printf("Would send password reset email to: %s", email)
+}
+
+func (r *RabbitMQService) handleMagicLinkRequested(data []byte) error {
+	var event models.MagicLinkRequestedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal magic link requested event: %v", errMalformedEvent, err)
+	}
+
+	link, err := tokenLink(r.config.MagicLinkURL, event.Token)
+	if err != nil {
+		return err
+	}
+
+	// The stored message must not contain the link: anyone reading it could sign in
+	notification := &models.Notification{
+		UserID:  event.UserID,
+		Type:    routingKeyMagicLinkRequested,
+		Message: fmt.Sprintf("Sign-in link sent to %s, valid until %s", event.Email, event.ExpiresAt.UTC().Format("2006-01-02 15:04 MST")),
+		Status:  "pending",
+	}
+
+	if err := r.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create notification record: %v", err)
+	}
+
+	r.sendMagicLinkEmail(event.Email, link)
+
+	return nil
+}
+
+// sendMagicLinkEmail delivers the sign-in link. The link is a credential and is never logged.
+func (r *RabbitMQService) sendMagicLinkEmail(email, link string) {
+	// TODO: Add email sending logic here, with link in the email body
+	log.Printf("Would send sign-in link email to: %s", email)
+}
+
+func (r *RabbitMQService) handleEmailVerificationRequested(data []byte) error {
+	var event models.EmailVerificationRequestedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal email verification requested event: %v", errMalformedEvent, err)
+	}
+
+	link, err := tokenLink(r.config.EmailVerificationURL, event.Token)
+	if err != nil {
+		return err
+	}
+
+	// The stored message must not contain the link: anyone reading it could verify the address
+	notification := &models.Notification{
+		UserID:  event.UserID,
+		Type:    routingKeyVerificationRequested,
+		Message: fmt.Sprintf("Email verification link sent to %s, valid until %s", event.Email, event.ExpiresAt.UTC().Format("2006-01-02 15:04 MST")),
+		Status:  "pending",
+	}
+
+	if err := r.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create notification record: %v", err)
+	}
+
+	r.sendVerificationEmail(event.Email, link)
+
+	return nil
+}
+
+// sendVerificationEmail delivers the verification link. The link is a credential and is never logged.
+func (r *RabbitMQService) sendVerificationEmail(email, link string) {
+	// TODO: Add email sending logic here, with link in the email body
+	log.Printf("Would send verification email to: %s", email)
+}
+
+func (r *RabbitMQService) handleUserEmailChanged(data []byte) error {
+	var event models.UserEmailChangedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal user email changed event: %v", errMalformedEvent, err)
+	}
+
+	if err := r.saveContact(event.UserID, event.NewEmail); err != nil {
+		return err
+	}
+
+	notification := &models.Notification{
+		UserID:  event.UserID,
+		Type:    routingKeyEmailChanged,
+		Message: fmt.Sprintf("Account email changed from %s to %s", event.OldEmail, event.NewEmail),
+		Status:  "pending",
+	}
+
+	if err := r.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create notification record: %v", err)
+	}
+
+	// The old address is told about the change, so the owner notices an unexpected one
+	// TODO: Add email sending logic here
+	log.Printf("Would send email change notice to: %s", event.OldEmail)
+
+	return nil
+}
+
+func (r *RabbitMQService) handleUserLocked(data []byte) error {
+	var event models.UserLockedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal user locked event: %v", errMalformedEvent, err)
+	}
+
+	notification := &models.Notification{
+		UserID:  event.UserID,
+		Type:    routingKeyUserLocked,
+		Message: fmt.Sprintf("Sign-in to %s locked until %s after too many failed attempts", event.Email, event.LockedUntil.UTC().Format("2006-01-02 15:04 MST")),
+		Status:  "pending",
+	}
+
+	if err := r.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create notification record: %v", err)
+	}
+
+	// The owner is alerted, since the failed attempts may come from someone guessing the password
+	// TODO: Add email sending logic here
+	log.Printf("Would send account locked alert to: %s", event.Email)
+
+	return nil
+}
+
+// handleUserDeleted purges the notifications and the contact of a deleted user and
+// acknowledges it to auth-service. Deleting again is harmless, so a redelivered or
+// republished event is simply acknowledged once more. Events that cannot be decoded
+// fail with errMalformedEvent and are discarded.
+func (r *RabbitMQService) handleUserDeleted(data []byte) error {
+	var event models.UserDeletedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal user deleted event: %v", errMalformedEvent, err)
+	}
+	if event.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user deleted event has no user_id", errMalformedEvent)
+	}
+
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted notifications still hold personal data, so they are removed for good
+		result := tx.Unscoped().Where("user_id = ?", event.UserID).Delete(&models.Notification{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return tx.Where("user_id = ?", event.UserID).Delete(&models.Contact{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete data of user %s: %v", event.UserID, err)
+	}
+	log.Printf("Deleted %d notifications and the contact of deleted user %s", deleted, event.UserID)
+
+	body, err := json.Marshal(models.UserDeletionAcknowledgedEvent{UserID: event.UserID, Service: deletionServiceName})
+	if err != nil {
+		return fmt.Errorf("failed to marshal user deletion acknowledgement: %v", err)
+	}
+	err = r.publisher.Publish(
+		body,
+		[]string{routingKeyDeletionAcknowledged},
+		rabbitmq.WithPublishOptionsContentType("application/json"),
+		rabbitmq.WithPublishOptionsExchange(r.config.RabbitMQ.Exchange),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish user deletion acknowledgement: %v", err)
+	}
+
+	return nil
+}
+
+// saveContact stores the current email of the user, replacing the previous one
+func (r *RabbitMQService) saveContact(userID uuid.UUID, email string) error {
+	contact := &models.Contact{UserID: userID, Email: email}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "updated_at"}),
+	}).Create(contact).Error
+	if err != nil {
+		return fmt.Errorf("failed to save contact of user %s: %v", userID, err)
+	}
+	return nil
+}
+
+// tokenLink returns the page URL that receives token in the ?token= query parameter.
+// Page URLs are validated when the configuration is loaded.
+func tokenLink(pageURL, token string) (string, error) {
+	link, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid link URL %q: %v", pageURL, err)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
 func (r *RabbitMQService) Close() {
 	// Cancel context for graceful shutdown
 	if r.cancel != nil {
@@ -123,6 +430,10 @@ func (r *RabbitMQService) Close() {
 		r.consumer.Close()
 	}
 
+	if r.publisher != nil {
+		r.publisher.Close()
+	}
+
 	if r.conn != nil {
 		r.conn.Close()
 	}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Koshsky/subs-service/notification-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/wagslane/go-rabbitmq"
+)
+
+func TestHandleMessage_DiscardsMalformedEvents(t *testing.T) {
+	validConfig := &config.Config{
+		PasswordResetURL:     "http://localhost:8080/reset-password",
+		EmailVerificationURL: "http://localhost:8080/verify-email",
+		MagicLinkURL:         "http://localhost:8080/magic-link",
+	}
+
+	tests := []struct {
+		name       string
+		routingKey string
+		body       string
+	}{
+		{name: "user created with malformed json", routingKey: routingKeyUserCreated, body: `{"user_id":`},
+		{name: "password reset with malformed json", routingKey: routingKeyPasswordResetRequested, body: `{"user_id":`},
+		{name: "magic link with malformed json", routingKey: routingKeyMagicLinkRequested, body: `{"user_id":`},
+		{name: "email verification with malformed json", routingKey: routingKeyVerificationRequested, body: `{"user_id":`},
+		{name: "email changed with malformed json", routingKey: routingKeyEmailChanged, body: `{"user_id":`},
+		{name: "user locked with malformed json", routingKey: routingKeyUserLocked, body: `{"user_id":`},
+		{name: "user deleted with malformed json", routingKey: routingKeyUserDeleted, body: `{"user_id":`},
+		{name: "user deleted without user id", routingKey: routingKeyUserDeleted, body: `{}`},
+		{name: "unexpected routing key", routingKey: "user.unknown", body: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange - handlers must fail before touching the database
+			service := &RabbitMQService{config: validConfig}
+
+			// Act
+			action := service.handleMessage(tt.routingKey, []byte(tt.body))
+
+			// Assert
+			assert.Equal(t, rabbitmq.NackDiscard, action)
+		})
+	}
+}
+
+func TestTokenLink(t *testing.T) {
+	// Act
+	link, err := tokenLink("https://example.com/reset-password?lang=en", "a b")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/reset-password?lang=en&token=a+b", link)
+}
+
+func TestTokenLink_InvalidURL(t *testing.T) {
+	// Act
+	link, err := tokenLink("http://[::1", "secret")
+
+	// Assert - a configuration error is not a malformed event
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errMalformedEvent)
+	assert.Empty(t, link)
+}
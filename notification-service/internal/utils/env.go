@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 )
@@ -99,6 +100,18 @@ func ValidatePort(port string) error {
 	return nil
 }
 
+// ValidateAbsoluteURL validates that a string is an absolute URL with a host
+func ValidateAbsoluteURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %v", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("URL must be absolute with a host")
+	}
+	return nil
+}
+
 // ValidateNonEmpty validates that a string is not empty
 func ValidateNonEmpty(value string) error {
 	if value == "" {
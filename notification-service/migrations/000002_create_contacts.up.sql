@@ -0,0 +1,6 @@
+-- Contact data of users, kept current from user.created and user.email_changed events
+CREATE TABLE IF NOT EXISTS contacts (
+    user_id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,238 @@
+// Package migrator applies the embedded SQL migrations of a service to its Postgres database.
+// It backs the migrate subcommand and the schema version check of every service binary.
+package migrator
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Migrator applies a service's migrations to its database
+type Migrator struct {
+	m        *migrate.Migrate
+	expected uint
+}
+
+// New opens a dedicated connection to the database described by dsn
+// and prepares the migrations in fsys, usually the service's embedded
+// migrations.FS. Close releases the connection.
+func New(dsn string, fsys fs.FS) (*Migrator, error) {
+	expected, err := LatestVersion(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	driver, err := pgx.WithInstance(db, &pgx.Config{})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to init migrator: %w", err)
+	}
+
+	return &Migrator{m: m, expected: expected}, nil
+}
+
+// LatestVersion returns the highest migration version found in fsys
+func LatestVersion(fsys fs.FS) (uint, error) {
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("no embedded migrations found: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// Close closes the migration source and the database connection
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// ExpectedVersion returns the schema version this binary was built for
+func (mg *Migrator) ExpectedVersion() uint {
+	return mg.expected
+}
+
+// Version returns the current schema version and whether it is dirty.
+// A database without any applied migration reports version 0.
+func (mg *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Up applies n pending migrations, or all of them when n <= 0
+func (mg *Migrator) Up(n int) error {
+	var err error
+	if n <= 0 {
+		err = mg.m.Up()
+	} else {
+		err = mg.m.Steps(n)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// Down rolls back n applied migrations
+func (mg *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of steps to roll back must be positive, got %d", n)
+	}
+	err := mg.m.Steps(-n)
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// CheckVersion returns an error unless the database schema is clean
+// and exactly at the version this binary expects
+func (mg *Migrator) CheckVersion() error {
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("database schema is dirty at version %d", version)
+	}
+	if version != mg.expected {
+		return fmt.Errorf("database schema version %d does not match expected version %d", version, mg.expected)
+	}
+	return nil
+}
+
+// Usage describes the migrate subcommand
+const Usage = "usage: migrate <up [N] | down [N] | status | version>"
+
+// Run executes a migrate subcommand such as "up", "down 2" or "status"
+func (mg *Migrator) Run(args []string, out io.Writer) error {
+	cmd, steps, err := ParseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "up":
+		if err := mg.Up(steps); err != nil {
+			return err
+		}
+	case "down":
+		if err := mg.Down(steps); err != nil {
+			return err
+		}
+	case "status":
+		return mg.printStatus(out)
+	case "version":
+		version, dirty, err := mg.Version()
+		if err != nil {
+			return err
+		}
+		if dirty {
+			fmt.Fprintf(out, "%d (dirty)\n", version)
+		} else {
+			fmt.Fprintf(out, "%d\n", version)
+		}
+		return nil
+	}
+
+	return mg.printStatus(out)
+}
+
+// ParseArgs validates migrate subcommand arguments and returns the command
+// and its step count. "down" defaults to one step, "up" to all pending ones.
+func ParseArgs(args []string) (string, int, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return "", 0, errors.New(Usage)
+	}
+
+	cmd := args[0]
+	switch cmd {
+	case "up", "down":
+	case "status", "version":
+		if len(args) != 1 {
+			return "", 0, errors.New(Usage)
+		}
+		return cmd, 0, nil
+	default:
+		return "", 0, fmt.Errorf("unknown migrate command %q: %s", cmd, Usage)
+	}
+
+	steps := 0
+	if cmd == "down" {
+		steps = 1
+	}
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return "", 0, fmt.Errorf("invalid number of steps %q: %s", args[1], Usage)
+		}
+		steps = n
+	}
+	return cmd, steps, nil
+}
+
+func (mg *Migrator) printStatus(out io.Writer) error {
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "current version:  %d\n", version)
+	fmt.Fprintf(out, "expected version: %d\n", mg.expected)
+	fmt.Fprintf(out, "dirty:            %t\n", dirty)
+
+	switch {
+	case dirty:
+		fmt.Fprintln(out, "status:           dirty, fix the failed migration and force a version")
+	case version < mg.expected:
+		fmt.Fprintln(out, "status:           pending migrations")
+	case version > mg.expected:
+		fmt.Fprintln(out, "status:           database is newer than this binary")
+	default:
+		fmt.Fprintln(out, "status:           up to date")
+	}
+	return nil
+}
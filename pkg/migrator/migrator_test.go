@@ -0,0 +1,74 @@
+package migrator_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/Koshsky/subs-service/pkg/migrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion_PicksHighestVersion(t *testing.T) {
+	// Arrange
+	fsys := fstest.MapFS{
+		"000001_a.up.sql":   {Data: []byte("SELECT 1;")},
+		"000001_a.down.sql": {Data: []byte("SELECT 1;")},
+		"000003_c.up.sql":   {Data: []byte("SELECT 1;")},
+		"000002_b.up.sql":   {Data: []byte("SELECT 1;")},
+	}
+
+	// Act
+	version, err := migrator.LatestVersion(fsys)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), version)
+}
+
+func TestLatestVersion_NoMigrations(t *testing.T) {
+	// Act
+	_, err := migrator.LatestVersion(fstest.MapFS{})
+
+	// Assert
+	require.Error(t, err)
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantCmd   string
+		wantSteps int
+		wantErr   bool
+	}{
+		{name: "UpAll", args: []string{"up"}, wantCmd: "up", wantSteps: 0},
+		{name: "UpSteps", args: []string{"up", "2"}, wantCmd: "up", wantSteps: 2},
+		{name: "DownDefaultsToOne", args: []string{"down"}, wantCmd: "down", wantSteps: 1},
+		{name: "DownSteps", args: []string{"down", "3"}, wantCmd: "down", wantSteps: 3},
+		{name: "Status", args: []string{"status"}, wantCmd: "status"},
+		{name: "Version", args: []string{"version"}, wantCmd: "version"},
+		{name: "NoArgs", args: nil, wantErr: true},
+		{name: "UnknownCommand", args: []string{"sideways"}, wantErr: true},
+		{name: "InvalidSteps", args: []string{"down", "abc"}, wantErr: true},
+		{name: "NegativeSteps", args: []string{"up", "-1"}, wantErr: true},
+		{name: "StatusWithSteps", args: []string{"status", "1"}, wantErr: true},
+		{name: "TooManyArgs", args: []string{"up", "1", "2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			cmd, steps, err := migrator.ParseArgs(tt.args)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCmd, cmd)
+			assert.Equal(t, tt.wantSteps, steps)
+		})
+	}
+}
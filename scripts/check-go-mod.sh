@@ -0,0 +1,27 @@
+#!/bin/bash
+
+# Script to check that go.mod and go.sum of every module are tidy.
+# Each service is built by its Dockerfile without go.work, so a missing
+# requirement or go.sum entry only shows up there.
+set -e
+
+modules=(
+    "auth-service"
+    "core-service"
+    "notification-service"
+    "pkg"
+)
+
+echo "🔍 Checking go.mod and go.sum"
+
+failed=0
+for module in "${modules[@]}"; do
+    if (cd "$module" && GOWORK=off go mod tidy -diff); then
+        echo "✅ $module"
+    else
+        echo "❌ $module: run 'go mod tidy' in $module"
+        failed=1
+    fi
+done
+
+exit $failed
@@ -6,6 +6,8 @@ set -e
 # Check for proto files
 proto_files=(
     "auth-service/internal/authpb/auth.proto"
+    "auth-service/internal/authpb/v2/auth.proto"
+    "core-service/internal/corepb/v2/auth.proto"
 )
 
 for proto_file in "${proto_files[@]}"; do
@@ -43,11 +45,13 @@ echo "📝 Generating auth-service protobuf..."
 cd auth-service
 protoc --go_out=. --go_opt=paths=source_relative \
        --go-grpc_out=. --go-grpc_opt=paths=source_relative \
-       internal/authpb/auth.proto
+       internal/authpb/auth.proto internal/authpb/v2/auth.proto
 
 echo "✅ Generated:"
 echo "  - internal/authpb/auth.pb.go"
 echo "  - internal/authpb/auth_grpc.pb.go"
+echo "  - internal/authpb/v2/auth.pb.go"
+echo "  - internal/authpb/v2/auth_grpc.pb.go"
 cd ..
 
 # Generate for core-service
@@ -55,11 +59,11 @@ echo "📝 Generating core-service protobuf..."
 cd core-service
 protoc --go_out=. --go_opt=paths=source_relative \
        --go-grpc_out=. --go-grpc_opt=paths=source_relative \
-       internal/corepb/auth.proto
+       internal/corepb/v2/auth.proto
 
 echo "✅ Generated:"
-echo "  - internal/corepb/auth.pb.go"
-echo "  - internal/corepb/auth_grpc.pb.go"
+echo "  - internal/corepb/v2/auth.pb.go"
+echo "  - internal/corepb/v2/auth_grpc.pb.go"
 cd ..
 
 echo "🎉 All protobuf files generated successfully!"
\ No newline at end of file
@@ -8,7 +8,7 @@ API_URL="http://localhost:8080"
 # Generate random credentials for each run
 RANDOM_ID=$(date +%s%N | cut -b1-13)
 EMAIL="test_${RANDOM_ID}@example.com"
-PASSWORD="password_$(openssl rand -hex 8)"
+PASSWORD="Passw0rd_$(openssl rand -hex 8)"
 COOKIES_FILE="/tmp/test_cookies_${RANDOM_ID}.txt"
 
 echo "🧪 Testing the API of the subscription service and Notification Service"